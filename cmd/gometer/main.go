@@ -0,0 +1,500 @@
+// gometer reads and configures meters from the command line. Meters are
+// named by URLs like "kmp:///dev/ttyUSB0?addr=0x3f&model=382" or
+// "iec62056://host:port", see the meter package for the parameters. The
+// decode command decodes captured frames and telegrams without a meter.
+//
+// The traffic can be written to a trace file with -trace. A trace file is
+// replayed by using a URL like "kmp:?replay=session.trace".
+//
+// Quantities can be selected by OBIS patterns like "1-0:1.8.*", by Kamstrup
+// register names like "EnergyIn" or "HeatEnergy", or by quantity names like
+// "voltage" or "active_energy_import". Register names are looked up in the
+// register map of the meter model. Quantity names cannot be selected by the
+// meter, so every quantity is read and the result is filtered.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abrander/gometer/decode"
+	"github.com/abrander/gometer/iec62056"
+	"github.com/abrander/gometer/kamstrup"
+	"github.com/abrander/gometer/meter"
+	"github.com/abrander/gometer/trace"
+)
+
+type (
+	// command is a subcommand of the tool.
+	command struct {
+		args  string
+		help  string
+		nargs int // The minimum number of arguments after the URL.
+		run   func(m meter.Meter, args []string, out *output) error
+	}
+
+	// selection is the quantities selected by the arguments of a command.
+	selection struct {
+		patterns   []iec62056.ObisPattern
+		quantities []iec62056.Quantity
+	}
+)
+
+var (
+	format   = flag.String("format", "table", "Output format: table, json or csv")
+	interval = flag.Duration("interval", 10*time.Second, "Poll interval of watch")
+	since    = flag.Duration("since", 24*time.Hour, "Age of the oldest record read by log")
+	traceTo  = flag.String("trace", "", "Write the traffic to a trace `file`, or - for stderr")
+
+	commands = map[string]command{
+		"identify":       {"", "Show the identity of the meter", 0, identify},
+		"read":           {"[quantity|register|pattern...]", "Read the selected quantities", 0, read},
+		"dump":           {"", "Show the identity and all quantities", 0, dump},
+		"watch":          {"[quantity|register|pattern...]", "Read the selected quantities every interval", 0, watch},
+		"set-clock":      {"[time]", "Set the meter clock to an RFC 3339 time, or now", 0, setClock},
+		"write-register": {"obis value [unit]", "Write a value to the register of an OBIS code", 2, writeRegister},
+		"scan":           {"", "List the addresses of the meters on the bus", 0, scan},
+		"log":            {"[quantity|register|pattern...]", "Read the log of the selected quantities", 0, readLog},
+	}
+)
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		usage()
+		os.Exit(2)
+	}
+
+	out, err := newOutput(os.Stdout, *format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+		os.Exit(2)
+	}
+
+	if *interval <= 0 {
+		fmt.Fprintf(os.Stderr, "-interval must be positive, got %s\n", *interval)
+		os.Exit(2)
+	}
+
+	if *since <= 0 {
+		fmt.Fprintf(os.Stderr, "-since must be positive, got %s\n", *since)
+		os.Exit(2)
+	}
+
+	name := flag.Arg(0)
+
+	// decode is the only command not talking to a meter.
+	if name == "decode" {
+		err = decodeFiles(flag.Args()[1:], out)
+		out.Flush()
+
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", name, err.Error())
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	cmd, found := commands[name]
+	if !found || flag.NArg()-2 < cmd.nargs {
+		usage()
+		os.Exit(2)
+	}
+
+	m, err := meter.Open(flag.Arg(1))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", flag.Arg(1), err.Error())
+		os.Exit(1)
+	}
+
+	if *traceTo != "" {
+		closeTrace, err := startTrace(m, *traceTo)
+		if err != nil {
+			m.Close()
+			fmt.Fprintf(os.Stderr, "%s: %s\n", *traceTo, err.Error())
+			os.Exit(1)
+		}
+
+		defer closeTrace()
+	}
+
+	err = cmd.run(m, flag.Args()[2:], out)
+	m.Close()
+	out.Flush()
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", name, err.Error())
+		os.Exit(1)
+	}
+}
+
+// startTrace will trace the traffic of m to the file at path, or to stderr
+// for "-". The function returned closes the file.
+func startTrace(m meter.Meter, path string) (func() error, error) {
+	traceable, ok := m.(meter.Traceable)
+	if !ok {
+		return nil, errors.New("tracing is not supported by the meter")
+	}
+
+	if path == "-" {
+		traceable.SetTracer(trace.NewWriter(os.Stderr))
+
+		return func() error { return nil }, nil
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	traceable.SetTracer(trace.NewWriter(f))
+
+	return f.Close, nil
+}
+
+func usage() {
+	w := flag.CommandLine.Output()
+
+	fmt.Fprintf(w, "Usage: %s [flags] command url [arguments]\n", os.Args[0])
+	fmt.Fprintf(w, "       %s [flags] decode [file...]\n\nCommands:\n", os.Args[0])
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		cmd := commands[name]
+		fmt.Fprintf(w, "  %-42s %s\n", name+" url "+cmd.args, cmd.help)
+	}
+
+	fmt.Fprintf(w, "\nQuantities are selected by OBIS patterns like \"1-0:1.8.*\", Kamstrup register\n")
+	fmt.Fprintf(w, "names like \"EnergyIn\" or quantity names like \"voltage\". Selecting a quantity\n")
+	fmt.Fprintf(w, "name reads every quantity of the meter and shows the selected ones.\n")
+
+	fmt.Fprintf(w, "\nSchemes: %s\n\nFlags:\n", strings.Join(meter.Schemes(), ", "))
+	flag.PrintDefaults()
+}
+
+// parseSelection will parse arguments as OBIS patterns, as Kamstrup register
+// names if m is a Kamstrup meter, or as quantity names otherwise. Register
+// names are replaced by the OBIS code they are mapped to for the model.
+func parseSelection(m meter.Meter, args []string) (selection, error) {
+	var s selection
+
+	for _, arg := range args {
+		p, err := iec62056.ParseObisPattern(arg)
+		if err == nil {
+			s.patterns = append(s.patterns, p)
+			continue
+		}
+
+		p, found, err := registerPattern(m, arg)
+		if err != nil {
+			return s, err
+		}
+
+		if found {
+			s.patterns = append(s.patterns, p)
+		} else {
+			s.quantities = append(s.quantities, iec62056.Quantity(arg))
+		}
+	}
+
+	return s, nil
+}
+
+// registerPattern will return a pattern matching the OBIS code of the
+// Kamstrup register named name. The name is not case sensitive. The second
+// return value is false if m is not a Kamstrup meter or name is not a
+// register name.
+func registerPattern(m meter.Meter, name string) (iec62056.ObisPattern, bool, error) {
+	k, ok := m.(*meter.Kamstrup)
+	if !ok {
+		return iec62056.ObisPattern{}, false, nil
+	}
+
+	for registerName, register := range kamstrup.RegisterNames {
+		if !strings.EqualFold(registerName, name) {
+			continue
+		}
+
+		obis, found := k.Registers[register]
+		if !found {
+			return iec62056.ObisPattern{}, false, fmt.Errorf("register %s is not read for this meter model", registerName)
+		}
+
+		p, err := iec62056.ParseObisPattern(obis.String())
+
+		return p, true, err
+	}
+
+	return iec62056.ObisPattern{}, false, nil
+}
+
+// read will return the patterns to read from the meter. Quantities cannot
+// be selected by the meter, so all values are read if any are selected.
+func (s selection) read() []iec62056.ObisPattern {
+	if len(s.quantities) > 0 {
+		return nil
+	}
+
+	return s.patterns
+}
+
+// filter will remove the values not selected from r.
+func (s selection) filter(r *meter.Reading) {
+	if len(s.quantities) == 0 {
+		return
+	}
+
+	for obis := range r.Values {
+		if !s.match(obis) {
+			delete(r.Values, obis)
+		}
+	}
+}
+
+func (s selection) match(o iec62056.Obis) bool {
+	for _, p := range s.patterns {
+		if p.Match(o) {
+			return true
+		}
+	}
+
+	semantic, found := o.Semantic()
+	if !found {
+		return false
+	}
+
+	for _, q := range s.quantities {
+		if semantic.Quantity == q {
+			return true
+		}
+	}
+
+	return false
+}
+
+func identify(m meter.Meter, _ []string, out *output) error {
+	id, err := m.Identify()
+	if err != nil {
+		return err
+	}
+
+	return out.Identity(id)
+}
+
+func read(m meter.Meter, args []string, out *output) error {
+	s, err := parseSelection(m, args)
+	if err != nil {
+		return err
+	}
+
+	r, err := m.Read(s.read()...)
+	if err != nil {
+		return err
+	}
+
+	s.filter(r)
+
+	return out.Reading(r)
+}
+
+func dump(m meter.Meter, _ []string, out *output) error {
+	id, err := m.Identify()
+	if err != nil {
+		return err
+	}
+
+	err = out.Identity(id)
+	if err != nil {
+		return err
+	}
+
+	r, err := m.Read()
+	if err != nil {
+		return err
+	}
+
+	return out.Reading(r)
+}
+
+// watch will read the meter every interval until interrupted. Failed reads
+// are reported, but do not stop the watch.
+func watch(m meter.Meter, args []string, out *output) error {
+	s, err := parseSelection(m, args)
+	if err != nil {
+		return err
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+
+	for {
+		r, err := m.Read(s.read()...)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "watch: %s\n", err.Error())
+		} else {
+			s.filter(r)
+
+			err = out.Reading(r)
+			if err != nil {
+				return err
+			}
+
+			out.Flush()
+		}
+
+		select {
+		case <-interrupt:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func setClock(m meter.Meter, args []string, out *output) error {
+	setter, ok := m.(meter.ClockSetter)
+	if !ok {
+		return errors.New("setting the clock is not supported by the meter")
+	}
+
+	t := time.Now()
+
+	if len(args) > 0 {
+		var err error
+
+		t, err = time.Parse(time.RFC3339, args[0])
+		if err != nil {
+			return err
+		}
+	}
+
+	return setter.SetClock(t)
+}
+
+func writeRegister(m meter.Meter, args []string, out *output) error {
+	writer, ok := m.(meter.RegisterWriter)
+	if !ok {
+		return errors.New("writing registers is not supported by the meter")
+	}
+
+	obis, err := iec62056.ParseObis(args[0])
+	if err != nil {
+		return err
+	}
+
+	value, err := strconv.ParseFloat(args[1], 64)
+	if err != nil {
+		return err
+	}
+
+	v := kamstrup.Value{Value: value}
+
+	if len(args) > 2 {
+		v.Unit = kamstrup.UnitFromString(args[2])
+		if v.Unit == kamstrup.UnitNone {
+			return fmt.Errorf("unknown unit %q", args[2])
+		}
+	}
+
+	return writer.WriteRegister(obis, v)
+}
+
+func scan(m meter.Meter, _ []string, out *output) error {
+	scanner, ok := m.(meter.Scanner)
+	if !ok {
+		return errors.New("scanning is not supported by the meter")
+	}
+
+	addresses, err := scanner.Scan()
+	if err != nil {
+		return err
+	}
+
+	return out.Addresses(addresses)
+}
+
+func readLog(m meter.Meter, args []string, out *output) error {
+	reader, ok := m.(meter.LogReader)
+	if !ok {
+		return errors.New("log readout is not supported by the meter")
+	}
+
+	s, err := parseSelection(m, args)
+	if err != nil {
+		return err
+	}
+
+	readings, err := reader.ReadLog(time.Now().Add(-*since), s.read()...)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range readings {
+		s.filter(r)
+
+		err = out.Reading(r)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeFiles will decode the frames and telegrams captured in files, or
+// read from stdin without files. Captures can be binary or hex dumps.
+func decodeFiles(paths []string, out *output) error {
+	if len(paths) == 0 {
+		paths = []string{"-"}
+	}
+
+	for _, path := range paths {
+		var raw []byte
+		var err error
+
+		if path == "-" {
+			raw, err = io.ReadAll(os.Stdin)
+		} else {
+			raw, err = os.ReadFile(path)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		results, err := decode.Decode(decode.ParseInput(raw))
+
+		for _, r := range results {
+			err := out.Decoded(r)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/abrander/gometer/decode"
+	"github.com/abrander/gometer/iec62056"
+	"github.com/abrander/gometer/kamstrup"
+	"github.com/abrander/gometer/meter"
+)
+
+type (
+	// output writes results as a table, JSON or CSV. JSON is written as one
+	// object per line, so watch and log results can be streamed.
+	output struct {
+		table *tabwriter.Writer
+		csv   *csv.Writer
+		json  *json.Encoder
+
+		// header is the header last written to a table or CSV.
+		header string
+	}
+
+	jsonIdentity struct {
+		Protocol     string `json:"protocol"`
+		Manufacturer string `json:"manufacturer,omitempty"`
+		Model        string `json:"model,omitempty"`
+		Serial       string `json:"serial,omitempty"`
+	}
+
+	jsonValue struct {
+		Obis        string  `json:"obis"`
+		Description string  `json:"description,omitempty"`
+		Value       float64 `json:"value"`
+		Unit        string  `json:"unit,omitempty"`
+	}
+
+	jsonField struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+
+	jsonResult struct {
+		Protocol      string      `json:"protocol"`
+		Raw           string      `json:"raw"`
+		Fields        []jsonField `json:"fields"`
+		ChecksumValid bool        `json:"checksumValid"`
+		Error         string      `json:"error,omitempty"`
+	}
+
+	jsonReading struct {
+		Time   time.Time   `json:"time"`
+		Values []jsonValue `json:"values"`
+	}
+)
+
+func newOutput(w io.Writer, format string) (*output, error) {
+	out := &output{}
+
+	switch format {
+	case "table":
+		out.table = tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	case "csv":
+		out.csv = csv.NewWriter(w)
+	case "json":
+		out.json = json.NewEncoder(w)
+	default:
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+
+	return out, nil
+}
+
+// Identity will write the identity of a meter.
+func (o *output) Identity(id *meter.Identity) error {
+	if o.json != nil {
+		return o.json.Encode(jsonIdentity{
+			Protocol:     id.Protocol,
+			Manufacturer: string(id.Manufacturer),
+			Model:        id.Model,
+			Serial:       id.Serial,
+		})
+	}
+
+	return o.rows([]string{"PROTOCOL", "MANUFACTURER", "MODEL", "SERIAL"}, [][]string{
+		{id.Protocol, string(id.Manufacturer), id.Model, id.Serial},
+	})
+}
+
+// Reading will write the values of a reading sorted by OBIS code.
+func (o *output) Reading(r *meter.Reading) error {
+	codes := make([]iec62056.Obis, 0, len(r.Values))
+	for obis := range r.Values {
+		codes = append(codes, obis)
+	}
+
+	sort.Slice(codes, func(i, j int) bool {
+		return codes[i].String() < codes[j].String()
+	})
+
+	if o.json != nil {
+		jr := jsonReading{
+			Time:   r.Time,
+			Values: make([]jsonValue, 0, len(codes)),
+		}
+
+		for _, obis := range codes {
+			v := r.Values[obis]
+
+			jr.Values = append(jr.Values, jsonValue{
+				Obis:        obis.String(),
+				Description: obis.Description(),
+				Value:       v.Value,
+				Unit:        unit(v.Unit),
+			})
+		}
+
+		return o.json.Encode(jr)
+	}
+
+	rows := make([][]string, 0, len(codes))
+	for _, obis := range codes {
+		v := r.Values[obis]
+
+		rows = append(rows, []string{
+			r.Time.Format(time.RFC3339),
+			obis.String(),
+			strconv.FormatFloat(v.Value, 'f', -1, 64),
+			unit(v.Unit),
+			obis.Description(),
+		})
+	}
+
+	return o.rows([]string{"TIME", "OBIS", "VALUE", "UNIT", "DESCRIPTION"}, rows)
+}
+
+// Addresses will write the addresses found by a scan.
+func (o *output) Addresses(addresses []string) error {
+	if o.json != nil {
+		if addresses == nil {
+			addresses = []string{}
+		}
+
+		return o.json.Encode(addresses)
+	}
+
+	rows := make([][]string, len(addresses))
+	for i, address := range addresses {
+		rows[i] = []string{address}
+	}
+
+	return o.rows([]string{"ADDRESS"}, rows)
+}
+
+// Decoded will write a decoded frame or telegram. Tables show the fields as
+// text, CSV has a row per field.
+func (o *output) Decoded(r *decode.Result) error {
+	switch {
+	case o.json != nil:
+		jr := jsonResult{
+			Protocol:      string(r.Protocol),
+			Raw:           hex.EncodeToString(r.Raw),
+			Fields:        make([]jsonField, len(r.Fields)),
+			ChecksumValid: r.ChecksumValid,
+		}
+
+		for i, f := range r.Fields {
+			jr.Fields[i] = jsonField{f.Name, f.Value}
+		}
+
+		if r.Err != nil {
+			jr.Error = r.Err.Error()
+		}
+
+		return o.json.Encode(jr)
+
+	case o.csv != nil:
+		rows := make([][]string, 0, len(r.Fields)+1)
+		for _, f := range r.Fields {
+			rows = append(rows, []string{string(r.Protocol), f.Name, f.Value})
+		}
+
+		if r.Err != nil {
+			rows = append(rows, []string{string(r.Protocol), "Error", r.Err.Error()})
+		}
+
+		return o.rows([]string{"PROTOCOL", "FIELD", "VALUE"}, rows)
+	}
+
+	_, err := fmt.Fprintln(o.table, r.String())
+
+	return err
+}
+
+// Flush will write buffered output.
+func (o *output) Flush() error {
+	switch {
+	case o.table != nil:
+		return o.table.Flush()
+	case o.csv != nil:
+		o.csv.Flush()
+		return o.csv.Error()
+	}
+
+	return nil
+}
+
+// rows will write rows to a table or CSV. The header is written only if it
+// differs from the header last written, so repeated readings form a single
+// table.
+func (o *output) rows(header []string, rows [][]string) error {
+	h := fmt.Sprint(header)
+	if h != o.header {
+		if o.header != "" && o.table != nil {
+			fmt.Fprintln(o.table)
+		}
+
+		o.header = h
+		rows = append([][]string{header}, rows...)
+	}
+
+	for _, row := range rows {
+		if o.csv != nil {
+			err := o.csv.Write(row)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		for i, field := range row {
+			if i > 0 {
+				fmt.Fprint(o.table, "\t")
+			}
+
+			fmt.Fprint(o.table, field)
+		}
+
+		fmt.Fprintln(o.table)
+	}
+
+	return nil
+}
+
+// unit will return the unit, or an empty string for values without a unit.
+func unit(u kamstrup.Unit) string {
+	if u == kamstrup.UnitNone || u == kamstrup.UnitSpace {
+		return ""
+	}
+
+	return u.String()
+}
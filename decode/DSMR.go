@@ -0,0 +1,62 @@
+package decode
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/abrander/gometer/dsmr"
+	"github.com/abrander/gometer/iec62056"
+)
+
+// isP1 will recognize a telegram by an identification line followed by data
+// lines instead of a data block.
+func isP1(raw []byte) bool {
+	if raw[0] != dsmr.TelegramStart || bytes.HasPrefix(raw, []byte("/?")) {
+		return false
+	}
+
+	n := lineLength(raw)
+
+	return n < len(raw) && raw[n] != iec62056.FrameStart && bytes.IndexByte(raw, dsmr.TelegramEnd) > 0
+}
+
+// decodeP1 will decode a telegram up to and including the checksum line.
+func decodeP1(raw []byte) (int, *Result) {
+	end := bytes.IndexByte(raw, dsmr.TelegramEnd)
+	n := end + lineLength(raw[end:])
+
+	r := newResult(ProtocolP1, raw[:n])
+
+	t, err := dsmr.ParseTelegram(raw[:n])
+	if err == dsmr.ErrInvalidChecksum {
+		// Decode the lines anyway, leaving out the checksum.
+		t, err = dsmr.ParseTelegram(raw[:end+1])
+	}
+
+	if err != nil {
+		r.Err = err
+		return n, r
+	}
+
+	r.identification("/" + t.Identification)
+
+	if data, err := t.Decode(); err == nil && data.Version != "" {
+		r.add("Version", "%s", data.Version)
+	}
+
+	for _, line := range t.Lines() {
+		r.dataLine(line)
+	}
+
+	checksum := strings.TrimSpace(string(raw[end+1 : n]))
+	if checksum == "" {
+		r.add("Checksum", "none")
+		return n, r
+	}
+
+	sum, _ := strconv.ParseUint(checksum, 16, 16)
+	r.checksum("%04X", sum, uint64(dsmr.CRC16(raw[:end+1])))
+
+	return n, r
+}
@@ -0,0 +1,220 @@
+// Package decode decodes captured frames and telegrams of the supported
+// protocols offline, showing every field found on the wire.
+package decode
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/abrander/gometer/iec62056"
+	"github.com/abrander/gometer/kamstrup"
+)
+
+type (
+	// Protocol is the protocol of decoded data.
+	Protocol string
+
+	// Field is a decoded field.
+	Field struct {
+		Name  string
+		Value string
+	}
+
+	// Result is a single frame, block or telegram.
+	Result struct {
+		Protocol Protocol
+
+		// Raw is the data decoded, as found on the wire.
+		Raw []byte
+
+		// Fields holds every field decoded, in the order found.
+		Fields []Field
+
+		// Values holds the values with a known OBIS code.
+		Values iec62056.ValueCollection
+
+		// ChecksumValid is set if the data has a checksum and it verified.
+		ChecksumValid bool
+
+		// Err is set if the data could only be decoded in part.
+		Err error
+	}
+)
+
+// Known protocols.
+const (
+	ProtocolKMP      = Protocol("KMP")
+	ProtocolIEC62056 = Protocol("IEC 62056-21")
+	ProtocolP1       = Protocol("P1")
+	ProtocolMBus     = Protocol("M-Bus")
+)
+
+var (
+	// ErrUnknownFormat will be returned if the data is not recognized as any
+	// of the supported protocols.
+	ErrUnknownFormat = errors.New("unknown format")
+
+	// ErrTruncated will be set for data ending before its end marker or
+	// checksum.
+	ErrTruncated = errors.New("data truncated")
+)
+
+// ParseInput will return the bytes of a hex dump like "40 3f 10" or
+// "0x40,0x3f,0x10". Input not being a hex dump is returned unchanged, as it
+// is taken as binary or text.
+func ParseInput(in []byte) []byte {
+	text := strings.ReplaceAll(strings.ToLower(string(in)), "0x", "")
+
+	var digits []byte
+	for _, c := range text {
+		switch {
+		case c >= '0' && c <= '9', c >= 'a' && c <= 'f':
+			digits = append(digits, byte(c))
+		case unicode.IsSpace(c), c == ':', c == ',', c == '-':
+		default:
+			return in
+		}
+	}
+
+	if len(digits) == 0 || len(digits)%2 != 0 {
+		return in
+	}
+
+	out := make([]byte, len(digits)/2)
+	_, err := hex.Decode(out, digits)
+	if err != nil {
+		return in
+	}
+
+	return out
+}
+
+// Decode will decode all frames, blocks and telegrams in raw. The protocol is
+// detected from the first byte of each. White space between them is skipped.
+// If unknown data is found, the results decoded before it are returned with
+// ErrUnknownFormat.
+func Decode(raw []byte) ([]*Result, error) {
+	var results []*Result
+
+	offset := 0
+	for offset < len(raw) {
+		if unicode.IsSpace(rune(raw[offset])) {
+			offset++
+			continue
+		}
+
+		n, r := decodeNext(raw[offset:])
+		if r == nil {
+			return results, fmt.Errorf("%w at offset %d", ErrUnknownFormat, offset)
+		}
+
+		results = append(results, r)
+		offset += n
+	}
+
+	return results, nil
+}
+
+// decodeNext will decode the data starting raw and return the number of bytes
+// used.
+func decodeNext(raw []byte) (int, *Result) {
+	switch {
+	case isKMP(raw):
+		return decodeKMP(raw)
+	case isMBus(raw):
+		return decodeMBus(raw)
+	case isP1(raw):
+		return decodeP1(raw)
+	case isIEC62056(raw):
+		return decodeIEC62056(raw)
+	}
+
+	return 0, nil
+}
+
+func newResult(protocol Protocol, raw []byte) *Result {
+	return &Result{
+		Protocol: protocol,
+		Raw:      raw,
+		Values:   make(iec62056.ValueCollection),
+	}
+}
+
+// add will add a field with a formatted value.
+func (r *Result) add(name string, format string, args ...interface{}) {
+	r.Fields = append(r.Fields, Field{
+		Name:  name,
+		Value: fmt.Sprintf(format, args...),
+	})
+}
+
+// checksum will add the checksum field.
+func (r *Result) checksum(format string, found uint64, expected uint64) {
+	r.ChecksumValid = found == expected
+
+	if r.ChecksumValid {
+		r.add("Checksum", format+", valid", found)
+	} else {
+		r.add("Checksum", format+", invalid, expected "+format, found, expected)
+	}
+}
+
+// dataLine will add a data line like "1-0:1.8.0(001234.5*kWh)" with its
+// description, and its value if numeric.
+func (r *Result) dataLine(line string) {
+	obis, values, err := iec62056.ParseDataLine(line)
+	if err != nil {
+		r.add("Line", "%q, %s", line, err.Error())
+		return
+	}
+
+	value := "(" + strings.Join(values, ")(") + ")"
+
+	v, err := iec62056.ParseValue(values[len(values)-1])
+	if err == nil {
+		r.Values[obis] = v
+		value = formatValue(v)
+	}
+
+	r.add(obis.String(), "%s, %s", value, obis.Description())
+}
+
+// formatValue will format a value like "1234.5 kWh", leaving out the unit if
+// none.
+func formatValue(v kamstrup.Value) string {
+	s := strconv.FormatFloat(v.Value, 'f', -1, 64)
+
+	if v.Unit != kamstrup.UnitNone {
+		s += " " + v.Unit.String()
+	}
+
+	return s
+}
+
+// String will return the result as text, one field per line.
+func (r *Result) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s, %d bytes: % x\n", r.Protocol, len(r.Raw), r.Raw)
+
+	width := 0
+	for _, f := range r.Fields {
+		if len(f.Name) > width {
+			width = len(f.Name)
+		}
+	}
+
+	for _, f := range r.Fields {
+		fmt.Fprintf(&b, "  %-*s  %s\n", width+1, f.Name+":", f.Value)
+	}
+
+	if r.Err != nil {
+		fmt.Fprintf(&b, "  Error: %s\n", r.Err.Error())
+	}
+
+	return b.String()
+}
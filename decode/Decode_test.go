@@ -0,0 +1,230 @@
+package decode
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/abrander/gometer/iec62056"
+	"github.com/abrander/gometer/kamstrup"
+	"github.com/abrander/gometer/mbus"
+)
+
+// p1Telegram is a short DSMR 5 telegram.
+var p1Telegram = strings.Replace(`/ISk5\2MT382-1000
+
+1-3:0.2.8(50)
+1-0:1.8.1(123456.789*kWh)
+1-0:32.7.0(220.1*V)
+!`, "\n", "\r\n", -1)
+
+// field will return the value of the first field named name.
+func field(r *Result, name string) string {
+	for _, f := range r.Fields {
+		if f.Name == name {
+			return f.Value
+		}
+	}
+
+	return ""
+}
+
+func decodeOne(t *testing.T, raw []byte) *Result {
+	t.Helper()
+
+	results, err := Decode(raw)
+	if err != nil {
+		t.Fatalf("Decode() returned %s", err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("Decode() returned %d results, expected 1", len(results))
+	}
+
+	return results[0]
+}
+
+func TestParseInput(t *testing.T) {
+	cases := []struct {
+		in  string
+		out []byte
+	}{
+		{"40 3f 10", []byte{0x40, 0x3f, 0x10}},
+		{"0x40,0x3F,0x10\n", []byte{0x40, 0x3f, 0x10}},
+		{"40:3f:10", []byte{0x40, 0x3f, 0x10}},
+		{"403", []byte("403")},
+		{"/ISk5\\2MT382", []byte("/ISk5\\2MT382")},
+	}
+
+	for _, c := range cases {
+		out := ParseInput([]byte(c.in))
+		if !bytes.Equal(out, c.out) {
+			t.Errorf("ParseInput(%q) returned %x, expected %x", c.in, out, c.out)
+		}
+	}
+}
+
+func TestDecodeKMP(t *testing.T) {
+	request := kamstrup.Frame{
+		Type:      kamstrup.ToMeter,
+		Address:   kamstrup.DefaultAddress,
+		CommandID: kamstrup.GetRegister,
+		Data:      []byte{1, 0x00, 0x01},
+	}
+
+	// The mantissa holds 0x0d, which is escaped.
+	reply := kamstrup.Frame{
+		Type:      kamstrup.FromMeter,
+		Address:   kamstrup.DefaultAddress,
+		CommandID: kamstrup.GetRegister,
+		Data:      []byte{0x00, 0x01, byte(kamstrup.UnitKWh), 4, 0x41, 0x00, 0x00, 0x00, 0x0d},
+	}
+
+	results, err := Decode(append(request.Encode(), reply.Encode()...))
+	if err != nil {
+		t.Fatalf("Decode() returned %s", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("Decode() returned %d results, expected 2", len(results))
+	}
+
+	r := results[0]
+	if r.Protocol != ProtocolKMP || !r.ChecksumValid || field(r, "Command") != "0x10, GetRegister" {
+		t.Errorf("Decode() of request returned %s", r)
+	}
+
+	if !strings.HasPrefix(field(r, "Register 0x0001"), "1-0:1.8.0") {
+		t.Errorf("Decode() of request returned %s", r)
+	}
+
+	r = results[1]
+	energy := r.Values[iec62056.ActiveEnergyImport]
+	if !r.ChecksumValid || energy.Value != 1.3 || energy.Unit != kamstrup.UnitKWh {
+		t.Errorf("Decode() of reply returned %s", r)
+	}
+
+	if !strings.Contains(field(r, "Escapes"), "1b f2") {
+		t.Errorf("Decode() of reply found no escapes: %s", r)
+	}
+
+	raw := reply.Encode()
+	raw[len(raw)-2]++
+
+	r = decodeOne(t, raw)
+	if r.ChecksumValid || !strings.Contains(field(r, "Checksum"), "invalid") {
+		t.Errorf("Decode() of corrupt frame returned %s", r)
+	}
+}
+
+func TestDecodeIEC62056(t *testing.T) {
+	values := iec62056.ValueCollection{
+		iec62056.NewObis("1.8.0"):  {Value: 12345.6, Unit: kamstrup.UnitKWh},
+		iec62056.NewObis("32.7.0"): {Value: 231, Unit: kamstrup.UnitV},
+	}
+
+	raw := append([]byte("/?!\r\n/KAM5MT174\r\n"), values.DataBlock()...)
+
+	results, err := Decode(raw)
+	if err != nil {
+		t.Fatalf("Decode() returned %s", err)
+	}
+
+	if len(results) != 2 || field(results[0], "Message") != "sign-on" {
+		t.Fatalf("Decode() returned %v", results)
+	}
+
+	r := results[1]
+	if r.Protocol != ProtocolIEC62056 || !r.ChecksumValid || len(r.Values) != 2 {
+		t.Errorf("Decode() of readout returned %s", r)
+	}
+
+	if !strings.HasPrefix(field(r, "Manufacturer"), "KAM") || !strings.HasPrefix(field(r, "Baud rate"), "5, 9600") {
+		t.Errorf("Decode() of identification returned %s", r)
+	}
+
+	raw[len(raw)-1] ^= 0xff
+
+	r = decodeOne(t, raw[5:])
+	if r.ChecksumValid {
+		t.Errorf("Decode() of corrupt readout returned %s", r)
+	}
+
+	r = decodeOne(t, raw[5:len(raw)-2])
+	if !errors.Is(r.Err, ErrTruncated) {
+		t.Errorf("Decode() of truncated readout returned %s", r)
+	}
+}
+
+func TestDecodeP1(t *testing.T) {
+	r := decodeOne(t, []byte(p1Telegram+"B246\r\n"))
+	if r.Protocol != ProtocolP1 || len(r.Values) != 3 || field(r, "Version") != "DSMR 5.0" {
+		t.Errorf("Decode() returned %s", r)
+	}
+
+	if !strings.Contains(field(r, "1-0:32.7.0"), "220.1 V") {
+		t.Errorf("Decode() returned %s", r)
+	}
+
+	if !r.ChecksumValid {
+		t.Errorf("Decode() returned checksum %s", field(r, "Checksum"))
+	}
+
+	r = decodeOne(t, []byte(p1Telegram+"0000\r\n"))
+	if r.ChecksumValid || len(r.Values) != 3 {
+		t.Errorf("Decode() of corrupt telegram returned %s", r)
+	}
+}
+
+func TestDecodeMBus(t *testing.T) {
+	data, _ := hex.DecodeString("78563412" + "2d2c" + "01" + "04" + "2a" + "00" + "0000" + "04061c330000" + "0f0102")
+
+	f := mbus.Frame{
+		Type:    mbus.FrameLong,
+		Control: mbus.RspUd,
+		Address: 5,
+		CI:      mbus.CIResponseLong,
+		Data:    data,
+	}
+
+	raw, err := f.Encode()
+	if err != nil {
+		t.Fatalf("Encode() returned %s", err)
+	}
+
+	raw = append([]byte{0x10, 0x5b, 0x05, 0x60, 0x16}, raw...)
+
+	results, err := Decode(raw)
+	if err != nil {
+		t.Fatalf("Decode() returned %s", err)
+	}
+
+	if len(results) != 2 || field(results[0], "Control") != "0x5b, REQ_UD2" || !results[0].ChecksumValid {
+		t.Fatalf("Decode() returned %v", results)
+	}
+
+	r := results[1]
+	if !r.ChecksumValid || !strings.HasPrefix(field(r, "Manufacturer"), "KAM, ") || field(r, "Access number") != "42" {
+		t.Errorf("Decode() returned %s", r)
+	}
+
+	if !strings.Contains(field(r, "Record 1"), "13084") || field(r, "Manufacturer data") != "01 02" {
+		t.Errorf("Decode() returned %s", r)
+	}
+
+	raw[len(raw)-2]++
+
+	r = decodeOne(t, raw[5:])
+	if r.ChecksumValid || field(r, "Record 1") == "" {
+		t.Errorf("Decode() of corrupt frame returned %s", r)
+	}
+}
+
+func TestDecodeUnknown(t *testing.T) {
+	_, err := Decode([]byte("hello"))
+	if !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Decode() returned %v", err)
+	}
+}
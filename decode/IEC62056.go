@@ -0,0 +1,147 @@
+package decode
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/abrander/gometer/iec62056"
+)
+
+var (
+	// baudRates is the baud rate of the baud rate characters of protocol
+	// mode C.
+	baudRates = map[byte]string{
+		'0': "300",
+		'1': "600",
+		'2': "1200",
+		'3': "2400",
+		'4': "4800",
+		'5': "9600",
+		'6': "19200",
+	}
+)
+
+func isIEC62056(raw []byte) bool {
+	return raw[0] == '/' || raw[0] == iec62056.FrameStart || raw[0] == iec62056.Acknowledge
+}
+
+// decodeIEC62056 will decode a sign-on, an acknowledgement and option
+// select message, or an identification and data block.
+func decodeIEC62056(raw []byte) (int, *Result) {
+	if raw[0] == iec62056.FrameStart {
+		n := dataBlockLength(raw)
+
+		r := newResult(ProtocolIEC62056, raw[:n])
+		r.dataBlock(raw[:n])
+
+		return n, r
+	}
+
+	n := lineLength(raw)
+	line := strings.TrimRight(string(raw[:n]), "\r\n")
+
+	switch {
+	case raw[0] == iec62056.Acknowledge:
+		r := newResult(ProtocolIEC62056, raw[:n])
+		r.add("Message", "acknowledgement and option select")
+
+		if len(line) >= 4 {
+			r.add("Protocol", "%c", line[1])
+			r.add("Baud rate", "%c, %s baud", line[2], baudRates[line[2]])
+			r.add("Mode", "%c", line[3])
+		}
+
+		return n, r
+
+	case strings.HasPrefix(line, "/?"):
+		r := newResult(ProtocolIEC62056, raw[:n])
+		r.add("Message", "sign-on")
+
+		address := strings.TrimSuffix(line[2:], "!")
+		if address == "" {
+			address = "none, all meters answer"
+		}
+
+		r.add("Address", "%s", address)
+
+		return n, r
+	}
+
+	// An identification, followed by the data block of a readout.
+	if n < len(raw) && raw[n] == iec62056.FrameStart {
+		n += dataBlockLength(raw[n:])
+	}
+
+	r := newResult(ProtocolIEC62056, raw[:n])
+	r.identification(line)
+
+	block := raw[lineLength(raw):n]
+	if len(block) > 0 {
+		r.dataBlock(block)
+	}
+
+	return n, r
+}
+
+// identification will add the fields of an identification line like
+// "/KAM5\2MT174".
+func (r *Result) identification(line string) {
+	r.add("Identification", "%s", line)
+
+	if len(line) < 5 {
+		return
+	}
+
+	manufacturer := iec62056.Manufacturer(line[1:4])
+	r.add("Manufacturer", "%s, %s", manufacturer, manufacturer.Description())
+	r.add("Baud rate", "%c, %s baud in mode C", line[4], baudRates[line[4]])
+}
+
+// dataBlock will add the lines of a data block from STX to the BCC.
+func (r *Result) dataBlock(block []byte) {
+	end := bytes.IndexByte(block, iec62056.FrameEnd)
+	if end < 0 {
+		r.Err = ErrTruncated
+		end = len(block)
+	}
+
+	for _, line := range strings.Split(string(block[1:end]), "\n") {
+		line = strings.TrimSpace(line)
+
+		switch line {
+		case "":
+		case "!":
+			r.add("End", "!")
+		default:
+			r.dataLine(line)
+		}
+	}
+
+	if end+1 >= len(block) {
+		r.Err = ErrTruncated
+		return
+	}
+
+	r.checksum("0x%02x", uint64(block[end+1]), uint64(iec62056.BCC(block[:end+1])))
+}
+
+// lineLength will return the length of the first line, line feed included.
+func lineLength(raw []byte) int {
+	end := bytes.IndexByte(raw, iec62056.LineFeed)
+	if end < 0 {
+		return len(raw)
+	}
+
+	return end + 1
+}
+
+// dataBlockLength will return the length of a data block from STX to the BCC
+// following ETX.
+func dataBlockLength(raw []byte) int {
+	end := bytes.IndexByte(raw, iec62056.FrameEnd)
+	if end < 0 || end+2 > len(raw) {
+		return len(raw)
+	}
+
+	return end + 2
+}
@@ -0,0 +1,213 @@
+package decode
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/abrander/gometer/iec62056"
+	"github.com/abrander/gometer/kamstrup"
+)
+
+var (
+	// commandNames is the names of the known KMP commands.
+	commandNames = map[byte]string{
+		kamstrup.GetType:           "GetType",
+		kamstrup.GetSerialNo:       "GetSerialNo",
+		kamstrup.SetClock:          "SetClock",
+		kamstrup.GetRegister:       "GetRegister",
+		kamstrup.PutRegister:       "PutRegister",
+		kamstrup.GetEventStatus:    "GetEventStatus",
+		kamstrup.ClearEventStatus:  "ClearEventStatus",
+		kamstrup.GetLogTimePresent: "GetLogTimePresent",
+		kamstrup.GetLogLastPresen:  "GetLogLastPresent",
+		kamstrup.GetLogIDPresent:   "GetLogIDPresent",
+		kamstrup.GetLogTimePast:    "GetLogTimePast",
+	}
+)
+
+func isKMP(raw []byte) bool {
+	switch raw[0] {
+	case kamstrup.ToMeter, kamstrup.FromMeter:
+		return true
+	case kamstrup.MeterAck:
+		// An IEC 62056-21 acknowledgement is followed by option digits.
+		return len(raw) == 1 || raw[1] < '0' || raw[1] > '9'
+	}
+
+	return false
+}
+
+// decodeKMP will decode a frame ending with the stop byte.
+func decodeKMP(raw []byte) (int, *Result) {
+	if raw[0] == kamstrup.MeterAck {
+		r := newResult(ProtocolKMP, raw[:1])
+		r.add("Start", "0x%02x, acknowledge", raw[0])
+
+		return 1, r
+	}
+
+	n := bytes.IndexByte(raw, kamstrup.Stop) + 1
+	if n == 0 {
+		n = len(raw)
+	}
+
+	r := newResult(ProtocolKMP, raw[:n])
+
+	var f kamstrup.Frame
+	err := f.Decode(raw[:n])
+	if err != nil && err != kamstrup.ErrInvalidChecksum {
+		r.Err = err
+		return n, r
+	}
+
+	toMeter := f.Type == kamstrup.ToMeter
+	if toMeter {
+		r.add("Start", "0x%02x, to meter", f.Type)
+	} else {
+		r.add("Start", "0x%02x, from meter", f.Type)
+	}
+
+	r.add("Address", "0x%02x", f.Address)
+
+	name, found := commandNames[f.CommandID]
+	if !found {
+		name = "unknown"
+	}
+
+	r.add("Command", "0x%02x, %s", f.CommandID, name)
+
+	// The escapes are searched between start and stop byte.
+	var escapes []string
+	for i := 1; i < n-2; i++ {
+		if raw[i] == kamstrup.Escape {
+			escapes = append(escapes, fmt.Sprintf("%02x %02x at %d is %02x", raw[i], raw[i+1], i, raw[i+1]^0xff))
+			i++
+		}
+	}
+
+	if len(escapes) > 0 {
+		r.add("Escapes", "%s", strings.Join(escapes, ", "))
+	}
+
+	r.add("Data", "% x", f.Data)
+
+	// The checksum is the two bytes before the stop byte, after unescaping.
+	var sum [2]byte
+	for i, j := n-2, 1; i > 0 && j >= 0; i, j = i-1, j-1 {
+		sum[j] = raw[i]
+		if raw[i-1] == kamstrup.Escape {
+			sum[j] ^= 0xff
+			i--
+		}
+	}
+
+	r.checksum("0x%04x", uint64(binary.BigEndian.Uint16(sum[:])), uint64(f.Checksum()))
+
+	err = r.kmpData(f, toMeter)
+	if err != nil {
+		r.Err = err
+	}
+
+	return n, r
+}
+
+// kmpData will decode the data of the known commands.
+func (r *Result) kmpData(f kamstrup.Frame, toMeter bool) error {
+	data := f.Data
+
+	switch {
+	case f.CommandID == kamstrup.GetRegister && toMeter:
+		if len(data) < 1 || len(data) < 1+2*int(data[0]) {
+			return kamstrup.ErrFrameTooShort
+		}
+
+		for i := 0; i < int(data[0]); i++ {
+			register := binary.BigEndian.Uint16(data[1+2*i:])
+			r.add(fmt.Sprintf("Register 0x%04x", register), "%s", registerDescription(register))
+		}
+
+	case f.CommandID == kamstrup.GetRegister, f.CommandID == kamstrup.PutRegister:
+		for len(data) > 0 {
+			// A PutRegister reply holds only the register.
+			if f.CommandID == kamstrup.PutRegister && len(data) == 2 {
+				register := binary.BigEndian.Uint16(data)
+				r.add(fmt.Sprintf("Register 0x%04x", register), "%s", registerDescription(register))
+				break
+			}
+
+			if len(data) < 3 {
+				return kamstrup.ErrFrameTooShort
+			}
+
+			register := binary.BigEndian.Uint16(data)
+
+			n, v, err := kamstrup.NewValue(data[2:])
+			if err != nil {
+				return err
+			}
+
+			r.add(fmt.Sprintf("Register 0x%04x", register), "%s, %s", formatValue(v), registerDescription(register))
+
+			if obis, found := registerObis(register); found {
+				r.Values[obis] = v
+			}
+
+			data = data[2+n:]
+		}
+
+	case f.CommandID == kamstrup.SetClock && toMeter:
+		for _, name := range []string{"Date", "Time"} {
+			n, v, err := kamstrup.NewValue(data)
+			if err != nil {
+				return err
+			}
+
+			r.add(name, "%s", formatValue(v))
+			data = data[n:]
+		}
+
+	case f.CommandID == kamstrup.GetSerialNo && !toMeter:
+		if len(data) < 4 {
+			return kamstrup.ErrFrameTooShort
+		}
+
+		r.add("Serial number", "%d", binary.BigEndian.Uint32(data))
+
+	case f.CommandID == kamstrup.GetType && !toMeter:
+		r.add("Type", "%x", data)
+	}
+
+	return nil
+}
+
+// registerObis will look up the OBIS code of a register in the maps of the
+// known models.
+func registerObis(register uint16) (iec62056.Obis, bool) {
+	models := make([]string, 0, len(iec62056.KamstrupModels))
+	for model := range iec62056.KamstrupModels {
+		models = append(models, model)
+	}
+
+	sort.Strings(models)
+
+	for _, model := range models {
+		if obis, found := iec62056.KamstrupModels[model][register]; found {
+			return obis, true
+		}
+	}
+
+	return iec62056.Obis{}, false
+}
+
+// registerDescription will describe a register by its OBIS code.
+func registerDescription(register uint16) string {
+	obis, found := registerObis(register)
+	if !found {
+		return "no OBIS code known"
+	}
+
+	return obis.String() + " " + obis.Description()
+}
@@ -0,0 +1,151 @@
+package decode
+
+import (
+	"fmt"
+
+	"github.com/abrander/gometer/iec62056"
+	"github.com/abrander/gometer/mbus"
+)
+
+var (
+	// functionNames is the names of the control field functions.
+	functionNames = map[byte]string{
+		mbus.SndNke: "SND_NKE",
+		mbus.SndUd:  "SND_UD",
+		mbus.ReqUd2: "REQ_UD2",
+		mbus.ReqUd1: "REQ_UD1",
+		mbus.RspUd:  "RSP_UD",
+	}
+)
+
+func isMBus(raw []byte) bool {
+	switch mbus.FrameType(raw[0]) {
+	case mbus.FrameAck, mbus.FrameShort:
+		return true
+	case mbus.FrameLong:
+		return len(raw) >= 4 && raw[1] == raw[2] && raw[3] == byte(mbus.FrameLong)
+	}
+
+	return false
+}
+
+// decodeMBus will decode a single character, short or long frame.
+func decodeMBus(raw []byte) (int, *Result) {
+	n := 1
+
+	switch mbus.FrameType(raw[0]) {
+	case mbus.FrameShort:
+		n = 5
+	case mbus.FrameLong:
+		n = int(raw[1]) + 6
+	}
+
+	if n > len(raw) {
+		r := newResult(ProtocolMBus, raw)
+		r.Err = ErrTruncated
+
+		return len(raw), r
+	}
+
+	r := newResult(ProtocolMBus, raw[:n])
+
+	// The checksum is the byte before the stop byte.
+	var sum byte
+	if n > 1 {
+		start := 1
+		if n > 5 {
+			start = 4
+		}
+
+		for _, b := range raw[start : n-2] {
+			sum += b
+		}
+	}
+
+	f, err := mbus.DecodeFrame(raw[:n])
+	if err == mbus.ErrInvalidChecksum {
+		// Decode the frame anyway, with the checksum corrected.
+		fixed := append([]byte{}, raw[:n]...)
+		fixed[n-2] = sum
+		f, err = mbus.DecodeFrame(fixed)
+	}
+
+	if err != nil {
+		r.Err = err
+		return n, r
+	}
+
+	switch f.Type {
+	case mbus.FrameAck:
+		r.add("Frame", "single character, acknowledge")
+		return n, r
+	case mbus.FrameShort:
+		r.add("Frame", "short")
+	case mbus.FrameLong:
+		r.add("Frame", "long, L=%d", raw[1])
+	}
+
+	name, found := functionNames[f.Function()]
+	if !found {
+		name = "unknown"
+	}
+
+	r.add("Control", "0x%02x, %s", f.Control, name)
+	r.add("Address", "%d", f.Address)
+
+	if f.Type == mbus.FrameLong {
+		r.add("CI", "0x%02x", f.CI)
+		r.add("Data", "% x", f.Data)
+	}
+
+	r.checksum("0x%02x", uint64(raw[n-2]), uint64(sum))
+
+	if f.Type == mbus.FrameLong {
+		r.Err = r.mbusData(f)
+	}
+
+	return n, r
+}
+
+// mbusData will decode the header and records of a variable data response.
+func (r *Result) mbusData(f *mbus.Frame) error {
+	if f.CI != mbus.CIResponseLong && f.CI != mbus.CIResponseShort && f.CI != mbus.CIResponseNone {
+		return nil
+	}
+
+	h, data, err := f.Decode()
+	if data == nil {
+		return err
+	}
+
+	if f.CI == mbus.CIResponseLong {
+		r.add("Secondary address", "%s", h.Address)
+		manufacturer, err := iec62056.ManufacturerFromID(h.Address.Manufacturer)
+		if err != nil {
+			r.add("Manufacturer", "invalid (0x%04x)", h.Address.Manufacturer)
+		} else {
+			r.add("Manufacturer", "%s, %s", manufacturer, manufacturer.Description())
+		}
+		r.add("Medium", "%s", h.Address.Medium)
+	}
+
+	if f.CI != mbus.CIResponseNone {
+		r.add("Access number", "%d", h.AccessNumber)
+		r.add("Status", "0x%02x", h.Status)
+		r.add("Signature", "0x%04x", h.Signature)
+	}
+
+	for i, record := range data.Records {
+		r.add(fmt.Sprintf("Record %d", i+1), "%s", record)
+	}
+
+	if len(data.ManufacturerData) > 0 {
+		r.add("Manufacturer data", "% x", data.ManufacturerData)
+	}
+
+	if data.MoreRecords {
+		r.add("More records", "yes")
+	}
+
+	return err
+}
@@ -0,0 +1,238 @@
+package dlms
+
+import (
+	"errors"
+	"fmt"
+)
+
+type (
+	// AssociationError will be returned if the meter rejects an association.
+	AssociationError struct {
+		Result     byte
+		Diagnostic byte
+	}
+)
+
+// Conformance bits as proposed in the AARQ.
+const (
+	ConformanceBlockTransferWithGet    = uint32(0x001000)
+	ConformanceBlockTransferWithSet    = uint32(0x000800)
+	ConformanceBlockTransferWithAction = uint32(0x000400)
+	ConformanceMultipleReferences      = uint32(0x000200)
+	ConformanceGet                     = uint32(0x000010)
+	ConformanceSet                     = uint32(0x000008)
+	ConformanceSelectiveAccess         = uint32(0x000004)
+	ConformanceAction                  = uint32(0x000001)
+
+	// DefaultConformance is what most clients propose.
+	DefaultConformance = uint32(0x007e1f)
+)
+
+// APDU tags of the association control service elements.
+const (
+	tagAARQ = byte(0x60)
+	tagAARE = byte(0x61)
+	tagRLRQ = byte(0x62)
+	tagRLRE = byte(0x63)
+)
+
+// Diagnostic values from the AARE.
+const (
+	DiagnosticNoReason               = byte(1)
+	DiagnosticContextNotSupported    = byte(2)
+	DiagnosticMechanismNotRecognised = byte(11)
+	DiagnosticMechanismRequired      = byte(12)
+	DiagnosticAuthenticationFailure  = byte(13)
+	DiagnosticAuthenticationRequired = byte(14)
+)
+
+// xDLMS initiate tags and identifiers used in the AARQ.
+const (
+	initiateRequest       = byte(0x01)
+	initiateResponse      = byte(0x08)
+	confirmedServiceError = byte(0x0e)
+	dlmsVersion           = byte(6)
+
+	// The conformance block is tagged [APPLICATION 31].
+	conformanceTag1 = byte(0x5f)
+	conformanceTag2 = byte(0x1f)
+
+	// Last arc of the object identifiers of the application context
+	// (logical name referencing, no ciphering) and the authentication
+	// mechanism (low level security).
+	contextLogicalNameNoCiphering = byte(1)
+	mechanismLowLevelSecurity     = byte(1)
+)
+
+var (
+	// ErrMalformedAPDU will be returned if a response from the meter cannot
+	// be parsed.
+	ErrMalformedAPDU = errors.New("malformed APDU")
+
+	// ErrNotAssociated will be returned if a request is made before
+	// Associate.
+	ErrNotAssociated = errors.New("not associated")
+)
+
+// Error implements error.
+func (e *AssociationError) Error() string {
+	switch e.Diagnostic {
+	case DiagnosticAuthenticationFailure:
+		return "association rejected: authentication failure"
+	case DiagnosticAuthenticationRequired, DiagnosticMechanismRequired:
+		return "association rejected: authentication required"
+	case DiagnosticContextNotSupported:
+		return "association rejected: application context not supported"
+	}
+
+	return fmt.Sprintf("association rejected (result %d, diagnostic %d)", e.Result, e.Diagnostic)
+}
+
+// encodeAARQ will encode an association request using logical name
+// referencing without ciphering. If password is not empty, low level
+// security is used.
+func encodeAARQ(password []byte, conformance uint32, maxPDUSize uint16) []byte {
+	// Application context name.
+	body := []byte{0xa1, 0x09, 0x06, 0x07, 0x60, 0x85, 0x74, 0x05, 0x08, 0x01, contextLogicalNameNoCiphering}
+
+	if len(password) > 0 {
+		// ACSE requirements: authentication.
+		body = append(body, 0x8a, 0x02, 0x07, 0x80)
+
+		// Mechanism name.
+		body = append(body, 0x8b, 0x07, 0x60, 0x85, 0x74, 0x05, 0x08, 0x02, mechanismLowLevelSecurity)
+
+		// Calling authentication value.
+		auth := appendLength([]byte{0x80}, len(password))
+		auth = append(auth, password...)
+		body = appendLength(append(body, 0xac), len(auth))
+		body = append(body, auth...)
+	}
+
+	initiate := []byte{
+		initiateRequest,
+		0x00, // No dedicated key
+		0x00, // Response allowed (default)
+		0x00, // No proposed quality of service
+		dlmsVersion,
+		conformanceTag1, conformanceTag2, 0x04, 0x00,
+		byte(conformance >> 16), byte(conformance >> 8), byte(conformance),
+		byte(maxPDUSize >> 8), byte(maxPDUSize),
+	}
+
+	userInformation := appendLength([]byte{0x04}, len(initiate))
+	userInformation = append(userInformation, initiate...)
+
+	body = appendLength(append(body, 0xbe), len(userInformation))
+	body = append(body, userInformation...)
+
+	aarq := appendLength([]byte{tagAARQ}, len(body))
+
+	return append(aarq, body...)
+}
+
+// parseAARE will parse an association response and return the negotiated
+// conformance and maximum PDU size.
+func parseAARE(apdu []byte) (uint32, uint16, error) {
+	if len(apdu) < 2 || apdu[0] != tagAARE {
+		return 0, 0, ErrMalformedAPDU
+	}
+
+	contents, _, err := berValue(apdu[1:])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	result := byte(0xff)
+	var diagnostic byte
+	var userInformation []byte
+
+	for len(contents) > 0 {
+		tag := contents[0]
+
+		value, n, err := berValue(contents[1:])
+		if err != nil {
+			return 0, 0, err
+		}
+		contents = contents[1+n:]
+
+		switch tag {
+		case 0xa2:
+			// Association result: INTEGER.
+			if len(value) != 3 {
+				return 0, 0, ErrMalformedAPDU
+			}
+
+			result = value[2]
+		case 0xa3:
+			// Result source diagnostic: CHOICE of INTEGER.
+			if len(value) != 5 {
+				return 0, 0, ErrMalformedAPDU
+			}
+
+			diagnostic = value[4]
+		case 0xbe:
+			// User information: OCTET STRING.
+			if len(value) < 2 || value[0] != 0x04 {
+				return 0, 0, ErrMalformedAPDU
+			}
+
+			userInformation, _, err = berValue(value[1:])
+			if err != nil {
+				return 0, 0, err
+			}
+		}
+	}
+
+	if result != 0 {
+		return 0, 0, &AssociationError{Result: result, Diagnostic: diagnostic}
+	}
+
+	return parseInitiateResponse(userInformation)
+}
+
+// parseInitiateResponse will parse the xDLMS InitiateResponse.
+func parseInitiateResponse(raw []byte) (uint32, uint16, error) {
+	if len(raw) > 0 && raw[0] == confirmedServiceError {
+		return 0, 0, &AssociationError{Result: 1, Diagnostic: DiagnosticNoReason}
+	}
+
+	if len(raw) < 2 || raw[0] != initiateResponse {
+		return 0, 0, ErrMalformedAPDU
+	}
+
+	pos := 1
+
+	// Negotiated quality of service is optional.
+	if raw[pos] != 0x00 {
+		pos++
+	}
+	pos++
+
+	// Version, conformance tag, length, unused bits, 3 bytes conformance and
+	// 2 bytes max PDU size.
+	if len(raw) < pos+10 || raw[pos+1] != conformanceTag1 || raw[pos+2] != conformanceTag2 {
+		return 0, 0, ErrMalformedAPDU
+	}
+
+	pos += 5
+	conformance := uint32(raw[pos])<<16 | uint32(raw[pos+1])<<8 | uint32(raw[pos+2])
+	maxPDUSize := uint16(raw[pos+3])<<8 | uint16(raw[pos+4])
+
+	return conformance, maxPDUSize, nil
+}
+
+// berValue will decode a BER length followed by the value, returning the
+// value and the number of bytes used.
+func berValue(raw []byte) ([]byte, int, error) {
+	length, n, err := decodeLength(raw)
+	if err != nil {
+		return nil, 0, ErrMalformedAPDU
+	}
+
+	if len(raw) < n+length {
+		return nil, 0, ErrMalformedAPDU
+	}
+
+	return raw[n : n+length], n + length, nil
+}
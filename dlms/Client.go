@@ -0,0 +1,564 @@
+package dlms
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/abrander/gometer/hdlc"
+	"github.com/abrander/gometer/iec62056"
+)
+
+type (
+	// Transport carries APDUs to and from a meter.
+	Transport interface {
+		Transact(request []byte) ([]byte, error)
+		Close() error
+	}
+
+	// Client is a DLMS/COSEM client using logical name referencing.
+	Client struct {
+		t Transport
+
+		// Password is used for low level security. If empty, the association
+		// is made without authentication.
+		Password []byte
+
+		// Conformance and MaxPDUSize are proposed in the AARQ and replaced by
+		// the values negotiated with the meter.
+		Conformance uint32
+		MaxPDUSize  uint16
+
+		invokeID   byte
+		associated bool
+	}
+
+	// AccessError is a data-access-result or action-result other than
+	// success returned by the meter.
+	AccessError byte
+
+	// hdlcTransport adds and removes LLC headers.
+	hdlcTransport struct {
+		conn *hdlc.Conn
+	}
+)
+
+// xDLMS APDU tags.
+const (
+	tagGetRequest        = byte(0xc0)
+	tagSetRequest        = byte(0xc1)
+	tagActionRequest     = byte(0xc3)
+	tagGetResponse       = byte(0xc4)
+	tagSetResponse       = byte(0xc5)
+	tagActionResponse    = byte(0xc7)
+	tagExceptionResponse = byte(0xd8)
+
+	// Request and response types.
+	typeNormal        = byte(0x01)
+	typeNextDataBlock = byte(0x02)
+	typeWithDataBlock = byte(0x02)
+)
+
+// Known access results.
+const (
+	AccessHardwareFault          = AccessError(1)
+	AccessTemporaryFailure       = AccessError(2)
+	AccessReadWriteDenied        = AccessError(3)
+	AccessObjectUndefined        = AccessError(4)
+	AccessObjectClassMismatch    = AccessError(9)
+	AccessObjectUnavailable      = AccessError(11)
+	AccessTypeUnmatched          = AccessError(12)
+	AccessScopeViolated          = AccessError(13)
+	AccessDataBlockUnavailable   = AccessError(14)
+	AccessLongGetAborted         = AccessError(15)
+	AccessNoLongGetInProgress    = AccessError(16)
+	AccessDataBlockNumberInvalid = AccessError(19)
+	AccessOtherReason            = AccessError(250)
+)
+
+// SelectorRange is the access selector for range_descriptor on Profile
+// Generic buffers.
+const SelectorRange = byte(1)
+
+var (
+	// ErrServiceError will be returned if the meter answers with an
+	// exception or confirmed service error.
+	ErrServiceError = errors.New("service error from meter")
+
+	// ErrUnexpectedResponse will be returned if the response does not match
+	// the request.
+	ErrUnexpectedResponse = errors.New("unexpected response")
+
+	accessErrors = map[AccessError]string{
+		AccessHardwareFault:          "hardware fault",
+		AccessTemporaryFailure:       "temporary failure",
+		AccessReadWriteDenied:        "read-write denied",
+		AccessObjectUndefined:        "object undefined",
+		AccessObjectClassMismatch:    "object class inconsistent",
+		AccessObjectUnavailable:      "object unavailable",
+		AccessTypeUnmatched:          "type unmatched",
+		AccessScopeViolated:          "scope of access violated",
+		AccessDataBlockUnavailable:   "data block unavailable",
+		AccessLongGetAborted:         "long get aborted",
+		AccessNoLongGetInProgress:    "no long get in progress",
+		AccessDataBlockNumberInvalid: "data block number invalid",
+		AccessOtherReason:            "other reason",
+	}
+)
+
+// Error implements error.
+func (e AccessError) Error() string {
+	s, found := accessErrors[e]
+	if !found {
+		return fmt.Sprintf("access error %d", byte(e))
+	}
+
+	return s
+}
+
+// NewClient will instantiate a new client on a user provided transport.
+func NewClient(t Transport) *Client {
+	return &Client{
+		t:           t,
+		Conformance: DefaultConformance,
+		MaxPDUSize:  0xffff,
+	}
+}
+
+// NewClientHDLC will instantiate a new client on a HDLC connection. The
+// connection must be connected.
+func NewClientHDLC(conn *hdlc.Conn) *Client {
+	return NewClient(&hdlcTransport{conn: conn})
+}
+
+// DialSerial will connect to a meter on a serial device using HDLC and
+// associate. The public client (16) is usually allowed without password,
+// the management client (1) usually requires one.
+func DialSerial(device string, client hdlc.Address, server hdlc.Address, password []byte) (*Client, error) {
+	conn, err := hdlc.NewConnSerial(device, client, server)
+	if err != nil {
+		return nil, err
+	}
+
+	err = conn.Connect()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	c := NewClientHDLC(conn)
+	c.Password = password
+
+	err = c.Associate()
+	if err != nil {
+		c.t.Close()
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Transact implements Transport.
+func (t *hdlcTransport) Transact(request []byte) ([]byte, error) {
+	response, err := t.conn.Transact(append(append([]byte{}, hdlc.LLCRequest...), request...))
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.HasPrefix(response, hdlc.LLCResponse) {
+		return nil, ErrMalformedAPDU
+	}
+
+	return response[len(hdlc.LLCResponse):], nil
+}
+
+// Close implements Transport.
+func (t *hdlcTransport) Close() error {
+	return t.conn.Close()
+}
+
+// Associate will send AARQ and wait for AARE.
+func (c *Client) Associate() error {
+	response, err := c.t.Transact(encodeAARQ(c.Password, c.Conformance, c.MaxPDUSize))
+	if err != nil {
+		return err
+	}
+
+	conformance, maxPDUSize, err := parseAARE(response)
+	if err != nil {
+		return err
+	}
+
+	c.Conformance = conformance
+	c.MaxPDUSize = maxPDUSize
+	c.associated = true
+
+	return nil
+}
+
+// Release will release the association.
+func (c *Client) Release() error {
+	c.associated = false
+
+	response, err := c.t.Transact([]byte{tagRLRQ, 0x03, 0x80, 0x01, 0x00})
+	if err != nil {
+		return err
+	}
+
+	if len(response) < 1 || response[0] != tagRLRE {
+		return ErrUnexpectedResponse
+	}
+
+	return nil
+}
+
+// Close will release the association if associated and close the transport.
+// Errors from the release are ignored, many meters just disconnect.
+func (c *Client) Close() error {
+	if c.associated {
+		c.Release()
+	}
+
+	return c.t.Close()
+}
+
+// Get will read a single attribute.
+func (c *Client) Get(class ClassID, name iec62056.Obis, attribute int8) (Data, error) {
+	return c.GetWithSelector(class, name, attribute, 0, Data{})
+}
+
+// GetWithSelector will read a single attribute using selective access. A
+// selector of 0 means no selective access. Responses sent in several blocks
+// are joined.
+func (c *Client) GetWithSelector(class ClassID, name iec62056.Obis, attribute int8, selector byte, params Data) (Data, error) {
+	request, err := c.request(tagGetRequest, typeNormal, class, name, attribute)
+	if err != nil {
+		return Data{}, err
+	}
+
+	if selector == 0 {
+		request = append(request, 0x00)
+	} else {
+		request = append(request, 0x01, selector)
+		request, err = params.appendEncoded(request)
+		if err != nil {
+			return Data{}, err
+		}
+	}
+
+	response, err := c.transact(request, tagGetResponse)
+	if err != nil {
+		return Data{}, err
+	}
+
+	var raw []byte
+	expected := uint32(1)
+	for {
+		if len(response) < 3 {
+			return Data{}, ErrMalformedAPDU
+		}
+
+		switch response[1] {
+		case typeNormal:
+			if len(raw) > 0 {
+				return Data{}, ErrUnexpectedResponse
+			}
+
+			return decodeGetResult(response[3:])
+
+		case typeWithDataBlock:
+			// Last block (boolean), block number (4 bytes) and result.
+			if len(response) < 9 {
+				return Data{}, ErrMalformedAPDU
+			}
+
+			last := response[3] != 0
+			block := binary.BigEndian.Uint32(response[4:])
+
+			// A repeated or skipped block would corrupt the data, and a
+			// meter resending the same block would keep us here forever.
+			if block != expected {
+				return Data{}, ErrUnexpectedResponse
+			}
+			expected++
+
+			if response[8] != 0x00 {
+				if len(response) < 10 {
+					return Data{}, ErrMalformedAPDU
+				}
+
+				return Data{}, AccessError(response[9])
+			}
+
+			data, _, err := berValue(response[9:])
+			if err != nil {
+				return Data{}, err
+			}
+			raw = append(raw, data...)
+
+			if last {
+				d, _, err := DecodeData(raw)
+
+				return d, err
+			}
+
+			next := []byte{tagGetRequest, typeNextDataBlock, c.invokeIDAndPriority(), 0, 0, 0, 0}
+			binary.BigEndian.PutUint32(next[3:], block)
+
+			response, err = c.transact(next, tagGetResponse)
+			if err != nil {
+				return Data{}, err
+			}
+
+		default:
+			return Data{}, ErrUnexpectedResponse
+		}
+	}
+}
+
+// Set will write a single attribute.
+func (c *Client) Set(class ClassID, name iec62056.Obis, attribute int8, value Data) error {
+	request, err := c.request(tagSetRequest, typeNormal, class, name, attribute)
+	if err != nil {
+		return err
+	}
+
+	// No selective access.
+	request = append(request, 0x00)
+	request, err = value.appendEncoded(request)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.transact(request, tagSetResponse)
+	if err != nil {
+		return err
+	}
+
+	if len(response) < 4 || response[1] != typeNormal {
+		return ErrMalformedAPDU
+	}
+
+	if response[3] != 0 {
+		return AccessError(response[3])
+	}
+
+	return nil
+}
+
+// Action will invoke a method. params can be the zero Data if the method
+// takes no parameters. The return parameters are returned if any.
+func (c *Client) Action(class ClassID, name iec62056.Obis, method int8, params Data) (Data, error) {
+	request, err := c.request(tagActionRequest, typeNormal, class, name, method)
+	if err != nil {
+		return Data{}, err
+	}
+
+	if params.Type == TypeNull && params.Value == nil {
+		request = append(request, 0x00)
+	} else {
+		request = append(request, 0x01)
+		request, err = params.appendEncoded(request)
+		if err != nil {
+			return Data{}, err
+		}
+	}
+
+	response, err := c.transact(request, tagActionResponse)
+	if err != nil {
+		return Data{}, err
+	}
+
+	if len(response) < 4 || response[1] != typeNormal {
+		return Data{}, ErrMalformedAPDU
+	}
+
+	if response[3] != 0 {
+		return Data{}, AccessError(response[3])
+	}
+
+	// Return parameters are optional.
+	if len(response) < 6 || response[4] == 0x00 {
+		return Data{}, nil
+	}
+
+	return decodeGetResult(response[5:])
+}
+
+// Read will read the given attributes of an object and set them. If no
+// attributes are given, the usual attributes of the class are read; for
+// Profile Generic everything but the buffer, and nothing for classes not
+// otherwise supported.
+func (c *Client) Read(object Object, attributes ...int8) error {
+	if len(attributes) == 0 {
+		attributes = classAttributes[object.ClassID()]
+	}
+
+	for _, attribute := range attributes {
+		d, err := c.Get(object.ClassID(), object.LogicalName(), attribute)
+		if err != nil {
+			return err
+		}
+
+		err = object.SetAttribute(attribute, d)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReadProfileBuffer will read capture objects, capture period and the part of
+// the buffer between from and to of a Profile Generic. If from and to are
+// zero, the complete buffer is read. If only one of them is zero, that end of
+// the range is left open.
+func (c *Client) ReadProfileBuffer(name iec62056.Obis, from, to time.Time) (*ProfileGeneric, error) {
+	p := NewObject(ClassProfileGeneric, name).(*ProfileGeneric)
+
+	err := c.Read(p, AttributeCaptureObjects, AttributeCapturePeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	if from.IsZero() && to.IsZero() {
+		return p, c.Read(p, AttributeBuffer)
+	}
+
+	clock, _ := iec62056.Clock.Bytes()
+
+	selector := Data{Type: TypeStructure, Value: []Data{
+		{Type: TypeStructure, Value: []Data{
+			{Type: TypeLongUnsigned, Value: uint64(ClassClock)},
+			{Type: TypeOctetString, Value: clock},
+			{Type: TypeInteger, Value: int64(AttributeTime)},
+			{Type: TypeLongUnsigned, Value: uint64(0)},
+		}},
+		{Type: TypeOctetString, Value: rangeBound(from)},
+		{Type: TypeOctetString, Value: rangeBound(to)},
+		{Type: TypeArray, Value: []Data{}},
+	}}
+
+	buffer, err := c.GetWithSelector(ClassProfileGeneric, name, AttributeBuffer, SelectorRange, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	return p, p.SetAttribute(AttributeBuffer, buffer)
+}
+
+// rangeBound will encode t as a date-time for a range descriptor. A zero t
+// is encoded with every field not specified, leaving that end open.
+func rangeBound(t time.Time) []byte {
+	if t.IsZero() {
+		return DateTime{
+			Year:       YearNotSpecified,
+			Month:      NotSpecified,
+			Day:        NotSpecified,
+			Weekday:    NotSpecified,
+			Hour:       NotSpecified,
+			Minute:     NotSpecified,
+			Second:     NotSpecified,
+			Hundredths: NotSpecified,
+			Deviation:  DeviationNotSpecified,
+			Status:     ClockStatusNotDefined,
+		}.Bytes()
+	}
+
+	return NewDateTime(t).Bytes()
+}
+
+// ReadProfile will read a load profile between from and to, and return the
+// records scaled using the scaler_unit of every captured register.
+func (c *Client) ReadProfile(name iec62056.Obis, from, to time.Time) ([]Record, error) {
+	p, err := c.ReadProfileBuffer(name, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make(Objects)
+	for _, co := range p.CaptureObjects {
+		if co.Class != ClassRegister && co.Class != ClassExtendedRegister {
+			continue
+		}
+
+		o := NewObject(co.Class, co.Name)
+		err = c.Read(o, AttributeScalerUnit)
+		if err != nil {
+			return nil, err
+		}
+
+		objects.Add(o)
+	}
+
+	return p.Records(objects), nil
+}
+
+// request will start a request with the attribute or method descriptor.
+func (c *Client) request(tag byte, typ byte, class ClassID, name iec62056.Obis, attribute int8) ([]byte, error) {
+	if !c.associated {
+		return nil, ErrNotAssociated
+	}
+
+	ln, err := name.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	c.invokeID = (c.invokeID + 1) & 0x0f
+
+	request := []byte{tag, typ, c.invokeIDAndPriority(), byte(class >> 8), byte(class)}
+	request = append(request, ln...)
+
+	return append(request, byte(attribute)), nil
+}
+
+// invokeIDAndPriority will return the current invoke id as a confirmed,
+// high priority service.
+func (c *Client) invokeIDAndPriority() byte {
+	return 0xc0 | c.invokeID
+}
+
+// transact will send a request and verify the response tag and invoke id.
+func (c *Client) transact(request []byte, expected byte) ([]byte, error) {
+	response, err := c.t.Transact(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(response) < 1 {
+		return nil, ErrMalformedAPDU
+	}
+
+	switch response[0] {
+	case expected:
+	case tagExceptionResponse, confirmedServiceError:
+		return nil, ErrServiceError
+	default:
+		return nil, ErrUnexpectedResponse
+	}
+
+	if len(response) < 3 || response[2]&0x0f != c.invokeID {
+		return nil, ErrUnexpectedResponse
+	}
+
+	return response, nil
+}
+
+// decodeGetResult will decode a Get-Data-Result: either Data or a
+// data-access-result.
+func decodeGetResult(raw []byte) (Data, error) {
+	if len(raw) < 2 {
+		return Data{}, ErrMalformedAPDU
+	}
+
+	if raw[0] != 0x00 {
+		return Data{}, AccessError(raw[1])
+	}
+
+	d, _, err := DecodeData(raw[1:])
+
+	return d, err
+}
@@ -0,0 +1,420 @@
+package dlms
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abrander/gometer/iec62056"
+	"github.com/abrander/gometer/kamstrup"
+)
+
+// testMeter is a Transport answering requests from a table of attributes.
+type testMeter struct {
+	t *testing.T
+
+	password   []byte
+	attributes map[string]Data
+
+	// blockSize, if not zero, makes GET responses be sent in blocks.
+	blockSize int
+	blocks    [][]byte
+
+	// blockSkew is added to the block sent for a next-data-block request,
+	// -1 repeats the previous block and 1 skips one.
+	blockSkew int
+
+	// selector holds the last selective access of a GET.
+	selector []byte
+
+	released bool
+	closed   bool
+}
+
+var (
+	acceptedAARE = "6129a109060760857405080101a203020100a305a103020100be10040e0800065f1f040000501f01f40007"
+	rejectedAARE = "611fa109060760857405080101a203020101a305a10302010dbe0604040e010602"
+)
+
+func newTestMeter(t *testing.T) *testMeter {
+	return &testMeter{
+		t:          t,
+		attributes: make(map[string]Data),
+	}
+}
+
+func attributeKey(class ClassID, name iec62056.Obis, attribute int8) string {
+	ln, _ := name.Bytes()
+
+	return hex.EncodeToString(append([]byte{byte(class >> 8), byte(class)}, append(ln, byte(attribute))...))
+}
+
+func (m *testMeter) set(class ClassID, name string, attribute int8, d Data) {
+	m.attributes[attributeKey(class, iec62056.NewObis(name), attribute)] = d
+}
+
+func (m *testMeter) Transact(request []byte) ([]byte, error) {
+	switch request[0] {
+	case tagAARQ:
+		if len(m.password) > 0 && !bytes.Contains(request, m.password) {
+			raw, _ := hex.DecodeString(rejectedAARE)
+			return raw, nil
+		}
+
+		raw, _ := hex.DecodeString(acceptedAARE)
+		return raw, nil
+
+	case tagRLRQ:
+		m.released = true
+		return []byte{tagRLRE, 0x03, 0x80, 0x01, 0x00}, nil
+
+	case tagGetRequest:
+		return m.get(request), nil
+
+	case tagSetRequest:
+		key := hex.EncodeToString(request[3:12])
+		if _, found := m.attributes[key]; !found {
+			return []byte{tagSetResponse, typeNormal, request[2], byte(AccessObjectUndefined)}, nil
+		}
+
+		d, _, err := DecodeData(request[13:])
+		if err != nil {
+			m.t.Fatalf("Set request has malformed data: %s", err)
+		}
+
+		m.attributes[key] = d
+
+		return []byte{tagSetResponse, typeNormal, request[2], 0x00}, nil
+
+	case tagActionRequest:
+		// Echo the parameters back as return parameters.
+		if request[12] == 0x00 {
+			return []byte{tagActionResponse, typeNormal, request[2], 0x00, 0x00}, nil
+		}
+
+		return append([]byte{tagActionResponse, typeNormal, request[2], 0x00, 0x01, 0x00}, request[13:]...), nil
+	}
+
+	return []byte{tagExceptionResponse, 0x01, 0x02}, nil
+}
+
+func (m *testMeter) get(request []byte) []byte {
+	invokeID := request[2]
+
+	if request[1] == typeNextDataBlock {
+		block := binary.BigEndian.Uint32(request[3:])
+
+		return m.block(invokeID, int(block)+m.blockSkew)
+	}
+
+	if request[12] != 0x00 {
+		m.selector = append([]byte{}, request[12:]...)
+	}
+
+	d, found := m.attributes[hex.EncodeToString(request[3:12])]
+	if !found {
+		return []byte{tagGetResponse, typeNormal, invokeID, 0x01, byte(AccessObjectUndefined)}
+	}
+
+	raw, err := d.Encode()
+	if err != nil {
+		m.t.Fatalf("Encode() returned %s", err)
+	}
+
+	if m.blockSize == 0 {
+		return append([]byte{tagGetResponse, typeNormal, invokeID, 0x00}, raw...)
+	}
+
+	m.blocks = nil
+	for len(raw) > 0 {
+		n := m.blockSize
+		if n > len(raw) {
+			n = len(raw)
+		}
+
+		m.blocks = append(m.blocks, raw[:n])
+		raw = raw[n:]
+	}
+
+	return m.block(invokeID, 0)
+}
+
+func (m *testMeter) block(invokeID byte, previous int) []byte {
+	last := byte(0)
+	if previous+1 == len(m.blocks) {
+		last = 1
+	}
+
+	response := []byte{tagGetResponse, typeWithDataBlock, invokeID, last, 0, 0, 0, 0, 0x00}
+	binary.BigEndian.PutUint32(response[4:], uint32(previous+1))
+	response = appendLength(response, len(m.blocks[previous]))
+
+	return append(response, m.blocks[previous]...)
+}
+
+func (m *testMeter) Close() error {
+	m.closed = true
+
+	return nil
+}
+
+func associate(t *testing.T, m *testMeter) *Client {
+	c := NewClient(m)
+
+	err := c.Associate()
+	if err != nil {
+		t.Fatalf("Associate() returned %s", err)
+	}
+
+	return c
+}
+
+func TestEncodeAARQ(t *testing.T) {
+	expected := "601da109060760857405080101be10040e01000000065f1f0400007e1f04b0"
+
+	got := hex.EncodeToString(encodeAARQ(nil, DefaultConformance, 1200))
+	if got != expected {
+		t.Errorf("encodeAARQ() returned %s, expected %s", got, expected)
+	}
+
+	expected = "6036a1090607608574050801018a0207808b0760857405080201ac0a80083132333435363738be10040e01000000065f1f0400007e1fffff"
+
+	got = hex.EncodeToString(encodeAARQ([]byte("12345678"), DefaultConformance, 0xffff))
+	if got != expected {
+		t.Errorf("encodeAARQ() with password returned %s, expected %s", got, expected)
+	}
+}
+
+func TestAssociate(t *testing.T) {
+	m := newTestMeter(t)
+	m.password = []byte("secret")
+
+	c := NewClient(m)
+
+	_, err := c.Get(ClassData, iec62056.Clock, AttributeTime)
+	if err != ErrNotAssociated {
+		t.Errorf("Get() before Associate() returned %v", err)
+	}
+
+	c.Password = []byte("wrong")
+	err = c.Associate()
+
+	var ae *AssociationError
+	if !errors.As(err, &ae) || ae.Diagnostic != DiagnosticAuthenticationFailure {
+		t.Fatalf("Associate() with wrong password returned %v", err)
+	}
+
+	c.Password = []byte("secret")
+	err = c.Associate()
+	if err != nil {
+		t.Fatalf("Associate() returned %s", err)
+	}
+
+	if c.Conformance != 0x00501f || c.MaxPDUSize != 500 {
+		t.Errorf("Negotiated conformance %06x and max PDU size %d", c.Conformance, c.MaxPDUSize)
+	}
+
+	err = c.Close()
+	if err != nil {
+		t.Errorf("Close() returned %s", err)
+	}
+
+	if !m.released || !m.closed {
+		t.Errorf("Close() did not release and close")
+	}
+}
+
+func TestGet(t *testing.T) {
+	m := newTestMeter(t)
+	m.set(ClassRegister, "1-0:1.8.0.255", AttributeValue, Data{Type: TypeDoubleLongUnsigned, Value: uint64(1234)})
+
+	c := associate(t, m)
+
+	d, err := c.Get(ClassRegister, iec62056.ActiveEnergyImport, AttributeValue)
+	if err != nil {
+		t.Fatalf("Get() returned %s", err)
+	}
+
+	if v, _ := d.Int(); v != 1234 {
+		t.Errorf("Get() returned %s", d)
+	}
+
+	_, err = c.Get(ClassRegister, iec62056.ActiveEnergyExport, AttributeValue)
+	if err != AccessObjectUndefined {
+		t.Errorf("Get() of undefined object returned %v", err)
+	}
+}
+
+func TestGetBlockTransfer(t *testing.T) {
+	var rows []Data
+	for i := 0; i < 50; i++ {
+		rows = append(rows, structure(Data{Type: TypeDoubleLongUnsigned, Value: uint64(i)}))
+	}
+
+	m := newTestMeter(t)
+	m.blockSize = 64
+	m.set(ClassProfileGeneric, "1-0:99.1.0.255", AttributeBuffer, array(rows...))
+
+	c := associate(t, m)
+
+	d, err := c.Get(ClassProfileGeneric, iec62056.NewObis("1-0:99.1.0.255"), AttributeBuffer)
+	if err != nil {
+		t.Fatalf("Get() returned %s", err)
+	}
+
+	if len(m.blocks) < 3 {
+		t.Fatalf("Response sent in %d blocks, expected more", len(m.blocks))
+	}
+
+	elements := d.Elements()
+	if len(elements) != 50 {
+		t.Fatalf("Got %d rows, expected 50", len(elements))
+	}
+
+	if v, _ := elements[49].Elements()[0].Int(); v != 49 {
+		t.Errorf("Last row is %s", elements[49])
+	}
+}
+
+func TestGetBlockTransferSequence(t *testing.T) {
+	var rows []Data
+	for i := 0; i < 50; i++ {
+		rows = append(rows, structure(Data{Type: TypeDoubleLongUnsigned, Value: uint64(i)}))
+	}
+
+	for _, skew := range []int{-1, 1} {
+		m := newTestMeter(t)
+		m.blockSize = 64
+		m.blockSkew = skew
+		m.set(ClassProfileGeneric, "1-0:99.1.0.255", AttributeBuffer, array(rows...))
+
+		c := associate(t, m)
+
+		_, err := c.Get(ClassProfileGeneric, iec62056.NewObis("1-0:99.1.0.255"), AttributeBuffer)
+		if err != ErrUnexpectedResponse {
+			t.Errorf("Get() with block skew %d returned %v", skew, err)
+		}
+	}
+}
+
+func TestSetAction(t *testing.T) {
+	m := newTestMeter(t)
+	m.set(ClassData, "0-0:96.1.0.255", AttributeValue, Data{Type: TypeVisibleString, Value: "old"})
+
+	c := associate(t, m)
+
+	err := c.Set(ClassData, iec62056.NewObis("0-0:96.1.0.255"), AttributeValue, Data{Type: TypeVisibleString, Value: "new"})
+	if err != nil {
+		t.Fatalf("Set() returned %s", err)
+	}
+
+	d, _ := c.Get(ClassData, iec62056.NewObis("0-0:96.1.0.255"), AttributeValue)
+	if d.Value != "new" {
+		t.Errorf("Get() after Set() returned %s", d)
+	}
+
+	err = c.Set(ClassData, iec62056.NewObis("0-0:96.1.1.255"), AttributeValue, Data{Type: TypeNull})
+	if err != AccessObjectUndefined {
+		t.Errorf("Set() of undefined object returned %v", err)
+	}
+
+	d, err = c.Action(ClassRegister, iec62056.ActiveEnergyImport, 1, Data{Type: TypeInteger, Value: int64(0)})
+	if err != nil {
+		t.Fatalf("Action() returned %s", err)
+	}
+
+	if v, _ := d.Int(); d.Type != TypeInteger || v != 0 {
+		t.Errorf("Action() returned %s", d)
+	}
+
+	d, err = c.Action(ClassRegister, iec62056.ActiveEnergyImport, 1, Data{})
+	if err != nil || d.Value != nil {
+		t.Errorf("Action() without parameters returned %s, %v", d, err)
+	}
+}
+
+func TestReadProfile(t *testing.T) {
+	location := time.FixedZone("CET", 3600)
+	from := time.Date(2021, 1, 12, 0, 0, 0, 0, location)
+	to := from.Add(time.Hour)
+
+	clock, _ := iec62056.Clock.Bytes()
+
+	m := newTestMeter(t)
+	m.set(ClassProfileGeneric, "1-0:99.1.0.255", AttributeCaptureObjects, array(
+		structure(
+			Data{Type: TypeLongUnsigned, Value: uint64(ClassClock)},
+			Data{Type: TypeOctetString, Value: clock},
+			Data{Type: TypeInteger, Value: int64(AttributeTime)},
+			Data{Type: TypeLongUnsigned, Value: uint64(0)},
+		),
+		structure(
+			Data{Type: TypeLongUnsigned, Value: uint64(ClassRegister)},
+			logicalName("1-0:1.8.0.255"),
+			Data{Type: TypeInteger, Value: int64(AttributeValue)},
+			Data{Type: TypeLongUnsigned, Value: uint64(0)},
+		),
+	))
+	m.set(ClassProfileGeneric, "1-0:99.1.0.255", AttributeCapturePeriod, Data{Type: TypeDoubleLongUnsigned, Value: uint64(900)})
+	m.set(ClassProfileGeneric, "1-0:99.1.0.255", AttributeBuffer, array(
+		structure(Data{Type: TypeOctetString, Value: NewDateTime(from).Bytes()}, Data{Type: TypeDoubleLongUnsigned, Value: uint64(100)}),
+		structure(Data{Type: TypeNull}, Data{Type: TypeDoubleLongUnsigned, Value: uint64(101)}),
+	))
+	m.set(ClassRegister, "1-0:1.8.0.255", AttributeScalerUnit, structure(
+		Data{Type: TypeInteger, Value: int64(1)},
+		Data{Type: TypeEnum, Value: uint64(UnitWattHour)},
+	))
+
+	c := associate(t, m)
+
+	records, err := c.ReadProfile(iec62056.NewObis("1-0:99.1.0.255"), from, to)
+	if err != nil {
+		t.Fatalf("ReadProfile() returned %s", err)
+	}
+
+	if len(m.selector) < 2 || m.selector[0] != 0x01 || m.selector[1] != SelectorRange {
+		t.Fatalf("Buffer read with access selection %x", m.selector)
+	}
+
+	selector, _, err := DecodeData(m.selector[2:])
+	if err != nil {
+		t.Fatalf("Malformed range descriptor: %s", err)
+	}
+
+	start, _ := selector.Elements()[1].DateTime()
+	if tm, _ := start.Time(); !tm.Equal(from) {
+		t.Errorf("Range starts at %s, expected %s", tm, from)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("Got %d records, expected 2", len(records))
+	}
+
+	if !records[1].Time.Equal(from.Add(15 * time.Minute)) {
+		t.Errorf("Second record at %s", records[1].Time)
+	}
+
+	v := records[1].Values[iec62056.ActiveEnergyImport]
+	if v.Value != 1010 || v.Unit != kamstrup.UnitWh {
+		t.Errorf("Second record value is %s", v)
+	}
+
+	// A zero end leaves the range open.
+	_, err = c.ReadProfileBuffer(iec62056.NewObis("1-0:99.1.0.255"), from, time.Time{})
+	if err != nil {
+		t.Fatalf("ReadProfileBuffer() returned %s", err)
+	}
+
+	selector, _, err = DecodeData(m.selector[2:])
+	if err != nil {
+		t.Fatalf("Malformed range descriptor: %s", err)
+	}
+
+	end, _ := selector.Elements()[2].Bytes()
+	if hex.EncodeToString(end) != "ffffffffffffffffff8000ff" {
+		t.Errorf("Open range ends at %x, expected every field not specified", end)
+	}
+}
@@ -0,0 +1,698 @@
+package dlms
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+type (
+	// DataType is the tag of an A-XDR encoded Data element.
+	DataType byte
+
+	// Data is a decoded A-XDR Data element. Value holds one of the following
+	// depending on Type:
+	//
+	//   nil                 null-data, dont-care
+	//   []Data              array, structure, compact-array
+	//   bool                boolean
+	//   BitString           bit-string
+	//   int64               integer, long, double-long, long64, bcd
+	//   uint64              unsigned, long-unsigned, double-long-unsigned,
+	//                       long64-unsigned, enum
+	//   float64             float32, float64, floating-point
+	//   []byte              octet-string
+	//   string              visible-string, utf8-string
+	//   DateTime            date-time, date, time
+	Data struct {
+		Type  DataType
+		Value interface{}
+	}
+
+	// BitString is a string of bits. Bits are numbered from the most
+	// significant bit of the first byte.
+	BitString struct {
+		Length int
+		Bytes  []byte
+	}
+)
+
+// Data types as defined by the DLMS Blue Book.
+const (
+	TypeNull               = DataType(0)
+	TypeArray              = DataType(1)
+	TypeStructure          = DataType(2)
+	TypeBoolean            = DataType(3)
+	TypeBitString          = DataType(4)
+	TypeDoubleLong         = DataType(5)
+	TypeDoubleLongUnsigned = DataType(6)
+	TypeFloatingPoint      = DataType(7)
+	TypeOctetString        = DataType(9)
+	TypeVisibleString      = DataType(10)
+	TypeUTF8String         = DataType(12)
+	TypeBCD                = DataType(13)
+	TypeInteger            = DataType(15)
+	TypeLong               = DataType(16)
+	TypeUnsigned           = DataType(17)
+	TypeLongUnsigned       = DataType(18)
+	TypeCompactArray       = DataType(19)
+	TypeLong64             = DataType(20)
+	TypeLong64Unsigned     = DataType(21)
+	TypeEnum               = DataType(22)
+	TypeFloat32            = DataType(23)
+	TypeFloat64            = DataType(24)
+	TypeDateTime           = DataType(25)
+	TypeDate               = DataType(26)
+	TypeTime               = DataType(27)
+	TypeDontCare           = DataType(255)
+)
+
+var (
+	// ErrMalformedData will be returned if A-XDR data is truncated or has
+	// invalid lengths.
+	ErrMalformedData = errors.New("malformed A-XDR data")
+
+	// ErrUnknownDataType will be returned when decoding an unknown tag.
+	ErrUnknownDataType = errors.New("unknown A-XDR data type")
+
+	typeNames = map[DataType]string{
+		TypeNull:               "null-data",
+		TypeArray:              "array",
+		TypeStructure:          "structure",
+		TypeBoolean:            "boolean",
+		TypeBitString:          "bit-string",
+		TypeDoubleLong:         "double-long",
+		TypeDoubleLongUnsigned: "double-long-unsigned",
+		TypeFloatingPoint:      "floating-point",
+		TypeOctetString:        "octet-string",
+		TypeVisibleString:      "visible-string",
+		TypeUTF8String:         "utf8-string",
+		TypeBCD:                "bcd",
+		TypeInteger:            "integer",
+		TypeLong:               "long",
+		TypeUnsigned:           "unsigned",
+		TypeLongUnsigned:       "long-unsigned",
+		TypeCompactArray:       "compact-array",
+		TypeLong64:             "long64",
+		TypeLong64Unsigned:     "long64-unsigned",
+		TypeEnum:               "enum",
+		TypeFloat32:            "float32",
+		TypeFloat64:            "float64",
+		TypeDateTime:           "date-time",
+		TypeDate:               "date",
+		TypeTime:               "time",
+		TypeDontCare:           "dont-care",
+	}
+
+	// fixedSizes is the encoded size of fixed length types.
+	fixedSizes = map[DataType]int{
+		TypeNull:               0,
+		TypeBoolean:            1,
+		TypeDoubleLong:         4,
+		TypeDoubleLongUnsigned: 4,
+		TypeFloatingPoint:      4,
+		TypeBCD:                1,
+		TypeInteger:            1,
+		TypeLong:               2,
+		TypeUnsigned:           1,
+		TypeLongUnsigned:       2,
+		TypeLong64:             8,
+		TypeLong64Unsigned:     8,
+		TypeEnum:               1,
+		TypeFloat32:            4,
+		TypeFloat64:            8,
+		TypeDateTime:           12,
+		TypeDate:               5,
+		TypeTime:               4,
+		TypeDontCare:           0,
+	}
+)
+
+// String will return the name of the type as used in the Blue Book.
+func (t DataType) String() string {
+	name, found := typeNames[t]
+	if !found {
+		return fmt.Sprintf("type-%d", byte(t))
+	}
+
+	return name
+}
+
+// DecodeData will decode one Data element from the start of raw, returning
+// the element and the number of bytes used.
+func DecodeData(raw []byte) (Data, int, error) {
+	if len(raw) < 1 {
+		return Data{}, 0, ErrMalformedData
+	}
+
+	t := DataType(raw[0])
+	if t == TypeCompactArray {
+		return decodeCompactArray(raw)
+	}
+
+	d, n, err := decodeValue(t, raw[1:])
+	if err != nil {
+		return Data{}, 0, err
+	}
+
+	return d, n + 1, nil
+}
+
+// decodeValue will decode the contents of an element of type t.
+func decodeValue(t DataType, raw []byte) (Data, int, error) {
+	d := Data{Type: t}
+
+	if size, found := fixedSizes[t]; found {
+		if len(raw) < size {
+			return Data{}, 0, ErrMalformedData
+		}
+
+		if t == TypeBCD {
+			v, ok := decodeBCD(raw[0])
+			if !ok {
+				return Data{}, 0, ErrMalformedData
+			}
+
+			d.Value = v
+
+			return d, size, nil
+		}
+
+		d.Value = decodeFixed(t, raw[:size])
+
+		return d, size, nil
+	}
+
+	switch t {
+	case TypeArray, TypeStructure:
+		count, pos, err := decodeLength(raw)
+		if err != nil {
+			return Data{}, 0, err
+		}
+
+		// Every element is at least its tag, so a count larger than
+		// what is left cannot be right.
+		if count > len(raw)-pos {
+			return Data{}, 0, ErrMalformedData
+		}
+
+		elements := make([]Data, 0, count)
+		for i := 0; i < count; i++ {
+			e, n, err := DecodeData(raw[pos:])
+			if err != nil {
+				return Data{}, 0, err
+			}
+
+			elements = append(elements, e)
+			pos += n
+		}
+
+		d.Value = elements
+
+		return d, pos, nil
+
+	case TypeOctetString, TypeVisibleString, TypeUTF8String:
+		length, pos, err := decodeLength(raw)
+		if err != nil {
+			return Data{}, 0, err
+		}
+
+		if len(raw) < pos+length {
+			return Data{}, 0, ErrMalformedData
+		}
+
+		contents := raw[pos : pos+length]
+		if t == TypeOctetString {
+			d.Value = append([]byte{}, contents...)
+		} else {
+			d.Value = string(contents)
+		}
+
+		return d, pos + length, nil
+
+	case TypeBitString:
+		bits, pos, err := decodeLength(raw)
+		if err != nil {
+			return Data{}, 0, err
+		}
+
+		length := (bits + 7) / 8
+		if len(raw) < pos+length {
+			return Data{}, 0, ErrMalformedData
+		}
+
+		d.Value = BitString{
+			Length: bits,
+			Bytes:  append([]byte{}, raw[pos:pos+length]...),
+		}
+
+		return d, pos + length, nil
+	}
+
+	return Data{}, 0, ErrUnknownDataType
+}
+
+// decodeBCD will decode two binary coded decimal digits, like 0x12 as 12. It
+// will return false if a digit is above 9.
+func decodeBCD(b byte) (int64, bool) {
+	high, low := b>>4, b&0x0f
+	if high > 9 || low > 9 {
+		return 0, false
+	}
+
+	return int64(high)*10 + int64(low), true
+}
+
+// decodeFixed will decode a fixed length type. raw must have the correct
+// length.
+func decodeFixed(t DataType, raw []byte) interface{} {
+	switch t {
+	case TypeBoolean:
+		return raw[0] != 0
+	case TypeInteger:
+		return int64(int8(raw[0]))
+	case TypeLong:
+		return int64(int16(binary.BigEndian.Uint16(raw)))
+	case TypeDoubleLong:
+		return int64(int32(binary.BigEndian.Uint32(raw)))
+	case TypeLong64:
+		return int64(binary.BigEndian.Uint64(raw))
+	case TypeUnsigned, TypeEnum:
+		return uint64(raw[0])
+	case TypeLongUnsigned:
+		return uint64(binary.BigEndian.Uint16(raw))
+	case TypeDoubleLongUnsigned:
+		return uint64(binary.BigEndian.Uint32(raw))
+	case TypeLong64Unsigned:
+		return binary.BigEndian.Uint64(raw)
+	case TypeFloat32, TypeFloatingPoint:
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(raw)))
+	case TypeFloat64:
+		return math.Float64frombits(binary.BigEndian.Uint64(raw))
+	case TypeDateTime, TypeDate, TypeTime:
+		dt, _ := decodeDateTime(t, raw)
+		return dt
+	}
+
+	return nil
+}
+
+// decodeCompactArray will decode a compact-array including the tag. The
+// elements are returned as an ordinary array.
+func decodeCompactArray(raw []byte) (Data, int, error) {
+	desc, pos, err := decodeTypeDescription(raw[1:])
+	if err != nil {
+		return Data{}, 0, err
+	}
+	pos++
+
+	// Elements must use at least one byte, or the contents would never be
+	// consumed.
+	if desc.zeroWidth() {
+		return Data{}, 0, ErrMalformedData
+	}
+
+	length, n, err := decodeLength(raw[pos:])
+	if err != nil {
+		return Data{}, 0, err
+	}
+	pos += n
+
+	if len(raw) < pos+length {
+		return Data{}, 0, ErrMalformedData
+	}
+
+	contents := raw[pos : pos+length]
+	var elements []Data
+	for len(contents) > 0 {
+		e, n, err := desc.decode(contents)
+		if err != nil {
+			return Data{}, 0, err
+		}
+
+		elements = append(elements, e)
+		contents = contents[n:]
+	}
+
+	return Data{Type: TypeArray, Value: elements}, pos + length, nil
+}
+
+// typeDescription describes the elements of a compact-array.
+type typeDescription struct {
+	t        DataType
+	count    int
+	elements []typeDescription
+}
+
+func decodeTypeDescription(raw []byte) (typeDescription, int, error) {
+	if len(raw) < 1 {
+		return typeDescription{}, 0, ErrMalformedData
+	}
+
+	desc := typeDescription{t: DataType(raw[0])}
+	pos := 1
+
+	switch desc.t {
+	case TypeArray:
+		if len(raw) < 3 {
+			return typeDescription{}, 0, ErrMalformedData
+		}
+
+		desc.count = int(binary.BigEndian.Uint16(raw[1:]))
+		element, n, err := decodeTypeDescription(raw[3:])
+		if err != nil {
+			return typeDescription{}, 0, err
+		}
+
+		if element.zeroWidth() {
+			return typeDescription{}, 0, ErrMalformedData
+		}
+
+		desc.elements = []typeDescription{element}
+		pos = 3 + n
+
+	case TypeStructure:
+		count, n, err := decodeLength(raw[1:])
+		if err != nil {
+			return typeDescription{}, 0, err
+		}
+		pos += n
+
+		for i := 0; i < count; i++ {
+			element, n, err := decodeTypeDescription(raw[pos:])
+			if err != nil {
+				return typeDescription{}, 0, err
+			}
+
+			desc.elements = append(desc.elements, element)
+			pos += n
+		}
+	}
+
+	return desc, pos, nil
+}
+
+// zeroWidth will return true if elements described by desc are encoded
+// using no bytes at all, like null-data or an empty structure.
+func (desc typeDescription) zeroWidth() bool {
+	switch desc.t {
+	case TypeArray:
+		return desc.count == 0 || desc.elements[0].zeroWidth()
+
+	case TypeStructure:
+		for _, element := range desc.elements {
+			if !element.zeroWidth() {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	size, found := fixedSizes[desc.t]
+
+	return found && size == 0
+}
+
+// decode will decode an untagged element described by desc.
+func (desc typeDescription) decode(raw []byte) (Data, int, error) {
+	var elements []Data
+	pos := 0
+
+	switch desc.t {
+	case TypeArray:
+		for i := 0; i < desc.count; i++ {
+			e, n, err := desc.elements[0].decode(raw[pos:])
+			if err != nil {
+				return Data{}, 0, err
+			}
+
+			elements = append(elements, e)
+			pos += n
+		}
+
+		return Data{Type: TypeArray, Value: elements}, pos, nil
+
+	case TypeStructure:
+		for _, element := range desc.elements {
+			e, n, err := element.decode(raw[pos:])
+			if err != nil {
+				return Data{}, 0, err
+			}
+
+			elements = append(elements, e)
+			pos += n
+		}
+
+		return Data{Type: TypeStructure, Value: elements}, pos, nil
+	}
+
+	return decodeValue(desc.t, raw)
+}
+
+// decodeLength will decode a BER encoded length as used for A-XDR strings
+// and element counts, returning the length and the number of bytes used.
+func decodeLength(raw []byte) (int, int, error) {
+	if len(raw) < 1 {
+		return 0, 0, ErrMalformedData
+	}
+
+	if raw[0]&0x80 == 0 {
+		return int(raw[0]), 1, nil
+	}
+
+	n := int(raw[0] & 0x7f)
+	if n < 1 || n > 3 || len(raw) < n+1 {
+		return 0, 0, ErrMalformedData
+	}
+
+	length := 0
+	for _, b := range raw[1 : n+1] {
+		length = length<<8 | int(b)
+	}
+
+	return length, n + 1, nil
+}
+
+// appendLength will append a BER encoded length.
+func appendLength(raw []byte, length int) []byte {
+	switch {
+	case length < 0x80:
+		return append(raw, byte(length))
+	case length < 0x100:
+		return append(raw, 0x81, byte(length))
+	}
+
+	return append(raw, 0x82, byte(length>>8), byte(length))
+}
+
+// Encode will encode the element including the tag.
+func (d Data) Encode() ([]byte, error) {
+	return d.appendEncoded(nil)
+}
+
+func (d Data) appendEncoded(raw []byte) ([]byte, error) {
+	t := d.Type
+	if t == TypeCompactArray {
+		// Compact arrays are decoded as arrays and encoded as such.
+		t = TypeArray
+	}
+
+	raw = append(raw, byte(t))
+
+	var err error
+	switch v := d.Value.(type) {
+	case nil:
+		if t != TypeNull && t != TypeDontCare {
+			return nil, ErrMalformedData
+		}
+
+	case []Data:
+		if t != TypeArray && t != TypeStructure {
+			return nil, ErrMalformedData
+		}
+
+		raw = appendLength(raw, len(v))
+		for _, e := range v {
+			raw, err = e.appendEncoded(raw)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+	case bool:
+		if v {
+			raw = append(raw, 1)
+		} else {
+			raw = append(raw, 0)
+		}
+
+	case BitString:
+		raw = appendLength(raw, v.Length)
+		raw = append(raw, v.Bytes...)
+
+	case int64:
+		if t == TypeBCD {
+			if v < 0 || v > 99 {
+				return nil, ErrMalformedData
+			}
+
+			raw = append(raw, byte(v/10)<<4|byte(v%10))
+			break
+		}
+
+		raw = appendInt(raw, uint64(v), fixedSizes[t])
+
+	case uint64:
+		raw = appendInt(raw, v, fixedSizes[t])
+
+	case float64:
+		if fixedSizes[t] == 8 {
+			raw = appendInt(raw, math.Float64bits(v), 8)
+		} else {
+			raw = appendInt(raw, uint64(math.Float32bits(float32(v))), 4)
+		}
+
+	case []byte:
+		raw = appendLength(raw, len(v))
+		raw = append(raw, v...)
+
+	case string:
+		raw = appendLength(raw, len(v))
+		raw = append(raw, v...)
+
+	case DateTime:
+		raw = append(raw, v.encode(t)...)
+
+	default:
+		return nil, ErrUnknownDataType
+	}
+
+	return raw, nil
+}
+
+// appendInt will append the size lowest bytes of v, most significant first.
+func appendInt(raw []byte, v uint64, size int) []byte {
+	for i := size - 1; i >= 0; i-- {
+		raw = append(raw, byte(v>>(uint(i)*8)))
+	}
+
+	return raw
+}
+
+// Int will return the value of integer, enum and boolean types.
+func (d Data) Int() (int64, bool) {
+	switch v := d.Value.(type) {
+	case int64:
+		return v, true
+	case uint64:
+		return int64(v), true
+	case bool:
+		if v {
+			return 1, true
+		}
+
+		return 0, true
+	}
+
+	return 0, false
+}
+
+// Float will return the value of any numeric type.
+func (d Data) Float() (float64, bool) {
+	switch v := d.Value.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	}
+
+	return 0, false
+}
+
+// Bytes will return the contents of octet- and character strings.
+func (d Data) Bytes() ([]byte, bool) {
+	switch v := d.Value.(type) {
+	case []byte:
+		return v, true
+	case string:
+		return []byte(v), true
+	}
+
+	return nil, false
+}
+
+// Elements will return the elements of arrays and structures.
+func (d Data) Elements() []Data {
+	elements, _ := d.Value.([]Data)
+
+	return elements
+}
+
+// DateTime will return the value of date-time, date and time types. Octet
+// strings of the right length are interpreted as date-time as well, this is
+// used by many meters.
+func (d Data) DateTime() (DateTime, bool) {
+	switch v := d.Value.(type) {
+	case DateTime:
+		return v, true
+	case []byte:
+		if len(v) == 12 {
+			dt, err := decodeDateTime(TypeDateTime, v)
+			return dt, err == nil
+		}
+	}
+
+	return DateTime{}, false
+}
+
+// String will return a human readable representation of the value.
+func (d Data) String() string {
+	switch v := d.Value.(type) {
+	case nil:
+		return d.Type.String()
+	case []Data:
+		parts := make([]string, len(v))
+		for i, e := range v {
+			parts[i] = e.String()
+		}
+
+		if d.Type == TypeStructure {
+			return "{" + strings.Join(parts, ", ") + "}"
+		}
+
+		return "[" + strings.Join(parts, ", ") + "]"
+	case []byte:
+		if printable(v) {
+			return fmt.Sprintf("%q", v)
+		}
+
+		return hex.EncodeToString(v)
+	case BitString:
+		return fmt.Sprintf("%x/%d", v.Bytes, v.Length)
+	case string:
+		return fmt.Sprintf("%q", v)
+	}
+
+	return fmt.Sprintf("%v", d.Value)
+}
+
+// printable will return true if all bytes are printable ASCII.
+func printable(b []byte) bool {
+	if len(b) == 0 {
+		return false
+	}
+
+	for _, c := range b {
+		if c < 0x20 || c > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
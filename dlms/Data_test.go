@@ -0,0 +1,155 @@
+package dlms
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+	"time"
+)
+
+func TestDecodeData(t *testing.T) {
+	cases := []struct {
+		in       string
+		typ      DataType
+		expected string
+	}{
+		{"00", TypeNull, "null-data"},
+		{"0301", TypeBoolean, "true"},
+		{"0f85", TypeInteger, "-123"},
+		{"10ff38", TypeLong, "-200"},
+		{"05fffffc18", TypeDoubleLong, "-1000"},
+		{"1100", TypeUnsigned, "0"},
+		{"120d80", TypeLongUnsigned, "3456"},
+		{"0600012d34", TypeDoubleLongUnsigned, "77108"},
+		{"150000000100000000", TypeLong64Unsigned, "4294967296"},
+		{"1603", TypeEnum, "3"},
+		{"0d12", TypeBCD, "12"},
+		{"0d99", TypeBCD, "99"},
+		{"1740490fdb", TypeFloat32, "3.1415927410125732"},
+		{"090401020304", TypeOctetString, "01020304"},
+		{"09074b464d5f303031", TypeOctetString, `"KFM_001"`},
+		{"0a0341424b", TypeVisibleString, `"ABK"`},
+		{"0403a0", TypeBitString, "a0/3"},
+		{"090c07e5010c0211233b00ffc400", TypeOctetString, "07e5010c0211233b00ffc400"},
+		{"020212000a1603", TypeStructure, "{10, 3}"},
+		{"0102120001120002", TypeArray, "[1, 2]"},
+		{"1907e5010c0211233b00ffc400", TypeDateTime, "2021-01-12 17:35:59 +0100"},
+		{"1a07e5010cff", TypeDate, "2021-01-12 **:**:**"},
+	}
+
+	for _, c := range cases {
+		raw, _ := hex.DecodeString(c.in)
+
+		d, n, err := DecodeData(raw)
+		if err != nil {
+			t.Errorf("DecodeData(%s) returned %s", c.in, err)
+			continue
+		}
+
+		if n != len(raw) {
+			t.Errorf("DecodeData(%s) used %d bytes, expected %d", c.in, n, len(raw))
+		}
+
+		if d.Type != c.typ {
+			t.Errorf("DecodeData(%s) returned type %s, expected %s", c.in, d.Type, c.typ)
+		}
+
+		if d.String() != c.expected {
+			t.Errorf("DecodeData(%s) returned %s, expected %s", c.in, d.String(), c.expected)
+		}
+
+		encoded, err := d.Encode()
+		if err != nil {
+			t.Errorf("Encode() of %s returned %s", c.in, err)
+		}
+
+		if !bytes.Equal(encoded, raw) {
+			t.Errorf("Encode() returned %x, expected %s", encoded, c.in)
+		}
+	}
+}
+
+func TestDecodeDataErrors(t *testing.T) {
+	cases := []struct {
+		in  string
+		err error
+	}{
+		{"", ErrMalformedData},
+		{"12ff", ErrMalformedData},
+		{"0905010203", ErrMalformedData},
+		{"0202120001", ErrMalformedData},
+		{"0183ffffff", ErrMalformedData},
+		{"0283ffffff00", ErrMalformedData},
+		{"08", ErrUnknownDataType},
+		{"0d1a", ErrMalformedData},
+		{"0da1", ErrMalformedData},
+
+		// compact-arrays of zero width elements.
+		{"130001aa", ErrMalformedData},
+		{"13ff01aa", ErrMalformedData},
+		{"13020001aa", ErrMalformedData},
+		{"1301000a0001aa", ErrMalformedData},
+		{"1302020000ff01aa", ErrMalformedData},
+	}
+
+	for _, c := range cases {
+		raw, _ := hex.DecodeString(c.in)
+
+		_, _, err := DecodeData(raw)
+		if err != c.err {
+			t.Errorf("DecodeData(%s) returned %v, expected %v", c.in, err, c.err)
+		}
+	}
+}
+
+func TestDecodeCompactArray(t *testing.T) {
+	// compact-array of structure {long-unsigned, integer}, two elements.
+	raw, _ := hex.DecodeString("130202120f06" + "0001ff" + "0002fe")
+
+	d, n, err := DecodeData(raw)
+	if err != nil {
+		t.Fatalf("DecodeData() returned %s", err)
+	}
+
+	if n != len(raw) {
+		t.Errorf("DecodeData() used %d bytes, expected %d", n, len(raw))
+	}
+
+	if d.String() != "[{1, -1}, {2, -2}]" {
+		t.Errorf("DecodeData() returned %s", d)
+	}
+}
+
+func TestDateTime(t *testing.T) {
+	raw, _ := hex.DecodeString("07e5010c0211233b00ffc400")
+
+	dt, err := ParseDateTime(raw)
+	if err != nil {
+		t.Fatalf("ParseDateTime() returned %s", err)
+	}
+
+	tm, err := dt.Time()
+	if err != nil {
+		t.Fatalf("Time() returned %s", err)
+	}
+
+	expected := time.Date(2021, 1, 12, 16, 35, 59, 0, time.UTC)
+	if !tm.Equal(expected) {
+		t.Errorf("Time() returned %s, expected %s", tm, expected)
+	}
+
+	back := NewDateTime(tm.In(time.FixedZone("CET", 3600)))
+	if !bytes.Equal(back.Bytes(), raw) {
+		t.Errorf("NewDateTime() encoded as %x, expected %x", back.Bytes(), raw)
+	}
+
+	_, err = ParseDateTime(raw[:11])
+	if err != ErrMalformedDateTime {
+		t.Errorf("ParseDateTime() of short input returned %v", err)
+	}
+
+	_, err = DateTime{Year: YearNotSpecified, Month: 1, Day: 1}.Time()
+	if err != ErrMalformedDateTime {
+		t.Errorf("Time() without year returned %v", err)
+	}
+}
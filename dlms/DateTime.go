@@ -0,0 +1,220 @@
+package dlms
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type (
+	// DateTime is a COSEM date-time. Fields not specified by the meter are
+	// set to NotSpecified (0xff), or DeviationNotSpecified for Deviation and
+	// YearNotSpecified for Year.
+	DateTime struct {
+		Year       uint16
+		Month      byte
+		Day        byte
+		Weekday    byte // 1 is Monday
+		Hour       byte
+		Minute     byte
+		Second     byte
+		Hundredths byte
+
+		// Deviation is the offset from local time to UTC in minutes. Central
+		// European Time is -60.
+		Deviation int16
+
+		// Status is the clock status. See ClockInvalid and friends.
+		Status byte
+	}
+)
+
+const (
+	// NotSpecified is used for date and time fields not specified.
+	NotSpecified = byte(0xff)
+
+	// YearNotSpecified is used if the year is not specified.
+	YearNotSpecified = uint16(0xffff)
+
+	// DeviationNotSpecified is used if the deviation is not specified.
+	DeviationNotSpecified = int16(-0x8000)
+)
+
+// Clock status bits.
+const (
+	ClockInvalid          = byte(0x01)
+	ClockDoubtful         = byte(0x02)
+	ClockDifferentBase    = byte(0x04)
+	ClockInvalidStatus    = byte(0x08)
+	ClockDaylightSaving   = byte(0x80)
+	ClockStatusNotDefined = byte(0xff)
+)
+
+var (
+	// Location is used for date-times without deviation.
+	Location = time.Local
+
+	// ErrMalformedDateTime will be returned if a date-time is not 12 bytes
+	// or cannot be converted to a time.
+	ErrMalformedDateTime = errors.New("malformed date-time")
+)
+
+// NewDateTime will convert t to a DateTime with deviation.
+func NewDateTime(t time.Time) DateTime {
+	_, offset := t.Zone()
+
+	weekday := byte(t.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+
+	status := byte(0)
+	if t.IsDST() {
+		status = ClockDaylightSaving
+	}
+
+	return DateTime{
+		Year:       uint16(t.Year()),
+		Month:      byte(t.Month()),
+		Day:        byte(t.Day()),
+		Weekday:    weekday,
+		Hour:       byte(t.Hour()),
+		Minute:     byte(t.Minute()),
+		Second:     byte(t.Second()),
+		Hundredths: byte(t.Nanosecond() / 10000000),
+		Deviation:  int16(-offset / 60),
+		Status:     status,
+	}
+}
+
+// ParseDateTime will parse a 12 byte date-time as sent in octet strings.
+func ParseDateTime(raw []byte) (DateTime, error) {
+	return decodeDateTime(TypeDateTime, raw)
+}
+
+// decodeDateTime will decode a date-time, date or time.
+func decodeDateTime(t DataType, raw []byte) (DateTime, error) {
+	dt := DateTime{
+		Year:       YearNotSpecified,
+		Month:      NotSpecified,
+		Day:        NotSpecified,
+		Weekday:    NotSpecified,
+		Hour:       NotSpecified,
+		Minute:     NotSpecified,
+		Second:     NotSpecified,
+		Hundredths: NotSpecified,
+		Deviation:  DeviationNotSpecified,
+		Status:     ClockStatusNotDefined,
+	}
+
+	if len(raw) != fixedSizes[t] {
+		return dt, ErrMalformedDateTime
+	}
+
+	if t == TypeDateTime || t == TypeDate {
+		dt.Year = binary.BigEndian.Uint16(raw)
+		dt.Month = raw[2]
+		dt.Day = raw[3]
+		dt.Weekday = raw[4]
+		raw = raw[5:]
+	}
+
+	if t == TypeDateTime || t == TypeTime {
+		dt.Hour = raw[0]
+		dt.Minute = raw[1]
+		dt.Second = raw[2]
+		dt.Hundredths = raw[3]
+	}
+
+	if t == TypeDateTime {
+		dt.Deviation = int16(binary.BigEndian.Uint16(raw[4:]))
+		dt.Status = raw[6]
+	}
+
+	return dt, nil
+}
+
+// Bytes will return the 12 byte date-time encoding.
+func (dt DateTime) Bytes() []byte {
+	return dt.encode(TypeDateTime)
+}
+
+func (dt DateTime) encode(t DataType) []byte {
+	var raw []byte
+
+	if t == TypeDateTime || t == TypeDate {
+		raw = append(raw, byte(dt.Year>>8), byte(dt.Year), dt.Month, dt.Day, dt.Weekday)
+	}
+
+	if t == TypeDateTime || t == TypeTime {
+		raw = append(raw, dt.Hour, dt.Minute, dt.Second, dt.Hundredths)
+	}
+
+	if t == TypeDateTime {
+		raw = append(raw, byte(uint16(dt.Deviation)>>8), byte(dt.Deviation), dt.Status)
+	}
+
+	return raw
+}
+
+// Time will convert the date-time to a time.Time. Year, month and day must be
+// specified, unspecified time fields are treated as zero. If no deviation is
+// given, Location is used.
+func (dt DateTime) Time() (time.Time, error) {
+	if dt.Year == YearNotSpecified || dt.Month == NotSpecified || dt.Day == NotSpecified {
+		return time.Time{}, ErrMalformedDateTime
+	}
+
+	if dt.Month < 1 || dt.Month > 12 || dt.Day < 1 || dt.Day > 31 {
+		return time.Time{}, ErrMalformedDateTime
+	}
+
+	field := func(b byte) int {
+		if b == NotSpecified {
+			return 0
+		}
+
+		return int(b)
+	}
+
+	loc := Location
+	if dt.Deviation != DeviationNotSpecified {
+		loc = time.FixedZone("", -int(dt.Deviation)*60)
+	}
+
+	return time.Date(int(dt.Year), time.Month(dt.Month), int(dt.Day),
+		field(dt.Hour), field(dt.Minute), field(dt.Second), field(dt.Hundredths)*10000000, loc), nil
+}
+
+// String will return the date-time as "2006-01-02 15:04:05", with unspecified
+// fields as "*".
+func (dt DateTime) String() string {
+	field := func(b byte) string {
+		if b == NotSpecified {
+			return "**"
+		}
+
+		return fmt.Sprintf("%02d", b)
+	}
+
+	year := "****"
+	if dt.Year != YearNotSpecified {
+		year = fmt.Sprintf("%04d", dt.Year)
+	}
+
+	s := fmt.Sprintf("%s-%s-%s %s:%s:%s", year, field(dt.Month), field(dt.Day), field(dt.Hour), field(dt.Minute), field(dt.Second))
+
+	if dt.Deviation != DeviationNotSpecified {
+		offset := -int(dt.Deviation)
+		sign := "+"
+		if offset < 0 {
+			sign = "-"
+			offset = -offset
+		}
+
+		s += fmt.Sprintf(" %s%02d%02d", sign, offset/60, offset%60)
+	}
+
+	return s
+}
@@ -0,0 +1,73 @@
+package dlms
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+type (
+	// DataNotification is a data-notification APDU as pushed by meters.
+	DataNotification struct {
+		InvokeID uint32
+
+		// Time is the time of the notification. HasTime is false if the meter
+		// did not include it.
+		Time    DateTime
+		HasTime bool
+
+		Body Data
+	}
+)
+
+// TagDataNotification is the APDU tag of a data-notification.
+const TagDataNotification = byte(0x0f)
+
+var (
+	// ErrNotDataNotification will be returned if an APDU is not a
+	// data-notification.
+	ErrNotDataNotification = errors.New("not a data-notification")
+)
+
+// ParseDataNotification will parse a data-notification APDU.
+func ParseDataNotification(apdu []byte) (*DataNotification, error) {
+	if len(apdu) < 6 || apdu[0] != TagDataNotification {
+		return nil, ErrNotDataNotification
+	}
+
+	n := &DataNotification{
+		InvokeID: binary.BigEndian.Uint32(apdu[1:]),
+	}
+
+	// The date-time is optional. Most meters send it as a tagged octet
+	// string or null-data, some leave out the tag.
+	pos := 5
+	switch apdu[pos] {
+	case 0x00:
+		pos++
+	case 0x0c:
+		if len(apdu) < pos+13 {
+			return nil, ErrMalformedData
+		}
+
+		n.Time, _ = ParseDateTime(apdu[pos+1 : pos+13])
+		n.HasTime = true
+		pos += 13
+	default:
+		d, used, err := DecodeData(apdu[pos:])
+		if err != nil {
+			return nil, err
+		}
+
+		n.Time, n.HasTime = d.DateTime()
+		pos += used
+	}
+
+	body, _, err := DecodeData(apdu[pos:])
+	if err != nil {
+		return nil, err
+	}
+
+	n.Body = body
+
+	return n, nil
+}
@@ -0,0 +1,599 @@
+package dlms
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/abrander/gometer/iec62056"
+	"github.com/abrander/gometer/kamstrup"
+)
+
+type (
+	// ClassID identifies a COSEM interface class.
+	ClassID uint16
+
+	// Object is a COSEM object. Attributes are set as they are read from a
+	// meter or received in a push message.
+	Object interface {
+		ClassID() ClassID
+		LogicalName() iec62056.Obis
+		SetAttribute(attribute int8, d Data) error
+	}
+
+	// Objects is a collection of COSEM objects keyed by logical name.
+	Objects map[iec62056.Obis]Object
+
+	// ScalerUnit is the scaler_unit attribute of registers. The value of a
+	// register is value * 10^Scaler.
+	ScalerUnit struct {
+		Scaler int8
+		Unit   Unit
+	}
+
+	// GenericObject holds the attributes of an object of a class not
+	// otherwise supported.
+	GenericObject struct {
+		Class      ClassID
+		Name       iec62056.Obis
+		Attributes map[int8]Data
+	}
+
+	// DataObject is an object of interface class Data (1).
+	DataObject struct {
+		Name  iec62056.Obis
+		Value Data
+	}
+
+	// Register is an object of interface class Register (3).
+	Register struct {
+		Name       iec62056.Obis
+		Value      Data
+		ScalerUnit ScalerUnit
+	}
+
+	// ExtendedRegister is an object of interface class Extended Register (4).
+	ExtendedRegister struct {
+		Register
+		Status      Data
+		CaptureTime DateTime
+	}
+
+	// Clock is an object of interface class Clock (8).
+	Clock struct {
+		Name                     iec62056.Obis
+		Time                     DateTime
+		TimeZone                 int16 // Deviation of local time to UTC in minutes
+		Status                   byte
+		DaylightSavingsBegin     DateTime
+		DaylightSavingsEnd       DateTime
+		DaylightSavingsDeviation int8
+		DaylightSavingsEnabled   bool
+		ClockBase                byte
+	}
+
+	// ProfileGeneric is an object of interface class Profile Generic (7).
+	ProfileGeneric struct {
+		Name           iec62056.Obis
+		Buffer         []Data // Structures with one element per capture object
+		CaptureObjects []CaptureObject
+		CapturePeriod  uint32 // Seconds
+		SortMethod     byte
+		EntriesInUse   uint32
+		ProfileEntries uint32
+	}
+
+	// CaptureObject is a column in a profile buffer.
+	CaptureObject struct {
+		Class     ClassID
+		Name      iec62056.Obis
+		Attribute int8
+		DataIndex uint16
+	}
+
+	// Record is a decoded row from a profile buffer.
+	Record struct {
+		Time   time.Time
+		Values iec62056.ValueCollection
+	}
+)
+
+// Interface classes.
+const (
+	ClassData             = ClassID(1)
+	ClassRegister         = ClassID(3)
+	ClassExtendedRegister = ClassID(4)
+	ClassDemandRegister   = ClassID(5)
+	ClassProfileGeneric   = ClassID(7)
+	ClassClock            = ClassID(8)
+	ClassAssociationLN    = ClassID(15)
+)
+
+// AttributeLogicalName is the first attribute of all interface classes.
+const AttributeLogicalName = int8(1)
+
+// Attributes of Data, Register and Extended Register.
+const (
+	AttributeValue       = int8(2)
+	AttributeScalerUnit  = int8(3)
+	AttributeStatus      = int8(4)
+	AttributeCaptureTime = int8(5)
+)
+
+// Attributes of Clock.
+const (
+	AttributeTime                     = int8(2)
+	AttributeTimeZone                 = int8(3)
+	AttributeClockStatus              = int8(4)
+	AttributeDaylightSavingsBegin     = int8(5)
+	AttributeDaylightSavingsEnd       = int8(6)
+	AttributeDaylightSavingsDeviation = int8(7)
+	AttributeDaylightSavingsEnabled   = int8(8)
+	AttributeClockBase                = int8(9)
+)
+
+// Attributes of Profile Generic.
+const (
+	AttributeBuffer         = int8(2)
+	AttributeCaptureObjects = int8(3)
+	AttributeCapturePeriod  = int8(4)
+	AttributeSortMethod     = int8(5)
+	AttributeSortObject     = int8(6)
+	AttributeEntriesInUse   = int8(7)
+	AttributeProfileEntries = int8(8)
+)
+
+var (
+	// ErrUnexpectedType will be returned if an attribute is set with data of
+	// the wrong type.
+	ErrUnexpectedType = errors.New("unexpected data type for attribute")
+
+	// ErrUnknownAttribute will be returned if an attribute is not defined for
+	// the interface class.
+	ErrUnknownAttribute = errors.New("unknown attribute")
+
+	// classAttributes are the attributes read by Client.Read if none are
+	// given. The buffer of Profile Generic is left out, as it can be large.
+	classAttributes = map[ClassID][]int8{
+		ClassData:             {AttributeValue},
+		ClassRegister:         {AttributeValue, AttributeScalerUnit},
+		ClassExtendedRegister: {AttributeValue, AttributeScalerUnit, AttributeStatus, AttributeCaptureTime},
+		ClassClock: {AttributeTime, AttributeTimeZone, AttributeClockStatus, AttributeDaylightSavingsBegin,
+			AttributeDaylightSavingsEnd, AttributeDaylightSavingsDeviation, AttributeDaylightSavingsEnabled,
+			AttributeClockBase},
+		ClassProfileGeneric: {AttributeCaptureObjects, AttributeCapturePeriod, AttributeSortMethod,
+			AttributeEntriesInUse, AttributeProfileEntries},
+	}
+
+	classNames = map[ClassID]string{
+		ClassData:             "Data",
+		ClassRegister:         "Register",
+		ClassExtendedRegister: "Extended Register",
+		ClassDemandRegister:   "Demand Register",
+		ClassProfileGeneric:   "Profile Generic",
+		ClassClock:            "Clock",
+		ClassAssociationLN:    "Association LN",
+	}
+)
+
+// String will return the name of the interface class.
+func (c ClassID) String() string {
+	name, found := classNames[c]
+	if !found {
+		return fmt.Sprintf("class-%d", uint16(c))
+	}
+
+	return name
+}
+
+// NewObject will instantiate an empty object of the given class. Classes not
+// otherwise supported are returned as *GenericObject.
+func NewObject(class ClassID, name iec62056.Obis) Object {
+	switch class {
+	case ClassData:
+		return &DataObject{Name: name}
+	case ClassRegister:
+		return &Register{Name: name}
+	case ClassExtendedRegister:
+		return &ExtendedRegister{Register: Register{Name: name}}
+	case ClassClock:
+		return &Clock{Name: name}
+	case ClassProfileGeneric:
+		return &ProfileGeneric{Name: name}
+	}
+
+	return &GenericObject{Class: class, Name: name, Attributes: make(map[int8]Data)}
+}
+
+// DecodeLogicalName will decode an octet string holding a binary OBIS code.
+func DecodeLogicalName(d Data) (iec62056.Obis, error) {
+	raw, ok := d.Value.([]byte)
+	if !ok {
+		return iec62056.Obis{}, ErrUnexpectedType
+	}
+
+	return iec62056.ObisFromBytes(raw)
+}
+
+// DecodeScalerUnit will decode a scaler_unit structure.
+func DecodeScalerUnit(d Data) (ScalerUnit, error) {
+	elements := d.Elements()
+	if d.Type != TypeStructure || len(elements) != 2 {
+		return ScalerUnit{}, ErrUnexpectedType
+	}
+
+	scaler, ok1 := elements[0].Int()
+	unit, ok2 := elements[1].Int()
+	if !ok1 || !ok2 {
+		return ScalerUnit{}, ErrUnexpectedType
+	}
+
+	return ScalerUnit{Scaler: int8(scaler), Unit: Unit(unit)}, nil
+}
+
+// Apply will scale a raw register value.
+func (s ScalerUnit) Apply(v float64) float64 {
+	if s.Scaler == 0 {
+		return v
+	}
+
+	return v * math.Pow10(int(s.Scaler))
+}
+
+// String will return the scaler_unit as "10^-1 Wh".
+func (s ScalerUnit) String() string {
+	return fmt.Sprintf("10^%d %s", s.Scaler, s.Unit)
+}
+
+// setLogicalName is used by all classes for attribute 1.
+func setLogicalName(name *iec62056.Obis, d Data) error {
+	o, err := DecodeLogicalName(d)
+	if err != nil {
+		return err
+	}
+
+	*name = o
+
+	return nil
+}
+
+// ClassID implements Object.
+func (o *GenericObject) ClassID() ClassID { return o.Class }
+
+// LogicalName implements Object.
+func (o *GenericObject) LogicalName() iec62056.Obis { return o.Name }
+
+// SetAttribute implements Object. All attributes are accepted.
+func (o *GenericObject) SetAttribute(attribute int8, d Data) error {
+	if attribute == AttributeLogicalName {
+		return setLogicalName(&o.Name, d)
+	}
+
+	o.Attributes[attribute] = d
+
+	return nil
+}
+
+// ClassID implements Object.
+func (o *DataObject) ClassID() ClassID { return ClassData }
+
+// LogicalName implements Object.
+func (o *DataObject) LogicalName() iec62056.Obis { return o.Name }
+
+// SetAttribute implements Object.
+func (o *DataObject) SetAttribute(attribute int8, d Data) error {
+	switch attribute {
+	case AttributeLogicalName:
+		return setLogicalName(&o.Name, d)
+	case AttributeValue:
+		o.Value = d
+		return nil
+	}
+
+	return ErrUnknownAttribute
+}
+
+// ClassID implements Object.
+func (r *Register) ClassID() ClassID { return ClassRegister }
+
+// LogicalName implements Object.
+func (r *Register) LogicalName() iec62056.Obis { return r.Name }
+
+// SetAttribute implements Object.
+func (r *Register) SetAttribute(attribute int8, d Data) error {
+	switch attribute {
+	case AttributeLogicalName:
+		return setLogicalName(&r.Name, d)
+	case AttributeValue:
+		r.Value = d
+		return nil
+	case AttributeScalerUnit:
+		su, err := DecodeScalerUnit(d)
+		if err != nil {
+			return err
+		}
+
+		r.ScalerUnit = su
+		return nil
+	}
+
+	return ErrUnknownAttribute
+}
+
+// Reading will return the scaled value of the register. false is returned if
+// the value is not numeric.
+func (r *Register) Reading() (kamstrup.Value, bool) {
+	v, ok := r.Value.Float()
+	if !ok {
+		return kamstrup.Value{}, false
+	}
+
+	return kamstrup.Value{
+		Value: r.ScalerUnit.Apply(v),
+		Unit:  r.ScalerUnit.Unit.Kamstrup(),
+	}, true
+}
+
+// ClassID implements Object.
+func (r *ExtendedRegister) ClassID() ClassID { return ClassExtendedRegister }
+
+// SetAttribute implements Object.
+func (r *ExtendedRegister) SetAttribute(attribute int8, d Data) error {
+	switch attribute {
+	case AttributeStatus:
+		r.Status = d
+		return nil
+	case AttributeCaptureTime:
+		dt, ok := d.DateTime()
+		if !ok {
+			return ErrUnexpectedType
+		}
+
+		r.CaptureTime = dt
+		return nil
+	}
+
+	return r.Register.SetAttribute(attribute, d)
+}
+
+// ClassID implements Object.
+func (c *Clock) ClassID() ClassID { return ClassClock }
+
+// LogicalName implements Object.
+func (c *Clock) LogicalName() iec62056.Obis { return c.Name }
+
+// SetAttribute implements Object.
+func (c *Clock) SetAttribute(attribute int8, d Data) error {
+	switch attribute {
+	case AttributeLogicalName:
+		return setLogicalName(&c.Name, d)
+	case AttributeTime, AttributeDaylightSavingsBegin, AttributeDaylightSavingsEnd:
+		dt, ok := d.DateTime()
+		if !ok {
+			return ErrUnexpectedType
+		}
+
+		switch attribute {
+		case AttributeTime:
+			c.Time = dt
+		case AttributeDaylightSavingsBegin:
+			c.DaylightSavingsBegin = dt
+		default:
+			c.DaylightSavingsEnd = dt
+		}
+
+		return nil
+	}
+
+	v, ok := d.Int()
+	if !ok {
+		return ErrUnexpectedType
+	}
+
+	switch attribute {
+	case AttributeTimeZone:
+		c.TimeZone = int16(v)
+	case AttributeClockStatus:
+		c.Status = byte(v)
+	case AttributeDaylightSavingsDeviation:
+		c.DaylightSavingsDeviation = int8(v)
+	case AttributeDaylightSavingsEnabled:
+		c.DaylightSavingsEnabled = v != 0
+	case AttributeClockBase:
+		c.ClockBase = byte(v)
+	default:
+		return ErrUnknownAttribute
+	}
+
+	return nil
+}
+
+// ClassID implements Object.
+func (p *ProfileGeneric) ClassID() ClassID { return ClassProfileGeneric }
+
+// LogicalName implements Object.
+func (p *ProfileGeneric) LogicalName() iec62056.Obis { return p.Name }
+
+// SetAttribute implements Object. Setting the buffer will append to it, so
+// buffers read in several parts are joined.
+func (p *ProfileGeneric) SetAttribute(attribute int8, d Data) error {
+	switch attribute {
+	case AttributeLogicalName:
+		return setLogicalName(&p.Name, d)
+	case AttributeBuffer:
+		if d.Type != TypeArray {
+			return ErrUnexpectedType
+		}
+
+		p.Buffer = append(p.Buffer, d.Elements()...)
+		return nil
+	case AttributeCaptureObjects:
+		objects, err := DecodeCaptureObjects(d)
+		if err != nil {
+			return err
+		}
+
+		p.CaptureObjects = objects
+		return nil
+	case AttributeSortObject:
+		// Not used.
+		return nil
+	}
+
+	v, ok := d.Int()
+	if !ok {
+		return ErrUnexpectedType
+	}
+
+	switch attribute {
+	case AttributeCapturePeriod:
+		p.CapturePeriod = uint32(v)
+	case AttributeSortMethod:
+		p.SortMethod = byte(v)
+	case AttributeEntriesInUse:
+		p.EntriesInUse = uint32(v)
+	case AttributeProfileEntries:
+		p.ProfileEntries = uint32(v)
+	default:
+		return ErrUnknownAttribute
+	}
+
+	return nil
+}
+
+// DecodeCaptureObjects will decode the capture_objects attribute of a Profile
+// Generic.
+func DecodeCaptureObjects(d Data) ([]CaptureObject, error) {
+	if d.Type != TypeArray {
+		return nil, ErrUnexpectedType
+	}
+
+	var objects []CaptureObject
+	for _, e := range d.Elements() {
+		fields := e.Elements()
+		if len(fields) != 4 {
+			return nil, ErrUnexpectedType
+		}
+
+		class, ok1 := fields[0].Int()
+		attribute, ok2 := fields[2].Int()
+		index, ok3 := fields[3].Int()
+		if !ok1 || !ok2 || !ok3 {
+			return nil, ErrUnexpectedType
+		}
+
+		name, err := DecodeLogicalName(fields[1])
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, CaptureObject{
+			Class:     ClassID(class),
+			Name:      name,
+			Attribute: int8(attribute),
+			DataIndex: uint16(index),
+		})
+	}
+
+	return objects, nil
+}
+
+// Records will decode the buffer. The time of each record is taken from a
+// captured Clock; if a row leaves out the time, it is derived from the
+// previous row and the capture period. Numeric values are scaled using the
+// scaler_unit of registers found in objects, which may be nil.
+func (p *ProfileGeneric) Records(objects Objects) []Record {
+	var records []Record
+	var last time.Time
+
+	for _, row := range p.Buffer {
+		record := Record{
+			Values: make(iec62056.ValueCollection),
+		}
+
+		for i, column := range row.Elements() {
+			if i >= len(p.CaptureObjects) {
+				break
+			}
+
+			c := p.CaptureObjects[i]
+
+			if c.Class == ClassClock && c.Attribute == AttributeTime {
+				dt, ok := column.DateTime()
+				if ok {
+					record.Time, _ = dt.Time()
+				}
+
+				continue
+			}
+
+			v, ok := column.Float()
+			if !ok || c.Attribute != AttributeValue {
+				continue
+			}
+
+			value := kamstrup.Value{Value: v}
+			if su, found := objects.scalerUnit(c.Name); found {
+				value.Value = su.Apply(v)
+				value.Unit = su.Unit.Kamstrup()
+			}
+
+			record.Values[c.Name] = value
+		}
+
+		if record.Time.IsZero() && !last.IsZero() && p.CapturePeriod > 0 {
+			record.Time = last.Add(time.Duration(p.CapturePeriod) * time.Second)
+		}
+		last = record.Time
+
+		records = append(records, record)
+	}
+
+	return records
+}
+
+// Add will add objects to the collection, replacing objects with the same
+// logical name.
+func (o Objects) Add(objects ...Object) {
+	for _, object := range objects {
+		o[object.LogicalName()] = object
+	}
+}
+
+// Values will return the scaled values of all registers and numeric data
+// objects.
+func (o Objects) Values() iec62056.ValueCollection {
+	c := make(iec62056.ValueCollection)
+
+	for name, object := range o {
+		switch v := object.(type) {
+		case *Register:
+			if value, ok := v.Reading(); ok {
+				c[name] = value
+			}
+		case *ExtendedRegister:
+			if value, ok := v.Reading(); ok {
+				c[name] = value
+			}
+		case *DataObject:
+			if value, ok := v.Value.Float(); ok {
+				c[name] = kamstrup.Value{Value: value}
+			}
+		}
+	}
+
+	return c
+}
+
+// scalerUnit will return the scaler_unit of a register in the collection.
+func (o Objects) scalerUnit(name iec62056.Obis) (ScalerUnit, bool) {
+	switch v := o[name].(type) {
+	case *Register:
+		return v.ScalerUnit, true
+	case *ExtendedRegister:
+		return v.ScalerUnit, true
+	}
+
+	return ScalerUnit{}, false
+}
@@ -0,0 +1,159 @@
+package dlms
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abrander/gometer/iec62056"
+	"github.com/abrander/gometer/kamstrup"
+)
+
+func logicalName(code string) Data {
+	raw, _ := iec62056.NewObis(code).Bytes()
+
+	return Data{Type: TypeOctetString, Value: raw}
+}
+
+func structure(elements ...Data) Data {
+	return Data{Type: TypeStructure, Value: elements}
+}
+
+func array(elements ...Data) Data {
+	return Data{Type: TypeArray, Value: elements}
+}
+
+func TestRegister(t *testing.T) {
+	o := NewObject(ClassRegister, iec62056.Obis{})
+
+	err := o.SetAttribute(AttributeLogicalName, logicalName("1-0:1.8.0.255"))
+	if err != nil {
+		t.Fatalf("SetAttribute(1) returned %s", err)
+	}
+
+	if o.LogicalName() != iec62056.ActiveEnergyImport {
+		t.Errorf("LogicalName() returned %s", o.LogicalName())
+	}
+
+	o.SetAttribute(AttributeValue, Data{Type: TypeDoubleLongUnsigned, Value: uint64(1234567)})
+	err = o.SetAttribute(AttributeScalerUnit, structure(
+		Data{Type: TypeInteger, Value: int64(1)},
+		Data{Type: TypeEnum, Value: uint64(UnitWattHour)},
+	))
+	if err != nil {
+		t.Fatalf("SetAttribute(3) returned %s", err)
+	}
+
+	err = o.SetAttribute(AttributeScalerUnit, Data{Type: TypeNull})
+	if err != ErrUnexpectedType {
+		t.Errorf("SetAttribute(3) with null-data returned %v", err)
+	}
+
+	err = o.SetAttribute(9, Data{Type: TypeNull})
+	if err != ErrUnknownAttribute {
+		t.Errorf("SetAttribute(9) returned %v", err)
+	}
+
+	value, ok := o.(*Register).Reading()
+	if !ok || value.Value != 12345670 || value.Unit != kamstrup.UnitWh {
+		t.Errorf("Reading() returned %s, %v", value, ok)
+	}
+
+	objects := make(Objects)
+	objects.Add(o)
+
+	values := objects.Values()
+	if values[iec62056.ActiveEnergyImport] != value {
+		t.Errorf("Values() returned %v", values)
+	}
+}
+
+func TestExtendedRegister(t *testing.T) {
+	o := NewObject(ClassExtendedRegister, iec62056.NewObis("1-0:1.6.0"))
+	if o.ClassID() != ClassExtendedRegister {
+		t.Errorf("ClassID() returned %s", o.ClassID())
+	}
+
+	o.SetAttribute(AttributeValue, Data{Type: TypeLongUnsigned, Value: uint64(2500)})
+	o.SetAttribute(AttributeScalerUnit, structure(
+		Data{Type: TypeInteger, Value: int64(-3)},
+		Data{Type: TypeEnum, Value: uint64(UnitWatt)},
+	))
+
+	dt := NewDateTime(time.Date(2021, 1, 12, 17, 0, 0, 0, time.UTC))
+	err := o.SetAttribute(AttributeCaptureTime, Data{Type: TypeOctetString, Value: dt.Bytes()})
+	if err != nil {
+		t.Fatalf("SetAttribute(5) returned %s", err)
+	}
+
+	r := o.(*ExtendedRegister)
+	if r.CaptureTime != dt {
+		t.Errorf("Wrong capture time %s", r.CaptureTime)
+	}
+
+	value, ok := r.Reading()
+	if !ok || value.Value != 2.5 || value.Unit != kamstrup.UnitW {
+		t.Errorf("Reading() returned %s, %v", value, ok)
+	}
+}
+
+func TestProfileGeneric(t *testing.T) {
+	energy := NewObject(ClassRegister, iec62056.ActiveEnergyImport)
+	energy.SetAttribute(AttributeScalerUnit, structure(
+		Data{Type: TypeInteger, Value: int64(3)},
+		Data{Type: TypeEnum, Value: uint64(UnitWattHour)},
+	))
+
+	objects := make(Objects)
+	objects.Add(energy)
+
+	p := NewObject(ClassProfileGeneric, iec62056.NewObis("1-0:99.1.0")).(*ProfileGeneric)
+
+	err := p.SetAttribute(AttributeCaptureObjects, array(
+		structure(
+			Data{Type: TypeLongUnsigned, Value: uint64(ClassClock)},
+			logicalName("0-0:1.0.0.255"),
+			Data{Type: TypeInteger, Value: int64(2)},
+			Data{Type: TypeLongUnsigned, Value: uint64(0)},
+		),
+		structure(
+			Data{Type: TypeLongUnsigned, Value: uint64(ClassRegister)},
+			logicalName("1-0:1.8.0.255"),
+			Data{Type: TypeInteger, Value: int64(2)},
+			Data{Type: TypeLongUnsigned, Value: uint64(0)},
+		),
+	))
+	if err != nil {
+		t.Fatalf("SetAttribute(3) returned %s", err)
+	}
+
+	p.SetAttribute(AttributeCapturePeriod, Data{Type: TypeDoubleLongUnsigned, Value: uint64(3600)})
+
+	start := time.Date(2021, 1, 12, 0, 0, 0, 0, time.UTC)
+	err = p.SetAttribute(AttributeBuffer, array(
+		structure(
+			Data{Type: TypeOctetString, Value: NewDateTime(start).Bytes()},
+			Data{Type: TypeDoubleLongUnsigned, Value: uint64(100)},
+		),
+		structure(
+			Data{Type: TypeNull},
+			Data{Type: TypeDoubleLongUnsigned, Value: uint64(101)},
+		),
+	))
+	if err != nil {
+		t.Fatalf("SetAttribute(2) returned %s", err)
+	}
+
+	records := p.Records(objects)
+	if len(records) != 2 {
+		t.Fatalf("Records() returned %d records, expected 2", len(records))
+	}
+
+	if !records[0].Time.Equal(start) || !records[1].Time.Equal(start.Add(time.Hour)) {
+		t.Errorf("Wrong record times %s and %s", records[0].Time, records[1].Time)
+	}
+
+	second := records[1].Values[iec62056.ActiveEnergyImport]
+	if second.Value != 101000 || second.Unit != kamstrup.UnitWh {
+		t.Errorf("Wrong value in second record: %s", second)
+	}
+}
@@ -0,0 +1,155 @@
+package dlms
+
+import (
+	"fmt"
+
+	"github.com/abrander/gometer/kamstrup"
+)
+
+type (
+	// Unit is a unit as enumerated by the DLMS Blue Book. It is used in
+	// scaler_unit and by SML.
+	Unit byte
+)
+
+// Known units.
+const (
+	UnitYear                 = Unit(1)
+	UnitMonth                = Unit(2)
+	UnitWeek                 = Unit(3)
+	UnitDay                  = Unit(4)
+	UnitHour                 = Unit(5)
+	UnitMinute               = Unit(6)
+	UnitSecond               = Unit(7)
+	UnitDegree               = Unit(8)
+	UnitCelsius              = Unit(9)
+	UnitCurrency             = Unit(10)
+	UnitMetre                = Unit(11)
+	UnitMetrePerSecond       = Unit(12)
+	UnitCubicMetre           = Unit(13)
+	UnitCubicMetreCorrected  = Unit(14)
+	UnitCubicMetrePerHour    = Unit(15)
+	UnitCubicMetrePerHourCor = Unit(16)
+	UnitCubicMetrePerDay     = Unit(17)
+	UnitCubicMetrePerDayCor  = Unit(18)
+	UnitLitre                = Unit(19)
+	UnitKilogram             = Unit(20)
+	UnitNewton               = Unit(21)
+	UnitNewtonMetre          = Unit(22)
+	UnitPascal               = Unit(23)
+	UnitBar                  = Unit(24)
+	UnitJoule                = Unit(25)
+	UnitJoulePerHour         = Unit(26)
+	UnitWatt                 = Unit(27)
+	UnitVoltAmpere           = Unit(28)
+	UnitVar                  = Unit(29)
+	UnitWattHour             = Unit(30)
+	UnitVoltAmpereHour       = Unit(31)
+	UnitVarHour              = Unit(32)
+	UnitAmpere               = Unit(33)
+	UnitCoulomb              = Unit(34)
+	UnitVolt                 = Unit(35)
+	UnitVoltPerMetre         = Unit(36)
+	UnitFarad                = Unit(37)
+	UnitOhm                  = Unit(38)
+	UnitHertz                = Unit(44)
+	UnitKelvin               = Unit(52)
+	UnitPercent              = Unit(56)
+	UnitAmpereHour           = Unit(57)
+	UnitWattHourPerCubic     = Unit(60)
+	UnitOther                = Unit(254)
+	UnitCount                = Unit(255)
+)
+
+var (
+	unitString = map[Unit]string{
+		UnitYear:                 "a",
+		UnitMonth:                "mo",
+		UnitWeek:                 "wk",
+		UnitDay:                  "d",
+		UnitHour:                 "h",
+		UnitMinute:               "min",
+		UnitSecond:               "s",
+		UnitDegree:               "°",
+		UnitCelsius:              "°C",
+		UnitCurrency:             "currency",
+		UnitMetre:                "m",
+		UnitMetrePerSecond:       "m/s",
+		UnitCubicMetre:           "m³",
+		UnitCubicMetreCorrected:  "m³",
+		UnitCubicMetrePerHour:    "m³/h",
+		UnitCubicMetrePerHourCor: "m³/h",
+		UnitCubicMetrePerDay:     "m³/d",
+		UnitCubicMetrePerDayCor:  "m³/d",
+		UnitLitre:                "l",
+		UnitKilogram:             "kg",
+		UnitNewton:               "N",
+		UnitNewtonMetre:          "Nm",
+		UnitPascal:               "Pa",
+		UnitBar:                  "bar",
+		UnitJoule:                "J",
+		UnitJoulePerHour:         "J/h",
+		UnitWatt:                 "W",
+		UnitVoltAmpere:           "VA",
+		UnitVar:                  "var",
+		UnitWattHour:             "Wh",
+		UnitVoltAmpereHour:       "VAh",
+		UnitVarHour:              "varh",
+		UnitAmpere:               "A",
+		UnitCoulomb:              "C",
+		UnitVolt:                 "V",
+		UnitVoltPerMetre:         "V/m",
+		UnitFarad:                "F",
+		UnitOhm:                  "Ω",
+		UnitHertz:                "Hz",
+		UnitKelvin:               "K",
+		UnitPercent:              "%",
+		UnitAmpereHour:           "Ah",
+		UnitWattHourPerCubic:     "Wh/m³",
+		UnitOther:                "other",
+		UnitCount:                "",
+	}
+
+	// kamstrupUnits maps DLMS units to the units used for values throughout
+	// gometer.
+	kamstrupUnits = map[Unit]kamstrup.Unit{
+		UnitDay:                  kamstrup.UnitDay,
+		UnitHour:                 kamstrup.UnitHour,
+		UnitMinute:               kamstrup.UnitMinute,
+		UnitSecond:               kamstrup.UnitSecond,
+		UnitCelsius:              kamstrup.UnitCelsius,
+		UnitCubicMetre:           kamstrup.UnitCubicMetre,
+		UnitCubicMetreCorrected:  kamstrup.UnitCubicMetre,
+		UnitCubicMetrePerHour:    kamstrup.UnitCubicMetrePerHour,
+		UnitCubicMetrePerHourCor: kamstrup.UnitCubicMetrePerHour,
+		UnitLitre:                kamstrup.UnitLitre,
+		UnitBar:                  kamstrup.UnitBar,
+		UnitJoule:                kamstrup.UnitJ,
+		UnitWatt:                 kamstrup.UnitW,
+		UnitVoltAmpere:           kamstrup.UnitVA,
+		UnitVar:                  kamstrup.UnitVar,
+		UnitWattHour:             kamstrup.UnitWh,
+		UnitVoltAmpereHour:       kamstrup.UnitVAh,
+		UnitVarHour:              kamstrup.UnitVarh,
+		UnitAmpere:               kamstrup.UnitA,
+		UnitVolt:                 kamstrup.UnitV,
+		UnitKelvin:               kamstrup.UnitKelvin,
+		UnitHertz:                kamstrup.UnitHz,
+	}
+)
+
+// String will return the unit symbol.
+func (u Unit) String() string {
+	s, found := unitString[u]
+	if !found {
+		return fmt.Sprintf("unit-%d", byte(u))
+	}
+
+	return s
+}
+
+// Kamstrup will return the equivalent kamstrup.Unit. Units without an
+// equivalent are returned as kamstrup.UnitNone.
+func (u Unit) Kamstrup() kamstrup.Unit {
+	return kamstrupUnits[u]
+}
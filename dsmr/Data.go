@@ -0,0 +1,445 @@
+package dsmr
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abrander/gometer/iec62056"
+	"github.com/abrander/gometer/kamstrup"
+)
+
+type (
+	// Version is the P1 protocol version of a telegram.
+	Version string
+
+	// Data is the decoded contents of a P1 telegram. Values not present in
+	// the telegram are left as the zero value.
+	Data struct {
+		Version   Version
+		Timestamp time.Time
+
+		// VersionCode is the raw version as sent by the meter, "50" for DSMR
+		// 5.0, "50217" for e-MUCS 1.7.1.
+		VersionCode string
+
+		EquipmentID string
+
+		// Electricity delivered to and returned by the client in kWh. Index 0
+		// is tariff 1, index 1 is tariff 2.
+		EnergyDelivered [2]float64
+		EnergyReturned  [2]float64
+
+		// TariffIndicator is the currently active tariff.
+		TariffIndicator int
+
+		// Actual power delivered to and returned by the client in kW.
+		PowerDelivered float64
+		PowerReturned  float64
+
+		PowerFailures     int
+		LongPowerFailures int
+		PowerFailureLog   []PowerFailure
+
+		// Per-phase values. Index 0 is L1.
+		VoltageSags          [3]int
+		VoltageSwells        [3]int
+		Voltage              [3]float64 // V
+		Current              [3]float64 // A
+		PowerDeliveredPhase  [3]float64 // kW
+		PowerReturnedPhase   [3]float64 // kW
+		CurrentAverageDemand float64    // kW, e-MUCS only
+		MaximumDemandMonth   Event      // kW, e-MUCS only
+		BreakerState         int        // e-MUCS only
+		LimiterThreshold     float64    // kW, e-MUCS only
+		FuseThreshold        float64    // A, e-MUCS only
+
+		TextMessage string
+		MessageCode string
+
+		// MBus holds devices connected to the meter, keyed by channel.
+		MBus map[int]*MBusDevice
+
+		// Other holds objects not decoded into any of the fields above,
+		// including known objects with values that could not be parsed.
+		Other map[iec62056.Obis][]string
+	}
+
+	// PowerFailure is a single entry in the power failure event log.
+	PowerFailure struct {
+		End      time.Time
+		Duration time.Duration
+	}
+
+	// Event is a value with the time it was captured.
+	Event struct {
+		Time  time.Time
+		Value kamstrup.Value
+	}
+
+	// MBusDevice is a device, usually a gas or water meter, connected to the
+	// electricity meter using M-Bus.
+	MBusDevice struct {
+		Channel       int
+		DeviceType    int
+		EquipmentID   string
+		ValvePosition int
+
+		// Value is the last reading from the device, and the time it was
+		// captured by the device.
+		Value Event
+	}
+)
+
+// Known protocol versions.
+const (
+	VersionUnknown = Version("")
+	Version22      = Version("2.2") // DSMR 2.2 and 3.0, no version object
+	Version40      = Version("4.0")
+	Version42      = Version("4.2")
+	Version50      = Version("5.0")
+	VersionEMUCS   = Version("e-MUCS") // Belgian e-MUCS H
+)
+
+var (
+	// Location is used for timestamps without a daylight saving time flag,
+	// as used by DSMR 2.2.
+	Location = time.FixedZone("CET", 3600)
+
+	// ErrMalformedTimestamp will be returned if a timestamp cannot be parsed.
+	ErrMalformedTimestamp = errors.New("malformed timestamp")
+
+	// ErrMalformedObject will be returned if an object does not have the
+	// expected number of values.
+	ErrMalformedObject = errors.New("malformed object")
+
+	winter = time.FixedZone("CET", 3600)
+	summer = time.FixedZone("CEST", 7200)
+)
+
+// Decode will decode all known objects of the telegram. Objects with values
+// that cannot be parsed are left in Other, and lines that are not data lines
+// are skipped, so a single bad object will not hide the rest of the telegram.
+func (t *Telegram) Decode() (*Data, error) {
+	d := &Data{
+		MBus:  make(map[int]*MBusDevice),
+		Other: make(map[iec62056.Obis][]string),
+	}
+
+	for _, line := range t.Lines() {
+		obis, values, err := iec62056.ParseDataLine(line)
+		if err != nil {
+			continue
+		}
+
+		err = d.decode(obis, values)
+		if err != nil {
+			d.Other[obis] = values
+		}
+	}
+
+	switch {
+	case d.Version != VersionUnknown:
+	case !t.HasChecksum:
+		d.Version = Version22
+	}
+
+	return d, nil
+}
+
+func (d *Data) decode(obis iec62056.Obis, values []string) error {
+	if len(values) == 0 {
+		return ErrMalformedObject
+	}
+
+	value := values[len(values)-1]
+
+	// M-Bus devices on channel 1-4.
+	if obis.A == "0" && obis.B != "0" && obis.B != "" && (obis.C == "24" || obis.C == "96") {
+		channel, err := strconv.Atoi(obis.B)
+		if err != nil {
+			return err
+		}
+
+		handled, err := d.device(channel).decode(obis, values)
+		if !handled {
+			d.Other[obis] = values
+		}
+
+		return err
+	}
+
+	var err error
+	switch key := obis.C + "." + obis.D + "." + obis.E; {
+	case obis.A == "1" && obis.B == "3" && key == "0.2.8":
+		d.VersionCode = value
+		d.Version = versionFromCode(value)
+	case key == "96.1.4":
+		d.VersionCode = value
+		d.Version = VersionEMUCS
+	case obis.A == "0" && key == "1.0.0":
+		d.Timestamp, err = parseTimestamp(value)
+	case key == "96.1.1":
+		d.EquipmentID = decodeHex(value)
+	case key == "1.8.1":
+		d.EnergyDelivered[0], err = parseFloat(value)
+	case key == "1.8.2":
+		d.EnergyDelivered[1], err = parseFloat(value)
+	case key == "2.8.1":
+		d.EnergyReturned[0], err = parseFloat(value)
+	case key == "2.8.2":
+		d.EnergyReturned[1], err = parseFloat(value)
+	case key == "96.14.0":
+		d.TariffIndicator, err = strconv.Atoi(value)
+	case key == "1.7.0":
+		d.PowerDelivered, err = parseFloat(value)
+	case key == "2.7.0":
+		d.PowerReturned, err = parseFloat(value)
+	case key == "96.7.21":
+		d.PowerFailures, err = strconv.Atoi(value)
+	case key == "96.7.9":
+		d.LongPowerFailures, err = strconv.Atoi(value)
+	case key == "99.97.0":
+		d.PowerFailureLog, err = parsePowerFailureLog(values)
+	case key == "1.4.0":
+		d.CurrentAverageDemand, err = parseFloat(value)
+	case key == "1.6.0":
+		d.MaximumDemandMonth, err = parseEvent(values)
+	case key == "96.3.10":
+		d.BreakerState, err = strconv.Atoi(value)
+	case key == "17.0.0":
+		d.LimiterThreshold, err = parseFloat(value)
+	case key == "31.4.0":
+		d.FuseThreshold, err = parseFloat(value)
+	case key == "96.13.0":
+		d.TextMessage = decodeHex(value)
+	case key == "96.13.1":
+		d.MessageCode = decodeHex(value)
+	default:
+		phase, found := phaseObjects[key]
+		if !found {
+			d.Other[obis] = values
+			break
+		}
+
+		switch {
+		case phase.sags:
+			d.VoltageSags[phase.phase], err = strconv.Atoi(value)
+		case phase.swells:
+			d.VoltageSwells[phase.phase], err = strconv.Atoi(value)
+		default:
+			*phase.field(d), err = parseFloat(value)
+		}
+	}
+
+	return err
+}
+
+// device will return the M-Bus device on channel, creating it if needed.
+func (d *Data) device(channel int) *MBusDevice {
+	device, found := d.MBus[channel]
+	if !found {
+		device = &MBusDevice{Channel: channel}
+		d.MBus[channel] = device
+	}
+
+	return device
+}
+
+func (m *MBusDevice) decode(obis iec62056.Obis, values []string) (bool, error) {
+	var err error
+
+	switch key := obis.C + "." + obis.D + "." + obis.E; {
+	case key == "24.1.0":
+		m.DeviceType, err = strconv.Atoi(values[0])
+	case key == "96.1.0" || key == "96.1.1":
+		m.EquipmentID = decodeHex(values[0])
+	case key == "24.4.0":
+		m.ValvePosition, err = strconv.Atoi(values[0])
+	case strings.HasPrefix(key, "24.2."):
+		m.Value, err = parseEvent(values)
+	case key == "24.3.0":
+		// DSMR 2.2: (timestamp)(00)(60)(1)(0-1:24.2.1)(unit)(value)
+		if len(values) != 7 {
+			return true, ErrMalformedObject
+		}
+
+		m.Value.Time, err = parseTimestamp(values[0])
+		if err != nil {
+			return true, err
+		}
+
+		m.Value.Value, err = iec62056.ParseValue(values[6] + "*" + values[5])
+	default:
+		return false, nil
+	}
+
+	return true, err
+}
+
+// String will return the version as a human readable string.
+func (v Version) String() string {
+	switch v {
+	case VersionUnknown:
+		return "unknown"
+	case VersionEMUCS:
+		return string(v)
+	}
+
+	return "DSMR " + string(v)
+}
+
+type phaseObject struct {
+	phase  int
+	sags   bool
+	swells bool
+	field  func(d *Data) *float64
+}
+
+var phaseObjects = map[string]phaseObject{}
+
+func init() {
+	for phase := 0; phase < 3; phase++ {
+		p := phase
+		c := 20*phase + 20
+
+		phaseObjects[fmt.Sprintf("%d.32.0", c+12)] = phaseObject{phase: p, sags: true}
+		phaseObjects[fmt.Sprintf("%d.36.0", c+12)] = phaseObject{phase: p, swells: true}
+		phaseObjects[fmt.Sprintf("%d.7.0", c+12)] = phaseObject{phase: p, field: func(d *Data) *float64 { return &d.Voltage[p] }}
+		phaseObjects[fmt.Sprintf("%d.7.0", c+11)] = phaseObject{phase: p, field: func(d *Data) *float64 { return &d.Current[p] }}
+		phaseObjects[fmt.Sprintf("%d.7.0", c+1)] = phaseObject{phase: p, field: func(d *Data) *float64 { return &d.PowerDeliveredPhase[p] }}
+		phaseObjects[fmt.Sprintf("%d.7.0", c+2)] = phaseObject{phase: p, field: func(d *Data) *float64 { return &d.PowerReturnedPhase[p] }}
+	}
+}
+
+func versionFromCode(code string) Version {
+	switch code {
+	case "40":
+		return Version40
+	case "42":
+		return Version42
+	case "50":
+		return Version50
+	}
+
+	if len(code) == 2 {
+		return Version(code[:1] + "." + code[1:])
+	}
+
+	return VersionUnknown
+}
+
+// parseTimestamp will parse a timestamp in the format YYMMDDhhmmssX, where X
+// is "S" for summer time or "W" for winter time. DSMR 2.2 leaves out X.
+func parseTimestamp(in string) (time.Time, error) {
+	loc := Location
+
+	if len(in) == 13 {
+		switch in[12] {
+		case 'S':
+			loc = summer
+		case 'W':
+			loc = winter
+		default:
+			return time.Time{}, ErrMalformedTimestamp
+		}
+
+		in = in[:12]
+	}
+
+	if len(in) != 12 {
+		return time.Time{}, ErrMalformedTimestamp
+	}
+
+	t, err := time.ParseInLocation("060102150405", in, loc)
+	if err != nil {
+		return time.Time{}, ErrMalformedTimestamp
+	}
+
+	return t, nil
+}
+
+// parseFloat will parse a value, ignoring the unit.
+func parseFloat(in string) (float64, error) {
+	v, err := iec62056.ParseValue(in)
+
+	return v.Value, err
+}
+
+// parseEvent will parse a (timestamp)(value) pair.
+func parseEvent(values []string) (Event, error) {
+	if len(values) != 2 {
+		return Event{}, ErrMalformedObject
+	}
+
+	t, err := parseTimestamp(values[0])
+	if err != nil {
+		return Event{}, err
+	}
+
+	v, err := iec62056.ParseValue(values[1])
+	if err != nil {
+		return Event{}, err
+	}
+
+	return Event{Time: t, Value: v}, nil
+}
+
+// parsePowerFailureLog will parse the power failure event log:
+// (count)(0-0:96.7.19)(end)(duration*s)... Some meters send an empty log as
+// a single empty value.
+func parsePowerFailureLog(values []string) ([]PowerFailure, error) {
+	if len(values) == 1 && values[0] == "" {
+		return nil, nil
+	}
+
+	count, err := strconv.Atoi(values[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if count == 0 {
+		return nil, nil
+	}
+
+	if len(values) != 2+2*count {
+		return nil, ErrMalformedObject
+	}
+
+	log := make([]PowerFailure, count)
+	for i := range log {
+		log[i].End, err = parseTimestamp(values[2+2*i])
+		if err != nil {
+			return nil, err
+		}
+
+		seconds, err := iec62056.ParseValue(values[3+2*i])
+		if err != nil {
+			return nil, err
+		}
+
+		log[i].Duration = time.Duration(seconds.Value) * time.Second
+	}
+
+	return log, nil
+}
+
+// decodeHex will decode a hex encoded string as used for equipment identifiers
+// and text messages. If the string is not hex encoded, it is returned as is.
+func decodeHex(in string) string {
+	decoded, err := hex.DecodeString(in)
+	if err != nil {
+		return in
+	}
+
+	for _, b := range decoded {
+		if b < 0x20 || b > 0x7e {
+			return in
+		}
+	}
+
+	return string(decoded)
+}
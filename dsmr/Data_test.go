@@ -0,0 +1,148 @@
+package dsmr
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abrander/gometer/iec62056"
+)
+
+// Example telegram from the DSMR 2.2 P1 companion standard.
+var exampleTelegram22 = strings.Replace(`/ISk5\2MT382-1004
+
+0-0:96.1.1(00000000000000)
+1-0:1.8.1(00001.001*kWh)
+1-0:1.8.2(00001.001*kWh)
+1-0:2.8.1(00001.001*kWh)
+1-0:2.8.2(00001.001*kWh)
+0-0:96.14.0(0001)
+1-0:1.7.0(0001.01*kW)
+1-0:2.7.0(0000.00*kW)
+0-0:17.0.0(0999.00*kW)
+0-0:96.3.10(1)
+0-0:96.13.1()
+0-0:96.13.0()
+0-1:24.1.0(3)
+0-1:96.1.0(000000000000)
+0-1:24.3.0(161107190000)(00)(60)(1)(0-1:24.2.1)(m3)
+(00001.001)
+0-1:24.4.0(1)
+!
+`, "\n", "\r\n", -1)
+
+func TestDecode(t *testing.T) {
+	telegram, err := ParseTelegram([]byte(exampleTelegram))
+	if err != nil {
+		t.Fatalf("ParseTelegram() failed: %s", err.Error())
+	}
+
+	d, err := telegram.Decode()
+	if err != nil {
+		t.Fatalf("Decode() failed: %s", err.Error())
+	}
+
+	if d.Version != Version50 {
+		t.Errorf("Wrong version: %s", d.Version)
+	}
+
+	if !d.Timestamp.Equal(time.Date(2010, 12, 9, 11, 30, 20, 0, time.FixedZone("", 3600))) {
+		t.Errorf("Wrong timestamp: %s", d.Timestamp)
+	}
+
+	if d.EquipmentID != "K8EG004046395507" {
+		t.Errorf("Wrong equipment ID: %s", d.EquipmentID)
+	}
+
+	if d.EnergyDelivered[1] != 123456.789 || d.TariffIndicator != 2 || d.PowerDelivered != 1.193 {
+		t.Errorf("Wrong electricity values: %+v", d)
+	}
+
+	if d.PowerFailures != 4 || d.LongPowerFailures != 2 || len(d.PowerFailureLog) != 2 {
+		t.Fatalf("Wrong power failures: %+v", d)
+	}
+
+	if d.PowerFailureLog[1].Duration != 301*time.Second || d.PowerFailureLog[1].End.Day() != 8 {
+		t.Errorf("Wrong power failure log: %+v", d.PowerFailureLog)
+	}
+
+	if d.VoltageSwells[1] != 3 || d.Voltage[2] != 220.3 || d.Current[0] != 1 || d.PowerReturnedPhase[2] != 6.666 {
+		t.Errorf("Wrong per-phase values: %+v", d)
+	}
+
+	if !strings.HasPrefix(d.TextMessage, "0123456789:;<=>?") {
+		t.Errorf("Wrong text message: %s", d.TextMessage)
+	}
+
+	gas := d.MBus[1]
+	if gas == nil || gas.DeviceType != 3 || gas.EquipmentID != "2222ABCD123456789" || gas.Value.Value.Value != 12785.123 {
+		t.Fatalf("Wrong M-Bus device: %+v", gas)
+	}
+
+	if gas.Value.Time.Hour() != 11 || gas.Value.Value.Unit.String() != "m³" {
+		t.Errorf("Wrong gas value: %+v", gas.Value)
+	}
+
+	if len(d.Other) != 0 {
+		t.Errorf("Objects not decoded: %+v", d.Other)
+	}
+}
+
+func TestDecode22(t *testing.T) {
+	telegram, err := ParseTelegram([]byte(exampleTelegram22))
+	if err != nil {
+		t.Fatalf("ParseTelegram() failed: %s", err.Error())
+	}
+
+	d, err := telegram.Decode()
+	if err != nil {
+		t.Fatalf("Decode() failed: %s", err.Error())
+	}
+
+	if d.Version != Version22 || d.LimiterThreshold != 999 || d.BreakerState != 1 {
+		t.Errorf("Wrong values: %+v", d)
+	}
+
+	gas := d.MBus[1]
+	if gas == nil || gas.Value.Value.Value != 1.001 || gas.Value.Time.Year() != 2016 || gas.ValvePosition != 1 {
+		t.Errorf("Wrong M-Bus device: %+v", gas)
+	}
+}
+
+func TestDecodeEmptyObjects(t *testing.T) {
+	raw := strings.Replace(`/ISk5\2MT382-1000
+
+1-3:0.2.8(50)
+0-0:96.14.0()
+1-0:1.7.0(01.193*kW)
+1-0:21.7.0()
+0-0:96.7.21(00000)
+1-0:99.97.0()
+0-1:24.2.1()()
+!
+`, "\n", "\r\n", -1)
+
+	telegram, err := ParseTelegram([]byte(raw))
+	if err != nil {
+		t.Fatalf("ParseTelegram() failed: %s", err.Error())
+	}
+
+	d, err := telegram.Decode()
+	if err != nil {
+		t.Fatalf("Decode() failed: %s", err.Error())
+	}
+
+	if d.Version != Version50 || d.PowerDelivered != 1.193 || d.PowerFailureLog != nil {
+		t.Errorf("Wrong values: %+v", d)
+	}
+
+	for _, obis := range []string{"0-0:96.14.0", "1-0:21.7.0", "0-1:24.2.1"} {
+		if _, found := d.Other[iec62056.NewObis(obis)]; !found {
+			t.Errorf("%s not in Other: %+v", obis, d.Other)
+		}
+	}
+
+	if _, found := d.Other[iec62056.NewObis("1-0:99.97.0")]; found {
+		t.Errorf("Empty power failure log not decoded: %+v", d.Other)
+	}
+}
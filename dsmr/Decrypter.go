@@ -0,0 +1,287 @@
+package dsmr
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+type (
+	// Decrypter will decrypt P1 telegrams wrapped in a DLMS
+	// general-glo-ciphering envelope, as pushed by Luxembourg (Smarty) and
+	// some Austrian meters.
+	Decrypter struct {
+		block   cipher.Block
+		authKey []byte
+	}
+
+	// Envelope is a parsed general-glo-ciphering envelope.
+	Envelope struct {
+		SystemTitle     []byte
+		SecurityControl byte
+		FrameCounter    uint32
+		Ciphertext      []byte // Includes the authentication tag if present
+	}
+)
+
+const (
+	// GeneralGloCiphering is the tag starting an encrypted frame.
+	GeneralGloCiphering = byte(0xdb)
+
+	// Bits in the security control byte.
+	SecurityAuthenticated = byte(0x10)
+	SecurityEncrypted     = byte(0x20)
+
+	// tagSize is the size of the GCM authentication tag used by DLMS.
+	tagSize = 12
+)
+
+var (
+	// SmartyAuthKey is the authentication key (AK) published for the P1 port
+	// of Luxembourg Smarty meters. It is the same for every meter, and must
+	// be passed to NewDecrypter along with the per-meter key.
+	SmartyAuthKey = []byte{0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99, 0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff}
+
+	// ErrInvalidKey will be returned if a key is not 16 bytes.
+	ErrInvalidKey = errors.New("keys must be 16 bytes (AES-128)")
+
+	// ErrNotEncrypted will be returned if a frame does not start with the
+	// general-glo-ciphering tag.
+	ErrNotEncrypted = errors.New("not a general-glo-ciphering frame")
+
+	// ErrMalformedEnvelope will be returned if the envelope is truncated or
+	// has invalid lengths.
+	ErrMalformedEnvelope = errors.New("malformed general-glo-ciphering envelope")
+
+	// ErrAuthenticationFailed will be returned if the authentication tag did
+	// not verify. This is most likely caused by a wrong key.
+	ErrAuthenticationFailed = errors.New("authentication tag did not verify")
+)
+
+// NewDecrypter will instantiate a new decrypter. key is the per-meter
+// encryption key (GUEK). authKey is the authentication key (GAK), used as the
+// additional authenticated data after the security control byte. If nil, the
+// additional data is the security control byte only, as used by meters
+// without an authentication key. Luxembourg Smarty meters use an
+// authentication key; use SmartyAuthKey for those, as frames from them will
+// fail with ErrAuthenticationFailed without it.
+func NewDecrypter(key []byte, authKey []byte) (*Decrypter, error) {
+	if len(key) != 16 || (authKey != nil && len(authKey) != 16) {
+		return nil, ErrInvalidKey
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Decrypter{
+		block:   block,
+		authKey: authKey,
+	}, nil
+}
+
+// NewEncryptedReader will initialize a new P1 reader for a meter pushing
+// encrypted telegrams. Telegrams are decrypted before they are parsed.
+func NewEncryptedReader(r io.Reader, key []byte, authKey []byte) (*Reader, error) {
+	d, err := NewDecrypter(key, authKey)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := NewReader(r)
+	reader.decrypter = d
+
+	return reader, nil
+}
+
+// ParseEnvelope will parse a complete general-glo-ciphering envelope.
+func ParseEnvelope(frame []byte) (*Envelope, error) {
+	if len(frame) < 2 || frame[0] != GeneralGloCiphering {
+		return nil, ErrNotEncrypted
+	}
+
+	titleLength := int(frame[1])
+	pos := 2 + titleLength
+	if len(frame) < pos+1 {
+		return nil, ErrMalformedEnvelope
+	}
+
+	e := &Envelope{
+		SystemTitle: frame[2:pos],
+	}
+
+	length, n, err := berLength(frame[pos:])
+	if err != nil {
+		return nil, err
+	}
+	pos += n
+
+	if length < 5 || len(frame) != pos+length {
+		return nil, ErrMalformedEnvelope
+	}
+
+	e.SecurityControl = frame[pos]
+	e.FrameCounter = binary.BigEndian.Uint32(frame[pos+1:])
+	e.Ciphertext = frame[pos+5:]
+
+	return e, nil
+}
+
+// Decrypt will decrypt a complete general-glo-ciphering frame and return the
+// plaintext telegram.
+func (d *Decrypter) Decrypt(frame []byte) ([]byte, error) {
+	e, err := ParseEnvelope(frame)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.DecryptEnvelope(e)
+}
+
+// DecryptEnvelope will decrypt and verify a parsed envelope.
+func (d *Decrypter) DecryptEnvelope(e *Envelope) ([]byte, error) {
+	// The initialization vector is the system title followed by the frame
+	// counter.
+	if len(e.SystemTitle) != 8 {
+		return nil, ErrMalformedEnvelope
+	}
+
+	iv := make([]byte, 12)
+	copy(iv, e.SystemTitle)
+	binary.BigEndian.PutUint32(iv[8:], e.FrameCounter)
+
+	// Without authentication, GCM is plain counter mode starting at counter
+	// value 2.
+	if e.SecurityControl&SecurityAuthenticated == 0 {
+		counter := make([]byte, aes.BlockSize)
+		copy(counter, iv)
+		counter[15] = 2
+
+		plaintext := make([]byte, len(e.Ciphertext))
+		cipher.NewCTR(d.block, counter).XORKeyStream(plaintext, e.Ciphertext)
+
+		return plaintext, nil
+	}
+
+	gcm, err := cipher.NewGCMWithTagSize(d.block, tagSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(e.Ciphertext) < tagSize {
+		return nil, ErrMalformedEnvelope
+	}
+
+	aad := append([]byte{e.SecurityControl}, d.authKey...)
+
+	if e.SecurityControl&SecurityEncrypted == 0 {
+		// Authenticated only: The data is sent in the clear and is part
+		// of the additional data.
+		data := e.Ciphertext[:len(e.Ciphertext)-tagSize]
+
+		_, err = gcm.Open(nil, iv, e.Ciphertext[len(data):], append(aad, data...))
+		if err != nil {
+			return nil, ErrAuthenticationFailed
+		}
+
+		return data, nil
+	}
+
+	plaintext, err := gcm.Open(nil, iv, e.Ciphertext, aad)
+	if err != nil {
+		return nil, ErrAuthenticationFailed
+	}
+
+	return plaintext, nil
+}
+
+// readEncrypted will read the next general-glo-ciphering frame from the
+// stream. Anything before the frame is discarded.
+func (r *Reader) readEncrypted() ([]byte, error) {
+	for {
+		b, err := r.r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		if b == GeneralGloCiphering {
+			break
+		}
+	}
+
+	frame := []byte{GeneralGloCiphering}
+
+	titleLength, err := r.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	if titleLength != 8 {
+		return nil, ErrMalformedEnvelope
+	}
+
+	frame = append(frame, titleLength)
+	frame, err = r.readN(frame, int(titleLength)+1)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read the rest of a multi-byte length.
+	first := frame[len(frame)-1]
+	if first&0x80 > 0 {
+		frame, err = r.readN(frame, int(first&0x7f))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	length, _, err := berLength(frame[2+titleLength:])
+	if err != nil {
+		return nil, err
+	}
+
+	if length > MaxTelegramSize {
+		return nil, ErrTelegramTooLong
+	}
+
+	return r.readN(frame, length)
+}
+
+func (r *Reader) readN(buf []byte, n int) ([]byte, error) {
+	start := len(buf)
+	buf = append(buf, make([]byte, n)...)
+
+	_, err := io.ReadFull(r.r, buf[start:])
+	if err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
+
+// berLength will decode a BER encoded length, returning the length and the
+// number of bytes used.
+func berLength(raw []byte) (int, int, error) {
+	if len(raw) < 1 {
+		return 0, 0, ErrMalformedEnvelope
+	}
+
+	if raw[0]&0x80 == 0 {
+		return int(raw[0]), 1, nil
+	}
+
+	n := int(raw[0] & 0x7f)
+	if n < 1 || n > 3 || len(raw) < n+1 {
+		return 0, 0, ErrMalformedEnvelope
+	}
+
+	length := 0
+	for _, b := range raw[1 : n+1] {
+		length = length<<8 | int(b)
+	}
+
+	return length, n + 1, nil
+}
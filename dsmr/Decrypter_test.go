@@ -0,0 +1,142 @@
+package dsmr
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"testing"
+
+	"github.com/abrander/gometer/iec62056"
+)
+
+var (
+	testKey     = []byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f}
+	testAuthKey = SmartyAuthKey
+	testTitle   = []byte("SAG\x05\x00\x6c\x0c\x38")
+)
+
+// encrypt will wrap plaintext in a general-glo-ciphering envelope like a
+// Smarty meter does.
+func encrypt(t *testing.T, plaintext []byte, counter uint32) []byte {
+	block, err := aes.NewCipher(testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gcm, err := cipher.NewGCMWithTagSize(block, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	iv := make([]byte, 12)
+	copy(iv, testTitle)
+	binary.BigEndian.PutUint32(iv[8:], counter)
+
+	sc := SecurityAuthenticated | SecurityEncrypted
+	ciphertext := gcm.Seal(nil, iv, plaintext, append([]byte{sc}, testAuthKey...))
+
+	length := 5 + len(ciphertext)
+	frame := []byte{GeneralGloCiphering, byte(len(testTitle))}
+	frame = append(frame, testTitle...)
+	frame = append(frame, 0x82, byte(length>>8), byte(length))
+	frame = append(frame, sc)
+	frame = binary.BigEndian.AppendUint32(frame, counter)
+
+	return append(frame, ciphertext...)
+}
+
+func TestDecrypt(t *testing.T) {
+	d, err := NewDecrypter(testKey, testAuthKey)
+	if err != nil {
+		t.Fatalf("NewDecrypter() failed: %s", err.Error())
+	}
+
+	frame := encrypt(t, []byte(exampleTelegram), 0x1234)
+
+	plaintext, err := d.Decrypt(frame)
+	if err != nil {
+		t.Fatalf("Decrypt() failed: %s", err.Error())
+	}
+
+	if string(plaintext) != exampleTelegram {
+		t.Errorf("Decrypt() returned wrong plaintext")
+	}
+
+	frame[len(frame)-20] ^= 0x01
+	if _, err := d.Decrypt(frame); err != ErrAuthenticationFailed {
+		t.Errorf("Decrypt() of tampered frame should fail with ErrAuthenticationFailed, got %v", err)
+	}
+
+	if _, err := d.Decrypt(frame[:30]); err != ErrMalformedEnvelope {
+		t.Errorf("Decrypt() of truncated frame should fail with ErrMalformedEnvelope, got %v", err)
+	}
+
+	if _, err := NewDecrypter(testKey[:8], nil); err != ErrInvalidKey {
+		t.Errorf("NewDecrypter() should fail with ErrInvalidKey, got %v", err)
+	}
+}
+
+// TestDecryptKnownAnswer decrypts the authenticated and encrypted example
+// from the DLMS UA Green Book (DLMS UA 1000-2), computed independently of
+// this package.
+func TestDecryptKnownAnswer(t *testing.T) {
+	key, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	authKey, _ := hex.DecodeString("d0d1d2d3d4d5d6d7d8d9dadbdcdddedf")
+	frame, _ := hex.DecodeString("db084d4d4d0000bc614e1e3001234567411312ff935a47566827c467bc7d825c3be4a77c3fcc056b6b")
+	expected := "c0010000080000010000ff0200"
+
+	d, err := NewDecrypter(key, authKey)
+	if err != nil {
+		t.Fatalf("NewDecrypter() failed: %s", err.Error())
+	}
+
+	plaintext, err := d.Decrypt(frame)
+	if err != nil {
+		t.Fatalf("Decrypt() failed: %s", err.Error())
+	}
+
+	if hex.EncodeToString(plaintext) != expected {
+		t.Errorf("Got: %x, expected: %s", plaintext, expected)
+	}
+
+	// Without the authentication key, the tag does not verify.
+	d, _ = NewDecrypter(key, nil)
+	if _, err := d.Decrypt(frame); err != ErrAuthenticationFailed {
+		t.Errorf("Decrypt() without authentication key should fail with ErrAuthenticationFailed, got %v", err)
+	}
+}
+
+func TestEncryptedReader(t *testing.T) {
+	var stream bytes.Buffer
+	stream.WriteString("noise")
+	stream.Write(encrypt(t, []byte(exampleTelegram), 1))
+	stream.Write(encrypt(t, []byte("not a telegram"), 2))
+	stream.Write(encrypt(t, []byte(exampleTelegram), 3))
+
+	r, err := NewEncryptedReader(&stream, testKey, testAuthKey)
+	if err != nil {
+		t.Fatalf("NewEncryptedReader() failed: %s", err.Error())
+	}
+
+	var errs []error
+	r.OnError = func(err error) {
+		errs = append(errs, err)
+	}
+
+	telegrams := make(chan *Telegram, 10)
+	if err := r.Listen(telegrams); err != io.EOF {
+		t.Errorf("Listen() should return io.EOF, got %v", err)
+	}
+
+	if len(telegrams) != 2 || len(errs) != 1 {
+		t.Errorf("Got %d telegrams and %d errors, expected 2 and 1", len(telegrams), len(errs))
+	}
+
+	telegram := <-telegrams
+	if telegram.Values[iec62056.VoltageL1].Value != 220.1 {
+		t.Errorf("Wrong value from decrypted telegram: %+v", telegram.Values[iec62056.VoltageL1])
+	}
+}
@@ -0,0 +1,204 @@
+package dsmr
+
+import (
+	"bufio"
+	"errors"
+	"io"
+
+	"github.com/tarm/serial"
+)
+
+type (
+	// Reader reads P1 telegrams from a DSMR meter.
+	Reader struct {
+		r         *bufio.Reader
+		closer    io.Closer
+		decrypter *Decrypter
+
+		// OnError, if set, will be called by Listen for every telegram that
+		// fails validation.
+		OnError func(error)
+	}
+)
+
+// MaxTelegramSize is the maximum size of a telegram accepted by Reader.
+const MaxTelegramSize = 16 * 1024
+
+var (
+	// ErrTelegramTooLong will be returned if no end of telegram was seen
+	// within MaxTelegramSize bytes.
+	ErrTelegramTooLong = errors.New("telegram too long")
+)
+
+// NewReader will initialize a new P1 reader with a user provided io.Reader.
+// If r is an io.Closer, Close will close it.
+func NewReader(r io.Reader) *Reader {
+	reader := &Reader{
+		r: bufio.NewReaderSize(r, 4096),
+	}
+
+	if closer, ok := r.(io.Closer); ok {
+		reader.closer = closer
+	}
+
+	return reader
+}
+
+// NewReaderSerial will initialize a new P1 reader on a serial device. Meters
+// using DSMR 4 or later push at 115200 baud, 8N1.
+func NewReaderSerial(device string) (*Reader, error) {
+	return NewReaderSerialConfig(&serial.Config{
+		Name: device,
+		Baud: 115200,
+		Size: 8,
+	})
+}
+
+// NewReaderSerialLegacy will initialize a new P1 reader on a serial device
+// for meters using DSMR 2.2 or 3.0. These push at 9600 baud, 7E1.
+func NewReaderSerialLegacy(device string) (*Reader, error) {
+	return NewReaderSerialConfig(&serial.Config{
+		Name:   device,
+		Baud:   9600,
+		Size:   7,
+		Parity: serial.ParityEven,
+	})
+}
+
+// NewReaderSerialConfig will initialize a new P1 reader on a serial device
+// with a custom configuration.
+func NewReaderSerialConfig(conf *serial.Config) (*Reader, error) {
+	port, err := serial.OpenPort(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewReader(port), nil
+}
+
+// Close will close the underlying reader if possible.
+func (r *Reader) Close() error {
+	if r.closer == nil {
+		return nil
+	}
+
+	return r.closer.Close()
+}
+
+// ReadRaw will read the next complete telegram without parsing it. Anything
+// before the start of the telegram is discarded. Encrypted telegrams are
+// decrypted.
+func (r *Reader) ReadRaw() ([]byte, error) {
+	frame, err := r.readFrame()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.decrypt(frame)
+}
+
+// ReadTelegram will read, verify and parse the next telegram.
+func (r *Reader) ReadTelegram() (*Telegram, error) {
+	raw, err := r.ReadRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseTelegram(raw)
+}
+
+// Listen will read telegrams and deliver them on telegrams until reading fails.
+// Telegrams failing validation are skipped and passed to OnError. The
+// error from the underlying reader is returned, io.EOF included.
+func (r *Reader) Listen(telegrams chan<- *Telegram) error {
+	for {
+		frame, err := r.readFrame()
+		switch err {
+		case nil:
+		case ErrTelegramTooLong, ErrMalformedEnvelope:
+			r.reportError(err)
+			continue
+		default:
+			return err
+		}
+
+		raw, err := r.decrypt(frame)
+		if err != nil {
+			r.reportError(err)
+			continue
+		}
+
+		t, err := ParseTelegram(raw)
+		if err != nil {
+			r.reportError(err)
+			continue
+		}
+
+		telegrams <- t
+	}
+}
+
+func (r *Reader) reportError(err error) {
+	if r.OnError != nil {
+		r.OnError(err)
+	}
+}
+
+// readFrame will read the next plain or encrypted frame.
+func (r *Reader) readFrame() ([]byte, error) {
+	if r.decrypter != nil {
+		return r.readEncrypted()
+	}
+
+	return r.readPlain()
+}
+
+func (r *Reader) decrypt(frame []byte) ([]byte, error) {
+	if r.decrypter == nil {
+		return frame, nil
+	}
+
+	return r.decrypter.Decrypt(frame)
+}
+
+// readPlain will read the next plain text telegram.
+func (r *Reader) readPlain() ([]byte, error) {
+	// Skip until start of telegram.
+	for {
+		b, err := r.r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		if b == TelegramStart {
+			break
+		}
+	}
+
+	raw := []byte{TelegramStart}
+
+	for {
+		b, err := r.r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		raw = append(raw, b)
+
+		if b == TelegramEnd {
+			break
+		}
+
+		if len(raw) > MaxTelegramSize {
+			return nil, ErrTelegramTooLong
+		}
+	}
+
+	// The checksum line. Meters without checksum will send just "\r\n".
+	line, err := r.r.ReadBytes('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	return append(raw, line...), nil
+}
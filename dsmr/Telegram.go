@@ -0,0 +1,182 @@
+package dsmr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/abrander/gometer/iec62056"
+)
+
+type (
+	// Telegram is a single P1 telegram as pushed by a DSMR meter.
+	Telegram struct {
+		// Identification is the identification line without the leading
+		// "/", for example "ISk5\2MT382-1000".
+		Identification string
+
+		// Raw is the complete telegram from "/" to and including the
+		// checksum line.
+		Raw []byte
+
+		// Checksum is the CRC16 from the telegram. DSMR versions before 4
+		// carry no checksum, HasChecksum will be false for these.
+		Checksum    uint16
+		HasChecksum bool
+
+		// Values holds all numeric values from the telegram, keyed by the
+		// OBIS code including value group A and B.
+		Values iec62056.ValueCollection
+	}
+)
+
+const (
+	// TelegramStart marks the start of a telegram.
+	TelegramStart = byte('/')
+
+	// TelegramEnd marks the end of the data in a telegram. It is followed by
+	// the checksum.
+	TelegramEnd = byte('!')
+)
+
+var (
+	// ErrNoStart will be returned if the telegram does not start with "/".
+	ErrNoStart = errors.New("telegram does not start with '/'")
+
+	// ErrNoEnd will be returned if the telegram has no "!".
+	ErrNoEnd = errors.New("telegram does not end with '!'")
+
+	// ErrMalformedChecksum will be returned if the checksum following "!" is
+	// not four hexadecimal characters.
+	ErrMalformedChecksum = errors.New("malformed checksum")
+
+	// ErrInvalidChecksum will be returned if the checksum did not verify.
+	ErrInvalidChecksum = errors.New("checksum did not validate")
+)
+
+// ParseTelegram will verify and parse a complete telegram.
+func ParseTelegram(raw []byte) (*Telegram, error) {
+	if len(raw) == 0 || raw[0] != TelegramStart {
+		return nil, ErrNoStart
+	}
+
+	end := bytes.IndexByte(raw, TelegramEnd)
+	if end < 0 {
+		return nil, ErrNoEnd
+	}
+
+	t := &Telegram{
+		Raw: raw,
+	}
+
+	checksum := bytes.TrimSpace(raw[end+1:])
+	if len(checksum) > 0 {
+		if len(checksum) != 4 {
+			return nil, ErrMalformedChecksum
+		}
+
+		sum, err := strconv.ParseUint(string(checksum), 16, 16)
+		if err != nil {
+			return nil, ErrMalformedChecksum
+		}
+
+		t.Checksum = uint16(sum)
+		t.HasChecksum = true
+
+		if CRC16(raw[:end+1]) != t.Checksum {
+			return nil, ErrInvalidChecksum
+		}
+	}
+
+	header := raw[1:end]
+	if i := bytes.IndexByte(header, iec62056.LineFeed); i >= 0 {
+		header = header[:i]
+	}
+	t.Identification = string(bytes.TrimSpace(header))
+
+	t.Values = parseValues(t.Lines())
+
+	return t, nil
+}
+
+// parseValues will parse data lines into a collection keyed by the OBIS code
+// as written, including value group A and B, as a telegram can hold the same
+// C.D.E for more than one channel. Lines that cannot be parsed are skipped.
+func parseValues(lines []string) iec62056.ValueCollection {
+	c := make(iec62056.ValueCollection)
+
+	for _, line := range lines {
+		obis, values, err := iec62056.ParseDataLine(line)
+		if err != nil {
+			continue
+		}
+
+		// M-Bus values are preceded by their capture time.
+		value, err := iec62056.ParseValue(values[len(values)-1])
+		if err != nil {
+			continue
+		}
+
+		c[obis] = value
+	}
+
+	return c
+}
+
+// Lines will return the data lines of the telegram, that is everything between
+// the identification line and "!". DSMR 2.2 meters split some objects over two
+// lines, these are joined.
+func (t *Telegram) Lines() []string {
+	end := bytes.IndexByte(t.Raw, TelegramEnd)
+	if end < 0 {
+		return nil
+	}
+
+	var lines []string
+	for i, line := range bytes.Split(t.Raw[:end], []byte{iec62056.LineFeed}) {
+		line = bytes.TrimSpace(line)
+		if i == 0 || len(line) == 0 {
+			continue
+		}
+
+		// A line starting with "(" continues the previous object.
+		if line[0] == '(' && len(lines) > 0 {
+			lines[len(lines)-1] += string(line)
+			continue
+		}
+
+		lines = append(lines, string(line))
+	}
+
+	return lines
+}
+
+// String will return the identification and checksum of the telegram.
+func (t *Telegram) String() string {
+	if !t.HasChecksum {
+		return fmt.Sprintf("/%s (%d values)", t.Identification, len(t.Values))
+	}
+
+	return fmt.Sprintf("/%s (%d values, CRC %04X)", t.Identification, len(t.Values), t.Checksum)
+}
+
+// CRC16 will calculate the CRC16 used by DSMR. This is CRC-16/ARC with the
+// polynomial 0x8005 (reversed 0xa001) and an initial value of 0.
+func CRC16(data []byte) uint16 {
+	var reg uint16
+
+	for _, b := range data {
+		reg ^= uint16(b)
+
+		for i := 0; i < 8; i++ {
+			if reg&0x0001 > 0 {
+				reg = (reg >> 1) ^ 0xa001
+			} else {
+				reg >>= 1
+			}
+		}
+	}
+
+	return reg
+}
@@ -0,0 +1,121 @@
+package dsmr
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/abrander/gometer/iec62056"
+)
+
+// Example telegram from the DSMR 5.0.2 P1 companion standard.
+var exampleTelegram = strings.Replace(`/ISk5\2MT382-1000
+
+1-3:0.2.8(50)
+0-0:1.0.0(101209113020W)
+0-0:96.1.1(4B384547303034303436333935353037)
+1-0:1.8.1(123456.789*kWh)
+1-0:1.8.2(123456.789*kWh)
+1-0:2.8.1(123456.789*kWh)
+1-0:2.8.2(123456.789*kWh)
+0-0:96.14.0(0002)
+1-0:1.7.0(01.193*kW)
+1-0:2.7.0(00.000*kW)
+0-0:96.7.21(00004)
+0-0:96.7.9(00002)
+1-0:99.97.0(2)(0-0:96.7.19)(101208152415W)(0000000240*s)(101208151004W)(0000000301*s)
+1-0:32.32.0(00002)
+1-0:52.32.0(00001)
+1-0:72.32.0(00000)
+1-0:32.36.0(00000)
+1-0:52.36.0(00003)
+1-0:72.36.0(00000)
+0-0:96.13.0(303132333435363738393A3B3C3D3E3F303132333435363738393A3B3C3D3E3F303132333435363738393A3B3C3D3E3F303132333435363738393A3B3C3D3E3F303132333435363738393A3B3C3D3E3F)
+1-0:32.7.0(220.1*V)
+1-0:52.7.0(220.2*V)
+1-0:72.7.0(220.3*V)
+1-0:31.7.0(001*A)
+1-0:51.7.0(002*A)
+1-0:71.7.0(003*A)
+1-0:21.7.0(01.111*kW)
+1-0:41.7.0(02.222*kW)
+1-0:61.7.0(03.333*kW)
+1-0:22.7.0(04.444*kW)
+1-0:42.7.0(05.555*kW)
+1-0:62.7.0(06.666*kW)
+0-1:24.1.0(003)
+0-1:96.1.0(3232323241424344313233343536373839)
+0-1:24.2.1(101209112500W)(12785.123*m3)
+!`, "\n", "\r\n", -1) + "E47C\r\n"
+
+func TestCRC16(t *testing.T) {
+	if crc := CRC16([]byte("123456789")); crc != 0xbb3d {
+		t.Errorf("CRC16(): Got: %04x, expected: bb3d", crc)
+	}
+}
+
+func TestParseTelegram(t *testing.T) {
+	telegram, err := ParseTelegram([]byte(exampleTelegram))
+	if err != nil {
+		t.Fatalf("ParseTelegram() failed: %s", err.Error())
+	}
+
+	if telegram.Identification != `ISk5\2MT382-1000` {
+		t.Errorf("Wrong identification: %s", telegram.Identification)
+	}
+
+	if !telegram.HasChecksum || telegram.Checksum != 0xe47c {
+		t.Errorf("Wrong checksum: %04x", telegram.Checksum)
+	}
+
+	expected := map[string]float64{
+		"1-0:1.8.1":  123456.789,
+		"1-0:32.7.0": 220.1,
+		"0-1:24.2.1": 12785.123,
+		"1-0:71.7.0": 3,
+	}
+
+	for raw, value := range expected {
+		v, found := telegram.Values[iec62056.NewObis(raw)]
+		if !found || v.Value != value {
+			t.Errorf("%s: Got: %v (%v), expected: %v", raw, v.Value, found, value)
+		}
+	}
+
+	if len(telegram.Lines()) != 35 {
+		t.Errorf("Lines() returned %d lines, expected 35", len(telegram.Lines()))
+	}
+
+	corrupted := strings.Replace(exampleTelegram, "220.1", "220.2", 1)
+	if _, err := ParseTelegram([]byte(corrupted)); err != ErrInvalidChecksum {
+		t.Errorf("ParseTelegram() should fail with ErrInvalidChecksum, got %v", err)
+	}
+
+	legacy := strings.Replace(exampleTelegram, "E47C", "", 1)
+	if telegram, err := ParseTelegram([]byte(legacy)); err != nil || telegram.HasChecksum {
+		t.Errorf("ParseTelegram() of telegram without checksum failed: %v", err)
+	}
+}
+
+func TestListen(t *testing.T) {
+	stream := "garbage" + exampleTelegram + strings.Replace(exampleTelegram, "E47C", "0000", 1) + exampleTelegram[:100] + exampleTelegram
+
+	r := NewReader(bytes.NewBufferString(stream))
+
+	var errs []error
+	r.OnError = func(err error) {
+		errs = append(errs, err)
+	}
+
+	telegrams := make(chan *Telegram, 10)
+	err := r.Listen(telegrams)
+	if err != io.EOF {
+		t.Errorf("Listen() should return io.EOF, got %v", err)
+	}
+
+	// The truncated telegram runs into the next one and fails the checksum.
+	if len(telegrams) != 1 || len(errs) != 2 {
+		t.Errorf("Got %d telegrams and %d errors, expected 1 and 2", len(telegrams), len(errs))
+	}
+}
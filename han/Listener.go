@@ -0,0 +1,134 @@
+package han
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/tarm/serial"
+
+	"github.com/abrander/gometer/hdlc"
+)
+
+type (
+	// Listener reads lists pushed by a meter on the HAN port.
+	Listener struct {
+		r      *hdlc.Reader
+		closer io.Closer
+
+		// OnError, if set, will be called by Listen for every frame or list
+		// that cannot be decoded.
+		OnError func(error)
+	}
+)
+
+// NewListener will initialize a new listener with a user provided io.Reader.
+// If r is an io.Closer, Close will close it.
+func NewListener(r io.Reader) *Listener {
+	l := &Listener{
+		r: hdlc.NewReader(r),
+	}
+
+	if closer, ok := r.(io.Closer); ok {
+		l.closer = closer
+	}
+
+	return l
+}
+
+// NewListenerSerial will initialize a new listener on a serial device with an
+// M-Bus slave adapter. Aidon and Kaifa push at 2400 baud, 8E1.
+func NewListenerSerial(device string) (*Listener, error) {
+	return NewListenerSerialConfig(&serial.Config{
+		Name:   device,
+		Baud:   2400,
+		Size:   8,
+		Parity: serial.ParityEven,
+	})
+}
+
+// NewListenerSerialConfig will initialize a new listener on a serial device
+// with a custom configuration. Kamstrup meters push at 2400 baud, 8N1.
+func NewListenerSerialConfig(conf *serial.Config) (*Listener, error) {
+	port, err := serial.OpenPort(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewListener(port), nil
+}
+
+// Close will close the underlying reader if possible.
+func (l *Listener) Close() error {
+	if l.closer == nil {
+		return nil
+	}
+
+	return l.closer.Close()
+}
+
+// ReadAPDU will read HDLC frames until a complete APDU is received. Segmented
+// frames are joined and the LLC header is removed.
+func (l *Listener) ReadAPDU() ([]byte, error) {
+	var apdu []byte
+
+	for {
+		f, err := l.r.ReadFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		apdu = append(apdu, f.Info...)
+
+		if !f.Segmented {
+			break
+		}
+	}
+
+	if bytes.HasPrefix(apdu, hdlc.LLCResponse) || bytes.HasPrefix(apdu, hdlc.LLCRequest) {
+		apdu = apdu[len(hdlc.LLCResponse):]
+	}
+
+	return apdu, nil
+}
+
+// ReadMessage will read and decode the next list.
+func (l *Listener) ReadMessage() (*Message, error) {
+	apdu, err := l.ReadAPDU()
+	if err != nil {
+		return nil, err
+	}
+
+	return DecodeAPDU(apdu)
+}
+
+// Listen will read lists and deliver them on messages until reading fails.
+// Frames and lists that cannot be decoded are skipped and passed to OnError.
+// The error from the underlying reader is returned, io.EOF included.
+func (l *Listener) Listen(messages chan<- *Message) error {
+	for {
+		apdu, err := l.ReadAPDU()
+		switch err {
+		case nil:
+		case hdlc.ErrInvalidFormat, hdlc.ErrFrameTooShort, hdlc.ErrInvalidAddress,
+			hdlc.ErrInvalidHCS, hdlc.ErrInvalidFCS:
+			l.reportError(err)
+			continue
+		default:
+			return err
+		}
+
+		m, err := DecodeAPDU(apdu)
+		if err != nil {
+			l.reportError(err)
+			continue
+		}
+
+		messages <- m
+	}
+}
+
+func (l *Listener) reportError(err error) {
+	if l.OnError != nil {
+		l.OnError(err)
+	}
+}
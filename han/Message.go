@@ -0,0 +1,319 @@
+package han
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/abrander/gometer/dlms"
+	"github.com/abrander/gometer/iec62056"
+	"github.com/abrander/gometer/kamstrup"
+)
+
+type (
+	// Vendor identifies the list layout used by a meter.
+	Vendor string
+
+	// Message is a decoded list pushed on the HAN port.
+	Message struct {
+		Vendor      Vendor
+		ListVersion string
+		MeterID     string
+		MeterType   string
+
+		// Time is the meter clock if part of the list, otherwise the time of
+		// the notification. It is zero if the meter sent neither.
+		Time time.Time
+
+		// Values holds all numeric values, scaled to the units of the
+		// meter.
+		Values iec62056.ValueCollection
+
+		// Body is the undecoded notification body.
+		Body dlms.Data
+	}
+
+	// column is a value in a list without OBIS codes.
+	column struct {
+		obis   iec62056.Obis
+		scaler dlms.ScalerUnit
+	}
+)
+
+// Known vendors.
+const (
+	VendorUnknown  = Vendor("")
+	VendorAidon    = Vendor("Aidon")
+	VendorKaifa    = Vendor("Kaifa")
+	VendorKamstrup = Vendor("Kamstrup")
+)
+
+var (
+	// ErrUnknownList will be returned if the layout of a list is not
+	// recognized.
+	ErrUnknownList = errors.New("unknown list layout")
+
+	// Alternative codes used by Kamstrup for identification.
+	kamstrupMeterID   = iec62056.NewObis("1-0:0.0.5")
+	kamstrupMeterType = iec62056.NewObis("1-0:96.1.1")
+
+	watt         = dlms.ScalerUnit{Scaler: 0, Unit: dlms.UnitWatt}
+	vAr          = dlms.ScalerUnit{Scaler: 0, Unit: dlms.UnitVar}
+	wattHour     = dlms.ScalerUnit{Scaler: 0, Unit: dlms.UnitWattHour}
+	vArHour      = dlms.ScalerUnit{Scaler: 0, Unit: dlms.UnitVarHour}
+	milliAmpere  = dlms.ScalerUnit{Scaler: -3, Unit: dlms.UnitAmpere}
+	deciVolt     = dlms.ScalerUnit{Scaler: -1, Unit: dlms.UnitVolt}
+	centiAmpere  = dlms.ScalerUnit{Scaler: -2, Unit: dlms.UnitAmpere}
+	volt         = dlms.ScalerUnit{Scaler: 0, Unit: dlms.UnitVolt}
+	decaWattHour = dlms.ScalerUnit{Scaler: 1, Unit: dlms.UnitWattHour}
+	decaVArHour  = dlms.ScalerUnit{Scaler: 1, Unit: dlms.UnitVarHour}
+
+	// Kaifa sends bare values. The layout is given by the number of
+	// elements: list 1 is active power only, list 2 adds identification and
+	// instantaneous values, list 3 adds clock and energy registers.
+	kaifaThreePhase = []column{
+		{iec62056.HANListVersion, dlms.ScalerUnit{}},
+		{iec62056.MeterID, dlms.ScalerUnit{}},
+		{iec62056.MeterType, dlms.ScalerUnit{}},
+		{iec62056.ActivePowerImport, watt},
+		{iec62056.ActivePowerExport, watt},
+		{iec62056.ReactivePowerImport, vAr},
+		{iec62056.ReactivePowerExport, vAr},
+		{iec62056.CurrentL1, milliAmpere},
+		{iec62056.CurrentL2, milliAmpere},
+		{iec62056.CurrentL3, milliAmpere},
+		{iec62056.VoltageL1, deciVolt},
+		{iec62056.VoltageL2, deciVolt},
+		{iec62056.VoltageL3, deciVolt},
+		{iec62056.Clock, dlms.ScalerUnit{}},
+		{iec62056.ActiveEnergyImport, wattHour},
+		{iec62056.ActiveEnergyExport, wattHour},
+		{iec62056.ReactiveEnergyImport, vArHour},
+		{iec62056.ReactiveEnergyExport, vArHour},
+	}
+
+	kaifaSinglePhase = []column{
+		{iec62056.HANListVersion, dlms.ScalerUnit{}},
+		{iec62056.MeterID, dlms.ScalerUnit{}},
+		{iec62056.MeterType, dlms.ScalerUnit{}},
+		{iec62056.ActivePowerImport, watt},
+		{iec62056.ActivePowerExport, watt},
+		{iec62056.ReactivePowerImport, vAr},
+		{iec62056.ReactivePowerExport, vAr},
+		{iec62056.CurrentL1, milliAmpere},
+		{iec62056.VoltageL1, deciVolt},
+		{iec62056.Clock, dlms.ScalerUnit{}},
+		{iec62056.ActiveEnergyImport, wattHour},
+		{iec62056.ActiveEnergyExport, wattHour},
+		{iec62056.ReactiveEnergyImport, vArHour},
+		{iec62056.ReactiveEnergyExport, vArHour},
+	}
+
+	kaifaLayouts = map[int][]column{
+		1:  {{iec62056.ActivePowerImport, watt}},
+		9:  kaifaSinglePhase[:9],
+		13: kaifaThreePhase[:13],
+		14: kaifaSinglePhase,
+		18: kaifaThreePhase,
+	}
+)
+
+// DecodeAPDU will decode a data-notification APDU.
+func DecodeAPDU(apdu []byte) (*Message, error) {
+	n, err := dlms.ParseDataNotification(apdu)
+	if err != nil {
+		return nil, err
+	}
+
+	return Decode(n)
+}
+
+// Decode will decode the list in a data-notification. Lists of OBIS tagged
+// structures (Aidon), structures of alternating OBIS codes and values
+// (Kamstrup) and structures of bare values (Kaifa) are supported.
+func Decode(n *dlms.DataNotification) (*Message, error) {
+	m := &Message{
+		Values: make(iec62056.ValueCollection),
+		Body:   n.Body,
+	}
+
+	elements := n.Body.Elements()
+
+	var err error
+	switch {
+	case n.Body.Type == dlms.TypeArray:
+		err = m.decodeTagged(elements)
+	case n.Body.Type != dlms.TypeStructure || len(elements) == 0:
+		err = ErrUnknownList
+	case len(elements) > 2 && isLogicalName(elements[1]):
+		err = m.decodePairs(elements)
+	default:
+		err = m.decodeColumns(elements)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	m.Vendor = vendor(m.ListVersion)
+	if len(elements) == 1 && n.Body.Type == dlms.TypeStructure {
+		// Only Kaifa sends a bare list 1.
+		m.Vendor = VendorKaifa
+	}
+
+	if m.Time.IsZero() && n.HasTime {
+		m.Time, _ = n.Time.Time()
+	}
+
+	return m, nil
+}
+
+// decodeTagged will decode an array of structures with an OBIS code, a value
+// and an optional scaler_unit, as sent by Aidon.
+func (m *Message) decodeTagged(elements []dlms.Data) error {
+	for _, e := range elements {
+		fields := e.Elements()
+		if len(fields) < 2 {
+			return ErrUnknownList
+		}
+
+		obis, err := dlms.DecodeLogicalName(fields[0])
+		if err != nil {
+			return err
+		}
+
+		var su dlms.ScalerUnit
+		if len(fields) > 2 {
+			su, err = dlms.DecodeScalerUnit(fields[2])
+			if err != nil {
+				return err
+			}
+		}
+
+		m.set(obis, fields[1], su)
+	}
+
+	return nil
+}
+
+// decodePairs will decode a structure of a list version followed by
+// alternating OBIS codes and values, as sent by Kamstrup. Kamstrup uses value
+// group B 1 for the meter itself, this is changed to 0 to match other meters.
+// Scalers are fixed by Kamstrup.
+func (m *Message) decodePairs(elements []dlms.Data) error {
+	if version, ok := elements[0].Bytes(); ok {
+		m.ListVersion = string(version)
+	}
+
+	for i := 1; i+1 < len(elements); i += 2 {
+		obis, err := dlms.DecodeLogicalName(elements[i])
+		if err != nil {
+			return err
+		}
+
+		if obis.B == "1" {
+			obis.B = "0"
+		}
+
+		m.set(obis, elements[i+1], kamstrupScaler(obis))
+	}
+
+	return nil
+}
+
+// decodeColumns will decode a structure of bare values as sent by Kaifa.
+func (m *Message) decodeColumns(elements []dlms.Data) error {
+	layout, found := kaifaLayouts[len(elements)]
+	if !found {
+		return ErrUnknownList
+	}
+
+	for i, c := range layout {
+		m.set(c.obis, elements[i], c.scaler)
+	}
+
+	return nil
+}
+
+// set will store a single value in the message.
+func (m *Message) set(obis iec62056.Obis, value dlms.Data, su dlms.ScalerUnit) {
+	if obis == iec62056.Clock {
+		if dt, ok := value.DateTime(); ok {
+			m.Time, _ = dt.Time()
+		}
+
+		return
+	}
+
+	if b, ok := value.Bytes(); ok {
+		switch obis {
+		case iec62056.HANListVersion:
+			m.ListVersion = string(b)
+		case iec62056.MeterID, kamstrupMeterID:
+			m.MeterID = string(b)
+		case iec62056.MeterType, kamstrupMeterType:
+			m.MeterType = string(b)
+		}
+
+		return
+	}
+
+	v, ok := value.Float()
+	if !ok {
+		return
+	}
+
+	m.Values[obis] = kamstrup.Value{
+		Value: su.Apply(v),
+		Unit:  su.Unit.Kamstrup(),
+	}
+}
+
+// kamstrupScaler will return the fixed scaler and unit used by Kamstrup.
+func kamstrupScaler(obis iec62056.Obis) dlms.ScalerUnit {
+	switch obis.D {
+	case "7":
+		switch obis.C {
+		case "1", "2":
+			return watt
+		case "3", "4":
+			return vAr
+		case "31", "51", "71":
+			return centiAmpere
+		case "32", "52", "72":
+			return volt
+		}
+	case "8":
+		switch obis.C {
+		case "1", "2":
+			return decaWattHour
+		case "3", "4":
+			return decaVArHour
+		}
+	}
+
+	return dlms.ScalerUnit{Unit: dlms.UnitCount}
+}
+
+// isLogicalName will return true if d looks like a binary OBIS code.
+func isLogicalName(d dlms.Data) bool {
+	b, ok := d.Value.([]byte)
+
+	return ok && d.Type == dlms.TypeOctetString && len(b) == 6
+}
+
+// vendor will guess the vendor from the list version identifier.
+func vendor(version string) Vendor {
+	version = strings.ToUpper(version)
+
+	switch {
+	case strings.HasPrefix(version, "AIDON"):
+		return VendorAidon
+	case strings.HasPrefix(version, "KFM"):
+		return VendorKaifa
+	case strings.HasPrefix(version, "KAMSTRUP"):
+		return VendorKamstrup
+	}
+
+	return VendorUnknown
+}
@@ -0,0 +1,280 @@
+package han
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/abrander/gometer/dlms"
+	"github.com/abrander/gometer/hdlc"
+	"github.com/abrander/gometer/iec62056"
+	"github.com/abrander/gometer/kamstrup"
+)
+
+var (
+	pushTime = time.Date(2021, 1, 12, 17, 35, 59, 0, time.UTC)
+)
+
+func octets(b []byte) dlms.Data {
+	return dlms.Data{Type: dlms.TypeOctetString, Value: b}
+}
+
+func visible(s string) dlms.Data {
+	return dlms.Data{Type: dlms.TypeVisibleString, Value: s}
+}
+
+func obis(code string) dlms.Data {
+	raw, _ := iec62056.NewObis(code).Bytes()
+
+	return octets(raw)
+}
+
+func u32(v uint64) dlms.Data {
+	return dlms.Data{Type: dlms.TypeDoubleLongUnsigned, Value: v}
+}
+
+func u16(v uint64) dlms.Data {
+	return dlms.Data{Type: dlms.TypeLongUnsigned, Value: v}
+}
+
+func structure(elements ...dlms.Data) dlms.Data {
+	return dlms.Data{Type: dlms.TypeStructure, Value: elements}
+}
+
+func scalerUnit(scaler int64, unit dlms.Unit) dlms.Data {
+	return structure(
+		dlms.Data{Type: dlms.TypeInteger, Value: scaler},
+		dlms.Data{Type: dlms.TypeEnum, Value: uint64(unit)},
+	)
+}
+
+// notification will encode a data-notification APDU with the given body.
+// Kamstrup leaves out the octet string tag of the time.
+func notification(t *testing.T, body dlms.Data, untaggedTime bool) []byte {
+	apdu := []byte{dlms.TagDataNotification, 0x40, 0x00, 0x00, 0x00}
+
+	dt := dlms.NewDateTime(pushTime.In(time.FixedZone("CET", 3600)))
+	if untaggedTime {
+		apdu = append(apdu, 0x0c)
+		apdu = append(apdu, dt.Bytes()...)
+	} else {
+		apdu = append(apdu, 0x09, 0x0c)
+		apdu = append(apdu, dt.Bytes()...)
+	}
+
+	encoded, err := body.Encode()
+	if err != nil {
+		t.Fatalf("Encode() returned %s", err)
+	}
+
+	return append(apdu, encoded...)
+}
+
+func kaifaList2() dlms.Data {
+	return structure(
+		visible("KFM_001"),
+		visible("6970631400000000"),
+		visible("MA304H3E"),
+		u32(1500), u32(0), u32(0), u32(120),
+		u32(3210), u32(1005), u32(2300),
+		u32(2301), u32(2312), u32(2295),
+	)
+}
+
+func checkValue(t *testing.T, m *Message, o iec62056.Obis, value float64, unit kamstrup.Unit) {
+	v, found := m.Values[o]
+	if !found {
+		t.Errorf("%s not found in %v", o, m.Values)
+		return
+	}
+
+	if math.Abs(v.Value-value) > 1e-9 || v.Unit != unit {
+		t.Errorf("%s is %s, expected %.3f %s", o, v, value, unit)
+	}
+}
+
+func TestDecodeKaifa(t *testing.T) {
+	m, err := DecodeAPDU(notification(t, kaifaList2(), false))
+	if err != nil {
+		t.Fatalf("DecodeAPDU() returned %s", err)
+	}
+
+	if m.Vendor != VendorKaifa || m.ListVersion != "KFM_001" || m.MeterID != "6970631400000000" || m.MeterType != "MA304H3E" {
+		t.Errorf("Wrong identification: %+v", m)
+	}
+
+	if !m.Time.Equal(pushTime) {
+		t.Errorf("Time is %s, expected %s", m.Time, pushTime)
+	}
+
+	checkValue(t, m, iec62056.ActivePowerImport, 1500, kamstrup.UnitW)
+	checkValue(t, m, iec62056.ReactivePowerExport, 120, kamstrup.UnitVar)
+	checkValue(t, m, iec62056.CurrentL1, 3.21, kamstrup.UnitA)
+	checkValue(t, m, iec62056.VoltageL3, 229.5, kamstrup.UnitV)
+
+	if len(m.Values) != 10 {
+		t.Errorf("Got %d values, expected 10", len(m.Values))
+	}
+
+	// List 1 is a single value without identification.
+	m, err = DecodeAPDU(notification(t, structure(u32(742)), false))
+	if err != nil {
+		t.Fatalf("DecodeAPDU() returned %s", err)
+	}
+
+	if m.Vendor != VendorKaifa {
+		t.Errorf("Vendor is %q, expected Kaifa", m.Vendor)
+	}
+
+	checkValue(t, m, iec62056.ActivePowerImport, 742, kamstrup.UnitW)
+
+	_, err = DecodeAPDU(notification(t, structure(u32(1), u32(2)), false))
+	if err != ErrUnknownList {
+		t.Errorf("DecodeAPDU() of unknown layout returned %v", err)
+	}
+}
+
+func TestDecodeAidon(t *testing.T) {
+	body := dlms.Data{Type: dlms.TypeArray, Value: []dlms.Data{
+		structure(obis("1-1:0.2.129.255"), visible("AIDON_V0001")),
+		structure(obis("0-0:96.1.0.255"), visible("7359992890941742")),
+		structure(obis("1-0:1.7.0.255"), u32(1822), scalerUnit(0, dlms.UnitWatt)),
+		structure(obis("1-0:31.7.0.255"), dlms.Data{Type: dlms.TypeLong, Value: int64(77)}, scalerUnit(-1, dlms.UnitAmpere)),
+		structure(obis("1-0:32.7.0.255"), u16(2317), scalerUnit(-1, dlms.UnitVolt)),
+		structure(obis("1-0:1.8.0.255"), u32(12345), scalerUnit(1, dlms.UnitWattHour)),
+	}}
+
+	apdu := notification(t, body, false)
+	// Aidon sends no time in the header.
+	apdu = append(apdu[:5], append([]byte{0x00}, apdu[5+14:]...)...)
+
+	m, err := DecodeAPDU(apdu)
+	if err != nil {
+		t.Fatalf("DecodeAPDU() returned %s", err)
+	}
+
+	if m.Vendor != VendorAidon || m.MeterID != "7359992890941742" {
+		t.Errorf("Wrong identification: %+v", m)
+	}
+
+	if !m.Time.IsZero() {
+		t.Errorf("Time is %s, expected zero", m.Time)
+	}
+
+	checkValue(t, m, iec62056.ActivePowerImport, 1822, kamstrup.UnitW)
+	checkValue(t, m, iec62056.CurrentL1, 7.7, kamstrup.UnitA)
+	checkValue(t, m, iec62056.VoltageL1, 231.7, kamstrup.UnitV)
+	checkValue(t, m, iec62056.ActiveEnergyImport, 123450, kamstrup.UnitWh)
+}
+
+func kamstrupList() dlms.Data {
+	clock := dlms.NewDateTime(time.Date(2021, 1, 12, 18, 0, 0, 0, time.FixedZone("CET", 3600)))
+
+	return structure(
+		visible("Kamstrup_V0001"),
+		obis("1-1:0.0.5.255"), visible("5706567274389702"),
+		obis("1-1:96.1.1.255"), visible("6841121BN243101040"),
+		obis("1-1:1.7.0.255"), u32(4321),
+		obis("1-1:31.7.0.255"), u32(1234),
+		obis("1-1:32.7.0.255"), u16(233),
+		obis("0-1:1.0.0.255"), octets(clock.Bytes()),
+		obis("1-1:1.8.0.255"), u32(1016788),
+	)
+}
+
+func TestDecodeKamstrup(t *testing.T) {
+	m, err := DecodeAPDU(notification(t, kamstrupList(), true))
+	if err != nil {
+		t.Fatalf("DecodeAPDU() returned %s", err)
+	}
+
+	if m.Vendor != VendorKamstrup || m.MeterID != "5706567274389702" || m.MeterType != "6841121BN243101040" {
+		t.Errorf("Wrong identification: %+v", m)
+	}
+
+	expected := time.Date(2021, 1, 12, 17, 0, 0, 0, time.UTC)
+	if !m.Time.Equal(expected) {
+		t.Errorf("Time is %s, expected the list clock %s", m.Time, expected)
+	}
+
+	checkValue(t, m, iec62056.ActivePowerImport, 4321, kamstrup.UnitW)
+	checkValue(t, m, iec62056.CurrentL1, 12.34, kamstrup.UnitA)
+	checkValue(t, m, iec62056.VoltageL1, 233, kamstrup.UnitV)
+	checkValue(t, m, iec62056.ActiveEnergyImport, 10167880, kamstrup.UnitWh)
+}
+
+// frames will wrap an APDU in UI frames of at most size bytes.
+func frames(t *testing.T, apdu []byte, size int) []byte {
+	info := append(append([]byte{}, hdlc.LLCResponse...), apdu...)
+
+	var stream []byte
+	for len(info) > 0 {
+		segment := info
+		if len(segment) > size {
+			segment = info[:size]
+		}
+		info = info[len(segment):]
+
+		f := &hdlc.Frame{
+			Segmented:   len(info) > 0,
+			Destination: hdlc.NewAddress(0x10, 0),
+			Source:      hdlc.NewAddress(1, 0),
+			Control:     hdlc.UI | 0x10,
+			Info:        segment,
+		}
+
+		raw, err := f.Encode()
+		if err != nil {
+			t.Fatalf("Encode() returned %s", err)
+		}
+
+		stream = append(stream, raw...)
+	}
+
+	return stream
+}
+
+func TestListener(t *testing.T) {
+	var stream []byte
+
+	stream = append(stream, frames(t, notification(t, kaifaList2(), false), 200)...)
+
+	// A corrupted frame.
+	corrupt := frames(t, notification(t, structure(u32(742)), false), 200)
+	corrupt[10] ^= 0xff
+	stream = append(stream, corrupt...)
+
+	// An unknown list.
+	stream = append(stream, frames(t, notification(t, structure(u32(1), u32(2)), false), 200)...)
+
+	// A segmented list.
+	stream = append(stream, frames(t, notification(t, kamstrupList(), true), 40)...)
+
+	var errors []error
+	l := NewListener(bytes.NewReader(stream))
+	l.OnError = func(err error) {
+		errors = append(errors, err)
+	}
+
+	messages := make(chan *Message, 10)
+	err := l.Listen(messages)
+	if err != io.EOF {
+		t.Errorf("Listen() returned %v, expected EOF", err)
+	}
+	close(messages)
+
+	var vendors []Vendor
+	for m := range messages {
+		vendors = append(vendors, m.Vendor)
+	}
+
+	if len(vendors) != 2 || vendors[0] != VendorKaifa || vendors[1] != VendorKamstrup {
+		t.Errorf("Got messages from %v, expected Kaifa and Kamstrup", vendors)
+	}
+
+	if len(errors) != 2 || errors[0] != hdlc.ErrInvalidFCS || errors[1] != ErrUnknownList {
+		t.Errorf("Got errors %v", errors)
+	}
+}
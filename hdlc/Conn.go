@@ -0,0 +1,379 @@
+package hdlc
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/tarm/serial"
+)
+
+type (
+	// Conn is a HDLC connection in normal response mode from a client to a
+	// server (the meter).
+	Conn struct {
+		port io.ReadWriteCloser
+		r    *Reader
+
+		// Client and Server are the addresses of the two ends. The public
+		// client has address 16, the management logical device of a server
+		// is usually 1.
+		Client Address
+		Server Address
+
+		// MaxInfoTX and MaxInfoRX are the maximum information field lengths
+		// when sending and receiving. They can be set before Connect to
+		// propose other values than the default of 128 bytes, and will be
+		// updated with the values negotiated with the server.
+		MaxInfoTX int
+		MaxInfoRX int
+
+		// ns and nr are the send and receive sequence numbers.
+		ns byte
+		nr byte
+
+		connected bool
+	}
+)
+
+// DefaultMaxInfo is the default maximum information field length.
+const DefaultMaxInfo = 128
+
+// Parameter negotiation identifiers used in SNRM and UA information fields.
+const (
+	formatIdentifier = byte(0x81)
+	groupIdentifier  = byte(0x80)
+	paramMaxInfoTX   = byte(0x05)
+	paramMaxInfoRX   = byte(0x06)
+	paramWindowTX    = byte(0x07)
+	paramWindowRX    = byte(0x08)
+)
+
+var (
+	// ErrNotConnected will be returned if data is sent before Connect.
+	ErrNotConnected = errors.New("not connected")
+
+	// ErrDisconnected will be returned if the server answers with DM.
+	ErrDisconnected = errors.New("server is in disconnected mode")
+
+	// ErrRejected will be returned if the server answers with FRMR.
+	ErrRejected = errors.New("frame rejected by server")
+
+	// ErrUnexpectedFrame will be returned if the server answers with a frame
+	// not valid at this point.
+	ErrUnexpectedFrame = errors.New("unexpected frame")
+
+	// ErrSequence will be returned if a sequence number is out of order.
+	ErrSequence = errors.New("sequence number out of order")
+)
+
+// NewConn will initialize a new HDLC connection with a user provided
+// io.ReadWriteCloser.
+func NewConn(port io.ReadWriteCloser, client Address, server Address) *Conn {
+	return &Conn{
+		port:      port,
+		r:         NewReader(port),
+		Client:    client,
+		Server:    server,
+		MaxInfoTX: DefaultMaxInfo,
+		MaxInfoRX: DefaultMaxInfo,
+	}
+}
+
+// NewConnSerial will initialize a new HDLC connection on a serial device
+// using 9600 baud, 8N1.
+func NewConnSerial(device string, client Address, server Address) (*Conn, error) {
+	conf := &serial.Config{
+		Name:        device,
+		Baud:        9600,
+		ReadTimeout: time.Millisecond * 2000,
+	}
+
+	port, err := serial.OpenPort(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewConn(port, client, server), nil
+}
+
+// Connect will send SNRM and wait for UA. The parameters from UA will be used
+// for the rest of the connection.
+func (c *Conn) Connect() error {
+	snrm := &Frame{
+		Control: SNRM | pollBit,
+	}
+
+	if c.MaxInfoTX != DefaultMaxInfo || c.MaxInfoRX != DefaultMaxInfo {
+		snrm.Info = encodeParameters(c.MaxInfoTX, c.MaxInfoRX)
+	}
+
+	reply, err := c.transact(snrm)
+	if err != nil {
+		return err
+	}
+
+	if reply.Type() != UA {
+		return ErrUnexpectedFrame
+	}
+
+	c.MaxInfoTX = DefaultMaxInfo
+	c.MaxInfoRX = DefaultMaxInfo
+
+	// The parameters are seen from the server, what it transmits we receive.
+	// A maximum of zero would leave Send segmenting forever.
+	params := decodeParameters(reply.Info)
+	if v, found := params[paramMaxInfoTX]; found {
+		if v < 1 {
+			return ErrUnexpectedFrame
+		}
+
+		c.MaxInfoRX = v
+	}
+
+	if v, found := params[paramMaxInfoRX]; found {
+		if v < 1 {
+			return ErrUnexpectedFrame
+		}
+
+		c.MaxInfoTX = v
+	}
+
+	c.ns = 0
+	c.nr = 0
+	c.connected = true
+
+	return nil
+}
+
+// Disconnect will send DISC and wait for UA or DM.
+func (c *Conn) Disconnect() error {
+	c.connected = false
+
+	reply, err := c.transact(&Frame{Control: DISC | pollBit})
+	if err == ErrDisconnected {
+		// Already disconnected.
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if reply.Type() != UA {
+		return ErrUnexpectedFrame
+	}
+
+	return nil
+}
+
+// Close will disconnect if connected and close the underlying port.
+func (c *Conn) Close() error {
+	var err error
+	if c.connected {
+		err = c.Disconnect()
+	}
+
+	closeErr := c.port.Close()
+	if err != nil {
+		return err
+	}
+
+	return closeErr
+}
+
+// Send will send info as one or more I-frames. If info is longer than
+// MaxInfoTX it will be segmented, and every segment but the last must be
+// acknowledged by the server with RR.
+func (c *Conn) Send(info []byte) error {
+	if !c.connected {
+		return ErrNotConnected
+	}
+
+	for {
+		segment := info
+		if len(segment) > c.MaxInfoTX {
+			segment = info[:c.MaxInfoTX]
+		}
+		info = info[len(segment):]
+
+		f := &Frame{
+			Segmented: len(info) > 0,
+			Control:   c.ns<<1 | c.nr<<5 | pollBit,
+			Info:      segment,
+		}
+
+		err := c.write(f)
+		if err != nil {
+			return err
+		}
+
+		c.ns = (c.ns + 1) & 0x07
+
+		if len(info) == 0 {
+			return nil
+		}
+
+		reply, err := c.read()
+		if err != nil {
+			return err
+		}
+
+		if reply.Type() != RR {
+			return ErrUnexpectedFrame
+		}
+
+		if reply.ReceiveSequence() != c.ns {
+			return ErrSequence
+		}
+	}
+}
+
+// Receive will receive I-frames from the server until a frame without the
+// segmentation bit arrives, and return the reassembled information.
+func (c *Conn) Receive() ([]byte, error) {
+	if !c.connected {
+		return nil, ErrNotConnected
+	}
+
+	var info []byte
+
+	for {
+		f, err := c.read()
+		if err != nil {
+			return nil, err
+		}
+
+		if !f.IsInformation() {
+			return nil, ErrUnexpectedFrame
+		}
+
+		// The frame must be the next in sequence, and acknowledge every
+		// frame we sent.
+		if f.SendSequence() != c.nr || f.ReceiveSequence() != c.ns {
+			return nil, ErrSequence
+		}
+
+		c.nr = (c.nr + 1) & 0x07
+		info = append(info, f.Info...)
+
+		if !f.Segmented {
+			return info, nil
+		}
+
+		// Ask for the next segment.
+		err = c.write(&Frame{Control: RR | c.nr<<5 | pollBit})
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Transact will send info and return the reply from the server.
+func (c *Conn) Transact(info []byte) ([]byte, error) {
+	err := c.Send(info)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Receive()
+}
+
+// transact will send a single frame and read a single reply.
+func (c *Conn) transact(f *Frame) (*Frame, error) {
+	err := c.write(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.read()
+}
+
+func (c *Conn) write(f *Frame) error {
+	f.Destination = c.Server
+	f.Source = c.Client
+
+	raw, err := f.Encode()
+	if err != nil {
+		return err
+	}
+
+	_, err = c.port.Write(raw)
+
+	return err
+}
+
+// read will read the next frame sent from the server to the client. Frames
+// between other stations on a shared bus are ignored. DM and FRMR are turned
+// into errors.
+func (c *Conn) read() (*Frame, error) {
+	for {
+		f, err := c.r.ReadFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		if f.Destination != c.Client || f.Source != c.Server {
+			continue
+		}
+
+		switch f.Type() {
+		case DM:
+			c.connected = false
+			return nil, ErrDisconnected
+		case FRMR:
+			return nil, ErrRejected
+		}
+
+		return f, nil
+	}
+}
+
+// encodeParameters will encode the parameter negotiation field for SNRM. The
+// window sizes are always 1.
+func encodeParameters(maxTX int, maxRX int) []byte {
+	params := []byte{}
+	params = appendParameter(params, paramMaxInfoTX, maxTX)
+	params = appendParameter(params, paramMaxInfoRX, maxRX)
+	params = appendParameter(params, paramWindowTX, 1)
+	params = appendParameter(params, paramWindowRX, 1)
+
+	return append([]byte{formatIdentifier, groupIdentifier, byte(len(params))}, params...)
+}
+
+func appendParameter(params []byte, id byte, value int) []byte {
+	if value < 0x100 {
+		return append(params, id, 1, byte(value))
+	}
+
+	return append(params, id, 2, byte(value>>8), byte(value))
+}
+
+// decodeParameters will decode a parameter negotiation field. Malformed
+// fields are ignored.
+func decodeParameters(info []byte) map[byte]int {
+	params := make(map[byte]int)
+
+	if len(info) < 3 || info[0] != formatIdentifier || info[1] != groupIdentifier {
+		return params
+	}
+
+	info = info[3:]
+	for len(info) >= 2 {
+		id := info[0]
+		length := int(info[1])
+		if len(info) < 2+length {
+			break
+		}
+
+		value := 0
+		for _, b := range info[2 : 2+length] {
+			value = value<<8 | int(b)
+		}
+
+		params[id] = value
+		info = info[2+length:]
+	}
+
+	return params
+}
@@ -0,0 +1,378 @@
+package hdlc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"net"
+	"testing"
+)
+
+var (
+	client = NewAddress(16, 0)
+	server = NewAddress(1, 0x11)
+)
+
+func TestAddress(t *testing.T) {
+	cases := []struct {
+		address Address
+		encoded string
+	}{
+		{NewAddress(16, 0), "21"},
+		{NewAddress(1, 0), "03"},
+		{NewAddress(1, 0x11), "0223"},
+		{NewAddress(1, 0x3fff), "0002feff"},
+	}
+
+	for _, c := range cases {
+		raw, err := c.address.Encode()
+		if err != nil {
+			t.Fatalf("%s: Encode() returned %s", c.address, err)
+		}
+
+		if hex.EncodeToString(raw) != c.encoded {
+			t.Errorf("%s: Encode() returned %x, expected %s", c.address, raw, c.encoded)
+		}
+
+		decoded, n, err := DecodeAddress(raw)
+		if err != nil {
+			t.Fatalf("%s: DecodeAddress() returned %s", c.address, err)
+		}
+
+		if decoded != c.address || n != len(raw) {
+			t.Errorf("DecodeAddress(%x) returned %s (%d bytes), expected %s", raw, decoded, n, c.address)
+		}
+	}
+
+	_, err := Address{Upper: 0x80, Size: 1}.Encode()
+	if err != ErrInvalidAddress {
+		t.Errorf("Encode() of too large address returned %v", err)
+	}
+
+	_, _, err = DecodeAddress([]byte{0x02, 0x04, 0x06})
+	if err != ErrInvalidAddress {
+		t.Errorf("DecodeAddress() without end bit returned %v", err)
+	}
+}
+
+func TestFrameEncode(t *testing.T) {
+	// SNRM from the public client to a server with a one byte address.
+	f := &Frame{
+		Destination: NewAddress(1, 0),
+		Source:      NewAddress(16, 0),
+		Control:     SNRM | pollBit,
+	}
+
+	raw, err := f.Encode()
+	if err != nil {
+		t.Fatalf("Encode() returned %s", err)
+	}
+
+	expected, _ := hex.DecodeString("7ea0070321930f017e")
+	if !bytes.Equal(raw, expected) {
+		t.Errorf("Encode() returned %x, expected %x", raw, expected)
+	}
+}
+
+func TestFrameDecode(t *testing.T) {
+	f := &Frame{
+		Segmented:   true,
+		Destination: server,
+		Source:      client,
+		Control:     0x32,
+		Info:        []byte{0xe6, 0xe6, 0x00, 0xc0, 0x01, 0xc1},
+	}
+
+	raw, err := f.Encode()
+	if err != nil {
+		t.Fatalf("Encode() returned %s", err)
+	}
+
+	decoded, err := DecodeFrame(raw)
+	if err != nil {
+		t.Fatalf("DecodeFrame() returned %s", err)
+	}
+
+	if !decoded.Segmented || decoded.Destination != server || decoded.Source != client || decoded.Control != f.Control || !bytes.Equal(decoded.Info, f.Info) {
+		t.Errorf("DecodeFrame() returned %+v, expected %+v", decoded, f)
+	}
+
+	if !decoded.IsInformation() || decoded.SendSequence() != 1 || decoded.ReceiveSequence() != 1 || !decoded.Poll() {
+		t.Errorf("Wrong control field interpretation: %s", decoded)
+	}
+
+	// Corrupt information field.
+	corrupt := append([]byte{}, raw...)
+	corrupt[len(corrupt)-4] ^= 0xff
+	_, err = DecodeFrame(corrupt)
+	if err != ErrInvalidFCS {
+		t.Errorf("DecodeFrame() of corrupt frame returned %v", err)
+	}
+
+	// Corrupt header, fix up FCS.
+	corrupt = append([]byte{}, raw[:len(raw)-3]...)
+	corrupt[5] ^= 0x10
+	corrupt = append(appendFCS(corrupt[1:]), Flag)
+	_, err = DecodeFrame(corrupt)
+	if err != ErrInvalidHCS {
+		t.Errorf("DecodeFrame() of corrupt header returned %v", err)
+	}
+
+	_, err = DecodeFrame([]byte{Flag, 0x80, 0x07, 0x03, 0x21, 0x93, 0x0f, 0x01, Flag})
+	if err != ErrInvalidFormat {
+		t.Errorf("DecodeFrame() of wrong format returned %v", err)
+	}
+}
+
+func TestReader(t *testing.T) {
+	a, _ := (&Frame{Destination: client, Source: server, Control: UA | pollBit}).Encode()
+	b, _ := (&Frame{Destination: client, Source: server, Control: UI, Info: []byte("push")}).Encode()
+
+	// Garbage before the first frame and a shared flag between frames.
+	stream := append([]byte{0x00, 0x13}, a...)
+	stream = append(stream, b[1:]...)
+
+	r := NewReader(bytes.NewReader(stream))
+
+	f, err := r.ReadFrame()
+	if err != nil || f.Type() != UA {
+		t.Fatalf("ReadFrame() returned %v, %v", f, err)
+	}
+
+	f, err = r.ReadFrame()
+	if err != nil || f.Type() != UI || string(f.Info) != "push" {
+		t.Fatalf("ReadFrame() returned %v, %v", f, err)
+	}
+}
+
+// testServer is a minimal HDLC server answering on one end of a pipe.
+type testServer struct {
+	t    *testing.T
+	conn net.Conn
+	r    *Reader
+	ns   byte
+	nr   byte
+}
+
+func (s *testServer) read() *Frame {
+	f, err := s.r.ReadFrame()
+	if err != nil {
+		s.t.Errorf("Server failed to read frame: %s", err)
+		return nil
+	}
+
+	return f
+}
+
+func (s *testServer) write(f *Frame) {
+	f.Destination = client
+	f.Source = server
+
+	raw, err := f.Encode()
+	if err != nil {
+		s.t.Errorf("Server failed to encode frame: %s", err)
+		return
+	}
+
+	s.conn.Write(raw)
+}
+
+// serve will accept a connection, receive one segmented request and echo it
+// back in segments of size bytes.
+func (s *testServer) serve(size int) {
+	f := s.read()
+	if f == nil || f.Type() != SNRM {
+		s.t.Errorf("Expected SNRM, got %v", f)
+		return
+	}
+
+	params := decodeParameters(f.Info)
+	if params[paramMaxInfoRX] != 16 {
+		s.t.Errorf("Expected proposed max info RX 16, got %v", params)
+	}
+
+	// We transmit at most size bytes and receive at most 10.
+	s.write(&Frame{Control: UA | pollBit, Info: encodeParameters(size, 10)})
+
+	var request []byte
+	for {
+		f = s.read()
+		if f == nil || !f.IsInformation() || f.SendSequence() != s.nr {
+			s.t.Errorf("Expected I-frame %d, got %v", s.nr, f)
+			return
+		}
+
+		s.nr = (s.nr + 1) & 0x07
+		request = append(request, f.Info...)
+
+		if !f.Segmented {
+			break
+		}
+
+		s.write(&Frame{Control: RR | s.nr<<5 | pollBit})
+	}
+
+	for len(request) > 0 {
+		segment := request
+		if len(segment) > size {
+			segment = request[:size]
+		}
+		request = request[len(segment):]
+
+		s.write(&Frame{
+			Segmented: len(request) > 0,
+			Control:   s.ns<<1 | s.nr<<5 | pollBit,
+			Info:      segment,
+		})
+		s.ns = (s.ns + 1) & 0x07
+
+		if len(request) > 0 {
+			f = s.read()
+			if f == nil || f.Type() != RR || f.ReceiveSequence() != s.ns {
+				s.t.Errorf("Expected RR(%d), got %v", s.ns, f)
+				return
+			}
+		}
+	}
+
+	f = s.read()
+	if f == nil || f.Type() != DISC {
+		s.t.Errorf("Expected DISC, got %v", f)
+		return
+	}
+
+	s.write(&Frame{Control: UA | pollBit})
+}
+
+func TestConn(t *testing.T) {
+	a, b := net.Pipe()
+	defer b.Close()
+
+	s := &testServer{t: t, conn: b, r: NewReader(b)}
+	done := make(chan struct{})
+	go func() {
+		s.serve(16)
+		close(done)
+	}()
+
+	c := NewConn(a, client, server)
+	c.MaxInfoRX = 16
+
+	_, err := c.Transact([]byte("hello"))
+	if err != ErrNotConnected {
+		t.Errorf("Transact() before Connect() returned %v", err)
+	}
+
+	err = c.Connect()
+	if err != nil {
+		t.Fatalf("Connect() returned %s", err)
+	}
+
+	if c.MaxInfoTX != 10 || c.MaxInfoRX != 16 {
+		t.Errorf("Negotiated TX %d, RX %d, expected 10 and 16", c.MaxInfoTX, c.MaxInfoRX)
+	}
+
+	request := []byte("The quick brown fox jumps over the lazy dog")
+	reply, err := c.Transact(request)
+	if err != nil {
+		t.Fatalf("Transact() returned %s", err)
+	}
+
+	if !bytes.Equal(reply, request) {
+		t.Errorf("Transact() returned %q, expected %q", reply, request)
+	}
+
+	err = c.Close()
+	if err != nil {
+		t.Errorf("Close() returned %s", err)
+	}
+
+	<-done
+}
+
+func TestConnDisconnected(t *testing.T) {
+	a, b := net.Pipe()
+	defer b.Close()
+
+	go func() {
+		s := &testServer{t: t, conn: b, r: NewReader(b)}
+		s.read()
+		s.write(&Frame{Control: DM | pollBit})
+	}()
+
+	c := NewConn(a, client, server)
+	defer a.Close()
+
+	err := c.Connect()
+	if err != ErrDisconnected {
+		t.Errorf("Connect() returned %v, expected %s", err, ErrDisconnected)
+	}
+}
+
+func TestConnZeroMaxInfo(t *testing.T) {
+	for _, params := range [][2]int{{0, 10}, {10, 0}} {
+		a, b := net.Pipe()
+
+		go func(tx int, rx int) {
+			s := &testServer{t: t, conn: b, r: NewReader(b)}
+			s.read()
+			s.write(&Frame{Control: UA | pollBit, Info: encodeParameters(tx, rx)})
+		}(params[0], params[1])
+
+		c := NewConn(a, client, server)
+
+		err := c.Connect()
+		if err != ErrUnexpectedFrame {
+			t.Errorf("Connect() with UA advertising %v returned %v, expected %s", params, err, ErrUnexpectedFrame)
+		}
+
+		_, err = c.Transact([]byte("hello"))
+		if err != ErrNotConnected {
+			t.Errorf("Transact() after failed Connect() returned %v", err)
+		}
+
+		a.Close()
+		b.Close()
+	}
+}
+
+func TestConnReceive(t *testing.T) {
+	a, b := net.Pipe()
+	defer b.Close()
+	defer a.Close()
+
+	go func() {
+		frames := []*Frame{
+			// From another server, must be ignored.
+			{Destination: client, Source: NewAddress(2, 0), Control: 0<<1 | 1<<5 | pollBit, Info: []byte("other")},
+			{Destination: client, Source: server, Control: 0<<1 | 1<<5 | pollBit, Info: []byte("mine")},
+
+			// Not acknowledging the frame sent.
+			{Destination: client, Source: server, Control: 1<<1 | 1<<5 | pollBit, Info: []byte("late")},
+		}
+
+		for _, f := range frames {
+			raw, err := f.Encode()
+			if err != nil {
+				t.Errorf("Failed to encode frame: %s", err)
+				return
+			}
+
+			b.Write(raw)
+		}
+	}()
+
+	c := NewConn(a, client, server)
+	c.connected = true
+	c.ns = 1
+
+	info, err := c.Receive()
+	if err != nil || string(info) != "mine" {
+		t.Errorf("Receive() returned %q, %v, expected \"mine\"", info, err)
+	}
+
+	c.ns = 2
+	_, err = c.Receive()
+	if err != ErrSequence {
+		t.Errorf("Receive() of frame with wrong N(R) returned %v, expected %s", err, ErrSequence)
+	}
+}
@@ -0,0 +1,480 @@
+package hdlc
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+type (
+	// Address is a HDLC address as used by DLMS/COSEM. Client addresses are
+	// always one byte, server addresses can be one, two or four bytes.
+	Address struct {
+		Upper uint16 // Logical device
+		Lower uint16 // Physical device, not used with one byte addresses
+		Size  int    // 1, 2 or 4
+	}
+
+	// Frame is a HDLC frame of frame format type 3.
+	Frame struct {
+		// Segmented is set if more segments follow this frame.
+		Segmented   bool
+		Destination Address
+		Source      Address
+		Control     byte
+		Info        []byte
+	}
+
+	// Reader reads HDLC frames from a stream.
+	Reader struct {
+		r *bufio.Reader
+
+		// flagged is set when the last closing flag can be the opening flag
+		// of the next frame.
+		flagged bool
+	}
+)
+
+const (
+	// Flag is the opening and closing flag of a frame.
+	Flag = byte(0x7e)
+
+	// FormatType3 is the frame format used by IEC 62056-46.
+	FormatType3 = byte(0xa0)
+
+	// segmentBit is set in the format field if more segments follow.
+	segmentBit = byte(0x08)
+
+	// pollBit is the poll/final bit in the control field.
+	pollBit = byte(0x10)
+)
+
+// Control field values for unnumbered and supervisory frames, without the
+// poll/final bit.
+const (
+	SNRM = byte(0x83) // Set normal response mode
+	DISC = byte(0x43) // Disconnect
+	UA   = byte(0x63) // Unnumbered acknowledge
+	DM   = byte(0x0f) // Disconnected mode
+	FRMR = byte(0x87) // Frame reject
+	UI   = byte(0x03) // Unnumbered information
+	RR   = byte(0x01) // Receive ready
+	RNR  = byte(0x05) // Receive not ready
+)
+
+// LLC headers used for DLMS/COSEM information fields.
+var (
+	LLCRequest  = []byte{0xe6, 0xe6, 0x00}
+	LLCResponse = []byte{0xe6, 0xe7, 0x00}
+)
+
+var (
+	// ErrInvalidAddress will be returned if an address cannot be encoded or
+	// decoded.
+	ErrInvalidAddress = errors.New("invalid HDLC address")
+
+	// ErrInvalidFormat will be returned if a frame is not format type 3.
+	ErrInvalidFormat = errors.New("invalid frame format")
+
+	// ErrFrameTooShort will be returned if a frame is shorter than its length
+	// field, or too short to hold addresses and control field.
+	ErrFrameTooShort = errors.New("frame too short")
+
+	// ErrFrameTooLong will be returned if a frame does not fit in the 11 bit
+	// length field.
+	ErrFrameTooLong = errors.New("frame too long")
+
+	// ErrInvalidHCS will be returned if the header check sequence did not
+	// verify.
+	ErrInvalidHCS = errors.New("header check sequence did not validate")
+
+	// ErrInvalidFCS will be returned if the frame check sequence did not
+	// verify.
+	ErrInvalidFCS = errors.New("frame check sequence did not validate")
+)
+
+// NewAddress will return a one byte address if lower is zero and upper fits,
+// a two byte address if both fit in 7 bits, and a four byte address
+// otherwise.
+func NewAddress(upper, lower uint16) Address {
+	switch {
+	case lower == 0 && upper < 0x80:
+		return Address{Upper: upper, Size: 1}
+	case upper < 0x80 && lower < 0x80:
+		return Address{Upper: upper, Lower: lower, Size: 2}
+	}
+
+	return Address{Upper: upper, Lower: lower, Size: 4}
+}
+
+// Encode will encode the address. The lowest bit of every byte is zero,
+// except for the last byte.
+func (a Address) Encode() ([]byte, error) {
+	var raw []byte
+
+	switch a.Size {
+	case 1:
+		if a.Upper > 0x7f {
+			return nil, ErrInvalidAddress
+		}
+
+		raw = []byte{byte(a.Upper << 1)}
+	case 2:
+		if a.Upper > 0x7f || a.Lower > 0x7f {
+			return nil, ErrInvalidAddress
+		}
+
+		raw = []byte{byte(a.Upper << 1), byte(a.Lower << 1)}
+	case 4:
+		if a.Upper > 0x3fff || a.Lower > 0x3fff {
+			return nil, ErrInvalidAddress
+		}
+
+		raw = []byte{
+			byte(a.Upper >> 7 << 1), byte(a.Upper << 1),
+			byte(a.Lower >> 7 << 1), byte(a.Lower << 1),
+		}
+	default:
+		return nil, ErrInvalidAddress
+	}
+
+	raw[len(raw)-1] |= 0x01
+
+	return raw, nil
+}
+
+// DecodeAddress will decode an address at the start of raw, returning the
+// address and the number of bytes used.
+func DecodeAddress(raw []byte) (Address, int, error) {
+	n := 0
+	for n < len(raw) && n < 4 {
+		n++
+
+		if raw[n-1]&0x01 > 0 {
+			break
+		}
+	}
+
+	if n == 0 || raw[n-1]&0x01 == 0 {
+		return Address{}, 0, ErrInvalidAddress
+	}
+
+	switch n {
+	case 1:
+		return Address{Upper: uint16(raw[0] >> 1), Size: 1}, 1, nil
+	case 2:
+		return Address{Upper: uint16(raw[0] >> 1), Lower: uint16(raw[1] >> 1), Size: 2}, 2, nil
+	case 4:
+		return Address{
+			Upper: uint16(raw[0]>>1)<<7 | uint16(raw[1]>>1),
+			Lower: uint16(raw[2]>>1)<<7 | uint16(raw[3]>>1),
+			Size:  4,
+		}, 4, nil
+	}
+
+	return Address{}, 0, ErrInvalidAddress
+}
+
+// String will return the address as "upper/lower".
+func (a Address) String() string {
+	if a.Size == 1 {
+		return fmt.Sprintf("%d", a.Upper)
+	}
+
+	return fmt.Sprintf("%d/%d", a.Upper, a.Lower)
+}
+
+// Encode will encode the frame including opening and closing flag.
+func (f *Frame) Encode() ([]byte, error) {
+	dst, err := f.Destination.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := f.Source.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	// Format field, filled in when the length is known.
+	body := []byte{0, 0}
+	body = append(body, dst...)
+	body = append(body, src...)
+	body = append(body, f.Control)
+
+	length := len(body) + 2
+	if len(f.Info) > 0 {
+		length += 2 + len(f.Info)
+	}
+
+	if length > 0x7ff {
+		return nil, ErrFrameTooLong
+	}
+
+	body[0] = FormatType3 | byte(length>>8)
+	body[1] = byte(length)
+	if f.Segmented {
+		body[0] |= segmentBit
+	}
+
+	if len(f.Info) > 0 {
+		body = appendFCS(body)
+		body = append(body, f.Info...)
+	}
+
+	body = appendFCS(body)
+
+	raw := make([]byte, 0, len(body)+2)
+	raw = append(raw, Flag)
+	raw = append(raw, body...)
+	raw = append(raw, Flag)
+
+	return raw, nil
+}
+
+// DecodeFrame will decode a single frame. The opening and closing flags are
+// optional.
+func DecodeFrame(raw []byte) (*Frame, error) {
+	if len(raw) > 0 && raw[0] == Flag {
+		raw = raw[1:]
+	}
+
+	if len(raw) > 0 && raw[len(raw)-1] == Flag {
+		raw = raw[:len(raw)-1]
+	}
+
+	if len(raw) < 2 {
+		return nil, ErrFrameTooShort
+	}
+
+	if raw[0]&0xf0 != FormatType3 {
+		return nil, ErrInvalidFormat
+	}
+
+	length := int(raw[0]&0x07)<<8 | int(raw[1])
+	if len(raw) < length {
+		return nil, ErrFrameTooShort
+	}
+	raw = raw[:length]
+
+	if length < 7 {
+		return nil, ErrFrameTooShort
+	}
+
+	if crc16(raw) != 0xf0b8 {
+		return nil, ErrInvalidFCS
+	}
+
+	f := &Frame{
+		Segmented: raw[0]&segmentBit > 0,
+	}
+
+	pos := 2
+
+	var n int
+	var err error
+	f.Destination, n, err = DecodeAddress(raw[pos:])
+	if err != nil {
+		return nil, err
+	}
+	pos += n
+
+	f.Source, n, err = DecodeAddress(raw[pos:])
+	if err != nil {
+		return nil, err
+	}
+	pos += n
+
+	if pos+3 > length {
+		return nil, ErrFrameTooShort
+	}
+
+	f.Control = raw[pos]
+	pos++
+
+	// Information field with header check sequence.
+	if length > pos+2 {
+		if pos+4 > length {
+			return nil, ErrFrameTooShort
+		}
+
+		if crc16(raw[:pos+2]) != 0xf0b8 {
+			return nil, ErrInvalidHCS
+		}
+
+		f.Info = raw[pos+2 : length-2]
+	}
+
+	return f, nil
+}
+
+// Poll will return true if the poll/final bit is set.
+func (f *Frame) Poll() bool {
+	return f.Control&pollBit > 0
+}
+
+// IsInformation will return true if the frame is an I-frame.
+func (f *Frame) IsInformation() bool {
+	return f.Control&0x01 == 0
+}
+
+// Type will return the control field without poll/final bit and sequence
+// numbers. For I-frames 0 is returned.
+func (f *Frame) Type() byte {
+	switch {
+	case f.IsInformation():
+		return 0
+	case f.Control&0x03 == 0x01:
+		// Supervisory frame.
+		return f.Control & 0x0f
+	}
+
+	return f.Control &^ pollBit
+}
+
+// SendSequence will return N(S) of an I-frame.
+func (f *Frame) SendSequence() byte {
+	return f.Control >> 1 & 0x07
+}
+
+// ReceiveSequence will return N(R) of an I-frame or supervisory frame.
+func (f *Frame) ReceiveSequence() byte {
+	return f.Control >> 5
+}
+
+// String will return a short description of the frame.
+func (f *Frame) String() string {
+	var kind string
+
+	switch f.Type() {
+	case 0:
+		kind = fmt.Sprintf("I(%d,%d)", f.SendSequence(), f.ReceiveSequence())
+	case RR:
+		kind = fmt.Sprintf("RR(%d)", f.ReceiveSequence())
+	case RNR:
+		kind = fmt.Sprintf("RNR(%d)", f.ReceiveSequence())
+	case SNRM:
+		kind = "SNRM"
+	case DISC:
+		kind = "DISC"
+	case UA:
+		kind = "UA"
+	case DM:
+		kind = "DM"
+	case FRMR:
+		kind = "FRMR"
+	case UI:
+		kind = "UI"
+	default:
+		kind = fmt.Sprintf("0x%02x", f.Control)
+	}
+
+	if f.Segmented {
+		kind += " segmented"
+	}
+
+	return fmt.Sprintf("%s %s->%s %d bytes", kind, f.Source, f.Destination, len(f.Info))
+}
+
+// NewReader will instantiate a new frame reader.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{
+		r: bufio.NewReader(r),
+	}
+}
+
+// ReadRaw will read the next frame including flags without decoding it.
+// Anything before the opening flag is discarded.
+func (r *Reader) ReadRaw() ([]byte, error) {
+	var b byte
+	var err error
+
+	// Find opening flag. The closing flag of a frame can be shared with the
+	// next, and consecutive flags are allowed between frames.
+	if r.flagged {
+		b = Flag
+		r.flagged = false
+	}
+
+	for b != Flag {
+		b, err = r.r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for b == Flag {
+		b, err = r.r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if b&0xf0 != FormatType3 {
+		return nil, ErrInvalidFormat
+	}
+
+	second, err := r.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	length := int(b&0x07)<<8 | int(second)
+	if length < 7 {
+		return nil, ErrFrameTooShort
+	}
+
+	raw := make([]byte, length+2)
+	raw[0] = Flag
+	raw[1] = b
+	raw[2] = second
+
+	// The rest of the frame and the closing flag.
+	_, err = io.ReadFull(r.r, raw[3:])
+	if err != nil {
+		return nil, err
+	}
+
+	r.flagged = raw[len(raw)-1] == Flag
+
+	return raw, nil
+}
+
+// ReadFrame will read and decode the next frame.
+func (r *Reader) ReadFrame() (*Frame, error) {
+	raw, err := r.ReadRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	return DecodeFrame(raw)
+}
+
+// appendFCS will calculate the check sequence of data and append it.
+func appendFCS(data []byte) []byte {
+	fcs := crc16(data) ^ 0xffff
+
+	return append(data, byte(fcs), byte(fcs>>8))
+}
+
+// crc16 will calculate CRC-16/X-25 as used for HCS and FCS, without the final
+// inversion. Running it over data including a valid check sequence will
+// result in 0xf0b8.
+func crc16(data []byte) uint16 {
+	reg := uint16(0xffff)
+
+	for _, b := range data {
+		reg ^= uint16(b)
+
+		for i := 0; i < 8; i++ {
+			if reg&0x0001 > 0 {
+				reg = (reg >> 1) ^ 0x8408
+			} else {
+				reg >>= 1
+			}
+		}
+	}
+
+	return reg
+}
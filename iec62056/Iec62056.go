@@ -4,15 +4,20 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	"github.com/tarm/serial"
+
+	"github.com/abrander/gometer/trace"
 )
 
 type (
 	// Iec62056 represents a Iec62056-compatible meter.
 	Iec62056 struct {
-		port io.ReadWriteCloser
+		port    io.ReadWriteCloser
+		pending []byte
+		tracer  trace.Tracer
 	}
 )
 
@@ -78,29 +83,36 @@ func (i *Iec62056) read(length int, until *byte) ([]byte, error) {
 	var reply []byte
 
 	for {
-		n, err := i.port.Read(buf)
-		if err != nil {
-			return reply, err
+		// Bytes following the end of the previous read are kept, as a
+		// network connection can deliver everything at once.
+		if len(i.pending) == 0 {
+			n, err := i.port.Read(buf)
+			if err != nil {
+				return reply, err
+			}
+
+			i.pending = append(i.pending, buf[0:n]...)
 		}
 
-		if n > 0 {
-			for _, b := range buf[0:n] {
-				reply = append(reply, b)
+		for len(i.pending) > 0 {
+			b := i.pending[0]
+			i.pending = i.pending[1:]
 
-				if until != nil && b == *until {
-					return reply, nil
-				}
+			reply = append(reply, b)
 
-				if len(reply) >= length {
-					return reply, nil
-				}
+			if until != nil && b == *until {
+				return reply, nil
+			}
+
+			if len(reply) >= length {
+				return reply, nil
 			}
 		}
 	}
 }
 
-// bcc will calculate a "block check character" according to ISO/IEC 1155:1978
-func bcc(message []byte) byte {
+// BCC will calculate a "block check character" according to ISO/IEC 1155:1978
+func BCC(message []byte) byte {
 	if len(message) < 1 {
 		return 0
 	}
@@ -121,35 +133,77 @@ func bcc(message []byte) byte {
 	return reg
 }
 
+// SetTracer will trace all traffic to t, and every sign-in annotated with
+// the values decoded.
+func (i *Iec62056) SetTracer(t trace.Tracer) {
+	i.port = trace.NewPort(i.port, t)
+	i.tracer = t
+}
+
 // Signin will start a session with the meter.
-func (i *Iec62056) Signin(address string) ([]byte, ValueCollection, error) {
+func (i *Iec62056) Signin(address string) (identify []byte, collection ValueCollection, err error) {
+	identify, payload, err := i.Readout(address)
+	if err != nil {
+		return identify, nil, err
+	}
+
+	collection, err = NewValueCollection(payload)
+	return identify, collection, err
+}
+
+// Readout will sign in like Signin, but return the data readout as received
+// from the meter, from STX to ETX, without parsing it.
+func (i *Iec62056) Readout(address string) (identify []byte, payload []byte, err error) {
 	if len(address) > 32 {
 		return nil, nil, ErrAddressTooLong
 	}
 
 	// Say hello :)
+	i.pending = nil
 	signin := fmt.Sprintf("/?%s!\r\n", address)
+
+	var checksum []byte
+	if i.tracer != nil {
+		start := time.Now()
+
+		defer func() {
+			e := trace.Exchange{
+				Protocol: "iec62056",
+				Start:    start,
+				End:      time.Now(),
+				Request:  []byte(signin),
+				Reply:    append(append(append([]byte{}, identify...), payload...), checksum...),
+				Err:      err,
+			}
+
+			if err == nil {
+				collection, _ := NewValueCollection(payload)
+				e.Result = fmt.Sprintf("identification %q, %d values", strings.TrimSpace(string(identify)), len(collection))
+			}
+
+			i.tracer.Exchange(e)
+		}()
+	}
+
 	i.port.Write([]byte(signin))
 
 	// Read "identify" line
-	identify, err := i.read(1000, &LineFeed)
+	identify, err = i.read(1000, &LineFeed)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	// read message
-	payload, err := i.read(3000, &FrameEnd)
+	payload, err = i.read(3000, &FrameEnd)
 	if err != nil {
 		return identify, nil, err
 	}
 
 	// read and ignore checksum
-	_, err = i.read(1, nil)
+	checksum, err = i.read(1, nil)
 	if err != nil {
 		return identify, nil, err
 	}
 
-	collection, err := NewValueCollection(payload)
-	//	fmt.Printf("ID: \033[32m%v\033[0m\nPayload:\n\033[32m%v\033[0m\nBCC: 0x\033[32m%x\033[0m\nCalculated BCC: 0x\033[32m%x\033[0m\n", string(identify), string(payload[1:]), checksum[0], bcc(payload))
-	return identify, collection, err
+	return identify, payload, nil
 }
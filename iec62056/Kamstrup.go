@@ -0,0 +1,101 @@
+package iec62056
+
+import (
+	"sort"
+
+	"github.com/abrander/gometer/kamstrup"
+)
+
+type (
+	// KamstrupMap maps the KMP registers of a meter model to OBIS codes.
+	KamstrupMap map[uint16]Obis
+)
+
+var (
+	// Kamstrup382 is the map of Kamstrup 382 and similar electricity meters.
+	// The hi-res energy registers are left out, as they carry the same
+	// quantity as EnergyIn and EnergyOut.
+	Kamstrup382 = KamstrupMap{
+		kamstrup.EnergyIn:  ActiveEnergyImport,
+		kamstrup.EnergyOut: ActiveEnergyExport,
+		kamstrup.VoltageP1: VoltageL1,
+		kamstrup.VoltageP2: VoltageL2,
+		kamstrup.VoltageP3: VoltageL3,
+		kamstrup.CurrentP1: CurrentL1,
+		kamstrup.CurrentP2: CurrentL2,
+		kamstrup.CurrentP3: CurrentL3,
+		kamstrup.PowerP1:   ActivePowerImportL1,
+		kamstrup.PowerP2:   ActivePowerImportL2,
+		kamstrup.PowerP3:   ActivePowerImportL3,
+	}
+
+	// KamstrupMultical601 is the map of Kamstrup Multical 601 heat meters.
+	// Energy registers without an OBIS equivalent are left out.
+	KamstrupMultical601 = KamstrupMap{
+		kamstrup.HeatEnergy:    HeatEnergy,
+		kamstrup.CoolingEnergy: CoolingEnergy,
+		kamstrup.Volume1:       NewObis("6-0:2.0.0"),
+		kamstrup.Power1:        NewObis("6-0:8.0.0"),
+		kamstrup.Flow1:         NewObis("6-0:9.0.0"),
+		kamstrup.T1:            NewObis("6-0:10.0.0"),
+		kamstrup.T2:            NewObis("6-0:11.0.0"),
+		kamstrup.T1T2:          NewObis("6-0:12.0.0"),
+	}
+
+	// KamstrupModels holds the maps of known models by name.
+	KamstrupModels = map[string]KamstrupMap{
+		"382":         Kamstrup382,
+		"multical601": KamstrupMultical601,
+	}
+)
+
+// Values will convert the result of Kamstrup.GetRegisters to a collection.
+// Registers not in the map are left out.
+func (m KamstrupMap) Values(registers map[uint16]kamstrup.Value) ValueCollection {
+	c := make(ValueCollection)
+
+	for register, value := range registers {
+		if obis, found := m[register]; found {
+			c[obis] = value
+		}
+	}
+
+	return c
+}
+
+// Registers will return the registers with an OBIS code matching at least
+// one of the patterns, in ascending order. Without patterns all registers
+// are returned.
+func (m KamstrupMap) Registers(patterns ...ObisPattern) []uint16 {
+	var registers []uint16
+
+	for register, obis := range m {
+		match := len(patterns) == 0
+		for _, p := range patterns {
+			if p.Match(obis) {
+				match = true
+				break
+			}
+		}
+
+		if match {
+			registers = append(registers, register)
+		}
+	}
+
+	sort.Slice(registers, func(i, j int) bool { return registers[i] < registers[j] })
+
+	return registers
+}
+
+// Register will return the register holding the value of an OBIS code. Codes
+// are compared in canonical form, so "1-0:01.08.00" finds "1-0:1.8.0".
+func (m KamstrupMap) Register(o Obis) (uint16, bool) {
+	for register, obis := range m {
+		if obis.Canonical() == o.Canonical() {
+			return register, true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,47 @@
+package iec62056
+
+import (
+	"testing"
+
+	"github.com/abrander/gometer/kamstrup"
+)
+
+func TestKamstrupMap(t *testing.T) {
+	registers := map[uint16]kamstrup.Value{
+		kamstrup.EnergyIn:            {Value: 1234.5, Unit: kamstrup.UnitKWh},
+		kamstrup.VoltageP1:           {Value: 230, Unit: kamstrup.UnitV},
+		kamstrup.InternalTemperature: {Value: 25, Unit: kamstrup.UnitCelsius},
+	}
+
+	c := Kamstrup382.Values(registers)
+	if len(c) != 2 {
+		t.Errorf("Values() returned %v", c)
+	}
+
+	if v := c[ActiveEnergyImport]; v.Value != 1234.5 || v.Unit != kamstrup.UnitKWh {
+		t.Errorf("Active energy import is %s", v)
+	}
+
+	if v := c[VoltageL1]; v.Value != 230 {
+		t.Errorf("Voltage L1 is %s", v)
+	}
+
+	voltages := Kamstrup382.Registers(MustObisPattern("1-0:[32,52,72].7.0"))
+	if len(voltages) != 3 || voltages[0] != kamstrup.VoltageP1 || voltages[2] != kamstrup.VoltageP3 {
+		t.Errorf("Registers() returned %v", voltages)
+	}
+
+	if all := Kamstrup382.Registers(); len(all) != len(Kamstrup382) {
+		t.Errorf("Registers() without patterns returned %v", all)
+	}
+
+	register, found := KamstrupMultical601.Register(NewObis("6-0:1.0.0"))
+	if !found || register != kamstrup.HeatEnergy {
+		t.Errorf("Register() of heat energy returned %04x, %v", register, found)
+	}
+
+	register, found = Kamstrup382.Register(NewObis("1-0:01.08.00*255"))
+	if !found || register != kamstrup.EnergyIn {
+		t.Errorf("Register() of non-canonical code returned %04x, %v", register, found)
+	}
+}
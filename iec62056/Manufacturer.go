@@ -1,939 +1,271 @@
 package iec62056
 
+//go:generate go run ./internal/genmanufacturers -o ManufacturerList.go flagids.csv
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/abrander/gometer/iec62056/internal/flagid"
+)
+
 type (
 	// Manufacturer is a manufacturer identified in a IEC62056 signin reply.
 	Manufacturer string
+
+	// ManufacturerEntry is a single entry in the DLMS FLAG ID list.
+	ManufacturerEntry struct {
+		Code    Manufacturer
+		Name    string
+		Address string
+		Country string
+	}
+
+	// ManufacturerRegistry is a set of known manufacturers. It is safe for
+	// concurrent use.
+	ManufacturerRegistry struct {
+		mu      sync.RWMutex
+		entries map[Manufacturer]ManufacturerEntry
+	}
 )
 
 var (
-	// Known manufacturers as of May 2016 as listed here:
-	// http://dlms.com/organization/flagmanufacturesids/
-	manufacturers = map[Manufacturer]string{
-		"AAA": "Aventies GmbH, Linzerstraße 25, 53577 Neustadt/Wied, Germany",
-		"ABB": "ABB AB, P.O. Box 1005, SE-61129 Nyköping, Nyköping,Sweden",
-		"ABN": "ABN Braun AG, Platenstraße 59, 90441 Nürnberg, Germany",
-		"ABR": "ABB s.r.o., Videnska 117, Brno, Czech Republic",
-		"ACA": "Acean, Zi de la Liane, BP 439, 62206 Boulogne Sur Mer Cedex, FRANCE",
-		"ACB": "AcBel Polytech Inc., No. 159, Sec. 3, Danjin Rd., Tamsui Dist.,New Taipei, Taiwan (R.O.C.)",
-		"ACC": "Accurate (Pvt) Ltd, Office # 2, first floor, Ross Residentia, 234 Dhana Singhwala, 1 Campus Road Canal Bank Lhr, Lahore, Pakistan",
-		"ACE": "Actaris, France. (Electricity)",
-		"ACG": "Actaris, France. (Gas)",
-		"ACH": "Acantho S.p.A. Via Molino Rosso 8 40026 Imola Italy",
-		"ACN": "ACN Advanced Communications Networks SA, Rue du Puits-Godet 8a, Neuchâtel, Switzerland",
-		"ACT": "Activis Metering GmbH, Roedgener Strasse 18, D-57234 Wilnsdorf, Germany",
-		"ACW": "Actaris, France. (Water and Heat)",
-		"ADE": "Yantai Aerospace Delu Energy-saving Technology Co., Ltd., No. 6 Mingda Road Shengquan Industrial Zone Laishan District, Yantai city Shandong Province, China",
-		"ADD": "ADD-Production S.R.L., 36, Dragomirna str., MD-2008, Chisinau, Republic of Moldova",
-		"ADX": "ADD-Production S.R.L., 36, Dragomirna str., MD-2008, Chisinau, Republic of Moldova",
-		"ADN": "Aidon Oy, 40101 Jyvaskyla, Finland",
-		"ADU": "Adunos GmbH, Am Schlangengraben 16, D-13597 Berlin, Germany",
-		"AEC": "Advance Electronics Company, Riyadh, Saudi Arabia",
-		"AEE": "Atlas Electronics, 17530 Surdulica, Serbia and Montenegro",
-		"AEG": "AEG",
-		"AEL": "Kohler, Turkey",
-		"AEM": "S.C. AEM S.A. Romania",
-		"AER": "Aerzener Maschinenfabrik GmbH, Reherweg 28, 31855 Aerzen, Germany",
-		"AFX": "Alflex Products, Zoetermeer, Holland",
-		"AGE": "AccessGate AB, Rissneleden 144, 174 57 Sundbyberg, Sweden",
-		"AGT": "Agnitio Technologies Pvt Ltd, G 251 Sector 63, Noida 201307India",
-		"AHV": "aventies GmbH Corinthstraße 54 Berlin Germany",
-		"ALC": "AlfaCentauri S.p.A. Via Giardino 1, Guardiagrele, Italy",
-		"ALF": "Alfatech Elektromed Elektronik, Ankara, Turkey",
-		"ALG": "Algodue Elettronica srl, Via Passerina, 3/A, Fontaneto D'Agogna, Italy",
-		"ALR": "Algorab S.R.L., via Negrelli 21/13, Lavis (TN) Italy",
-		"ALT": "Amplitec GmbH, Gootkoppel 28, Reinfeld, Germany",
-		"ALV": "Alvicom Ltd. Infopark walkway 1. Budapest Hungary",
-		"AMB": "Amber wireless GmbH, Rudi-Schillings-Str. 31, 54296 Trier, Germany",
-		"AMC": "Arch Meter Corporation 4F, No.3-2, Industry E. Rd. 9, Hsinchu Science Park Hsinchu Taiwan",
-		"AME": "AVON METERS PRIVATE LIMITED, D-15/16/17, INDUSTRIAL FOCAL POINT, DERABASSI,PUNJAB-140507INDIA",
-		"AMH": "AmiHo Ltd, 1010 Cambourne Business Park, Cambourne, Cambridge, CB1 9AY, UK",
-		"AMI": "AMI Tech(I) Pvt. Ltd, #205&206,NSIC-EMDBP, Kamalanagar, ECIL PO, Hyderabad- 500062, India",
-		"AML": "Eon Electric Ltd., C-124, Hosierry Complex, Noida Phase II, NOIDA, INDIA",
-		"AMP": "Ampy Automation Digilog Ltd",
-		"AMR": "Actislink, Krakusa 11, Krakow, Poland",
-		"AMS": "Zhejiang Joy Electronic Technology Co.,Ltd, Zhejiang, China.",
-		"AMT": "Aquametro",
-		"AMX": "APATOR METRIX SA, Piaskowa 3, 83-110 Tczew, Poland",
-		"ANA": "Anacle Systems PTE LTD, 1 International Business Park #05-02, Singapore",
-		"AND": "ANDIS sro, Bratislava, Slovakia",
-		"AON": "ASTRON d.o.o., Cesta XIV. Divizije 51, Maribor, Slovenia",
-		"APA": "SA (Electricity), Zolkiewskiego 21/29, 87-100, Torun,Poland",
-		"APL": "APLI s.r.o, Kladnianska 1, Bratislava, Slovakia",
-		"APR": "Apronecs Ltd, Gabrovo, Bulgaria",
-		"APS": "Apsis Kontrol Sistemleri, Turkey",
-		"APT": "Apator SA (Gas, water and heat), ó kiewskiego 21/29, Toru , Poland",
-		"APX": "Amplex A/S, Aarhus C, Denmark",
-		"AQL": "Aqualoc, Unit 8 Marvil Park, Roodepoort, Johannesburg, South Africa",
-		"AQM": "Aquametro AG, Ringstrasse 75, Therwil, Switzerland",
-		"AQT": "AQUATHERM P.P.H, Kujawinski, Lomianki, Poland",
-		"AQU": "Aquamess gmbh, Gewerbering 32 Brieselang, Germany",
-		"ARC": "Arcelik AS., Istanbul, Turkey",
-		"ARF": "ADEUNIS_RF, 283 rue Louis NEEL, CROLLES 38920, France",
-		"ARM": "arivus metering GmbH, Mielestr. 2, 14542 Werder (Havel), Germany",
-		"ARS": "ADD Bulgaria Ltd, Bul. 6 septemvri 252 Et.7, Plovdiv, Bulgaria",
-		"ART": "Electrotécnica Arteche Smart Grid, Derio Bidea 28, Zabalondo Industrialdea, 48100 Mungia, Bizkaia. Spain",
-		"ASA": "Asac srl, via degli Olmi, 28, Cessalto, Italy",
-		"ASR": "Erelsan Elektrik ve Elektronik, Malzeme, Istanbul, Turkey",
-		"AST": "ASTINCO Inc., 114 Anderson Ave. Suite 7A, ON, L6E1A5, Markham, Canada",
-		"ATF": "AKTIF Otomasyon ve GS ve Tic, Turkey",
-		"ATI": "ANALOGICS TECH INDIA LIMITED, Plot No.9/10, Road No.6, Nacharam Industrial Estate, HYDERABAD, INDIA",
-		"ATL": "Atlas Elektronik, ANKARA, Turkey",
-		"ATM": "Atmel, Torre C2, Polígono Puerta norte, A-23, 50820, (Zaragoza) Spain",
-		"ATS": "Atlas Sayaç Sanayi A. ., Erciyes Teknopark 4.Bina Talas, Kayseri, Turkey",
-		"AUX": "Ningbo Sanxing Smart Electric Co., Ltd., No.16, Fengwan Road, Cicheng Town, Jiangbei District, Ningbo, China",
-		"AVA": "Avangard JSC, Kondratevskiy av., 72, Saint Petersburg, Russia",
-		"AXI": "UAB Axis Industries, LT-47190, Lithuania",
-		"AXS": "AXSEM AG, Oskar-Bider-Str. 1, 8600 Dübendorf, Switzerland",
-		"AYS": "Euromet Ltd Stl, ATB Is Merkezi No: 84 C Blok, Ankara, Turkey",
-		"AZE": "AZEL Electronics, B. Ankara, Turkey",
-		"BAM": "Bachmann GmbH & Co KG, Ernsthaldenstr, 33 70565, Stuttgart, Germany",
-		"BAR": "Baer Industrie-Elektronik GmbH, Fuerth, Germany",
-		"BAS": "BASIC INTELLIGENCE TECHNOLOGY CO.,LTD., 1st Floor, No.1 NanLi Rd. PanYu District, GuangZhou, GuangDong, China.",
-		"BBS": "BBS Electronics, Singapore",
-		"BCE": "ShenZhen B.C Electronic CO.Ltd, 4F, Strength Building,GaoXin Ave.1.s, South, Hi-technology industry Zone, ShenZhen, China",
-		"BCR": "Jiangsu Bingchen Electronics Co Ltd, Technical Economic Development Area, Xinyi, China",
-		"BEE": "Bentec India Limited, 150,Upen Banerjee Road,Kolkata, Kolkata, India",
-		"BEF": "BEFEGA GmbH, Reichenbacher Str. 22, Schwabach, Germany",
-		"BEG": "begcomm Communication AB, Brunnehagen 109, GöteborgSweden",
-		"BER": "Bernina Electronic AG",
-		"BHG": "Brunata A/S, DK-2730 Herlev, Denmark",
-		"BJY": "Beijing JOYO smart water meter co.,ltd., No2,BaiYang Road,Fangshan,Beijing,China",
-		"BKB": "Boendekomfort AB, Box 37, 260 40 Viken, SWEDEN",
-		"BKT": "Bekto Precisa, Ibrahima Popovi a bb., Gora de, Bosnia and Herzegovina",
-		"BKO": "Beko Elektronik AS., Istanbul, Turkey",
-		"BLU": "Bluering, Brescia, Italy",
-		"BME": "Beifeng GmbH, 60599 Frankfurt am Main, Germany",
-		"BMI": "Badger Meter Inc., 6116 E 15th St., Tulsa, USA",
-		"BMP": "BMETERS Polska Sp.z.o.o., Glowna 60, Psary, Poland",
-		"BMT": "BMETERS srl, Via Friuli, 3, 33050, Gonars (UD), ITALY",
-		"BNR": "Beijing Banner Electric Co., Ltd. Long Cheng Villas, Changping District, Beijing, China",
-		"BRA": "Brandes GmbH, D-23701 Eutin, Germany",
-		"BSC": "Sanaye Sanjesh Energy Behineh Sazan Toos, Toos Industrial Estate,Mashhad,Iran",
-		"BSE": "Basari Elektronik A.S. Turkey",
-		"BSM": "Bluestar Electrical Meter Research Institute, Nanjing, China",
-		"BSP": "Byucksan Power Co. Ltd., 6th Fl. New Hosung Bldg. Yoido-Dong, Youngdeungpo-Gu, Seoul, Korea.",
-		"BSS": "Baylan Su Sayaçlar , 10032 sok. No:16 A.O.S.B Çi li, zmir, Turkey",
-		"BST": "BESTAS Elektronik Optik, Turkey",
-		"BSX": "BS-Messtechnik UG, Kassel, Germany",
-		"BTL": "BIT-LAB",
-		"BTR": "RIA-BTR Produktions-GmbH, D-78176 Blumberg",
-		"BTS": "Basari Teknolojik Sistemler AS, Ankara, Turkey",
-		"BUR": "Bopp und Reuther Messtechnik GmbH, Speyer, Germany",
-		"BYD": "BYD Company Limited, BYD Road NO.3009 PingShan, ShenZhen, China",
-		"BYL": "BAYLAN, Turkey",
-		"BYW": "Baylan Water Meters, 10032 sok. No:16 A.O.S.B Çi li, zmir, Turkey",
-		"BXC": "Beijing Fuxing Xiao-Cheng Electronic Technology Stock Co., Ltd, Room 503, Block D, IFEC Blog, No.87 Xisanhuan Beilu, Haidian District, Beijing, China",
-		"BZR": "Gebr. Bauer GbR, 87719 Mindelheim, Germany",
-		"CAH": "MAEC GROUPE CAHORS, ZI DE REGOURD BP 149, 46003 CAHORS CEDEX 9, FRANCE",
-		"CAL": "Caleffi S.p.A., S.R. 229, n. 25, Fontaneto d'Agogna (NO), Italy",
-		"CAR": "CARI Electronic, 8 rue olivier de serre Parc Rovaltain, 26958 valence, France",
-		"CAT": "Cubes And Tubes OY, Olli Kytölän tie 1, MUURAME, FINLAND",
-		"CBI": "Circuit Breaker Industries, South Africa",
-		"CCS": "Chetas Control Systems Pvt Ltd, 1, Siddhatek Society, Sutarwadi, Pashan, Pune, India",
-		"CDL": "Customised Data Ltd., 44 Allerburn Lea, Alnwick, UK",
-		"CEB": "Cebyc AS, Vestre Rosten 81 / 13th Floor, Tiller, Norway",
-		"CEL": "Creative Electronics (Pvt) Ltd, 476 Sundar Industrial Estate, Lahore, Pakistan",
-		"CEM": "YAVUZ METAL SANAYI VE TICARET A.S. Organize Sanayi Bolgesi 2.Cadde No:4 Arsin, Trabzon / TÜRK YE",
-		"CET": "Cetinkaya Aydinlatma, Istanbul, Turkey",
-		"CGC": "Contor Group S.A., Romania 310059 Arad, Calea Bodrogului nr. 2-4",
-		"CHC": "CHUN-IL INSTRUMENT CO.,LTD, #801 Sin-Mora Venture Building, 9, Mora-ro 192 Beon-Gil, Sasang-gu, Busan, South Korea",
-		"CIR": "Circutor, Viladecavalls/Barcelona, Spain",
-		"CLE": "Shen Zhen Clou Electronics Co Ltd, Guangdong, China",
-		"CLO": "Clorius Raab Karcher Energi Service A/S",
-		"CLT": "Zhuhai S.E.Z. Calintech Electric Co., Ltd. No.4 Cuizhu 3Rd Street, Xiangzhou, Zhuhai, 519070, P.R. China",
-		"CLY": "Clayster, FO Petersons gata 6, 421 31 Västra Frölunda, Sweden",
-		"CMC": "CMC EKOCON d.o.o., IOC ZAPOLJE I/10, LOGATEC, SLOVENIA",
-		"CMP": "CM Partner Inc, Yongin, South Korea",
-		"CMT": "CMOSTEK MICROELECTRONICS CO., LTD. Qianhai Road, Nanshan District, Shenzhen, P.R.China",
-		"CMV": "Comverge, Inc., 5390 Triangle Parkway, Suite 300, Norcross, GA 30041, USA",
-		"CNM": "COSTEL, #462-870 COSTEL Bldg., 223-39, Sangdaewon-Dong, Jungwon-Gu, Sungnam-Si, Kyunggi-Do, Korea",
-		"COM": "COMMON S.A., Aleksandrowska 67/93, LODZ, POLAND",
-		"CON": "Conlog",
-		"CPG": "CentraPlus GmbH, Sandreuthstrasse 41, Nürnberg, Germany",
-		"CPL": "CPL CONCORDIA Soc.Coop., Via A. Grandi, 39 41033 Concordia s/S (MO), Italy",
-		"CPO": "C3PO S.A., Alejandro Goicoechea 6, Sant Just Desvern, Spain",
-		"CPS": "CAPITAL POWER SYSTEMS LIMITED, B 40 SECTOR 4 noida 201301, Noida, India",
-		"CQC": "Yueqing Qicheng Electric Co., Ltd, Xuezhai Industrial Zone, Liushi, Wenzhou, China",
-		"CRD": "CRDM DEVELOPPEMENTS 21 rue Paul Eluard, Floriac, France",
-		"CRW": "Xuzhou Runwu Science and Technology Development Co. Ltd., NO.5,Huijin Road, Damiao Industry Park,Economic Development Zone, Xuzhou,Jiangsu, P.R.China",
-		"CRY": "Crystal Power, No 23 NWA Club Road, West Punjabi Bagh, New Delhi, India",
-		"CSC": "CHUBUSEIKI Co.,LTD., 3-5-1 Kibukicho, Kasugai city, Aichi pref, Japan",
-		"CSP": "CSP Innovazione nelle ICT Via Nizza 150 Torino Italy",
-		"CTE": "COSTER T.E. S.p.A. Via San G.B. De La Salle, 4/A20132 Milano (MI) Italy",
-		"CTL": "Cyan Technology Ltd, Buckingway Business Park, Swavesey, Cambridge, CB24 4UQ",
-		"CTQ": "Control-Q b.v, Schorsweg 13b, Vaassen, Netherlands",
-		"CTR": "Contar Electronica Industrial, Lisboa, Portugal",
-		"CTT": "DLMS User Association Conformance, Bahnhofstrasse 28, CH-6304 Zug, Switzerland",
-		"CTX": "Contronix GmbH, Nizzastr 6, Radebeul, Germany",
-		"CUC": "cuculus GmbH, Ehrenbergstrasse 11, D-98693 Ilmenau,Germany",
-		"CUR": "CURRENT Group, LLC, 20420 Century Boulevard, Germantown, MD, USA",
-		"CWA": "CompWell AB, BOX 24 139, 104 51 Stockholm, Sweden",
-		"CWI": "Cewe Instrument AB, Nykoping, Sweden",
-		"CWV": "CMEC Electric Import & Export Co. Ltd, Beijing 100055, China",
-		"CYE": "Quanzhou Chiyoung Electronics Technology Co. Ltd., #20 Hongshan Rd,Shudou community, Changtai St, Quanzhou City, Fujian 362000, China",
-		"CYI": "QUANZHOU CHIYOUNG INSTRUMENT CO., LTD, #20 Hongshan Rd,Shudou community, Changtai St, Quanzhou City, Fujian, China 362000",
-		"CYN": "Cynox, Weinart Engineering, Bad Zwischenahn, Germany",
-		"CZA": "Contazara, Zaragoza, Spain",
-		"CZM": "Cazzaniga S.p.A.",
-		"DAE": "DAE Srl, Via Trieste, 4/E, Santa Lucia di Piave, Italy",
-		"DAF": "Daf Enerji Sanayi ve Ticaret A.S, Atasehir Bulvari Ata Carsi Kat:4 No:52 34758 Atasehir, Istanbul, Turkey",
-		"DAN": "Danubia",
-		"DBE": "Decibels Electronics P Ltd, Decibels Electronics Pvt Ltd., 6-1-85/4, Saifabad, Hyderabad, AP, India",
-		"DCD": "Delhi Control Devices Pvt. Ltd., C-23, Sector-4, Noida, India",
-		"DDE": "D&D Elettronica srl, Via XXV Aprile, 37, Bresso (MI), ITALY",
-		"DEA": "Dea HT srl, Turriaco, Gorizia, Italy",
-		"DEC": "DECODE d.o.o. Data Communications, Belgrade",
-		"DEL": "DELTAMESS DWWF GmbH, Sebenter Weg 42, 23758 Oldenburg in Holstein, Germany",
-		"DES": "Desi (Alarms) Ltd, Turkey",
-		"DEV": "Develco Products, Olof Palmes Allé 40, 8200 Aarhus N, Denmark",
-		"DFE": "Dongfang Electronics Co., Ltd., JiChang road 2#, Yantai City, Shandong Province, China",
-		"DFS": "Danfoss A/S",
-		"DGC": "Digicom S.p.A., Via A.Volta 39, 21010 Cardano al Campo (VA), Italy",
-		"DGM": "Diehl Gas Metering GmbH, Industriestrasse 13, Ansbach, Germany",
-		"DHQ": "YIDU Smart Technology ( Beijing ) Co.,Ltd., No.19,Xingsheng Street,Beijing Economic-Technological Development Area, Beijing, China",
-		"DIE": "Dielen GmbH, Zeppelinstrasse 9, 47638 Straelen, Deutschland",
-		"DJA": "De Jaeger Automation bvba, Molenstraat 200, B-9900 EEKLO, Belgium",
-		"DKY": "Electric Power Research Institute of Guangdong Power Grid Corporation, No. 8, Shui jungang Dongfengdong Road, Guangzhou, China",
-		"DIL": "DECCAN INFRATECH LIMETED, A3-4/A,Electronic Complex, Kushaiguda, HYDERABAD, INDIA",
-		"DMC": "DMC International, Al Gharhoud, Dubai, UAE",
-		"DME": "DIEHL Metering, Industriestrasse 13, 91522 Ansbach, Germany",
-		"DMP": "DM Power Co., Ltd, #SB118 Megavalley, Gwanyang,-Dong, Anyang City, South Korea",
-		"DNO": "DENO d.o.o, Zagreb, Croatia",
-		"DNT": "Dr Neuhaus Telekommunikation GmbH, Hamburg, Germany",
-		"DNV": "DNV KEMA, Utrechtseweg 310, Arnhem, Netherlands",
-		"DPP": "DECCAN POWER PRODUCTS PVT. LTD., A3-4/A, Electronic Complex, Kushaiguda, Hyderabad-500062, INDIA",
-		"DRT": "DRESSER Italia S.r.l., Via Roma, 772, Talamona (SO), Italy",
-		"DSE": "Digitech Systems and Engineering Private Limited, 18 Ramamurthy Street, Nehru Nagar, Chromepet, Chennai-600044, Tamil Nadu, India",
-		"DUA": "DLMS User Association, Bahnhofstrasse 28, CH-6304 Zug, Switzerland",
-		"DVL": "devolo AG, Charlottenburger Allee 60, D-52068 Aachen, Germany",
-		"DWZ": "Lorenz GmbH & Co. KG, Burgweg 3, 89601 Schelklingen, Germany",
-		"DZG": "DZG Metering GmbH, Heidelberger Straße 32, D-16515 Oranienburg, Germany",
-		"EAA": "Electronic Afzar Azma, Iran",
-		"EAH": "Endress+Hauser, 87484 Nesselwang, Germany",
-		"EAS": "EAS Elektronik San. Tic. A.S., Ankara, Turkey",
-		"EBK": "Biesenthal GmbH, Hafenstraße 4, 56575 Weißenthurm, Germany",
-		"EBV": "EBV Elektronik GmbH & Co KG Im Technologiepark 2-8 Poing, Germany",
-		"EBZ": "eBZ GmbH Meisenstrasse 65 33607 Bielefeld, Germany",
-		"ECA": "ECO ADAPT, 39 rue chateaudun, 75009 Paris FRANCE",
-		"ECC": "Energycare Company, P.O Box No:22235, Jeddah, Saudi Arabia",
-		"ECH": "Echelon Corporation, 550 Meridian Avenue, San Jose, California, USA.",
-		"ECL": "Electronics Corporation of India Ltd, Hyderabad, India",
-		"ECM": "Ecomess Sp. z o. o., Szczawi ska 42C, Zgierz, Poland",
-		"ECO": "Ecometering Smart Energie Services, Le Jean Monnet, 11 Place des Vosges, 92400-Courbevoie, FRANCE",
-		"ECS": "Herholdt Controls srl, Milan, Italy",
-		"EDI": "Enel Distribuzione S.p.A, Via Ombrone, 2, Rome, Italy",
-		"EDM": "EDMI Pty. Ltd.",
-		"EEE": "3E s.r.l., Via Biandrate, 24, Novara, Italy",
-		"EEO": "Eppeltone Engineers, A 293/1 Okhla Industrial Area Phase 1, New Delhi, India",
-		"EFA": "EFACEC Engenharia e Sistemas SA, Apartado 3078, MAIA, PORTUGAL",
-		"EFE": "Engelmann Sensor GmbH, Rudolf-Diesel-Straße 24-28, 69168 Wiesloch, Germany",
-		"EFI": "Efimatic Iberica, Carrer Sant Josep 38, Sant Just Desvern, Spain",
-		"EFN": "EFEN GmbH, Schlangenbader Str. 40, 65344 Eltville am Rhein, Deutschland / Germany",
-		"EFR": "Europäische Funk-Rundsteuerung, Nymphenburger Strasse 20b, Munich, Germany",
-		"EFS": "EFSYS, 12 Rue des prés pécate, Saulcy-sur-Meurthe, France",
-		"EGA": "eGain International AB, Faktorvägen 9, Kungsbacka, Sweden",
-		"EGD": "EcoGuard AB, Radiatorvaegen 11, 702 27 Oerebro, SWEDEN",
-		"EGM": "Elgama-Elektronika Ltd, Lithuania",
-		"EGW": "Enermess Energie Mess- und Servicedienste GmbH, Friedenstraße 16, 39112 Magdeburg, Germany",
-		"EHL": "Secure Meters Limited",
-		"EIT": "EnergyICT NV, 8500 Kortrijk, Belgium",
-		"EKA": "Eka Systems, Germantown, MD 20874, USA",
-		"EKO": "EKOLIS, PA de Beaujardin Bat Redhae, Chateaugiron, France",
-		"EKT": "PA KVANT J.S., Russian Federation",
-		"ELD": "Elektromed Elektronik Ltd, Turkey, O.S.B. Uygurlar Cad. No:4 Sincan, Ankara, Turkey",
-		"ELE": "Elster Electricity LLC, 208 Rogers Lane, Raleigh, USA",
-		"ELG": "Elgas s.r.o., Pardubice, Czech Republic",
-		"ELM": "Elektromed Elektronik Ltd, Turkey",
-		"ELO": "ELO Sistemas Eletronicos S.A., Brazil",
-		"ELQ": "ELEQ b.v., Karl-Ferdinand-Braun-Straße 1, Kerpen, Germay",
-		"ELR": "Elster Metering Limited, 130 Camford Way, Luton, UK",
-		"ELS": "Elster GmbH, 55252 Mainz-Kastell, Germany",
-		"ELT": "ELTAKO GmbH, Hofener Straße 54, 70736 Fellbach, Germany",
-		"ELV": "Elvaco AB, Kungsbacka, Sweden",
-		"EMC": "Embedded Communication Systems GmbH, vom Staal-Weg 10, 4500 Solothurn, Switzerland",
-		"EME": "SC. Electromagnetica SA, Bucharest, Romania",
-		"EMF": "IT-Beratung Energiemanagement Flammang, An der Piwipp 75, Düsseldorf, Germany",
-		"EMH": "EMH metering GmbH & Co. KG (formerly EMH Elektrizitatszahler GmbH & CO KG)",
-		"EML": "Emlite ltd, 10 Reynolds Business Park, Stevern Way, PE1 5EL Peterborough, UK",
-		"EMM": "Email Metering, Australia",
-		"EMO": "Enermet",
-		"EMS": "EMS-PATVAG AG, CH-7013 Domat/Ems, Switzerland",
-		"EMR": "CJSC Energomera 415 Lenin St, Stavropol, Russia",
-		"EMT": "Elster Messtechnik GmbH, Lampertheim, Germany",
-		"EMU": "EMU Elektronik AG, 6432 Rickenbach SZ, Switzerland",
-		"END": "ENDYS GmbH",
-		"ENE": "ENERDIS, 16 rue Georges Besse SILIC44, 92182 ANTONYFRANCE",
-		"ENG": "ENER-G Switch2 Ltd, The Waterfront, Salts Mill Rd, Bradford, BD17 7EZ, UK",
-		"ENI": "entec innovations GmbH, Hebelstr. 1, 79379 Müllheim, Germany",
-		"ENL": "ENEL d.o.o. Beograd, Belgrade, Serbia and Montenegro",
-		"ENM": "ENMAS GmbH, Holzkoppelweg 23, Kiel, Germany",
-		"ENO": "ennovatis GmbH, Stammheimer 10Kornwestheim, Germany",
-		"ENP": "Kiev Polytechnical Scientific Research",
-		"ENR": "Energisme",
-		"ENS": "ENSO NETZ GmbH, Postfach 12 01 23, 01002 Dresden, Dresden, Deutschland",
-		"ENT": "ENTES Elektronik, Istambul",
-		"ENX": "Enetronx GmbH, Prinz-Eugen-Str. 16, DE-13347 Berlin, Germany.",
-		"EPL": "Escorts Pakistan Limited 26- Davis Road Lahore Pakistan",
-		"ERE": "Enermatics Energy (PTY) LTD, Mertech Building, Glenfield Office Park, Oberon str., Faerleglen, Pretoria, South Africa",
-		"ERI": "Easun Reyrolle Limited, 389, Rasukumaki, Hulimavu, Bannerghatta Road, Bangalore-560076, India",
-		"ERL": "Erelsan Elektrik ve Elektronik, Turkey",
-		"ERN": "Ericsson Telecomunicazioni S.p.A via Anagnina 203 Roma, Italia",
-		"ESA": "ESAC srl, via Agostino da Montefeltro 2, Torino, Italy",
-		"ESE": "ESE Nordic AB, Slottagårdsgatan 9, Vellinge, Sweden",
-		"ESG": "ESG CO.,LTD. 6F, CC BLDG, 439, Bongeunsa-ro, Gangnam-gu Seoul, Korea",
-		"ESI": "Monosan Monofaze Elektrik Motorlari, Turkey",
-		"ESM": "Monosan Monofase Elekrik Motorlari, Turkey",
-		"ESO": "Monosan Monofaze Elektrik Motorlari, Turkey",
-		"ESS": "Energy Saving Systems LTD., Zroshyvalna, 15b, Kiev, Ukraine",
-		"ESY": "EasyMeter GmbH",
-		"EUE": "E+E Electronic, Langwiesen 7, 4209 Engerwitzdorf, Austria",
-		"EUR": "Eurometers Ltd",
-		"EUS": "Ebeling und Sohn GmbH & Co. KG, Eickhofstraße 21, 58239 Schwerte, Germany",
-		"EVD": "DREWAG NETZ GmbH, Rosenstrasse 32, Dresden, Germany",
-		"EVK": "EV KUR ELEKTRIK, Istanbul, Turkey",
-		"EWA": "EWATTCH, 15, rue du Petit Saint Die, SAINT DIE DES VOSGES, France",
-		"EWG": "EWG DOO, Bulevar Svetog Cara Konstantina 80-82, Ni , 18106, Serbia",
-		"EWT": "Elin Wasserwerkstechnik",
-		"EYE": "Eco-eye Ltd, 2-3 Commerce Way Lancing BN15 8TA United Kingdom",
-		"EYT": "Enerlyt Potsdam GmbH",
-		"FAN": "Fantini Cosmi S.p.A., Via dell Osio 6, 20090 Caleppio di Settala, Miano, Italy",
-		"FAR": "FARAB, No. 18, Mirhadi St., Jooybar St., Fatemi Sq., Tehran, IRAN",
-		"FED": "Federal Elektrik, Turkey",
-		"FFD": "Fast Forward AG, Ruedesheimer Strasse 11, Munich, Germany",
-		"FID": "Fi Muhendislik Ltd. Istanbul Turkey",
-		"FIM": "Frodexim Ltd, Sofia, Bulgaria",
-		"FIN": "Finder GmbH, Hans-Böckler-Starße 44, 65468 Trebur-Astheim, Deutschland",
-		"FIO": "Pietro Fiorentini, Via Rosellini,1, Milano, Italy",
-		"FLD": "Fludia, 65 rue Jean-Jacques Rousseau, SURESNES, FRANCE",
-		"FLE": "XI AN FLAG ELECTRONIC CO.,LTD, Flag Electronic Industry Park,No.11,Zhangba 6 Rd.(New Zone), Hi-Tech Development Zone, Xi an, ShaanXi, PRC., China",
-		"FLG": "FLOMAG s.r.o, Brno, Czech Republic",
-		"FLO": "Flonidan A/S, 8700 Horsens, Denmark",
-		"FLS": "FLASH o.s, Istanbul, Turkey",
-		"FLU": "SHANDONG FEILONG INSTRUMENT CO,. LTD, Feilong Road High Tech Industrial Park Longkou Citty Shandong Province, Longkou Yantai, China",
-		"FLX": "FLEXIM Flexible Industriemesstechnik GmbH Wolfener Straße 36 Berlin Germany",
-		"FMG": "Flow Meter Group, Menisstraat 5c, 7091 ZZ Dinxperlo, The Netherlands",
-		"FML": "Siemens Measurements Ltd. (Formerly FML Ltd.)",
-		"FMM": "F.IMM s.r.l. Viale delle Industrie 13/A, Rovigo, Italy",
-		"FNX": "Flownetix Ltd, Marlow Bottom, Bucks, UK",
-		"FPL": "fifthplay NV Generaal Lemanstraat 47 Antwerpen 2018 Belguim",
-		"FPR": "F-Pribor LLC, 220141, F.Skoriny 54A, Minsk, Belarus",
-		"FRE": "Frer Srl, Viale Europa, 12, Cologno Monzese (MI), Italy",
-		"FRU": "OJSC NRPA FRUNZE, Nighny Novgorod, Gagarina av., 174, Nighny Novgorod, Russia",
-		"FSP": "Finmek Space S.p.A., I-34012 Trieste",
-		"FST": "FieldServer Technologies, 1991 Tarob Court, Milpitas, USA",
-		"FSY": "FlowService, Tulipanowa 22, Torun, Poland",
-		"FTL": "Tritschler GmbH, Schönaustr. 10+12, Laufenburg, Deutschland",
-		"FUS": "Fuccesso, 98 Yingchundong, Taizhou, China",
-		"FUT": "first:utility, Tachbrook Park, Warwick, UK",
-		"FWS": "FW Systeme GmBH, Ehnkenweg 11, 26125 Oldenburg, Germany",
-		"FZK": "FUNZIN.Co.,Ltd Haeyoung B/D 4F,Yulgok-ro 53, Jongro-gu Seoul South Korea",
-		"GAV": "Carlo Gavazzi Controls S.p.A, Via Safforze 8 C.A.P. 32100, Belluno, Italy",
-		"GBJ": "Grundfoss A/S",
-		"GCE": "Genergica, Caracas, Venezuela",
-		"GEC": "GEC Meters Ltd.",
-		"GEE": "GE Energy, Lauder House, Almondvale Business Park, Livingston, UK",
-		"GEL": "Industrial Technology Research Institute, Rm. 809, Blg.51, No. 195, Sec. 4, Chung Hsing Rd., Chutung, Hsinchu, Taiwan",
-		"GEN": "Goerlitz AG, Germany",
-		"GEO": "Green Energy Options Limited, 3 St. Mary's Court, Main Street Hardwick, Camridge, England, CB23 7QS",
-		"GET": "Genus Electrotech Ltd., Survey No-43,Galpadar Road, Taluka anjar, District-kutch, gandhidham-370110 Gujrat, Taluka anjar,India",
-		"GEX": "Global Evolution Lighting, ZI. Hammam Zriba, Rue de Tozeur Zaghouane, Tunisia",
-		"GFM": "GE Fuji Meter Co.,Ltd., Horigane Karasugawa 2191, Azumino-City Nagano, Japan",
-		"GIL": "Genus Innovations Limited, SPL-2B, RIICO Industrial Area, Sitapura, Jaipur, India",
-		"GIN": "Gineers Ltd, 1756 Sofia, Bulgaria",
-		"GLM": "GETRALINE, 15, rue d'Angiviller, VERSAILLES, FRANCE",
-		"GLX": "Vietnam Electrical Equipment Joint Stock Corporation, No 52, Le Dai Hanh Str, Hai Ba Trung District, Hanoi, Vietnam",
-		"GMC": "GMC-I Messtechnik GmbH, Südwestpark 15, D-90449 Germany",
-		"GME": "Global Metering Electronics, Amsterdam, Netherlands",
-		"GMM": "Gamma International Egypt, Abour, St 130, industrial area, Cairo, Egypt",
-		"GMT": "GMT GmbH, Odenwaldstraße 19, 64521 Groß-Gerau, Germany",
-		"GNY": "JiangSu GuoNeng Instrument Technology CO.,LTD, No.28.xingyuanroad,rucheng town rugao city jiangsu Prov.china, rugao city, China",
-		"GOE": "Genus Power Infrastructures Ltd., Jaipur, India",
-		"GRA": "Grässlin GmbH, Bundesstrasse 36, 78112 St. Georgen, Deutschland",
-		"GRE": "GE2, Green Energy Electronics, R. Fonte Caspolina, N.6,2.C, 2774-521, PACO DE ARCOS, Portugal.",
-		"GRI": "Grinpal Energy Management, 50 Oak Avenue, Pretoria, South Africa",
-		"GSP": "Ingenieurbuero Gasperowicz",
-		"GSS": "R&D Gran-System-S LLC, 220141, F.Skoriny 54A, Minsk, Belarus",
-		"GST": "Shenzhen Golden Square Technology Co.,Ltd, Zone C&D,5/F,Block A3,Shenzhen Digital Technology Park,Hi-Tech South 7 Rd.,Nanshan ,Shenzhen,Guangdong, China",
-		"GTE": "GREATech GmbH, Lindenstrasse 66a, 45478 Muelheim an der Ruhr, Germany",
-		"GTR": "Globaltronics for Electronics S.A.E. Lot 13/C, 2nd Industrial Zone, 6th October City, Giza, Egypt",
-		"GTS": "GIGATRONIK Stuttgart GmbH, Hortensienweg 21, 70374, Stuttgart, Germany",
-		"GUH": "ShenZhen GuangNing Industrial CO.,Ltd, Room 802, 8th Floor, ShenZhen Software Building ,NanShan, District,ShenZhen ,China",
-		"GWF": "Gas- u. Wassermesserfabrik Luzern",
-		"GWI": "George Wilson Industries, Aldermans Green Industrial Estate, Barlow Road, Coventry UK",
-		"HAG": "Hager Electro GmbH, 66131 Saarbruecken, Germany",
-		"HCE": "Hsiang Cheng Electric Corp, Hsin-Tien City, Taipei, R.o.China",
-		"HDX": "Beijing TianRuiXiangDe Measuring Technology Co.,Ltd., SongZhuang BaiFuYuan Industrial Zone, Tongzhou District, Beijing, China",
-		"HEG": "Hamburger Elektronik Gesellschaft",
-		"HEI": "Hydro-Eco-Invest SP. Z O.O., Gliwice, Poland",
-		"HEL": "Heliowatt",
-		"HEM": "Hokkaido Electric Meter Industry Co.,Inc. 14-13-2-12,Hassamu,Nishi-ku, Sapporo-city, Japan",
-		"HER": "Hermes Systems, Australia",
-		"HEX": "Shenzhen Hexcell Electronics Technology CO.,LTD 1-6# Bldg., Tongfuyu Industrial Zone, Aiqun Road, Shiyan Town, Bao'an District Shenzen China",
-		"HFR": "SAERI HEAT METERING TECHNOLOGY CO.,LTD, WANLIAN ROAD 1,SHENHE DISTRICT SHENYANG CHINA",
-		"HGM": "HG meter a/s, Ejgårds Tværvej 8, DK-2920, Charlottenlund, Denmark",
-		"HIE": "Shenzhen Holley South Electronics Technology Co., Ltd., 7/F, No.2 Jianxing Building, Chaguang Industrial Zone, Nanshan District, Shenzhen, China",
-		"HKK": "Hokuriku Instrumentation Co.,Inc, 18-1 Takahashimachi, Nonoichi city, Ishikawa pref, Japan",
-		"HLY": "Holley Metering Ltd",
-		"HMI": "HMI Energy Co., Ltd, No.38, Alley 175, Lane 75, Sec3, Kongning Rd., Neihu, Taipe, Taiwan",
-		"HMS": "Hermes Systems, Australia",
-		"HMU": "Hugo Müller GmbH & Co KG, Sturmbühlstraße 145-149, 78054 VS-Schwenningen, Germany",
-		"HND": "Shenzhen Haoningda Meters Co., Ltd., 6/F, Huake Mansion, East Science Park, Qiaoxiang Rd, Nanshan District, Shenzhen, China",
-		"HOE": "HOENTZSCH GMBH, Gottlieb-Daimler-Str.37, 71334 Waiblingen, Germany",
-		"HOL": "Holosys d.o.o, Matije Gupca 7, 49243, Oroslavje, Croatia",
-		"HON": "Honeywell Technologies Sarl, Ecublens, Switzerland",
-		"HOY": "Holley Meters4-7-18/1A, ECIL Road, Ragavendra Nagar, Nacharam Hyderabad India",
-		"HPL": "HPL-Socomec Pvt. Ltd., 133 Pace City 1, Sector 37, Gurgaon, India",
-		"HRM": "Hefei Runa Metering Co., Ltd, 1102# jinchi Rd. Luyang industrial park, Hefei, Anhui Province, Hefei, CHINA",
-		"HRS": "HomeRider SA, France",
-		"HSD": "Ningbo Histar Meter Technology Co.,Ltd., No.181 Haichuan Road Jiangbei District, Ningbo City, Zhejiang Province, CHINA",
-		"HST": "HST Equipamentos Electronicos Ltda",
-		"HTC": "Horstmann Timers and Controls Ltd.",
-		"HTI": "Shandong Hetong Information Technology CO.,Ltd.( High tech Development Zone Ji'nan City Olympic Sports Road West No. 8 Industrial Base Room 106 ji'nan China",
-		"HTL": "Ernst Heitland GmbH & Co. KG, Erlenstr. 8-10, 42697 Solingen, Deutschland",
-		"HTS": "HTS-Elektronik GmbH",
-		"HUK": "Helbeck & Kusemann GmbH & Co. KG, Barmer Str. 24, 42899 Remscheid, Germany",
-		"HVT": "Helvatron AG Riedstrasse 7 CH-6330 ChamSwitzerland",
-		"HWC": "Qingdao Hiwits Meter Co.,Ltd, #153,Zhuzhou Road, Qingdao, China",
-		"HWM": "Beijing Hongwei Chaoda Instrument Manufacturing Co.,Ltd., 8-802 No.6,Hengye Street,Yongle Development Zone,Tongzhou District of Beijing",
-		"HWT": "Huawei Technologies Co. Ltd., Department of Industry Standards, Huawei Industrial Base, Shenzhen, China",
-		"HXD": "Beijing HongHaoXingDa Meters CO.,LTD, HouXing,the third street,18,HuoXian, TongZhou., Beijing, China(P.R.C)",
-		"HXE": "Hexing Electrical Co., Ltd, Hangzhou, China",
-		"HXW": "Hangzhou Xili Watthour Meter Manufacture Co. Ltd., No. 14, JiaQi Road, XianLin Industrial Park, Yuhang District, Hangzhou, China",
-		"HYD": "Hydrometer GmbH",
-		"HYE": "Zhejiang Hyayi Electronic Industry Co Ltd, Zhejiang, China",
-		"HYG": "Hydrometer Group, 91522 Ansbach, Germany",
-		"HZC": "TANGSHAN HUIZHONG INSTRUMENTATION CO., LTD., Qinghua Road,New and Hi-Tech Development, Zone,Tangshan,Hebei Province,China, Tangshan, China",
-		"HZI": "TANGSHAN HUIZHONG INSTRUMENTATION CO., LTD., Qinghua Road,New and Hi-Tech Development, Zone,Tangshan,Hebei Province,China, Tangshan, China",
-		"HZZ": "Huizhou Zhongcheng Electronic Technology Co.,Ltd., No.7,Hechang East 4th Rd.,zhongkai High-new-tech Zone,Huizhou City,Guangdong Province,China",
-		"ICB": "Inscobee, F9, cheongdam venture plaza, 704, seolleung-ro gangnam-gu, Seoul, Korea",
-		"ICM": "Intracom, Greece",
-		"ICP": "PT Indonesia Comnets Plus, PLN building 9th Floor Jl.Jendral Gatot Subroto kav.18, Jakarta Selatan, Indonesia",
-		"ICS": "ICSA (India) Limited, Plot No. 12, 1st Floor, Software units Layout, Cyberabad, Hyderabad, India",
-		"ICT": "International Control Metering-Technologies GmbHWillhoop 7, D-22453 Hamburg, Germany",
-		"ICU": "i-cube, route d'Eclagnens 5, 1376 Goumoens-la-Ville, Switzerland",
-		"IDE": "IMIT S.p.A",
-		"IEC": "leonnardo Corporation, Peremogy, 31, Sutysky, Ukraine",
-		"IEE": "I.E. Electromatic, S.L, Quart de Poblet (Valencia), Spain",
-		"IEI": "Informage Energy Pvt. Ltd., 52/2, Hamelia Street, Emilia Tower 2, Vatika City, Sector 49, Gurgaon, India",
-		"IES": "QINGDAO INTEGRATED ELECTRONIC SYSTEMS LAB CO.,LTD No.188, East End of Huayuan Road,Jinan,Shandong, P.R.China",
-		"IFC": "infocon doo jele andrijasevic 1 niksic Montenegro",
-		"IFX": "Infineon Technologies, AM Campeon 1-12, Nuebiberg, Germany",
-		"IGS": "I.G.S.DATAFLOW S.r.l. Via Giuseppe di Vittorio, n 337 Sesto San Giovanni (MILANO) Italy",
-		"IHM": "Shenzhen Inhemeter Co., Ltd., 7/F, Science & Industry Park Building, Science & Industry Park, Nanshan District, Shenzhen, China",
-		"IJE": "ILJIN Electric, Kyunggi-Do, Korea",
-		"IJK": "IJENKO SA, 49 rue Fernand Pelloutier, 92100 Boulogne Billancourt, France",
-		"IKE": "IK Elektronik GmbH, Friedrichsgruener Str. 11-13, 08262 Muldenhammer, Germany",
-		"IKS": "IKASIAN, Av. Josep Tarradellas, 38. SBC Office 55, Barcelona, Spain",
-		"IME": "Fellows s.c. ul. Do Studzienki 34B Gda sk Poland",
-		"IMS": "IMST GmbH, Carl-Friedrich-Gauss-Straße 2-4, 47475 Kamp-Lintfort, Germany",
-		"INC": "Incotex, 16th Parkovaya st, 26, Moscow, Russia",
-		"IND": "INDRA SISTEMAS, Avda. Bruselas, 35, Alcobendas (Madrid)",
-		"INE": "INNOTAS Elektronik GmbH, Rathenaustr. 18a, 02763 Zittau, Germany",
-		"INF": "Infometric, Sollentunavägen 50, 19140 Sollentuna, Sweden",
-		"INI": "Altero AB, 21211 Malmoe, Sweden",
-		"INM": "Inepro Metering BV, Pondweg 7, Nieuw Vennep, Netherlands",
-		"INN": "INNEXIV, Inc., 11710 Plaza America Drive Suite 2000 Reston, Virginia 20190, USA",
-		"INO": "Indotech Switchgear & Controls(Delhi) Pvt. Ltd, 67, Rajendra Nagar Industrial Area, Sahibabad, Ghaziabad, India",
-		"INP": "INNOTAS Produktions GmbH, Rathenaustr. 18a, 02763 Zittau, Germany",
-		"INS": "INSYS MICROELECTRONICS GmbH, Hermann-Köhl-Str. 22, 93049, Regensburg, GERMANY",
-		"INT": "Infranet Technologies GmbH, 21079 Hamburg, Germany",
-		"INV": "Sensus Metering Systems, Ludwigshafen/Rh, Germany",
-		"INX": "Innolex Engineering BV, Molenlei 2A, Akersloot, The Netherlands",
-		"IPD": "IPD Industrial Products Australia, Sydney, Australia",
-		"IRE": "IREN Energia S.p.A. Corso Svizzera 95 Turin, Italy",
-		"ISI": "Akcionarsko Drustvo Insa Industrija Satova, Trscanska 21, Belgrade-Zemun, Serbia",
-		"ISK": "Iskraemeco, Slovenia",
-		"ISO": "Isoil Industria spa, via F.lli Gracchi n.27, Cinisello Balsamo (Milan), Italy",
-		"IST": "Ista",
-		"ITA": "iTrona GmbH, CH-6432 Rickenbach SZ, Switzerland",
-		"ITB": "ITRON Brazil, rua Fioravante Mancino, 1560, CEP: 13175-575 Sumaré, Brazil",
-		"ITC": "INTECH TUNISIE, Rue de Tozeur ZI Hammam Zriba, Zaghouan, Tunisia",
-		"ITE": "ITRON (Electricity), 52, Avenu Camille Desmoulin, 92130 Issy les Moulineaux, FRANCE",
-		"ITG": "ITRON (Gas), 52, Avenue Camille Desmoulin, 92130 Issy les Moulineaux, FRANCE",
-		"ITH": "INTELTEH d.o.o., Bozidara Magovca 87, 10000, Zagreb, Croatia",
-		"ITF": "ITF Fröschl GmbH, Hauserbachstrasse 9, 93194 Walderbach, Germany",
-		"ITI": "ITRON Asia, EJIP Plot 6B-2, Lemah Abang, Bekasi 17550, Jawa Barat, Indonesia",
-		"ITK": "Itron GmbH, Hardeckstraße 2, D-76185 Karlsruhe, Germany",
-		"ITR": "Itron",
-		"ITS": "ITRON Australia, Rosberg Road, Wingfield, SA, 5013, Adelaide, Australia",
-		"ITU": "ITRON United States, 2111 N Molter Road, Liberty Lake, WA 99019, United States",
-		"ITW": "ITRON (Water), 52, Avenue Camille Desmoulin, 92130 Issy les Moulineaux, FRANCE",
-		"ITZ": "ITRON South Africa, Tygerberg Office Park, Hendrik, Verwoerd Drive, 7500 Plattekloof, Cape Town, South Africa",
-		"IUS": "IUSA SA DE CV, Km 109 Carr Panamericana, Pasteje Jocotitlan Edo. de Mex., Mexico",
-		"IWK": "IWK Regler und Kompensatoren GmbH",
-		"IYI": "KAYI ENERGY Hacettepe Üniversitesi - Beytepe KampüsüÜnüversiteliler Mah. 1596. Cad. NO:101KOSGEB 19 Ankara Turkey",
-		"IZE": "iZenze AB, Slottagårdsgatan 9, 235 35 Vellinge, Sweden",
-		"JAN": "Janitza electronics GmbH, Lahnau-Waldgirmes, Germany",
-		"JCE": "Janz Contadores de Energia SA, Lisbon, Portugal",
-		"JED": "JED Co Ltd, Dongan-Gu, Anyang, Kyunggi-Do, South Korea",
-		"JGD": "tianjin guangdaweiye measuring instrument technology Co.,Ltd, Three road high tech Zone Branch No. 9 Haita,TianJin,China",
-		"JGF": "Janz Contagem e Gestao de Fluidos SA, Lisbon, Portugal",
-		"JHM": "Changzhou Jianhu Intelligentize Meter Co.,Ltd., No.11 Lijia Industrial District,Wujin, Changzhou, China",
-		"JJN": "BeiJing Fine&Clean Enery Technology Co.,Ltd Tongzhou Beijing, China",
-		"JMT": "JM-TRONIC Sp. z o.o., ul. Wybrze e Ko ciuszkowskie 31/33, 00-379 Warszawa, Poland",
-		"JOY": "Zhejiang Joy Electronic Technology Co., Ltd., No.88,West Zhengyang Road, Youchegang, Xiuzhou, Jiaxing, Zhejiang, P.R.China",
-		"JSM": "Jining Goldwater Science&Technology Co.,Ltd., No.52 JinYu Road,high-tech zone, Jining, China",
-		"JSO": "Schlösser Armaturen GmbH & Co. KG, Im Dohm 3, 57642 Olpe, Germany",
-		"JUM": "JUMO GmbH & Co. KG, Herrmann - Muth - Strasse 1, 36039 Fulda, Germany",
-		"JWH": "TIANJIN WANHUA CO.,LTD., Xinghua Road No. 7, Xiqing Economic Development Zone TianJin,China",
-		"JWR": "Shandong jingwei telecommunications equipment co., LTD, Jinan university science park north district F 3 unit 301 room, Jinan, China",
-		"JWS": "Wesson energy-saving technology (tianjin) co., LTD, Tianjin baodi district economic development zone industrial park of nine garden at the third street on the east side, no. 4 road south, tianjin, China",
-		"JYS": "Beijing Jingyuan instrument limited company, Fangshan District, Beijing, 2 Poplar Road, a 1 block -1 to 3 layer 101, Beijing, China",
-		"KAM": "Kamstrup Energi A/S",
-		"KAS": "Kamstrup A/S, Industrivej 28, 8660 Skanderborg, Denmark",
-		"KAT": "KATHREIN-Werke KG, Anton-Kathrein-Straße 1-3, D-83022 Rosenheim, Germany",
-		"KBH": "K. Biesinger GmbH, Neckarsteinacher Straße 74, 69434 Hirschhorn/Neckar, Germany",
-		"KBK": "KBK ELECTRONICS (PVT) LTD., 1st Floor, Sanam Building, 37-Ferozepur Road, Lahore, Pakistan",
-		"KBN": "Alpamis IT Ltd, ANKARA, Turkey",
-		"KDS": "Kedros a.s. Pribisova 19/a Bratislava Slovakia",
-		"KEL": "KELEMINIC d.o.o., Zagreb, Croatia",
-		"KER": "KERMS UG (haftungsbeschränkt), Fontanestraße 39, 15569 Woltersdorf, Germany",
-		"KES": "Kavosh Electronic Sepahan No. 141-Sheikh bahai Building-Isfahan University of Technology Isfahan Iran",
-		"KFM": "Shenzhen KAIFA Technology Co, Ltd, Shenzhen, China",
-		"KGE": "Guangzhou Keli General Electric Co., Ltd, No1.Sui Hua Nan street, Jiang Nan Da Dao Zhong Road, Guangzhou P.R.China",
-		"KHL": "Kohler, Turkey",
-		"KIG": "Kiwigrid GmbH Kleiststraße 10a-c 01129 Dresden Germany",
-		"KKE": "KK-Electronic A/S",
-		"KLE": "SHIJIAZHUANG KELIN ELECTRIC CO., LTD., Nanjiangbi,Hongqi Street, Shijiazhuang, China",
-		"KLK": "Kerlink, 1 rue Jacqueline AURIOL, 35235 THORIGNE, FOUILLARD, FRANCE",
-		"KMB": "Kamstrup A/S, Industrivej 28, Stilling, DK 8660 Skanderborg, Denmark",
-		"KMT": "Krohne Messtechnik GmbH, Ludwig-Krohne-Straße, Duisburg,Germany",
-		"KNX": "KONNEX-based users (Siemens Regensburg)",
-		"KRO": "Kromschroder",
-		"KRT": "Karat, Yasnaya St. 22b., Yekaterinberg, Russia",
-		"KST": "Kundo SystemTechnik GmbH",
-		"KSY": "KUNDO SystemTechnik GmbH, St Georgen, Germany",
-		"KTC": "Kerman Tablo Co, Tehran, Iran",
-		"LAC": "Heinz Lackmann GmbH & Co KG, Harkortstrasse 15, 48163 Münster, Germany",
-		"LAN": "Langmatz GmbH, Am Gschwend 10, Garmisch-Partenkirchen, Germany",
-		"LAS": "Lansen Systems AB Helmfeldsgatan 59, Box 186 SE-30105 Halmstad, Sweden",
-		"LCG": "Landis+Gyr Meter & System (Zhuhai) Co., Ltd, No.12 Pingdong 3RD,Nanping Industry Community,Zhuhai 519060, P.R.China",
-		"LCR": "ShanDong LiChuang Science and Technology Co.,Ltd, No. 9 Fenghuang Road High-tech District, Laiwu ShandongChina",
-		"LDE": "Shenzhen Londian Electrics Co., Ltd, 3/F, Build 107#, 1st Nanyou Industrial Zone, Nanshan District, Shenzhen, China",
-		"LEC": "Lectrotek Systems Pvt Ltd, 33 Parvati Industrial Estate, 411009, Pune, India",
-		"LEM": "LEM HEME Ltd., UK",
-		"LFS": "Payolcer Metering Devices, Erciyes Un versity Cybertech Center-4 Num:25, KAYSERI, TURKEY",
-		"LGB": "Landis+Gyr Ltd., UK",
-		"LGD": "Landis+Gyr GmbH, Germany",
-		"LGG": "Ningxia LGG Instrument Co., LTD, No. 26 Guangming Road,National Economic & Technical Development ZoneYinchuan, Ningxia, China",
-		"LGS": "Landis+Gyr (Pty) Ltd. South Africa",
-		"LGU": "LG Uplus Corp, Namdaemunno 5-ga, Jung-gu, Seoul, Korea",
-		"LGZ": "Landis+Gyr AG Zug",
-		"LHA": "Atlantic Meters, South Africa",
-		"LIT": "Punos Electronic AB, Kärrlyckegatan 29, Gothenburg, Sweden",
-		"LJP": "Lars Jansen Productions, Soephuisstraatje 18-09, 9712BZ, Groningen, The Netherlands",
-		"LMC": "Lumacol s.r.o. Talichova 2, Bratislava, Slovak Republic",
-		"LML": "LUMEL, Poland",
-		"LNK": "Loenk F9, Cheongdam Vemture Plaza, 41, Cheongdamm-dong, Gangnam-gu Seoul Korea",
-		"LNT": "Larsen & Toubro Ltd, MPS, KHebbal-Hootagalli, Mysore, India",
-		"LOG": "Logarex Smart Metering s.r.o., Belnická 813, Jesenice, Czech Republic",
-		"LSC": "Lund Science AB, Mobilvagen 10, Lund, Sweden",
-		"LSE": "Landis & Staefa electronic",
-		"LSK": "LS Industrial Systems Co Ltd, Cheongju, South Korea",
-		"LSP": "Landis+Gyr GmbH, Germany",
-		"LSZ": "Siemens Building Technologies",
-		"LUG": "Landis+Gyr GmbH, Germany",
-		"LUN": "LUNA Elektrik Elektronik A.S., 167 Sok., No 42 Isikkent, Izmir, Turkey",
-		"LYE": "Jiangsu Linyang Electronics Co.,Ltd., No.666,Linyang Road, Qidong, China",
-		"MAC": "RUDNAP Group Meter & Control, Omladinskih brigada 182, Belgrade, Serbia",
-		"MAD": "Maddalena S.p.A.. Italy",
-		"MAE": "Mates Elektronik Metin Ates, Ankara, Turkey",
-		"MAN": "Manthey GmbH, Walter-Freitag-Str. 30, 42897 Remscheid, Deutschland",
-		"MAT": "Mitsubishi Electric Automation, Bangkok, Thailand",
-		"MAX": "MAXMET Inc, Seogu, Daejeon 302-834, Korea",
-		"MBS": "MBS AG, Eisnachstraße 51, 74429 Sulzbach-Laufen, Germany",
-		"MCR": "MICRORISC, D lnická 222, Ji ín, Czech Republic",
-		"MDE": "Diehl Metering Deutschland, Industriestraße 13, Ansbach, Germany",
-		"MDX": "mdex AG Bäckerbarg 6 Tangstedt Germany",
-		"MEC": "Mitsubishi Electric Corporation, 1-8, Midorimachi Fukuyama-city Hiroshima, 720-8647, Japan",
-		"MED": "MAHARASHTRA STATE ELECTRICITY DISTRIBUTION COMPANY LIMITED, PLOT NO. G-9, PRAKASHGAD, 5TH FLOOR, PROF. ANANT KANEKAR MARG, BANDRA (EAST)MUMBAI 400051, INDIA",
-		"MEE": "Metron Europe, bat. Gay Lussac 20 Av. Edouard Herriot, 92350 le Plessis Robinson, France",
-		"MEH": "Mueller-electronic GmbH, Fritz-Garbe-Str. 2, 30974, Wennigsen, Germany",
-		"MEI": "Sensus Metering Systems, Ludwigshafen/Rh, Germany",
-		"MEL": "Mikroelektronika a.d, Banja Luka, Bosnia and Herzegovina",
-		"MEM": "MEMS AG, Segelhofstrasse, CH-5405 Baden-Dättwil, Switzerland",
-		"MET": "METRA Energie-Messtechnik GmbH, Speyer, Germany",
-		"MHT": "Mechatronic Meters srl, Via Alliste, 52, Felline di Alliste Italy",
-		"MIC": "Microtronics Engineering GmbH, Hauptstrasse 7, A-3244 Ruprechtshofen, Austria",
-		"MII": "Apator Miitors ApS, Ecopark, Bautavej 1A, 8210 Aarhus V, Denmark",
-		"MIM": "Malaysian Intelligence Meters Sdn. Bhd., No. 3, Jalan Pemberita U1/49, Temasya Industrial Park, Seksyen U1, Glenmarie Shah Alam, Selangor Darul Ehsan, Malaysia",
-		"MIR": "MIR Research and Production Association, 51 Uspeshnaya644105 Omsk, Russia",
-		"MIS": "Iskra MIS d.d, 4000 Kranj, Slovenia",
-		"MKE": "MKEK Genel Mudurlugu Gazi Fisek (ELSA), Ankara, Turkey",
-		"MIT": "Meter Italia S.p.A, Via Grandi 39, Concordia sulla Secchia (MO), Italy",
-		"MKL": "MAKEL Elektrik Malzemeleri, Turkey",
-		"MKS": "MAK-SAY Elektrik Elektronik, Turkey",
-		"MLQ": "MeterLinq, Via G. Savelli, 128, Padova, Italy",
-		"MMC": "Modern Meters Co., Damascus Sahnaya, Syria,",
-		"MMI": "MyMeterInfo, 95 rue du Morellon, 38070 Saint Quentin, Fallavier, France",
-		"MMS": "Brunswick Bowling and Billiards UK Ltd, Unit L1, Temple Court, Knights Park, Knight Road, Strood, Kent, UK",
-		"MMT": "METMOTEC, Cambridge, UK",
-		"MNS": "MANAS Elektronik, Turkey",
-		"MNW": "MENOWATT GE SRL, VIA BOLIVIA, 55 63066 GROTTAMMARE ITALY",
-		"MOS": "MOMAS SYSTEMS NIGERIA LIMITED, #4, Bode Thomas Street, Surulere, Lagos, NIGERIA",
-		"MOT": "The Motwane Manufacturing Company Private Limited, Gyan Baug, Motwane Road, Nasik Road, Nasik, India",
-		"MPA": "Mega Power Automation International Limited, 16/F., Block A-1, Fortune Factory Building, 40 Lee Chung Street, Chai Wan, Hong Kong.",
-		"MPR": "Michael Rac GmbH, Sonnenfeld 29, 91522 Ansbach, Germany",
-		"MPS": "Multiprocessor Systems Ltd, Bulgaria",
-		"MRT": "MIRTEK LTD., Gagarin street, Building 4, Stavropol, Russia",
-		"MSB": "MISA SDN BHD, LOT 30, JALAN MODAL 23/2, 40300, SHAH ALAM, SELANGOR, MALAYSIA",
-		"MSE": "Mahashakti Energy Limited, A-8, New Focal Point, Dabwali Road, Bathinda (Punjab), India",
-		"MSM": "MS-M Co., Ltd., 237 Bukjung-Dong Yangsan-City, Korea",
-		"MSO": "Metiso, Tr anska 21, Zemun, Belgrade, Serbia",
-		"MSY": "MAK-SAY Elektrik Elektronik Malzemeleri, Turkey",
-		"MTD": "Removed - November 2006",
-		"MTC": "Metering Technology Corporation, USA",
-		"MTH": "njmeter, Binjiang Development Zone of Jiangning Road No. 6Nanjing, China",
-		"MTI": "Micrtotech Industries Pakistan, Plot#2,Street#2,Attari industrial estate 18-Km. Ferozepure Raod, Lahore, Pakistan",
-		"MTM": "Metrum Sweden AB, Vestagatan 2A, Gothenburg, Sweden",
-		"MTR": "METER, 32 Borovaya street, St Petersburg, Russia",
-		"MTS": "MeteRSit S.r.L., Viale dell Industria, 31, Padova, Italy",
-		"MTX": "Matrix Energy Pvt. Ltd., Soni Arcade, No. 242, 2nd Floor, 7th Cross, 6th Block, Banashankari 3rd Stage, Bangalore, INDIA",
-		"MUK": "Meters UK Ltd, Whitegate, White Lund Trading Estate, Lancaster, UK",
-		"MWU": "METRONA Wärmemesser Union GmbH, Aidenbachstraße 40, 81379 München, Germany",
-		"MXM": "Maxim India Integrated Circuit Design Pvt Ltd., 132/133, Divyasree Technolopolis, Off Airport Road, Bangalore, India",
-		"NAE": "Nanjing NengRui Automation Equipment Co., LTD.NO.108, Ganjiabian East,Yaohua Town, Qixia District,Nanjing , Jiangsu , 210046China",
-		"NAR": "NARI Group Corporation-NARI Technology Development Co., Ltd, No.8 NARI Rd. Gulou District, Nanjing, China",
-		"NAT": "Natural Heat Ahorro Energetico SL Marques de San Nicolas 37Logroño (La Rioja) Spain",
-		"NDF": "NÚCLEO DURO FELGUERA, Avda. de la Industria, 24, 28760, Tres Cantos, Madrid",
-		"NDM": "Northern Design, 228 Bolton Road, Bradford, United Kingdom",
-		"NES": "NORA ELK. MALZ. SAN. ve T C. A. ., nönü Cad. Sümer Sok. Zita Merkezi C1 Blok No:9 Kozyata -Kad köy- STANBUL, TURKEY",
-		"NET": "Netatmo, 17 route de la Reine; 92100, Boulogne-Billancourt, France",
-		"NIS": "Nisko Industries Israel",
-		"NJC": "NAMJUN Co Ltd, Gimhae Gyoungnam, South Korea",
-		"NMG": "NMG S.A., Fordonska 246, 85-766 Bydgoszcz, Poland",
-		"NMS": "Nisko Advanced Metering Solutions Israel",
-		"NMT": "Nova-met s.r.o. Sumavska 530/8, 787 01 Sumperk, Czech Republic",
-		"NNT": "2N Telekomunikace a.s., Modranska 621, 14301 Praha 4, Czech Republic",
-		"NPS": "NEOPIS CO., LTD 4F, Neo Bd, 47, Jeonpa-ro 23beon-gil, Manan-gu Anyang-si, Gyeonggi-do Korea",
-		"NPT": "CJSC PROGTECH Amet-Khan Sultan Street 7A Russia",
-		"NRM": "Norm Elektronik, Turkey",
-		"NRN": "Neuron GmbH, Stahlrain 6, Brugg, Switzerland",
-		"NTC": "Nuri Telecom Co Ltd, Geumcheon-gu, Seoul, Korea",
-		"NTM": "Netinium, Postbus 86, Wormerveer, The Netherlands",
-		"NVD": "METER Ltd, City Velikiy Novgorod",
-		"NVN": "NOVEN ENERGY AND ICT LTD., Hacettepe University KOSGEB Technology Center T1-Blok B:14 Beytepe, Ankara,Turkiye",
-		"NWM": "Ningbo Water Meter Co.Ltd., No.99 Lane 268 Beihai Road Jiangbei District, Ningbo City Zhejiang ProvinceCHINA",
-		"NXP": "NXP Semiconductors, High Tech Campus 32, 5656AE Eindhoven, The Netherlands",
-		"NYG": "Ningbo Yonggang Instrument Co.,Ltd, Weisan Road, West Industrial Zone, Xinpu Town, Cixi City, China",
-		"NYN": "Nanjing Yuneng Instrument Co Ltd, Nanjing, China",
-		"NZR": "Nordwestdeutsche Zählerrevision Ing. Aug. Knemeyer GmbH & Co. KG, Heideweg 33, 49196, Bad Laer, Germany",
-		"OAS": "Omni Agate Systems, Chennai, India",
-		"OBR": "RMZ OBRIY LTD, Polliska, 10, str. Ternopil, Ukraine",
-		"OBC": "shandong oubiao information Technology.,LTD, shandong jinan lichengqu jingshidonglu33558-33166, jinan, China",
-		"ODI": "OAS Digital Infrastructures Pvt. Ltd., No:4/3, Stringer Road, Periamet, Vepery, Chennai, INDIA",
-		"ODK": "Okinawa Denki Kogyo Co.,Ltd, 12-55 SUZAKI,URUMA, OKINAWA,904-2234, Japan",
-		"OEE": "ONUR Elektrik ve Elektronik, Turkey",
-		"OLI": "Olivetti S.p.A. Via Jervis, 77 Ivrea Italia",
-		"OMS": "OMNISYSTEM Co., Ltd., Goyang-shi, Gyeonggi-do, Korea",
-		"ONR": "ONUR Elektroteknik, Turkey",
-		"ONS": "ONUR Elektroteknik, Turkey",
-		"OPT": "Optec GmbH, Grundstrasse 22, 8344 Bäretswil, Switzerland",
-		"ORB": "ORBIS Tecnologia Electrica, SA, Madrid, Spain",
-		"ORM": "Ormazabal, B Basauntz, 2, Igorre, Spain",
-		"OSA": "Osaki Electric Co., Ltd.(Europe), Gotanda-Square, Tokyo, Japan",
-		"OSK": "Osaki Electric Co Ltd. (Japan), Shinagawa-ku, Tokyo, Japan",
-		"OYK": "GULLWING(TIANJIN)INDUSTRY DEVELOPMENGT CO.,LTD., Energy-conserving and environment-protective industrial zone,baodi,Tianjin, China",
-		"OZK": "Oz-kar Enerji, Kayseri, Turkey",
-		"PAD": "PadMess GmbH, Germany",
-		"PAF": "FAP PAFAL S.A., 26 Lukasinskiego street, widnica, Poland",
-		"PAK": "Paktim Energy Sp.zo.o., ul. Swiety Marcin 29/8, 61-806 Poznan, Poland",
-		"PAN": "Panasonic Corporation, 800 Tsutsui-cho, Yamatokoriyama-shiNara Pref., Japan",
-		"PCE": "Precise Electronics co.,LTD., 1/333 Moo 9, T. BANGPUD A.PAKKRED, Nonthaburi, Thailand",
-		"PCR": "Powercom 88 Abshalom Gisin St. Petak Tikva Israel",
-		"PDE": "Pfiffner Deutschland GmbH, Zusestraße 6, Itzehoe, Germany",
-		"PDX": "Paradox Engineering SA, Via Ronchetto, 9, Cadempino, Switzerland",
-		"PEL": "Pak Elektron Ltd. (PEL), 14-km Ferozpur Road, Lahore, Pakistan",
-		"PGP": "P.G.P. - Smart Sensing s.a., Rue Fond Cattelain 2 / 1.15, Mont-St-Guibert, Belgium",
-		"PHL": "HangZhou PAX Electronic Technology Co., Ltd., China",
-		"PII": "PiiGAB Processinformation i Goteborg AB, Sweden",
-		"PIK": "pikkerton GmbH, Kienhorststrasse 70, 13403 Berlin, Germany",
-		"PIL": "Pilot Systems (London) Ltd, Chiswick, London",
-		"PIM": "Power Innovation GmbH, Rehland 2, Achim,Germany",
-		"PIP": "Hermann Pipersberg jr., Felder Hof 2, D-42899 Remscheid, Deutschland",
-		"PLN": "Prolan Process Control Company, Szentendrei út 1-3, Budakalász, Hungary",
-		"PLO": "Weihai Ploumeter Co. Ltd., : No. 28 Hengrui Street, Torch Hi-Tech Industries Development Zone, Weihai, Shandong, China",
-		"PMG": "Sensus Metering Systems, Ludwigshafen/Rh, Germany",
-		"PMP": "Phoenix Mecano Power Quality REDUR Messwandler, Industriestr. 6, D-52355 Dueren, Germany",
-		"PMS": "PMS-Elektronik GmbH, Humboldtstraße 14, D-74915, Waibstadt, Germany",
-		"PNC": "PNC Technology Co., Ltd, 62, Jeonpa-ro 104beon-gil, Dongan-gu, Anyang-si, Gyeonggi-do, Korea",
-		"POD": "PowerOneData, Bangalore 560082, India",
-		"POW": "PowerApp, Esromgade 15 opg. 2 - 2 sal., Copenhagen, Denmark",
-		"POZ": "ZEUP Pozyton sp. z o.o, ul. Czestochowa, Poland",
-		"PPC": "Power Plus Communications AG, Am Exerzierplatz 2, 68167 Mannheim, Germany",
-		"PRE": "Predicate Software, 7 Protea Ave, Dooringkloof, 0140, Centurion, Gauteng, South Africa",
-		"PPS": "Palace Power Systems, 50 Oak Avenue, Pretoria, South Africa",
-		"PRI": "Polymeters Response International Ltd.",
-		"PRG": "Paud Raad Industrial Group, No. 18, 2nd St., Shah Nazari Ave.,Madar Sq., Mirdamad Blvd., Tehran, Iran",
-		"PRO": "Proton - Elektromed Ltd, Ankara, Turkey",
-		"PST": "PSTec Co.,Ltd, Seoul, Korea",
-		"PUK": "Paktim Consulting UK Ltd, 2 West Regent Street, Glasgow, G2 1RW, United Kingdom",
-		"PVT": "Pavo Tasar m Üretim Elektronik Tic. A. ., Sanayi Mah. Havaalan ç Yolu Cad. D Kap No:3 Teknopark stanbul A Blok Pendik, stanbul, Turkey",
-		"PWB": "Paul Wegener GmbH, Ballenstedt, Germany",
-		"PWR": "Powrtec, Scotts Valley, CA 95066, USA",
-		"PXC": "Phoenix Contact GmbH & Co. KG, Flachsmarktstr. 8, Blomberg, Germany",
-		"PYU": "PYUNGIL Co. Ltd, Anyang-si, Gyeonggi-do, Korea",
-		"QDS": "Qundis GmbH, Sonnentor 2, 99098 Erfurt, Germany",
-		"QTS": "QT systems ab, Alfavägen 3, 92133 Lycksele, Sweden",
-		"RAC": "Michael Rac GmbH, Sonnenfeld 29, Ansbach, Germany",
-		"RAD": "Radiocrafts AS, Sandakerveien 64, 0484 OSLO, NORWAY",
-		"RAM": "Rossweiner Armaturen und Messgeräte GmbH & Co. OHG, Wehrstraße 8, Roßwein, Germany",
-		"RAS": "Rubikon Apskaitos Sistemos, Vilnius, Lithuania",
-		"RBM": "R.B.M. S.p.A, Via S. Giuseppe nÂ 1, 25075 Nave (BS), Italy",
-		"RCE": "RC ENERGY METERING PVT,LTD., B-65 GATE NO.1,N.I.A.PHASE-II NEW DELHI -110028, DELHI, INDIA",
-		"REC": "Zhejiang Reallin Electron Co.,Ltd, 2F,Building3,No.202 Zhenzhong Rd,Sandun Technology Park,Xihu District, Hangzhou, China",
-		"REF": "REFERANS ELEKTRIK SAYACLARI A.S., TURGUT OZAL MH. 68. SK. OTOPORT 46/245 ESENYURT, Istanbul",
-		"REL": "Relay GmbH, Germany",
-		"REM": "Remote Energy Monitoring, Tring, UK",
-		"RIC": "Richa Equipments Pvt. Ltd., Z B 5-6/487, Zulfe Bengal, Dilshad Garden Shahdara, Delhi, India",
-		"RIL": "Rikken Instrumentation Limited, Plot No. 369, Phase 2, Industrial Area, Panchkula, India",
-		"RIM": "CJSC \"Radio and Microelectronics\", 630082, Novosibirsk, the Red Prospectus, 220, the case 17, Novosibirsk, Russia",
-		"RIT": "Ritz Instrument Transformers GmbH, Wandsbeker Zollstr. 92 98, 22041 Hamburg, Germany",
-		"RIX": "Raonix Co., Ltd. 21-11 3F Changtteurwit-gil, Opo-eup, Gwangju-si, Gyeonggi-do, Republic of Korea",
-		"RIZ": "RIZ Transmitters, Bozidareviceva 13, Zagreb, Croatia",
-		"RKE": "Viterra Energy Services (formerly Raab Karcher ES)",
-		"RMG": "RMG by Honeywell, Otto-Hahn-Strasse 5, 35510 Butzbach, Butzbach, Germany",
-		"RML": "ROLEX METERS RPIVATE LIMITED, Plot No 20&21, Prashanthi Nagar, Kukatpally Industrial Estate, Hyderabad, India",
-		"RMR": "Advanced Technology RAMAR, Christchurch, UK",
-		"RMT": "Richter Messtechnik GmbH & Co.KG Fleckebyer Str. 4 D-18239 Satow Germany",
-		"RNW": "Renergy Micro-Technologies, F11, Huaying Building,#97 Nanshang Road,Nanshan District, Shenzhen, P.R.China",
-		"RSA": "Rahrovan Sepehr Andisheh Pte. Co.",
-		"RSM": "Zhejiang Risesun Science and Technology Co.,Ltd. No. 4 Small Zone, High and New Park, Economic and Technical Development Zone, Wenzhou City, Zhejiang Province, China",
-		"RSW": "RSW Technik GmbH, Giessen, Germany",
-		"SAA": "Sanjesh Afzar Asia Ltd. Co., 3 rd Flr/No. 8/16 St./Gandi Ave./Tehran, Iran",
-		"SAC": "Sacofgas 1927 SpA, Via Ascanio Sforza 85, Milano, Italy",
-		"SAE": "SAE IT-systems GmbH & Co. KG Im Gewerbegebiet Pesch 14 50767 Cologne Germany",
-		"SAG": "SAGEM, Cergy Saint-Christophe, France",
-		"SAM": "Siemens AG Österreich, AMIS (Automated Metering and Information System), Ruthnergasse 3, Vienna, Austria",
-		"SAP": "Sappel",
-		"SAT": "SATEC Ltd, 7 Hamarpe Street, Jerusalem, Israel",
-		"SBC": "Saia-Burgess Controls, Bahnhofstrasse 18, 3280 Murten, Switzerland",
-		"SCA": "SENECA srl Via Austria n. 26 Padova, Italy",
-		"SCE": "Seo Chang Electric Communication Co Ltd, Daegu, Korea",
-		"SCH": "Schinzel GmbH",
-		"SCM": "CalinMeter Co.,ltd., F2, BLD 3, ChaoHuiLou Industrial Park, HuatingRoad,Dalang,Bao'an District, ShenZhen, China",
-		"SCR": "Sanchuan Wisdom Technology Co., Ltd., Sanchuan Hydraulic Industrial Park, Longgang Section, Hi-Tech Development Zone, Yingtan, Jiangxi, China",
-		"SCW": "ScatterWeb GmbH, Charlottenstr. 16, Berlin, Germany",
-		"SDC": "SdC Sistemas de Contagem, Vila Nova de Famalicao, PT",
-		"SDM": "Shandong Delu Measurement Co., Ltd., Tower C, Qiln Software Park, High-Tech Industrial Development, JiNan, China",
-		"SDS": "Sitec s.r.l Elettronica industriale, via A. Tomba 15 Loc.Tomasoni, Valdagno (VI) Italy",
-		"SEC": "Schneider Electric Canada, Saanichton, Canada",
-		"SEE": "El Sewedy Electrometer Egypt, 6th of October, Egypt",
-		"SEL": "Selec Controls Pvt Ltd, EL27/1, TTC Industial Area, MIDC, Mahape, Navi Mumbai, India",
-		"SEN": "Sensus Metering Systems, Ludwigshafen/Rh, Germany",
-		"SEO": "SENSOCO Greatech GmbH, Lindenstrasse 66a, Muelheim an der Ruhr, Germany",
-		"SET": "Sagemcom Energy & Telecom, 250, route de l'Empereur, 92500 RUEIL MALMAISON, France",
-		"SFI": "Siemens A/S, Flow Instruments Nordborgvej 81 6430 Nordborg Denmark",
-		"SFT": "Sotflink s.r.o, Tomkova 409, 278 01 Kralupy nad VltavouCzech Republic",
-		"SGA": "smartGAS Mikrosensorik GmbH, Kreuzenstraße 98, 74076 Heilbronn, Germany",
-		"SGM": "Swiss Gas Metering AG, Reichenauerstrasse, Domat/Ems, Switzerland",
-		"SHD": "Beijing SanHuaDeBao Energy Technoligy Co.,Ltd., Floor4 Jinyanlong R&D Building Jiancaicheng West Road Changping District Beijing City China",
-		"SHE": "Shenzhen SingHang Elec-tech Co., Ltd., Rm203-206, Terra Science & Technology Park, Futian District, Shenzhen, China",
-		"SHM": "Shanghai Metering, China, No.2065 Kongjiang Road, Shanghai, China",
-		"SHT": "Shitek Technology srl, via Malerbe 3, Grumolo delle Abbadesse, Italy",
-		"SIC": "SICK Engineering GmbH, Bergener Ring 27, Ottendorf-Okrilla, GERMANY",
-		"SIE": "Siemens AG",
-		"SIG": "Sigrenea, 111, Boulevard Duhamel, du Monceau, 45166 Olivet Cedex, France",
-		"SIL": "Silicon Laboratories, 400 West Cesar Chavez, Austin, TX 78701, USA",
-		"SIM": "Sana Intelligent Meter, sana meter . chb.iran, shahrekord, Iran.",
-		"SIN": "SINAPSI SRL VIA DELLE QUERCE, 11/13 BASTIA UMBRA (PG) Italy",
-		"SIT": "SITEL doo, Belgrade, Serbia and Montenegro",
-		"SIV": "Sieverding Heizungs- und Sanitaertechnik GmbH, Tenstedter Strasse 40, Cappeln, Germany",
-		"SIX": "Six Innovation AB St Eriksgatan 117A Stockholm Sweden",
-		"SKI": "S.K.I. GmbH, Hanns-Martin-Schleyer Str. 22, Moenchengladbach, Germany",
-		"SKK": "Shikoku Instrumentation Co.,Ltd., 200-1, Minamigamo, Tadotsu-cho, Nakatado-gun, Kagawa Pref., Japan",
-		"SKT": "SkyToll a.s. Lamacska 3/a Bratislava, Slovakia",
-		"SLB": "Schlumberger Industries Ltd.",
-		"SLP": "Sylop, ul. Jagiellonska 4, PL-32830 Wojnicz, Poland",
-		"SLV": "Solvimus GmbH, Ehrenbergstr. 11, 98693 Ilmenau, Germany",
-		"SLX": "Ymatron AG, Bruelstrasse 7, Dielsdorf, Switzerland",
-		"SMA": "Smart-me AG, Rickenbachstrasse 142, Rickenbach, Switzerland",
-		"SMC": "Sierra Monitor Corporation, 1991 Tarob Court, Milpitas, USA",
-		"SME": "Siame, Tunisia",
-		"SMG": "Samgas s.r.l., SP 33 km 0,600 20080, Vernate (MI), Italy",
-		"SML": "Siemens Measurements Ltd.",
-		"SMM": "Smart Metering S.r.l., Viale Giovanni XXIII, 119, BARI, ITALY",
-		"SMN": "Saiman Corporation LLC, 162d Shevchenko Street, Almaty, Kazakhstan",
-		"SMP": "LLC FIRM SEMPAL CO LTD, Kulibina str., 3, Kiev, Ukraine",
-		"SMT": "Smarteh d.o.o., Trg tigrovcev 1, Tolmin, Slovenia",
-		"SMX": "Smart Meters Technologies Sdn Bhd, 2 Jalan Angklung 33/20, Shah Alam, Malaysia",
-		"SNM": "ShenZhen Northmeter Co.Ltd , floor 5, Dongshan Building, Huafeng first science park,Baoan, Shenzhen, China",
-		"SNR": "NTN-SNR, 1 Rue des Usines, 74010 Annecy, France",
-		"SNS": "Signals and Systems India Private Limited, MF-7, Cipet Hostel Road, Thiru-Vi-Ka Industrial Estate, Chennai, India",
-		"SOC": "SOCOMEC, 1, rue de Westhouse, 67230 Benfeld, France",
-		"SOF": "Softflow.de GmbH, Dorfstasse, 15834 Gross Machnow, Germany",
-		"SOG": "Sogecam Industrial, S.A., C/ Rosalind Franklin, 22-24, Campanillas (Málaga), Spain",
-		"SOL": "Soledia Srl, Via di Selva Candida 85, Rome, Italy",
-		"SOM": "Somesca, 6 rue Jean Jaurès, 92807 PUTEAUX CEDEX - France",
-		"SON": "Sontex SA",
-		"SOS": "SOFTSERVICE, Tobolska 42, off. 302 Kharkov Ukraine",
-		"SPE": "SUKHILA POWER ELECTRONICS PVT LTD, D8&9, Industrial Estate,Moula-Ali, Hyderabad, India",
-		"SPL": "Sappel",
-		"SPX": "Sensus Metering Systems, Ludwigshafen/Rh, Germany",
-		"SRE": "Guangzhou Sunrise Electronics Development Co., Ltd., Guangzhou Avenue South, Guangzhou, Guangdong, China",
-		"SRF": "Saraf Industries, Saraf Industries, Bathinda Road, Rampura Phul - 151103, Punjab, India",
-		"SRN": "Shandong SARON Intelligent Technology Co., Ltd., 3F,South E,International Business Center, Environmental Technology Area, Middle of Zhengfeng Road, Jinan City Shandong Province,China",
-		"SRV": "Servic LLC, Kirova 16-9, Dniprodzerzhinsk, Ukraine",
-		"SSI": "Security Solutions Institute LTD, 8, Munhen Str, Sofia, Bulgaria",
-		"SSN": "Silver Spring Networks, 555 Broadway Street, Redwood City, United States",
-		"SST": "Qingdao Haina Electric Automation Systems Co., Ltd., No.151,Zhuzhou Road, Laoshan, Qingdao, China",
-		"STA": "Shenzhen Star Instrument Co Ltd, Shenzhen, China",
-		"STC": "Sunrise Technology Co., Ltd, Building C, Xiyuan 8th Road 2#, West-Lake Technological & Economic Zone, Hangzhou, China",
-		"STD": "Stedin, Essebaan 71, Capelle a/d Ijssel, Netherlands",
-		"STF": "STACKFORCE GmbH Poststrasse 35 Heitersheim Germany",
-		"STR": "Strike Technologies, South Africa",
-		"STV": "STV Automation, Branch of STV Electronic, Detmold, Germ.",
-		"STZ": "Steinbeis Innovation Center Embedded Design and Networking c/oUniversity of Cooperative Education Loerrach Hangstrasse 46-50, D79539 Loerrach",
-		"SVM": "AB Svensk Värmemätning SVM",
-		"SVT": "SPUTNIK, 11/3 A, Professora Kachalova st, St Petersburg, Russian Federation 192019",
-		"SWI": "Swistec GmbH, Graue-Burg-Strasse 24-26, Bornheim, 53332 Germany",
-		"SWS": "Stadtwerke Senftenberg GmbH, Postfach 10 15 28, 01958 Senftenberg, Deutschland",
-		"SWT": "Beijing Swirling Technology Co. Ltd, Beijing, China",
-		"SYC": "Sycous Limited, Leeds Innovation Centre, 103 Clarendon Road, Leeds, UK",
-		"SYN": "SMSISTEM Ltd., ANKARA, Turkey",
-		"SYS": "Softwareentwicklung & Systemdesign, Breitenseer Str. 49/3/16, 1140 Wien, Austria",
-		"SYX": "SyxthSense Ltd, 3 Topsham Units, Exeter, United Kingdom",
-		"TAG": "Telma AG, Gewerbeweg 10, 3662 Seftigen, Switzerland",
-		"TAT": "Tatung Co., 22, Chungshan N. Rd., 3rd Sec., Taipei, Taiwan",
-		"TAY": "Taytech Otomasyon ve Bilisim Teknolojileri LTD. Sti, Tasdelen Gungoren Mah. Izan Sok. No:15 Cekmekoy., Istanbul, Turkey",
-		"TBN": "TBEA Nanjing Intelligent Electric Co., Ltd. No 2211, Chengxin Road, Nanjing, China",
-		"TCE": "Qindao Techen Electronic Technology Co.,LTD, No. 169 Songling Road. Laoshan District, Qingdao, China",
-		"TCH": "Techem Service AG & Co. KG",
-		"TCO": "Teco a.s., Havlickova 260, Kolin, Czech Republic",
-		"TCT": "Tecnotel Srl Via Santa Croce 289 Bertinoro Italy",
-		"TCX": "Qingdao Topscomm Communication Co.,Ltd, 5th floor, No. 6 building, Qingdao Software Park, No. 288 Ningxia Road, Qingdao, China",
-		"TCZ": "Tianjin Chuangzhan Tongcheng Technology Development Co.,Ltd., K1-1-302, Haitai Green Industry Base, Xiqing District, Tianjin, China",
-		"TDC": "Telecom Design, Rue Romaine Voie de Remora, GradignanFRANCE",
-		"TEA": "TEAM-R, Grazhdansky pr. 111/1, Saint-Petersburg, Russia",
-		"TEC": "TECSON Digital, Felde, Germany",
-		"TEI": "Tohoku Electric Meter Industry Co.,Inc., Yoshioka,Tiwa-cho, kurokawa-gun, Miyagi Pref, Japan",
-		"TEK": "Tekmar Regelsysteme GmbH, Möllneyer Ufer 17, 45257 Essen, Germany",
-		"TEO": "ubitricity Gesellschaft für verteilte Energiesysteme mbH, Torgauer Str. 12-15, 10623 Berlin, Germany",
-		"TEP": "TEPEECAL, 69730 Genay, France",
-		"TFC": "Toos Fuse Co. 375 Sanat Blvd. Toos Industrial Estate, Mashad, Iran",
-		"THE": "Theben AG, Hohenbergstrasse 32, 72401 Haigerloch, Germany",
-		"TIC": "TOKYO KEISO CO.,LTD., 1-22-2 , Hakusan , Midori-ku, Yokohama City , Kanagawa, Japan",
-		"TIG": "ZHONGHUAN-TIG CO.,LTD, No.1,2nd Hi-tech Development Rd.,Huayuan Industrial Area(Huan wai),Tianjin, China",
-		"TII": "Pal Mohan Electronics Pvt Ltd, Mohan Plaza, 40-DLF Industrial Area, Kirti Nagar, New Delhi-110015, India",
-		"TIL": "Thermal Integration Limited 8 Curzon Road Sudbury Suffolk UK",
-		"TIP": "TIP GmbH, Bahnhofstr. 26, 99842 Ruhla, Germany",
-		"TIS": "Texas Instruments (Hong Kong) Ltd., Rm 110-121, 1/F Building 7, N 5, Science Park East Avenue, Science Park,ShaTin, Hong Kong",
-		"TIX": "Tixi.Com GmbH, D-13465 Berlin",
-		"TKS": "Teksan Teknolojik Ölçüm Sistemleri A. ., Catalme e Mah., Re aadiye Cd. 185. Sk No:6 Alemda Çekmeköy, Istanbul, Turkey",
-		"TLC": "TELECON GALICIA,S.A., AVDA NOSTIAN POLIGONO ARTISTICA NAVE 7, A CORUÑA, SPAIN",
-		"TLM": "Theodor Lange Messgeräte GmbH, Rodeberg 7, 31226 Peine,Germany",
-		"TLR": "Telereading srl, Blocco Palma II - Zona Industriale, CataniaItalia",
-		"TLS": "TLS Energimätning AB, Sankt Eriksgatan 117A, Stockholm, Sweden",
-		"TMK": "Timi Kosova Sh.p.k.",
-		"TMS": "TEMASS IMALAT A.S, Macunkoy, Ankara, Turkey",
-		"TOP": "KMB systems, s.r.o., Dr. M. Horakove 559, Liberec, Czech Republic",
-		"TPB": "2 Plus Bulgaria AD, 20 Yanko Komitov Street, Burgas, Bulgaria",
-		"TPC": "Taipit - measuring equipment, Voroshilova street,2 Saint Petersburg, Russia",
-		"TPJ": "TAKAHATA PRECISION JAPAN CO., LTD. 390 Maemada, Sakaigawa-cho, Fuefuki-shi, Yamanashi 406-0843 Japan",
-		"TPL": "Teplocom Holding, 45, Vyborgskaya Naberezhnaya, ST Petersburg, Russian Federation",
-		"TPI": "Transfopower Industries (Pvt.) Ltd, 2km, Katar Bund Road, Off Multan Road, Thokar Niaz Beg, Lahore, Pakistan",
-		"TRI": "Tritech Technology AB, Sturegatan, 10-12 PO Box 1094, SE-172 22 Sundbyberg, Stockholm, Sweden",
-		"TRJ": "SHENZHEN TECHRISE ELECTRONICS CO.,LTD, Building 112,1st Industrial park, Liantang, Luohu District, Shenzhen City, China.",
-		"TRL": "Trilliant Inc., 610 du Luxembourg, Granby, (Quebec), Canada, J2J 2V2",
-		"TRV": "Transvaro Elektron Aletleri A.S., Turkey",
-		"TRX": "Beijing TianRuiXiang Equipment Co.,Ltd., SongZhuang BaiFuYuan Industrial Zone, Tongzhou District, Beijing, China",
-		"TSD": "Theobroma Systems Design und Consulting GmbH, Gutheil-Schoder Gasse 17, Wien, Austria",
-		"TTM": "Toshiba Toko Meter Systems Co., Ltd., 12-7, Shiba 1-chome, Minato-ku, TOKYO, JAPAN",
-		"TTR": "Tetraedre Sarl, Epancheurs 34b, 2012 Auvernier, Switzerland",
-		"TTT": "Telephone and Telegraph Technique Plc, Sofia, Bulgaria",
-		"TUR": "TURKSAY ELEKTRONIK ELEKTRIK ENDUSTRISI",
-		"TXL": "CETC46 TianJin New Top Electronics Technology Co.,Ltd., KEYAN East Road 15,Nankai District, Tianjin, China",
-		"UAG": "Uher",
-		"UBI": "Ubitronix system solutions gmbh, 4232 Hagenberg, Austria",
-		"UBY": "ubitricity - Gesellschaft für verteilte Energiesysteme mbH, Torgauer Straße 12-15, D-10829, Berlin, Germany",
-		"UEI": "United Electrical Industries Limited, Pallimukku, Kollam, India",
-		"UGI": "United Gas Industries",
-		"UHM": "Micronics Ltd Unit B3 Knaves Beech Davies Way Loudwater UK",
-		"URM": "Urmet Telecomunicazioni SPA, via di Castel Romano 167, ROME, ITALY",
-		"USC": "Usanca Soluciones S.L. Laguna del Marquesado, 10 - Nave 3 Madrid, Spain",
-		"UTF": "UtiliFlex, 701 Broad Street #201, Chattanooga TN, USA",
-		"UTI": "Utilia Spa, Via Chiabrera, 34/D, Rimini (RN 47924), Italia",
-		"UTL": "United Telecoms Limited, #18A/19, Doddanekundi Industrial Area, II Phase, Mahadevapura Post, Bangalore - 560 048, Karnataka, INDIA",
-		"VDP": "\"Plant Vodopribor\" management company, JSC, 16/13, Novoalekseevskaya st., Moscow, Russia",
-		"VER": "VERAUT GmbH, Siemensstr.52, Linz, Austria",
-		"VES": "Viterra Energy Services",
-		"VIK": "VI-KO ELEKTRIK, Istanbul, Turkey",
-		"VIP": "VIPA CZ s.r.o. Kadlická 20 Liberec 15 Czech Republic",
-		"VLT": "ABB s.R.O., EPMV DIVISION, VIDENSKA 117, BRNO, CZECH Republic",
-		"VMP": "VAMP Oy., Yrittäjänkatu 15 P.O. Box 810 Fl-65101, Vaasa, Finland",
-		"VNE": "Vision Networks Flat No 3, Shivneri Apartments, Survey No 66/6 & 66/7, Opp Sky Gym, Near Kalmkar Vasti, Pancard Club Lane, Baner, Pune India",
-		"VPI": "Van Putten Instruments B.V.",
-		"VSE": "Valenciana Smart Energy of Mediterranean Sea S.A, Sir Alexander Fleming, 12 . Warehouse 11, Parque tecnologico de Valencia, 46980, Valencia, Spain",
-		"VTC": "Vitelec, Kapittelweg 18, NL 4827 HG Breda, Postbus 6543, NL 4802 HM Breda, Netherlands",
-		"VTK": "Linkwell Telesystems Pvt. Ltd., Gowra Kalssic, 1-11-252/1/A, Begumpet, Hyderabad 500016, India",
-		"YTL": "ZheJiang yongtailong electronic co.,ltd, No.8 KangDing Road, Tongxiang, China",
-		"VTZ": "VitzroSys Co., Ltd., Vitzro building, 233-3 Seongsu 2-ga, 1-dong, Seongdong-gu, Seoul, South Korea",
-		"WAH": "WAHESOFT UG, Moeoerte 16, 26316 Varel, Germany",
-		"WAI": "Chongqing WECAN Precision Instruments Co.,Ltd, #66 HuangShan Rd,HI-Tech Park , New North Zone, ChongqingP.R.China",
-		"WAL": "Wallaby Metering Systems Pvt. Ltd., M-3, 9th Street, Dr.VSI Estate, Thiruvanmiyur, Chennai - 600 041, INDIA",
-		"WDN": "Webdyn SA, 26 rue des Gaudines, 78100 Saint Germain en Laye, France",
-		"WEB": "Webolution GmbH & Co. KG, Sendenhorsterstrasse 32, 48317 Drensteinfurt, Germany",
-		"WEG": "WEG Equipamentos Elétricos S.A. Automação, Av. Pref. Waldemar Grubba, 3000, Jaraguá do Sul, Brazil",
-		"WEH": "E. Wehrle GmbH, Obertalstraße 8, 78120 Furtwangen, Germany",
-		"WEL": "WELLTECH automation, 263# HongZhong Road, Shanghai,P.R.China",
-		"WEP": "Weptech elektronik GmbH, Ostring 10, 76829 Landau, Germany",
-		"WFT": "Waft Embedded Circuit Solutions, A-109, Sahni Tower, Sector-5, Rajendra Nagar, Sahibabad, Ghaziabad (U.P.), India",
-		"WIG": "Wigersma & Sikkema, Leigraafseweg 4, Doesburg, The Netherlands",
-		"WIN": "Windmill Innovations BV, Paasbosweg 14-16, 3862 ZS, Nijkerk (GLD), The Netherlands",
-		"WKL": "Shenyang jia DE lian yi energy technology co., LTD, No.C9-12, Shanggonhyuan, No. 66-1, Qingzhou Street, Sujiatun District, Shenyang Liaoning, China",
-		"WKX": "JINING WUKEXING METER CO.,LTD, CHONGWEN ROAD, JINING, China",
-		"WMO": "Westermo Teleindustri AB, Sweden",
-		"WNC": "wireless-netcontrol GmbH, Berliner Strasse 4a, 16540 Hohen Neuendorf, Germany",
-		"WNW": "Wistron NeWeb Corporation, Marketing Center, 20 Park Ave. II, Hsinchu Science Park, Hsinchu 308, Taiwan, R.O.C.",
-		"WSD": "Yantai Wisdom Electric Co., Ltd., JiChang road 2#, Yantai, Shangdong Province, China",
-		"WSE": "Changsha Weisheng Electronics Ltd, Changsha, P.R. China",
-		"WTI": "Weihai Sunts Electric Meter Co., Ltd, 39-7#, Shenyang Middle Rd. Gaoji Weihai CHINA",
-		"WTL": "Wipro Technologies, Doddakannelli, Sarjapur Road. Bangalore, India",
-		"WTM": "Watertech S.r.l., Strada dell Antica Fornace, 2/4, 14053 Canelli (At) Italy",
-		"WTT": "Watertech S.p.a. Str. Antica Fornace, 2/4 14053 CANELLI (AT) Italy",
-		"WZG": "Neumann & Co. Wasserzähler Glaubitz GmbH, Industriestraße A7, 01612 Glaubitz, Germany / Deutschland",
-		"WZT": "Wizit Co Ltd, Ansin-City Gyeonggi-Do, S Korea",
-		"XAO": "Info Solution SpA, Via della Burrona, 51, Vimodrone (MI), Italy",
-		"XEM": "XEMTEC AG, Sarnen, Switzerland",
-		"XJM": "XJ Metering Co., Ltd, No 416, Ruixiang Road, Xuchang, Henan, China",
-		"XMA": "XMASTER s.c. ul. Gersona 41, Wroclaw, Poland",
-		"XMX": "Xemex NV, B-2900 Schoten, Belgium",
-		"XTM": "Suntront Tech Co., Ltd, No.252 Hongsong Road, High-tech Industrial Development Zone, Zhengzhou, China",
-		"XTR": "HENAN SUNTRONT TECH CO., LTD, No.19 Guohuai Street, High and New Tech Industrial Development Zone, Zhengzhou City, Henan Province, China",
-		"XTY": "LianYuanGang Tengyue Electronics & Technology Co.LianYunGang.Jiangsu.china Haizhou, LianYunGang, China",
-		"YDD": "Jilin Yongda Group Co., Ltd",
-		"YDS": "YIDU Smart Technology ( Beijing ) Co.,Ltd., No.19,Xingsheng Street,Beijing Economic-Technological Development Area, Beijing, China",
-		"YFC": "Yufeng Changhui Automation Instrument Co., Ltd., Daoxing Community,Shengli Street, Dongying, PR. China",
-		"YHE": "Youho Electric Co Ltd, Yangjoo, South Korea",
-		"YPP": "YPP Corp, 24, Gasan digital-2ro, Geumcheon-gu, Seoul, Korea South",
-		"YSS": "Yellowstone Soft, Brunnenstr. 32, 89584 Ehingen, Germany",
-		"YTE": "Yuksek Teknoloji, Turkey",
-		"YZR": "Cangzhou City Hebei Electronic Technology Co. Ltd., Hebei city of Cangzhou province Nanpi County Yucai Road No. 88, Cangzhou, China",
-		"ZAG": "Zellweger Uster AG",
-		"ZAP": "Zaptronix",
-		"ZEL": "Dr. techn. Josef Zelisko GmbH, Beethovengasse 43 45A-2340 Mödling, Austria",
-		"ZFY": "Hang Zhou Fu Yang Instrument General Factory, Zhe Jiang.Hang Zhou.Fu Yang.High Road No. 78, Hang Zhou, China",
-		"ZIP": "St. Petersburg Meters' Factory Co., Ltd., 139 Leninskiy Avenue, St. Petersburg, Russia",
-		"ZIV": "ZIV Aplicaciones y Tecnologia, S.A.",
-		"ZJY": "Zhejiang Jiayou Thermal Technology Equipment Co., LTD, Houwan Industrial Point,Yucheng Street,Yuhuan County, Taizhou, China",
-		"ZPA": "ZPA Smart Energy a.s., Komenského 821, CZ-541 01 TrutnovCzech Republic",
-		"ZRI": "ZENNER International GmbH & Co. KG, Postfach 10 33 39D-66033 Saarbrücken, Germany",
-		"ZRM": "ZENNER International GmbH & Co. KG, Postfach 10 33 39D-66033 Saarbrücken, Germany",
-		"ZTE": "ZTE Corporation ZTE Plaza, Keji South Road, Nanshan District, Shenzhen, P.R.China",
-		"ZTY": "ZHEJIANG CHINT INSTRUMENT & METER CO.,LTD, 7th Floor, New Building, No 313, Tianmushan Road, Hangzhou 310013, China",
-		"ZXY": "Linyi City Xiaoyuan Water Meter Co., Ltd., Baishabu Town Industrial Park, Lanshan District,Linyi City,shandong,P.R.China, Linyi, China",
-		"ZYB": "WEIHAI ZHENYU INTELL & TECH Co.,Ltd 8#,QingDao Mid Road, Weihai, China",
-		"ZZZ": "Michael Rac GmbH, Am Hirtenfeld 51, Ansbach, Germany",
-	}
+	// Manufacturers is the registry used by Manufacturer.Description. It is
+	// populated from the DLMS FLAG ID list at build time, and can be
+	// extended or overridden at runtime using Add, Load or LoadFile.
+	Manufacturers = NewManufacturerRegistry(manufacturerList...)
+
+	// ErrInvalidManufacturer will be returned if a manufacturer code cannot
+	// be encoded or decoded.
+	ErrInvalidManufacturer = errors.New("invalid manufacturer code")
+
+	// ErrNoManufacturers will be returned if a manufacturer list contains no
+	// usable entries.
+	ErrNoManufacturers = errors.New("no manufacturers found in list")
 )
 
+// NewManufacturerRegistry will instantiate a new registry with the given
+// entries.
+func NewManufacturerRegistry(entries ...ManufacturerEntry) *ManufacturerRegistry {
+	r := &ManufacturerRegistry{
+		entries: make(map[Manufacturer]ManufacturerEntry, len(entries)),
+	}
+
+	r.Add(entries...)
+
+	return r
+}
+
+// Add will add entries to the registry. Existing entries with the same code
+// will be replaced.
+func (r *ManufacturerRegistry) Add(entries ...ManufacturerEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, e := range entries {
+		e.Code = e.Code.normalize()
+		r.entries[e.Code] = e
+	}
+}
+
+// Lookup will find a manufacturer by its three letter code.
+func (r *ManufacturerRegistry) Lookup(code Manufacturer) (ManufacturerEntry, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	e, found := r.entries[code.normalize()]
+
+	return e, found
+}
+
+// Find will return all manufacturers with a name containing name. The search
+// is case insensitive and the result is sorted by code.
+func (r *ManufacturerRegistry) Find(name string) []ManufacturerEntry {
+	name = strings.ToLower(name)
+
+	r.mu.RLock()
+	var found []ManufacturerEntry
+	for _, e := range r.entries {
+		if strings.Contains(strings.ToLower(e.Name), name) {
+			found = append(found, e)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].Code < found[j].Code
+	})
+
+	return found
+}
+
+// Entries will return all entries sorted by code.
+func (r *ManufacturerRegistry) Entries() []ManufacturerEntry {
+	r.mu.RLock()
+	entries := make([]ManufacturerEntry, 0, len(r.entries))
+	for _, e := range r.entries {
+		entries = append(entries, e)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Code < entries[j].Code
+	})
+
+	return entries
+}
+
+// Load will read a manufacturer list as accepted by ReadManufacturers and add
+// all entries to the registry.
+func (r *ManufacturerRegistry) Load(reader io.Reader) error {
+	entries, err := ReadManufacturers(reader)
+	if err != nil {
+		return err
+	}
+
+	r.Add(entries...)
+
+	return nil
+}
+
+// LoadFile is like Load but reads from a file.
+func (r *ManufacturerRegistry) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return r.Load(f)
+}
+
+// ReadManufacturers will read a manufacturer list such as the FLAG ID list
+// published by the DLMS User Association, saved as comma, semicolon or tab
+// separated text. If the first line is a header, columns are identified by
+// name ("FLAG ID", "Manufacturer", "Address", "Country"). Otherwise the
+// columns are expected in that order. If no address or country column is
+// present, the manufacturer column is split into name, address and country.
+// The built-in list is generated from flagids.csv the same way.
+func ReadManufacturers(reader io.Reader) ([]ManufacturerEntry, error) {
+	list, err := flagid.Read(reader)
+	if err == flagid.ErrNoEntries {
+		return nil, ErrNoManufacturers
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]ManufacturerEntry, len(list))
+	for i, e := range list {
+		entries[i] = ManufacturerEntry{
+			Code:    Manufacturer(e.Code),
+			Name:    e.Name,
+			Address: e.Address,
+			Country: e.Country,
+		}
+	}
+
+	return entries, nil
+}
+
 // Description will return a human readable description of the manufacturer.
 func (m Manufacturer) Description() string {
-	desc := manufacturers[m]
-
-	if desc == "" {
+	e, found := Manufacturers.Lookup(m)
+	if !found {
 		return "UNKNOWN"
 	}
 
-	return desc
+	return e.String()
+}
+
+// Entry will look up the manufacturer in the default registry.
+func (m Manufacturer) Entry() (ManufacturerEntry, bool) {
+	return Manufacturers.Lookup(m)
+}
+
+// ManufacturerFromID will decode a manufacturer code packed into 15 bits as
+// used by M-Bus and wireless M-Bus. Each letter is stored as five bits
+// (A=1, B=2, ...) with the first letter in the most significant bits.
+func ManufacturerFromID(id uint16) (Manufacturer, error) {
+	if id&0x8000 != 0 {
+		return "", ErrInvalidManufacturer
+	}
+
+	code := []byte{
+		byte(id>>10&0x1f) + 64,
+		byte(id>>5&0x1f) + 64,
+		byte(id&0x1f) + 64,
+	}
+
+	m := Manufacturer(code)
+	if !m.valid() {
+		return "", ErrInvalidManufacturer
+	}
+
+	return m, nil
+}
+
+// ManufacturerFromBytes will decode the 2-byte little-endian manufacturer
+// field from an M-Bus or wireless M-Bus telegram.
+func ManufacturerFromBytes(raw []byte) (Manufacturer, error) {
+	if len(raw) != 2 {
+		return "", ErrInvalidManufacturer
+	}
+
+	return ManufacturerFromID(uint16(raw[0]) | uint16(raw[1])<<8)
+}
+
+// ID will return the manufacturer code packed into 15 bits as used by M-Bus.
+func (m Manufacturer) ID() (uint16, error) {
+	m = m.normalize()
+	if !m.valid() {
+		return 0, ErrInvalidManufacturer
+	}
+
+	var id uint16
+	for _, c := range []byte(m) {
+		id <<= 5
+		id |= uint16(c - 64)
+	}
+
+	return id, nil
+}
+
+// Bytes will return the manufacturer code as the 2-byte little-endian field
+// used in M-Bus telegrams.
+func (m Manufacturer) Bytes() ([]byte, error) {
+	id, err := m.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte{byte(id), byte(id >> 8)}, nil
+}
+
+// normalize will return the code in upper case. IEC 62056-21 uses a lower
+// case third letter to signal support for a 20 ms reaction time.
+func (m Manufacturer) normalize() Manufacturer {
+	return Manufacturer(flagid.NormalizeCode(string(m)))
+}
+
+func (m Manufacturer) valid() bool {
+	return flagid.ValidCode(string(m))
+}
+
+// String will return name, address and country as a single line.
+func (e ManufacturerEntry) String() string {
+	var parts []string
+
+	for _, part := range []string{e.Name, e.Address, e.Country} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(parts, ", ")
 }
@@ -0,0 +1,179 @@
+package iec62056
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestReadManufacturers(t *testing.T) {
+	lists := map[string]string{
+		"header":    "FLAG ID,Manufacturer,Address,Country\nKAM,Kamstrup A/S,\"Industrivej 28, Stilling, 8660 Skanderborg\",Denmark\n",
+		"tabs":      "Country\tFLAG ID\tManufacturer\tAddress\nDenmark\tKAM\tKamstrup A/S\tIndustrivej 28, Stilling, 8660 Skanderborg\n",
+		"free text": "KAM;Kamstrup A/S, Industrivej 28, Stilling, 8660 Skanderborg, Denmark\nxx;ignored\n",
+	}
+
+	expected := ManufacturerEntry{"KAM", "Kamstrup A/S", "Industrivej 28, Stilling, 8660 Skanderborg", "Denmark"}
+
+	for name, list := range lists {
+		entries, err := ReadManufacturers(strings.NewReader(list))
+		if err != nil {
+			t.Fatalf("%s: ReadManufacturers() failed: %s", name, err.Error())
+		}
+
+		if len(entries) != 1 || entries[0] != expected {
+			t.Errorf("%s: Got: %+v, expected: %+v", name, entries, expected)
+		}
+	}
+
+	if _, err := ReadManufacturers(strings.NewReader("nothing,here\n")); err != ErrNoManufacturers {
+		t.Errorf("ReadManufacturers() should fail with ErrNoManufacturers, got %v", err)
+	}
+}
+
+func TestReadManufacturersSplit(t *testing.T) {
+	testSet := map[string]ManufacturerEntry{
+		"ADE,\"Yantai Aerospace Delu Energy-saving Technology Co., Ltd., No. 6 Mingda Road, Yantai city, China\"": {"ADE", "Yantai Aerospace Delu Energy-saving Technology Co., Ltd.", "No. 6 Mingda Road, Yantai city", "China"},
+		"ACE,\"Actaris, France. (Electricity)\"":                      {"ACE", "Actaris (Electricity)", "", "France"},
+		"FZK,\"FUNZIN.Co.,Ltd Haeyoung B/D 4F,Yulgok-ro 53, Korea\"":  {"FZK", "FUNZIN.Co., Ltd", "Haeyoung B/D 4F, Yulgok-ro 53", "Korea"},
+		"FLU,\"FEILONG INSTRUMENT CO,. LTD, Longkou Yantai, China\"":  {"FLU", "FEILONG INSTRUMENT CO., LTD", "Longkou Yantai", "China"},
+		"SMG,\"Samgas s.r.l., SP 33 km 0,600 20080, Vernate, Italy\"": {"SMG", "Samgas s.r.l.", "SP 33 km 0,600 20080, Vernate", "Italy"},
+		"ABB,\"ABB AB, P.O. Box 1005, SE-61129 Nyköping,Sweden\"":     {"ABB", "ABB AB", "P.O. Box 1005, SE-61129 Nyköping", "Sweden"},
+		"AEG,AEG": {"AEG", "AEG", "", ""},
+	}
+
+	for list, expected := range testSet {
+		entries, err := ReadManufacturers(strings.NewReader(list))
+		if err != nil {
+			t.Fatalf("ReadManufacturers(%q) failed: %s", list, err.Error())
+		}
+
+		if len(entries) != 1 || entries[0] != expected {
+			t.Errorf("ReadManufacturers(%q): Got: %+v, expected: %+v", list, entries, expected)
+		}
+	}
+}
+
+func TestManufacturerListGenerated(t *testing.T) {
+	f, err := os.Open("flagids.csv")
+	if err != nil {
+		t.Fatalf("Open() failed: %s", err.Error())
+	}
+	defer f.Close()
+
+	entries, err := ReadManufacturers(f)
+	if err != nil {
+		t.Fatalf("ReadManufacturers() failed: %s", err.Error())
+	}
+
+	if len(entries) != len(manufacturerList) {
+		t.Fatalf("flagids.csv has %d entries, ManufacturerList.go %d. Run go generate.", len(entries), len(manufacturerList))
+	}
+
+	for i, e := range entries {
+		if e != manufacturerList[i] {
+			t.Errorf("flagids.csv has %+v, ManufacturerList.go %+v. Run go generate.", e, manufacturerList[i])
+		}
+	}
+}
+
+func TestManufacturerListFields(t *testing.T) {
+	testSet := []ManufacturerEntry{
+		{"ACH", "Acantho S.p.A.", "Via Molino Rosso 8, 40026 Imola", "Italy"},
+		{"ALR", "Algorab S.R.L.", "via Negrelli 21/13, Lavis (TN)", "Italy"},
+		{"ALV", "Alvicom Ltd.", "Infopark walkway 1., Budapest", "Hungary"},
+		{"AMC", "Arch Meter Corporation", "4F, No.3-2, Industry E. Rd. 9, Hsinchu Science Park, Hsinchu", "Taiwan"},
+		{"AME", "AVON METERS PRIVATE LIMITED", "D-15/16/17, INDUSTRIAL FOCAL POINT, DERABASSI, PUNJAB-140507", "INDIA"},
+		{"KAM", "Kamstrup Energi A/S", "", "Denmark"},
+		{"KDS", "Kedros a.s.", "Pribisova 19/a, Bratislava", "Slovakia"},
+	}
+
+	for _, expected := range testSet {
+		e, found := Manufacturer(expected.Code).Entry()
+		if !found || e != expected {
+			t.Errorf("Entry(%s): Got: %+v, expected: %+v", expected.Code, e, expected)
+		}
+	}
+
+	for _, e := range manufacturerList {
+		if strings.ContainsAny(e.Country, "0123456789,") {
+			t.Errorf("%s: Country contains an address: %q", e.Code, e.Country)
+		}
+
+		if e.Address != "" && e.Country == "" {
+			t.Errorf("%s: Address without country: %+v", e.Code, e)
+		}
+	}
+}
+
+func TestManufacturerRegistry(t *testing.T) {
+	r := NewManufacturerRegistry(manufacturerList...)
+
+	e, found := r.Lookup("kaM")
+	if !found || e.Name != "Kamstrup Energi A/S" {
+		t.Errorf("Lookup(kaM): Got: %+v, %v", e, found)
+	}
+
+	err := r.Load(strings.NewReader("FLAG ID,Manufacturer,Country\nKAM,Kamstrup A/S,Denmark\nQQQ,Example Meters,Nowhere\n"))
+	if err != nil {
+		t.Fatalf("Load() failed: %s", err.Error())
+	}
+
+	if e, _ := r.Lookup("KAM"); e.Name != "Kamstrup A/S" || e.Country != "Denmark" {
+		t.Errorf("Load() did not override KAM: %+v", e)
+	}
+
+	if _, found := r.Lookup("QQQ"); !found {
+		t.Errorf("Load() did not add QQQ")
+	}
+
+	matches := r.Find("kamstrup")
+	if len(matches) == 0 || matches[0].Code != "KAM" {
+		t.Errorf("Find(kamstrup): Got: %+v", matches)
+	}
+
+	if Manufacturer("XYZ").Description() != "UNKNOWN" {
+		t.Errorf("Description() of unknown manufacturer should be UNKNOWN")
+	}
+}
+
+func TestManufacturerID(t *testing.T) {
+	testSet := map[Manufacturer]uint16{
+		"KAM": 0x2c2d,
+		"AAA": 0x0421,
+		"ZZZ": 0x6b5a,
+		"ELS": 0x1593,
+	}
+
+	for m, expected := range testSet {
+		id, err := m.ID()
+		if err != nil {
+			t.Fatalf("%s.ID() failed: %s", m, err.Error())
+		}
+
+		if id != expected {
+			t.Errorf("%s.ID(): Got: 0x%04x, expected: 0x%04x", m, id, expected)
+		}
+
+		decoded, err := ManufacturerFromBytes([]byte{byte(id), byte(id >> 8)})
+		if err != nil || decoded != m {
+			t.Errorf("ManufacturerFromBytes(0x%04x): Got: %s, %v, expected: %s", id, decoded, err, m)
+		}
+	}
+
+	for _, id := range []uint16{0x0000, 0x8421, 0x2c20, 0x7fff} {
+		if m, err := ManufacturerFromID(id); err == nil {
+			t.Errorf("ManufacturerFromID(0x%04x) should fail, got %s", id, m)
+		}
+	}
+
+	for _, m := range []Manufacturer{"", "KA", "KAMS", "K4M"} {
+		if _, err := m.ID(); err == nil {
+			t.Errorf("%q.ID() should fail", m)
+		}
+	}
+
+	if raw, _ := Manufacturer("kam").Bytes(); raw[0] != 0x2d || raw[1] != 0x2c {
+		t.Errorf("Bytes(): Got: % x, expected: 2d 2c", raw)
+	}
+}
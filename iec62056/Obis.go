@@ -1,6 +1,8 @@
 package iec62056
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -9,131 +11,40 @@ type (
 	Obis struct {
 		A, B, C, D, E, F string
 	}
+
+	// ObisGroup identifies one of the six value groups (A to F) of an OBIS
+	// code.
+	ObisGroup int
+
+	// ObisError will be returned by ParseObis if an OBIS code is malformed.
+	ObisError struct {
+		Code   string
+		Reason string
+	}
+)
+
+// The six value groups of an OBIS code.
+const (
+	GroupA ObisGroup = iota // Medium
+	GroupB                  // Channel
+	GroupC                  // Quantity
+	GroupD                  // Processing
+	GroupE                  // Classification, often tariff
+	GroupF                  // Billing period
 )
 
+// NotUsed is the binary value used for a value group that is not present.
+const NotUsed = byte(0xff)
+
 var (
-	aDescription = map[string]string{
-		"0": "Abstract objects",
-		"1": "Electricity",
-		"4": "Heating costs",
-		"5": "Cooling energy",
-		"6": "Heat",
-		"7": "Gas",
-		"8": "Cold Water",
-		"9": "Warm water",
-	}
-
-	bDescription = map[string]string{}
-
-	cDescription = map[string]string{
-		"0":  "General purpose objects",
-		"1":  "Σ Li active power + (import)",
-		"2":  "Σ Li active power - (export )",
-		"3":  "Σ Li reactive power +",
-		"4":  "Σ Li reactive power -",
-		"5":  "Σ Li reactive power Q I",
-		"6":  "Σ Li reactive power Q II",
-		"7":  "Σ Li reactive power Q III",
-		"8":  "Σ Li reactive power Q IV",
-		"9":  "Σ Li apparent power +",
-		"10": "Σ Li apparent power -",
-		"11": "",
-		"12": "",
-		"13": "Power factor",
-		"14": "Frequency",
-
-		"21": "L1 active power +",
-		"22": "L1 active power -",
-		"23": "L1 reactive power +",
-		"24": "L1 reactive power -",
-		"25": "L1 reactive power Q I",
-		"26": "L1 reactive power Q II",
-		"27": "L1 reactive power Q III",
-		"28": "L1 reactive power Q IV",
-		"29": "L1 apparent power +",
-		"30": "L1 apparent power -",
-		"31": "L1 phase A current",
-		"32": "L1 phase A voltage",
-		"33": "L1 power factor",
-		"41": "L2 active power +",
-		"42": "L2 active power -",
-		"43": "L2 reactive power +",
-		"44": "L2 reactive power -",
-		"45": "L2 reactive power Q I",
-		"46": "L2 reactive power Q II",
-		"47": "L2 reactive power Q III",
-		"48": "L2 reactive power Q IV",
-		"49": "L2 apparent power +",
-		"50": "L2 apparent power -",
-		"51": "L2 phase B current",
-		"52": "L2 phase B voltage",
-		"53": "L2 power factor",
-		"61": "L3 active power +",
-		"62": "L3 active power -",
-		"63": "L3 reactive power +",
-		"64": "L3 reactive power -",
-		"65": "L3 reactive power Q I",
-		"66": "L3 reactive power Q II",
-		"67": "L3 reactive power Q III",
-		"68": "L3 reactive power Q IV",
-		"69": "L3 apparent power +",
-		"70": "L3 apparent power -",
-		"71": "L3 phase C current",
-		"72": "L3 phase C voltage",
-		"73": "L3 power factor",
-		"94": "Country specific OBIS codes for different countries",
-		"C":  "Service information",
-		"F":  "Error messages",
-		"L":  "List objects",
-		"P":  "Data profiles",
-	}
-
-	dDescription = map[string]string{
-		"0":  "",
-		"1":  "Cumulative minimum 1",
-		"2":  "Cumulative maximum 1",
-		"3":  "Minimum 1",
-		"4":  "Average value 1 current measuring period",
-		"5":  "Average value 1 last measuring period",
-		"6":  "Maximum 1",
-		"7":  "Instantaneous value",
-		"8":  "Energy",
-		"9":  "Time integral 2",
-		"10": "Time integral 3",
-		"11": "Cumulative minimum 2",
-		"12": "Cumulative maximum 2",
-		"13": "Minimum 2",
-		"14": "Average value 2 current measuring period",
-		"15": "Average value 2 last measuring period",
-		"16": "Maximum 2",
-		"21": "Cumulative minimum 3",
-		"22": "Cumulative maximum 3",
-		"23": "Minimum 3",
-		"24": "Average value 3 current measuring period",
-		"25": "Average value 3 last measuring period",
-		"26": "Maximum 3",
-		"27": "",
-		"28": "",
-		"29": "Energy feed",
-		"55": "Test equipment",
-		"58": "Testing time integral",
-		"F":  "Error message ",
-	}
-
-	eDescription = map[string]string{
-		"0": "Total",
-		"1": "Tariff 1",
-		"2": "Tariff 2",
-		"3": "Tariff 3",
-		"4": "Tariff 4",
-		"5": "Tariff 5",
-		"6": "Tariff 6",
-		"7": "Tariff 7",
-		"8": "Tariff 8",
-		"9": "Tariff 9",
-	}
-
-	fDescription = map[string]string{}
+	// Letter codes allowed in value group C and D, and their binary
+	// equivalents as defined by IEC 62056-61.
+	letterCodes = map[string]byte{
+		"C": 96,
+		"F": 97,
+		"L": 98,
+		"P": 99,
+	}
 )
 
 // NewObis will istantiate a new Obis.
@@ -166,12 +77,14 @@ func (o *Obis) Parse(raw string) {
 			// A or B ended.
 			current = &o.C
 		case byte(0x2e): // .
-			// C or D ended.
+			// C, D or E ended.
 			switch current {
 			case &o.C:
 				current = &o.D
 			case &o.D:
 				current = &o.E
+			case &o.E:
+				current = &o.F
 			}
 		case byte(0x2a): // *
 			fallthrough
@@ -195,6 +108,8 @@ func (o Obis) String() string {
 		if o.B != "" {
 			ret += "-" + o.B
 		}
+
+		ret += ":"
 	}
 
 	if o.C != "" {
@@ -213,34 +128,237 @@ func (o Obis) String() string {
 	return ret
 }
 
-// Description will return a human readable description of the OBIS code (if
-// available).
-func (o *Obis) Description() string {
-	description := ""
+// Error implements error.
+func (e *ObisError) Error() string {
+	return fmt.Sprintf("invalid OBIS code %q: %s", e.Code, e.Reason)
+}
+
+// ParseObis will parse and validate an OBIS code. Unlike Parse, every value
+// group is checked. Accepted forms are "C.D", "C.D.E" with optional "A:" or
+// "A-B:" prefix, and an optional billing period suffix "*F", "&F" or ".F".
+//
+// Value groups are returned in canonical form, as returned by ObisFromBytes:
+// letter codes are replaced by their number, leading zeros are removed and
+// groups with the value 255 are left empty. "C.1.0*01" is returned as
+// "96.1.0&1", and "1-0:1.8.0*255" as "1-0:1.8.0".
+func ParseObis(raw string) (Obis, error) {
+	o := Obis{}
+	fail := func(reason string, args ...interface{}) (Obis, error) {
+		return Obis{}, &ObisError{Code: raw, Reason: fmt.Sprintf(reason, args...)}
+	}
+
+	rest := strings.TrimSpace(raw)
+	if rest == "" {
+		return fail("empty code")
+	}
+
+	// Medium and channel.
+	if i := strings.IndexByte(rest, ':'); i >= 0 {
+		prefix := rest[:i]
+		rest = rest[i+1:]
+
+		o.A = prefix
+		if j := strings.IndexByte(prefix, '-'); j >= 0 {
+			o.A = prefix[:j]
+			o.B = prefix[j+1:]
+
+			if o.B == "" {
+				return fail("empty value group B")
+			}
+		}
+
+		if o.A == "" {
+			return fail("empty value group A")
+		}
+	} else if strings.IndexByte(rest, '-') >= 0 {
+		return fail("value group B must be followed by ':'")
+	}
+
+	// Billing period.
+	if i := strings.IndexAny(rest, "*&"); i >= 0 {
+		o.F = rest[i+1:]
+		rest = rest[:i]
+
+		if o.F == "" {
+			return fail("empty value group F")
+		}
+	}
+
+	parts := strings.Split(rest, ".")
+	switch {
+	case len(parts) < 2:
+		return fail("value groups C and D are required")
+	case len(parts) > 4:
+		return fail("too many value groups")
+	case len(parts) == 4 && o.F != "":
+		return fail("value group F given twice")
+	}
+
+	o.C = parts[0]
+	o.D = parts[1]
+	if len(parts) > 2 {
+		o.E = parts[2]
+	}
+	if len(parts) > 3 {
+		o.F = parts[3]
+	}
+
+	groups := []struct {
+		group   ObisGroup
+		value   string
+		present bool
+	}{
+		{GroupA, o.A, o.A != ""},
+		{GroupB, o.B, o.B != ""},
+		{GroupC, o.C, true},
+		{GroupD, o.D, true},
+		{GroupE, o.E, len(parts) > 2},
+		{GroupF, o.F, o.F != "" || len(parts) > 3},
+	}
+
+	for _, g := range groups {
+		if !g.present {
+			continue
+		}
+
+		if g.value == "" {
+			return fail("empty value group %s", g.group)
+		}
+
+		if _, err := groupValue(g.group, g.value); err != nil {
+			return fail("value group %s: %s", g.group, err.Error())
+		}
+	}
+
+	return o.Canonical(), nil
+}
+
+// ObisFromBytes will decode the 6-byte binary representation of an OBIS code
+// as used by DLMS/COSEM. Groups with the value NotUsed will be left empty.
+func ObisFromBytes(raw []byte) (Obis, error) {
+	if len(raw) != 6 {
+		return Obis{}, &ObisError{Code: fmt.Sprintf("% x", raw), Reason: "binary OBIS codes must be 6 bytes"}
+	}
 
-	if aDescription[o.A] != "" {
-		description += aDescription[o.A]
+	var groups [6]string
+	for i, b := range raw {
+		if b != NotUsed {
+			groups[i] = strconv.Itoa(int(b))
+		}
 	}
 
-	if bDescription[o.B] != "" {
-		description += " " + bDescription[o.B]
+	return Obis{groups[0], groups[1], groups[2], groups[3], groups[4], groups[5]}, nil
+}
+
+// String will return the letter used for the value group.
+func (g ObisGroup) String() string {
+	if g < GroupA || g > GroupF {
+		return fmt.Sprintf("[UNKNOWN GROUP: %d]", int(g))
 	}
 
-	if cDescription[o.C] != "" {
-		description += " " + cDescription[o.C]
+	return string(rune('A' + g))
+}
+
+// Group will return the numeric value of a value group. Letter codes are
+// translated to their binary equivalent. The second return value will be
+// false if the group is empty or invalid.
+func (o Obis) Group(g ObisGroup) (byte, bool) {
+	v, err := groupValue(g, o.group(g))
+	if err != nil {
+		return 0, false
 	}
 
-	if dDescription[o.D] != "" {
-		description += " " + dDescription[o.D]
+	return v, true
+}
+
+// Bytes will return the 6-byte binary representation of the OBIS code as used
+// by DLMS/COSEM. Empty value groups are encoded as NotUsed.
+func (o Obis) Bytes() ([]byte, error) {
+	raw := make([]byte, 6)
+
+	for g := GroupA; g <= GroupF; g++ {
+		str := o.group(g)
+		if str == "" {
+			raw[g] = NotUsed
+			continue
+		}
+
+		v, err := groupValue(g, str)
+		if err != nil {
+			return nil, &ObisError{Code: o.String(), Reason: fmt.Sprintf("value group %s: %s", g, err.Error())}
+		}
+
+		raw[g] = v
 	}
 
-	if eDescription[o.E] != "" {
-		description += " " + eDescription[o.E]
+	return raw, nil
+}
+
+// Canonical will return the code with every valid value group written as
+// ObisFromBytes would, so "C.1.0*255" becomes "96.1.0". Invalid groups are
+// left untouched. Codes from NewObis are kept as written, use Canonical to
+// compare codes written in different forms.
+func (o Obis) Canonical() Obis {
+	groups := []*string{&o.A, &o.B, &o.C, &o.D, &o.E, &o.F}
+
+	for g, str := range groups {
+		if *str == "" {
+			continue
+		}
+
+		v, err := groupValue(ObisGroup(g), *str)
+		if err != nil {
+			continue
+		}
+
+		*str = ""
+		if v != NotUsed {
+			*str = strconv.Itoa(int(v))
+		}
+	}
+
+	return o
+}
+
+func (o Obis) group(g ObisGroup) string {
+	switch g {
+	case GroupA:
+		return o.A
+	case GroupB:
+		return o.B
+	case GroupC:
+		return o.C
+	case GroupD:
+		return o.D
+	case GroupE:
+		return o.E
+	case GroupF:
+		return o.F
+	}
+
+	return ""
+}
+
+// groupValue will validate and convert a single value group.
+func groupValue(g ObisGroup, str string) (byte, error) {
+	if v, found := letterCodes[str]; found {
+		if g != GroupC && g != GroupD {
+			return 0, fmt.Errorf("letter code %q only allowed in value group C and D", str)
+		}
+
+		return v, nil
+	}
+
+	for _, r := range str {
+		if r < '0' || r > '9' {
+			return 0, fmt.Errorf("%q is not a number", str)
+		}
 	}
 
-	if fDescription[o.F] != "" {
-		description += " " + fDescription[o.F]
+	v, err := strconv.Atoi(str)
+	if err != nil || v > 255 {
+		return 0, fmt.Errorf("%q is out of range (0-255)", str)
 	}
 
-	return strings.TrimSpace(description)
+	return byte(v), nil
 }
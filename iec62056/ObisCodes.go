@@ -0,0 +1,263 @@
+package iec62056
+
+import (
+	"strconv"
+
+	"github.com/abrander/gometer/kamstrup"
+)
+
+type (
+	// Quantity is a protocol and vendor neutral name for a measured or
+	// reported quantity.
+	Quantity string
+
+	// Semantic describes what an OBIS code means independent of the vendor
+	// or protocol that delivered it.
+	Semantic struct {
+		Quantity Quantity
+		Phase    int           // 1-3 for L1-L3, 0 if not phase specific
+		Tariff   int           // Tariff number, 0 for total
+		Channel  int           // M-Bus channel, 0 for the meter itself
+		Unit     kamstrup.Unit // The unit usually used for the quantity
+	}
+)
+
+// Known quantities.
+const (
+	QuantityActiveEnergyImport   = Quantity("active_energy_import")
+	QuantityActiveEnergyExport   = Quantity("active_energy_export")
+	QuantityReactiveEnergyImport = Quantity("reactive_energy_import")
+	QuantityReactiveEnergyExport = Quantity("reactive_energy_export")
+	QuantityActivePowerImport    = Quantity("active_power_import")
+	QuantityActivePowerExport    = Quantity("active_power_export")
+	QuantityReactivePowerImport  = Quantity("reactive_power_import")
+	QuantityReactivePowerExport  = Quantity("reactive_power_export")
+	QuantityVoltage              = Quantity("voltage")
+	QuantityCurrent              = Quantity("current")
+	QuantityPowerFactor          = Quantity("power_factor")
+	QuantityFrequency            = Quantity("frequency")
+	QuantityMeterID              = Quantity("meter_id")
+	QuantityMeterType            = Quantity("meter_type")
+	QuantityClock                = Quantity("clock")
+	QuantityLocalTime            = Quantity("local_time")
+	QuantityLocalDate            = Quantity("local_date")
+	QuantityFirmwareVersion      = Quantity("firmware_version")
+	QuantityProtocolVersion      = Quantity("protocol_version")
+	QuantityTariffIndicator      = Quantity("tariff_indicator")
+	QuantityPowerFailures        = Quantity("power_failures")
+	QuantityLongPowerFailures    = Quantity("long_power_failures")
+	QuantityPowerFailureLog      = Quantity("power_failure_log")
+	QuantityVoltageSags          = Quantity("voltage_sags")
+	QuantityVoltageSwells        = Quantity("voltage_swells")
+	QuantityTextMessage          = Quantity("text_message")
+	QuantityMessageCode          = Quantity("message_code")
+	QuantityDeviceType           = Quantity("device_type")
+	QuantityGasVolume            = Quantity("gas_volume")
+	QuantityWaterVolume          = Quantity("water_volume")
+	QuantityHeatEnergy           = Quantity("heat_energy")
+	QuantityCoolingEnergy        = Quantity("cooling_energy")
+)
+
+// Well-known OBIS codes for electricity meters.
+var (
+	ActiveEnergyImport          = NewObis("1-0:1.8.0") // Active energy import (+A), total
+	ActiveEnergyImportTariff1   = NewObis("1-0:1.8.1") // Active energy import (+A), tariff 1
+	ActiveEnergyImportTariff2   = NewObis("1-0:1.8.2") // Active energy import (+A), tariff 2
+	ActiveEnergyImportTariff3   = NewObis("1-0:1.8.3") // Active energy import (+A), tariff 3
+	ActiveEnergyImportTariff4   = NewObis("1-0:1.8.4") // Active energy import (+A), tariff 4
+	ActiveEnergyExport          = NewObis("1-0:2.8.0") // Active energy export (-A), total
+	ActiveEnergyExportTariff1   = NewObis("1-0:2.8.1") // Active energy export (-A), tariff 1
+	ActiveEnergyExportTariff2   = NewObis("1-0:2.8.2") // Active energy export (-A), tariff 2
+	ActiveEnergyExportTariff3   = NewObis("1-0:2.8.3") // Active energy export (-A), tariff 3
+	ActiveEnergyExportTariff4   = NewObis("1-0:2.8.4") // Active energy export (-A), tariff 4
+	ReactiveEnergyImport        = NewObis("1-0:3.8.0") // Reactive energy import (+R), total
+	ReactiveEnergyExport        = NewObis("1-0:4.8.0") // Reactive energy export (-R), total
+	ActivePowerImport           = NewObis("1-0:1.7.0") // Instantaneous active power import (+P)
+	ActivePowerExport           = NewObis("1-0:2.7.0") // Instantaneous active power export (-P)
+	ReactivePowerImport         = NewObis("1-0:3.7.0") // Instantaneous reactive power import (+Q)
+	ReactivePowerExport         = NewObis("1-0:4.7.0") // Instantaneous reactive power export (-Q)
+	ActivePowerImportL1         = NewObis("1-0:21.7.0")
+	ActivePowerImportL2         = NewObis("1-0:41.7.0")
+	ActivePowerImportL3         = NewObis("1-0:61.7.0")
+	ActivePowerExportL1         = NewObis("1-0:22.7.0")
+	ActivePowerExportL2         = NewObis("1-0:42.7.0")
+	ActivePowerExportL3         = NewObis("1-0:62.7.0")
+	VoltageL1                   = NewObis("1-0:32.7.0")
+	VoltageL2                   = NewObis("1-0:52.7.0")
+	VoltageL3                   = NewObis("1-0:72.7.0")
+	CurrentL1                   = NewObis("1-0:31.7.0")
+	CurrentL2                   = NewObis("1-0:51.7.0")
+	CurrentL3                   = NewObis("1-0:71.7.0")
+	PowerFactor                 = NewObis("1-0:13.7.0")
+	Frequency                   = NewObis("1-0:14.7.0")
+	FirmwareVersion             = NewObis("1-0:0.2.0")
+	LocalTime                   = NewObis("1-0:0.9.1") // Shown as "0.9.1" in IEC 62056-21 readouts
+	LocalDate                   = NewObis("1-0:0.9.2") // Shown as "0.9.2" in IEC 62056-21 readouts
+	DeviceAddress               = NewObis("1-0:0.0.0") // Shown as "0.0.0" in IEC 62056-21 readouts
+	MeterID                     = NewObis("0-0:96.1.0")
+	MeterType                   = NewObis("0-0:96.1.7")
+	Clock                       = NewObis("0-0:1.0.0")
+	PowerFailureLog             = NewObis("1-0:99.97.0")
+	HANListVersion              = NewObis("1-1:0.2.129") // List version identifier used by Norwegian HAN meters
+	DSMRVersion                 = NewObis("1-3:0.2.8")   // DSMR version
+	DSMREquipmentID             = NewObis("0-0:96.1.1")  // DSMR equipment identifier
+	DSMRTariffIndicator         = NewObis("0-0:96.14.0") // DSMR tariff indicator
+	DSMRPowerFailures           = NewObis("0-0:96.7.21") // Number of power failures in any phase
+	DSMRLongPowerFailures       = NewObis("0-0:96.7.9")  // Number of long power failures in any phase
+	DSMRVoltageSagsL1           = NewObis("1-0:32.32.0")
+	DSMRVoltageSagsL2           = NewObis("1-0:52.32.0")
+	DSMRVoltageSagsL3           = NewObis("1-0:72.32.0")
+	DSMRVoltageSwellsL1         = NewObis("1-0:32.36.0")
+	DSMRVoltageSwellsL2         = NewObis("1-0:52.36.0")
+	DSMRVoltageSwellsL3         = NewObis("1-0:72.36.0")
+	DSMRTextMessage             = NewObis("0-0:96.13.0")
+	DSMRMessageCode             = NewObis("0-0:96.13.1")
+	DSMRMBusDeviceType          = NewObis("0-1:24.1.0") // M-Bus device type on channel 1
+	DSMRMBusEquipmentID         = NewObis("0-1:96.1.0") // M-Bus equipment identifier on channel 1
+	DSMRMBusValue               = NewObis("0-1:24.2.1") // Last M-Bus value on channel 1 (gas in most installations)
+	DSMRMBusValueWater          = NewObis("0-1:24.2.3") // Last M-Bus value on channel 1, used by e-MUCS for water
+	DSMRGasDelivered            = NewObis("0-1:24.3.0") // Gas delivered on channel 1 (DSMR 2.2)
+	HeatEnergy                  = NewObis("6-0:1.0.0")
+	CoolingEnergy               = NewObis("5-0:1.0.0")
+	GasVolume                   = NewObis("7-0:3.0.0")
+	ColdWaterVolume             = NewObis("8-0:1.0.0")
+	HotWaterVolume              = NewObis("9-0:1.0.0")
+	IEC62056MeterSerial         = NewObis("C.1.0") // Meter serial number as used in IEC 62056-21 readouts
+	IEC62056ErrorRegister       = NewObis("F.F")   // Error register as used in IEC 62056-21 readouts
+	IEC62056BillingPeriodsReset = NewObis("0.1.0") // Billing period reset counter
+)
+
+var (
+	semantics = map[Obis]Semantic{
+		ActiveEnergyImport:    {QuantityActiveEnergyImport, 0, 0, 0, kamstrup.UnitKWh},
+		ActiveEnergyExport:    {QuantityActiveEnergyExport, 0, 0, 0, kamstrup.UnitKWh},
+		ReactiveEnergyImport:  {QuantityReactiveEnergyImport, 0, 0, 0, kamstrup.UnitKVarh},
+		ReactiveEnergyExport:  {QuantityReactiveEnergyExport, 0, 0, 0, kamstrup.UnitKVarh},
+		ActivePowerImport:     {QuantityActivePowerImport, 0, 0, 0, kamstrup.UnitKW},
+		ActivePowerExport:     {QuantityActivePowerExport, 0, 0, 0, kamstrup.UnitKW},
+		ReactivePowerImport:   {QuantityReactivePowerImport, 0, 0, 0, kamstrup.UnitKVar},
+		ReactivePowerExport:   {QuantityReactivePowerExport, 0, 0, 0, kamstrup.UnitKVar},
+		PowerFactor:           {QuantityPowerFactor, 0, 0, 0, kamstrup.UnitNone},
+		Frequency:             {QuantityFrequency, 0, 0, 0, kamstrup.UnitHz},
+		FirmwareVersion:       {QuantityFirmwareVersion, 0, 0, 0, kamstrup.UnitNone},
+		LocalTime:             {QuantityLocalTime, 0, 0, 0, kamstrup.UnitClock},
+		LocalDate:             {QuantityLocalDate, 0, 0, 0, kamstrup.UnitDate},
+		DeviceAddress:         {QuantityMeterID, 0, 0, 0, kamstrup.UnitNone},
+		MeterID:               {QuantityMeterID, 0, 0, 0, kamstrup.UnitNone},
+		MeterType:             {QuantityMeterType, 0, 0, 0, kamstrup.UnitNone},
+		Clock:                 {QuantityClock, 0, 0, 0, kamstrup.UnitDatetime},
+		PowerFailureLog:       {QuantityPowerFailureLog, 0, 0, 0, kamstrup.UnitNone},
+		HANListVersion:        {QuantityProtocolVersion, 0, 0, 0, kamstrup.UnitNone},
+		DSMRVersion:           {QuantityProtocolVersion, 0, 0, 0, kamstrup.UnitNone},
+		DSMREquipmentID:       {QuantityMeterID, 0, 0, 0, kamstrup.UnitNone},
+		DSMRTariffIndicator:   {QuantityTariffIndicator, 0, 0, 0, kamstrup.UnitNone},
+		DSMRPowerFailures:     {QuantityPowerFailures, 0, 0, 0, kamstrup.UnitNone},
+		DSMRLongPowerFailures: {QuantityLongPowerFailures, 0, 0, 0, kamstrup.UnitNone},
+		DSMRTextMessage:       {QuantityTextMessage, 0, 0, 0, kamstrup.UnitNone},
+		DSMRMessageCode:       {QuantityMessageCode, 0, 0, 0, kamstrup.UnitNone},
+		DSMRMBusDeviceType:    {QuantityDeviceType, 0, 0, 1, kamstrup.UnitNone},
+		DSMRMBusEquipmentID:   {QuantityMeterID, 0, 0, 1, kamstrup.UnitNone},
+		DSMRMBusValue:         {QuantityGasVolume, 0, 0, 1, kamstrup.UnitCubicMetre},
+		DSMRMBusValueWater:    {QuantityWaterVolume, 0, 0, 1, kamstrup.UnitCubicMetre},
+		DSMRGasDelivered:      {QuantityGasVolume, 0, 0, 1, kamstrup.UnitCubicMetre},
+		HeatEnergy:            {QuantityHeatEnergy, 0, 0, 0, kamstrup.UnitKWh},
+		CoolingEnergy:         {QuantityCoolingEnergy, 0, 0, 0, kamstrup.UnitKWh},
+		GasVolume:             {QuantityGasVolume, 0, 0, 0, kamstrup.UnitCubicMetre},
+		ColdWaterVolume:       {QuantityWaterVolume, 0, 0, 0, kamstrup.UnitCubicMetre},
+		HotWaterVolume:        {QuantityWaterVolume, 0, 0, 0, kamstrup.UnitCubicMetre},
+	}
+)
+
+func init() {
+	// Tariff registers.
+	for tariff := 1; tariff <= 9; tariff++ {
+		e := strconv.Itoa(tariff)
+
+		semantics[Obis{"1", "0", "1", "8", e, ""}] = Semantic{QuantityActiveEnergyImport, 0, tariff, 0, kamstrup.UnitKWh}
+		semantics[Obis{"1", "0", "2", "8", e, ""}] = Semantic{QuantityActiveEnergyExport, 0, tariff, 0, kamstrup.UnitKWh}
+		semantics[Obis{"1", "0", "3", "8", e, ""}] = Semantic{QuantityReactiveEnergyImport, 0, tariff, 0, kamstrup.UnitKVarh}
+		semantics[Obis{"1", "0", "4", "8", e, ""}] = Semantic{QuantityReactiveEnergyExport, 0, tariff, 0, kamstrup.UnitKVarh}
+	}
+
+	// Per-phase values. Phase L1 is at C+20, L2 at C+40 and L3 at C+60.
+	perPhase := map[int]Semantic{
+		1:  {QuantityActivePowerImport, 0, 0, 0, kamstrup.UnitKW},
+		2:  {QuantityActivePowerExport, 0, 0, 0, kamstrup.UnitKW},
+		3:  {QuantityReactivePowerImport, 0, 0, 0, kamstrup.UnitKVar},
+		4:  {QuantityReactivePowerExport, 0, 0, 0, kamstrup.UnitKVar},
+		11: {QuantityCurrent, 0, 0, 0, kamstrup.UnitA},
+		12: {QuantityVoltage, 0, 0, 0, kamstrup.UnitV},
+		13: {QuantityPowerFactor, 0, 0, 0, kamstrup.UnitNone},
+	}
+
+	for c, semantic := range perPhase {
+		for phase := 1; phase <= 3; phase++ {
+			semantic.Phase = phase
+
+			semantics[Obis{"1", "0", strconv.Itoa(c + 20*phase), "7", "0", ""}] = semantic
+		}
+	}
+
+	for phase := 1; phase <= 3; phase++ {
+		c := strconv.Itoa(12 + 20*phase)
+
+		semantics[Obis{"1", "0", c, "32", "0", ""}] = Semantic{QuantityVoltageSags, phase, 0, 0, kamstrup.UnitNone}
+		semantics[Obis{"1", "0", c, "36", "0", ""}] = Semantic{QuantityVoltageSwells, phase, 0, 0, kamstrup.UnitNone}
+	}
+}
+
+// Semantic will look up the meaning of the OBIS code. Codes are compared by
+// their numeric value, so "C.1.0" will find "0-0:96.1.0". If value group A
+// and B are absent, as is common in IEC 62056-21 readouts, the code is looked
+// up as electricity ("1-0:") and then as an abstract object ("0-0:"). M-Bus
+// codes on channel 2 and up are found using the channel 1 entry.
+func (o Obis) Semantic() (Semantic, bool) {
+	normalized, ok := o.normalize()
+	if !ok {
+		return Semantic{}, false
+	}
+
+	if s, found := semantics[normalized]; found {
+		return s, true
+	}
+
+	if normalized.A == "" && normalized.B == "" {
+		for _, prefix := range [][2]string{{"1", "0"}, {"0", "0"}} {
+			normalized.A, normalized.B = prefix[0], prefix[1]
+
+			if s, found := semantics[normalized]; found {
+				return s, true
+			}
+		}
+
+		return Semantic{}, false
+	}
+
+	if b, _ := o.Group(GroupB); b > 1 && b <= 64 {
+		normalized.B = "1"
+
+		if s, found := semantics[normalized]; found && s.Channel == 1 {
+			s.Channel = int(b)
+
+			return s, true
+		}
+	}
+
+	return Semantic{}, false
+}
+
+// normalize will return the OBIS code with all value groups in decimal, and
+// NotUsed value groups removed.
+func (o Obis) normalize() (Obis, bool) {
+	raw, err := o.Bytes()
+	if err != nil {
+		return Obis{}, false
+	}
+
+	normalized, err := ObisFromBytes(raw)
+	if err != nil {
+		return Obis{}, false
+	}
+
+	return normalized, true
+}
@@ -0,0 +1,476 @@
+package iec62056
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	// ObisDescription is a structured description of an OBIS code according
+	// to IEC 62056-61 (and EN 13757-1 for non-electricity media). Fields not
+	// applicable to the code are left empty.
+	ObisDescription struct {
+		Medium        string // Value group A, "Electricity", "Heat", ...
+		Channel       string // Value group B
+		Quantity      string // Value group C, "Active power +", "Voltage", ...
+		Phase         string // Value group C, "L1", "L2", "L3", "Σ Li" or "Neutral"
+		Direction     string // Value group C, "Import" or "Export"
+		Processing    string // Value group D, "Instantaneous value", "Time integral 1", ...
+		Tariff        string // Value group E, "Total", "Tariff 1", ...
+		BillingPeriod string // Value group F
+	}
+
+	// obisMedium holds the description tables for a single medium.
+	obisMedium struct {
+		name       string
+		quantity   map[byte]string
+		processing map[byte]string
+	}
+)
+
+// Media as found in value group A.
+const (
+	MediumAbstract    = byte(0)
+	MediumElectricity = byte(1)
+	MediumHCA         = byte(4)
+	MediumCooling     = byte(5)
+	MediumHeat        = byte(6)
+	MediumGas         = byte(7)
+	MediumColdWater   = byte(8)
+	MediumHotWater    = byte(9)
+	MediumOther       = byte(15)
+)
+
+// mediumNotSpecified is used when value group A is absent.
+const mediumNotSpecified = NotUsed
+
+var (
+	// Value group C codes shared by all media.
+	commonQuantity = map[byte]string{
+		93: "Consortia specific identifiers",
+		94: "Country specific identifiers",
+		96: "Service entries",
+		97: "Error registers",
+		98: "List objects",
+		99: "Data profiles",
+	}
+
+	abstractQuantity = map[byte]string{
+		0:  "General purpose objects",
+		1:  "Clock",
+		2:  "Modem configuration",
+		10: "Script tables",
+		11: "Special days tables",
+		12: "Schedules",
+		13: "Activity calendars",
+		14: "Register activation",
+		15: "Single action schedules",
+		16: "Register monitors",
+		17: "Limiters",
+		19: "IEC local port setup",
+		20: "IEC optical port setup",
+		21: "Standard readout profile",
+		22: "IEC HDLC setup",
+		23: "IEC twisted pair setup",
+		24: "M-Bus",
+		25: "Internet setup",
+		40: "Association",
+		41: "SAP assignment",
+		42: "Logical device name",
+		43: "Security setup",
+		65: "Utility tables",
+	}
+
+	// Electricity quantities in value group C 1-20. 21-40, 41-60 and 61-80
+	// repeat these for L1, L2 and L3.
+	electricityQuantity = map[byte]string{
+		0:  "General purpose objects",
+		1:  "Active power +",
+		2:  "Active power -",
+		3:  "Reactive power +",
+		4:  "Reactive power -",
+		5:  "Reactive power QI",
+		6:  "Reactive power QII",
+		7:  "Reactive power QIII",
+		8:  "Reactive power QIV",
+		9:  "Apparent power +",
+		10: "Apparent power -",
+		11: "Current",
+		12: "Voltage",
+		13: "Power factor",
+		14: "Supply frequency",
+		15: "Active power QI+QII+QIII+QIV",
+		16: "Active power QI+QIV-QII-QIII",
+		17: "Active power QI",
+		18: "Active power QII",
+		19: "Active power QIII",
+		20: "Active power QIV",
+		81: "Angles",
+		82: "Unitless quantities",
+		84: "Power factor -",
+		85: "Power factor -",
+		86: "Power factor -",
+		87: "Power factor -",
+		88: "Ampere-squared hours",
+		89: "Volt-squared hours",
+		90: "Current",
+		91: "Current",
+		92: "Voltage",
+	}
+
+	electricityDirection = map[byte]string{
+		1:  "Import",
+		2:  "Export",
+		3:  "Import",
+		4:  "Export",
+		9:  "Import",
+		10: "Export",
+	}
+
+	electricityProcessing = map[byte]string{
+		0:  "Billing period average",
+		1:  "Cumulative minimum 1",
+		2:  "Cumulative maximum 1",
+		3:  "Minimum 1",
+		4:  "Current average 1",
+		5:  "Last average 1",
+		6:  "Maximum 1",
+		7:  "Instantaneous value",
+		8:  "Time integral 1",
+		9:  "Time integral 2",
+		10: "Time integral 3",
+		11: "Cumulative minimum 2",
+		12: "Cumulative maximum 2",
+		13: "Minimum 2",
+		14: "Current average 2",
+		15: "Last average 2",
+		16: "Maximum 2",
+		17: "Time integral 7",
+		18: "Time integral 8",
+		19: "Time integral 9",
+		20: "Time integral 10",
+		21: "Cumulative minimum 3",
+		22: "Cumulative maximum 3",
+		23: "Minimum 3",
+		24: "Current average 3",
+		25: "Last average 3",
+		26: "Maximum 3",
+		27: "Current average 5",
+		28: "Current average 6",
+		29: "Time integral 5",
+		30: "Time integral 6",
+		31: "Under limit threshold",
+		32: "Under limit occurrence counter",
+		33: "Under limit duration",
+		34: "Under limit magnitude",
+		35: "Over limit threshold",
+		36: "Over limit occurrence counter",
+		37: "Over limit duration",
+		38: "Over limit magnitude",
+		39: "Missing threshold",
+		40: "Missing occurrence counter",
+		41: "Missing duration",
+		42: "Missing magnitude",
+		55: "Test average",
+		58: "Time integral 4",
+	}
+
+	// Processing codes where value group E denotes a tariff.
+	tariffProcessing = map[byte]bool{
+		1: true, 2: true, 3: true, 4: true, 5: true, 6: true,
+		8: true, 9: true, 10: true,
+		11: true, 12: true, 13: true, 14: true, 15: true, 16: true,
+		21: true, 22: true, 23: true, 24: true, 25: true, 26: true,
+		29: true, 30: true, 58: true,
+	}
+
+	// Electricity general purpose objects, value group C 0, keyed by D and E.
+	electricityGeneral = map[[2]byte]string{
+		{0, 0}: "Device identification",
+		{0, 1}: "Device identification 2",
+		{0, 2}: "Device identification 3",
+		{1, 0}: "Billing period counter",
+		{1, 1}: "Number of available billing periods",
+		{1, 2}: "Time stamp of the most recent billing period",
+		{2, 0}: "Firmware version",
+		{2, 1}: "Parameter set identifier",
+		{2, 2}: "Time switch program identifier",
+		{3, 0}: "Active energy meter constant",
+		{3, 1}: "Reactive energy meter constant",
+		{3, 2}: "Apparent energy meter constant",
+		{4, 2}: "Current transformer ratio",
+		{4, 3}: "Voltage transformer ratio",
+		{6, 0}: "Nominal voltage",
+		{6, 1}: "Nominal current",
+		{6, 2}: "Nominal frequency",
+		{6, 3}: "Maximum current",
+		{8, 0}: "Measurement period for averages",
+		{8, 4}: "Recording interval for load profile",
+		{9, 1}: "Local time",
+		{9, 2}: "Local date",
+		{9, 6}: "Start of daylight saving time",
+		{9, 7}: "End of daylight saving time",
+	}
+
+	// Service entries, value group C 96, keyed by D.
+	serviceEntries = map[byte]string{
+		1:  "Device identification",
+		2:  "Parameter changes",
+		3:  "Input/output control signals",
+		4:  "Internal control signals",
+		5:  "Internal operating status",
+		6:  "Battery entries",
+		7:  "Power failure monitoring",
+		8:  "Operating time",
+		10: "Status register",
+		12: "Communication",
+		13: "Consumer message",
+		14: "Currently active tariff",
+		15: "Event counter",
+		50: "Manufacturer specific",
+	}
+
+	// Data profiles, value group C 99, keyed by D.
+	dataProfiles = map[byte]string{
+		1:  "Load profile 1",
+		2:  "Load profile 2",
+		3:  "Load profile during test",
+		97: "Power failure event log",
+		98: "Event log",
+		99: "Certification data log",
+	}
+
+	heatQuantity = map[byte]string{
+		0:  "General purpose objects",
+		1:  "Energy",
+		2:  "Accounted volume",
+		3:  "Accounted mass",
+		4:  "Flow volume",
+		5:  "Flow mass",
+		6:  "Return volume",
+		7:  "Return mass",
+		8:  "Power",
+		9:  "Flow rate",
+		10: "Flow temperature",
+		11: "Return temperature",
+		12: "Temperature difference",
+		13: "Media pressure",
+	}
+
+	hcaQuantity = map[byte]string{
+		0: "General purpose objects",
+		1: "Unrated integral",
+		2: "Rated integral",
+		3: "Radiator surface temperature",
+		4: "Heating medium temperature",
+		5: "Flow temperature",
+		6: "Return temperature",
+		7: "Room temperature",
+	}
+
+	gasQuantity = map[byte]string{
+		0:  "General purpose objects",
+		1:  "Forward undisturbed meter volume",
+		2:  "Forward disturbed meter volume",
+		3:  "Forward absolute meter volume",
+		4:  "Reverse undisturbed meter volume",
+		5:  "Reverse disturbed meter volume",
+		6:  "Reverse absolute meter volume",
+		11: "Forward undisturbed converted volume",
+		12: "Forward disturbed converted volume",
+		13: "Forward absolute converted volume",
+		14: "Reverse undisturbed converted volume",
+		15: "Reverse disturbed converted volume",
+		16: "Reverse absolute converted volume",
+		41: "Temperature",
+		42: "Pressure",
+	}
+
+	waterQuantity = map[byte]string{
+		0: "General purpose objects",
+		1: "Accumulated volume",
+		2: "Flow rate",
+		3: "Forward temperature",
+	}
+
+	// Processing for the non-electricity media.
+	otherProcessing = map[byte]string{
+		0: "Current value",
+		1: "Periodical value",
+		2: "Set date value",
+		3: "Billing date value",
+		4: "Minimum",
+		5: "Maximum",
+		6: "Test value",
+	}
+
+	media = map[byte]obisMedium{
+		MediumAbstract:     {"Abstract objects", abstractQuantity, nil},
+		MediumElectricity:  {"Electricity", electricityQuantity, electricityProcessing},
+		MediumHCA:          {"Heat cost allocator", hcaQuantity, otherProcessing},
+		MediumCooling:      {"Cooling energy", heatQuantity, otherProcessing},
+		MediumHeat:         {"Heat", heatQuantity, otherProcessing},
+		MediumGas:          {"Gas", gasQuantity, otherProcessing},
+		MediumColdWater:    {"Cold water", waterQuantity, otherProcessing},
+		MediumHotWater:     {"Hot water", waterQuantity, otherProcessing},
+		MediumOther:        {"Other media", nil, nil},
+		mediumNotSpecified: {"", electricityQuantity, electricityProcessing},
+	}
+)
+
+// Describe will return a structured description of the OBIS code. If value
+// group A is absent, as is common in IEC 62056-21 readouts, the code is
+// described as electricity.
+func (o Obis) Describe() ObisDescription {
+	var d ObisDescription
+
+	a := o.value(GroupA)
+	b := o.value(GroupB)
+	c := o.value(GroupC)
+	dd := o.value(GroupD)
+	e := o.value(GroupE)
+	f := o.value(GroupF)
+
+	medium, found := media[a]
+	d.Medium = medium.name
+	if !found {
+		d.Medium = fmt.Sprintf("Medium %d", a)
+	}
+
+	d.Channel = describeChannel(b)
+
+	electricity := a == MediumElectricity || a == mediumNotSpecified
+	switch {
+	case commonQuantity[c] != "":
+		d.Quantity = commonQuantity[c]
+	case electricity && c >= 21 && c <= 80:
+		base := (c-1)%20 + 1
+		d.Quantity = electricityQuantity[base]
+		d.Phase = fmt.Sprintf("L%d", (c-1)/20)
+		d.Direction = electricityDirection[base]
+	case electricity && (c == 11 || c == 12):
+		d.Quantity = electricityQuantity[c]
+		d.Phase = "Any phase"
+	case electricity && c == 14:
+		d.Quantity = electricityQuantity[c]
+	case electricity && (c >= 1 && c <= 20 || c == 84 || c == 90):
+		d.Quantity = electricityQuantity[c]
+		d.Phase = "Σ Li"
+		d.Direction = electricityDirection[c]
+	case electricity && c >= 85 && c <= 87:
+		d.Quantity = electricityQuantity[c]
+		d.Phase = fmt.Sprintf("L%d", c-84)
+	case electricity && (c == 91 || c == 92):
+		d.Quantity = electricityQuantity[c]
+		d.Phase = "Neutral"
+	case c >= 128 && c <= 199 || c == 240:
+		d.Quantity = "Manufacturer specific"
+	default:
+		d.Quantity = medium.quantity[c]
+	}
+
+	switch {
+	case c == 96:
+		d.Processing = serviceEntries[dd]
+	case c == 97:
+		d.Processing = "Error register"
+	case c == 99:
+		d.Processing = dataProfiles[dd]
+	case electricity && c == 0:
+		d.Processing = electricityGeneral[[2]byte{dd, e}]
+	case c != 0 && dd != NotUsed:
+		d.Processing = medium.processing[dd]
+	}
+
+	if e != NotUsed && a != MediumAbstract && c != 0 && c < 93 {
+		switch {
+		case electricity && dd == 7:
+			// For instantaneous values E is the harmonic, 0 being the total.
+			if e > 0 {
+				d.Tariff = fmt.Sprintf("Harmonic %d", e)
+			}
+		case e == 0:
+			d.Tariff = "Total"
+		case electricity && !tariffProcessing[dd]:
+		case e <= 63:
+			d.Tariff = fmt.Sprintf("Tariff %d", e)
+		}
+	}
+
+	d.BillingPeriod = describeBillingPeriod(f)
+
+	return d
+}
+
+// Description will return a human readable description of the OBIS code (if
+// available).
+func (o Obis) Description() string {
+	return o.Describe().String()
+}
+
+// String will return the description as a single line of text.
+func (d ObisDescription) String() string {
+	var parts []string
+
+	add := func(s string) {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+
+	add(d.Medium)
+	add(d.Channel)
+	add(d.Phase)
+	add(d.Quantity)
+	if d.Direction != "" {
+		add("(" + strings.ToLower(d.Direction) + ")")
+	}
+	add(d.Processing)
+	add(d.Tariff)
+	add(d.BillingPeriod)
+
+	return strings.Join(parts, " ")
+}
+
+// value will return the numeric value of a value group, or NotUsed if absent
+// or invalid.
+func (o Obis) value(g ObisGroup) byte {
+	v, ok := o.Group(g)
+	if !ok {
+		return NotUsed
+	}
+
+	return v
+}
+
+func describeChannel(b byte) string {
+	switch {
+	case b == 0 || b == NotUsed:
+		return ""
+	case b <= 64:
+		return fmt.Sprintf("Channel %d", b)
+	case b <= 127:
+		return fmt.Sprintf("Utility specific channel %d", b)
+	case b <= 199:
+		return fmt.Sprintf("Manufacturer specific channel %d", b)
+	}
+
+	return fmt.Sprintf("Reserved channel %d", b)
+}
+
+func describeBillingPeriod(f byte) string {
+	switch {
+	case f == NotUsed:
+		return ""
+	case f <= 99:
+		return fmt.Sprintf("Billing period %d", f)
+	case f == 101:
+		return "Last billing period"
+	case f >= 102 && f <= 125:
+		return fmt.Sprintf("%d billing periods ago", f-100)
+	case f == 126:
+		return "Unspecified billing periods"
+	}
+
+	return fmt.Sprintf("Billing period code %d", f)
+}
@@ -0,0 +1,306 @@
+package iec62056
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	// ObisPattern is a pattern matching a set of OBIS codes. A pattern is
+	// written like an OBIS code, but every value group can be a wildcard
+	// ("*"), or a list of values and ranges in brackets ("[21,41,61]" or
+	// "[1-4]"). Value groups left out of the pattern will match anything.
+	// Examples:
+	//
+	//   1-0:*.8.*            All energy registers
+	//   1-0:1.8.[1-4]        Active energy import, tariff 1 to 4
+	//   1-0:[32,52,72].7.0   Per-phase voltages
+	ObisPattern struct {
+		raw    string
+		groups [6]obisGroupPattern
+	}
+
+	obisGroupPattern struct {
+		any    bool
+		ranges []obisRange
+	}
+
+	obisRange struct {
+		from, to byte
+	}
+)
+
+// ParseObisPattern will parse an OBIS pattern.
+func ParseObisPattern(raw string) (ObisPattern, error) {
+	p := ObisPattern{raw: strings.TrimSpace(raw)}
+	fail := func(reason string, args ...interface{}) (ObisPattern, error) {
+		return ObisPattern{}, &ObisError{Code: raw, Reason: fmt.Sprintf(reason, args...)}
+	}
+
+	for g := range p.groups {
+		p.groups[g].any = true
+	}
+
+	rest := p.raw
+	if rest == "" {
+		return fail("empty pattern")
+	}
+
+	depth := 0
+	for i := 0; i < len(rest); i++ {
+		switch rest[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		}
+
+		if depth < 0 || depth > 1 {
+			return fail("unbalanced brackets")
+		}
+	}
+
+	if depth != 0 {
+		return fail("unbalanced brackets")
+	}
+
+	var tokens [6]string
+
+	// Medium and channel.
+	if i := indexOutside(rest, ':'); i >= 0 {
+		prefix := rest[:i]
+		rest = rest[i+1:]
+
+		tokens[GroupA] = prefix
+		if j := indexOutside(prefix, '-'); j >= 0 {
+			tokens[GroupA] = prefix[:j]
+			tokens[GroupB] = prefix[j+1:]
+
+			if tokens[GroupB] == "" {
+				return fail("empty value group B")
+			}
+		}
+
+		if tokens[GroupA] == "" {
+			return fail("empty value group A")
+		}
+	} else if indexOutside(rest, '-') >= 0 {
+		return fail("value group B must be followed by ':'")
+	}
+
+	// Billing period. A "*" is a wildcard when it makes up a whole value
+	// group, and a separator when it follows a value group.
+	for i := 0; i < len(rest); i++ {
+		switch rest[i] {
+		case '[':
+			j := strings.IndexByte(rest[i:], ']')
+			if j < 0 {
+				return fail("unbalanced brackets")
+			}
+
+			i += j
+			continue
+		case '&':
+		case '*':
+			if i == 0 || rest[i-1] == '.' {
+				continue
+			}
+		default:
+			continue
+		}
+
+		tokens[GroupF] = rest[i+1:]
+		rest = rest[:i]
+
+		if tokens[GroupF] == "" {
+			return fail("empty value group F")
+		}
+
+		break
+	}
+
+	parts := splitOutside(rest, '.')
+	switch {
+	case len(parts) < 2:
+		return fail("value groups C and D are required")
+	case len(parts) > 4:
+		return fail("too many value groups")
+	case len(parts) == 4 && tokens[GroupF] != "":
+		return fail("value group F given twice")
+	}
+
+	for i, part := range parts {
+		if part == "" {
+			return fail("empty value group %s", GroupC+ObisGroup(i))
+		}
+
+		tokens[GroupC+ObisGroup(i)] = part
+	}
+
+	for g, token := range tokens {
+		if token == "" {
+			continue
+		}
+
+		gp, err := parseGroupPattern(ObisGroup(g), token)
+		if err != nil {
+			return fail("value group %s: %s", ObisGroup(g), err.Error())
+		}
+
+		p.groups[g] = gp
+	}
+
+	return p, nil
+}
+
+// MustObisPattern is like ParseObisPattern but panics if the pattern cannot
+// be parsed. It is meant for patterns known at compile time.
+func MustObisPattern(raw string) ObisPattern {
+	p, err := ParseObisPattern(raw)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return p
+}
+
+// Match will return true if the OBIS code matches the pattern. Empty value
+// groups in the OBIS code are matched as NotUsed (255).
+func (p ObisPattern) Match(o Obis) bool {
+	for g, gp := range p.groups {
+		if gp.any {
+			continue
+		}
+
+		v := NotUsed
+		if str := o.group(ObisGroup(g)); str != "" {
+			var err error
+			v, err = groupValue(ObisGroup(g), str)
+			if err != nil {
+				return false
+			}
+		}
+
+		if !gp.match(v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String will return the pattern as given to ParseObisPattern.
+func (p ObisPattern) String() string {
+	return p.raw
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (p ObisPattern) MarshalText() ([]byte, error) {
+	return []byte(p.raw), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. This allows patterns to
+// be used directly in configuration files.
+func (p *ObisPattern) UnmarshalText(text []byte) error {
+	parsed, err := ParseObisPattern(string(text))
+	if err != nil {
+		return err
+	}
+
+	*p = parsed
+
+	return nil
+}
+
+func (gp obisGroupPattern) match(v byte) bool {
+	for _, r := range gp.ranges {
+		if v >= r.from && v <= r.to {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseGroupPattern(g ObisGroup, token string) (obisGroupPattern, error) {
+	if token == "*" {
+		return obisGroupPattern{any: true}, nil
+	}
+
+	if !strings.HasPrefix(token, "[") {
+		v, err := groupValue(g, token)
+		if err != nil {
+			return obisGroupPattern{}, err
+		}
+
+		return obisGroupPattern{ranges: []obisRange{{v, v}}}, nil
+	}
+
+	if !strings.HasSuffix(token, "]") || len(token) < 3 {
+		return obisGroupPattern{}, fmt.Errorf("malformed list %q", token)
+	}
+
+	var gp obisGroupPattern
+	for _, item := range strings.Split(token[1:len(token)-1], ",") {
+		item = strings.TrimSpace(item)
+
+		from, to := item, item
+		if i := strings.IndexByte(item, '-'); i >= 0 {
+			from, to = item[:i], item[i+1:]
+		}
+
+		f, err := groupValue(g, from)
+		if err != nil {
+			return obisGroupPattern{}, err
+		}
+
+		t, err := groupValue(g, to)
+		if err != nil {
+			return obisGroupPattern{}, err
+		}
+
+		if f > t {
+			return obisGroupPattern{}, fmt.Errorf("empty range %q", item)
+		}
+
+		gp.ranges = append(gp.ranges, obisRange{f, t})
+	}
+
+	return gp, nil
+}
+
+// indexOutside will find the first occurrence of c not enclosed in brackets.
+func indexOutside(s string, c byte) int {
+	depth := 0
+
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case c:
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// splitOutside will split s at every occurrence of sep not enclosed in
+// brackets.
+func splitOutside(s string, sep byte) []string {
+	var parts []string
+
+	for {
+		i := indexOutside(s, sep)
+		if i < 0 {
+			return append(parts, s)
+		}
+
+		parts = append(parts, s[:i])
+		s = s[i+1:]
+	}
+}
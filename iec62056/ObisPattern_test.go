@@ -0,0 +1,86 @@
+package iec62056
+
+import (
+	"testing"
+
+	"github.com/abrander/gometer/kamstrup"
+)
+
+func TestObisPatternMatch(t *testing.T) {
+	testSet := []struct {
+		pattern string
+		obis    string
+		match   bool
+	}{
+		{"1-0:*.8.*", "1-0:1.8.0", true},
+		{"1-0:*.8.*", "1-0:2.8.1", true},
+		{"1-0:*.8.*", "1-0:1.7.0", false},
+		{"1-0:*.8.*", "0-0:1.8.0", false},
+		{"1-0:*.8.*", "1.8.0", false},
+		{"1.8.0", "1-0:1.8.0", true},
+		{"1.8.0", "1-0:1.8.0*01", true},
+		{"1.8.0*255", "1-0:1.8.0", true},
+		{"1.8.0.255", "1-0:1.8.0*01", false},
+		{"1-0:[21,41,61].7.0", "1-0:41.7.0", true},
+		{"1-0:[21,41,61].7.0", "1-0:1.7.0", false},
+		{"1-0:1.8.[1-4]", "1-0:1.8.3", true},
+		{"1-0:1.8.[1-4]", "1-0:1.8.0", false},
+		{"1-0:1.8.[1-4,9]", "1-0:1.8.9", true},
+		{"1-0:[31-33,51].7.0", "1-0:32.7.0", true},
+		{"*-*:C.*", "0-0:C.1.0", true},
+		{"0-0:[96-99].*", "0-0:C.1.0", true},
+		{"1.8.*&*", "1.8.0*1", true},
+		{"1.8.*&[1-12]", "1.8.0&13", false},
+	}
+
+	for _, test := range testSet {
+		p, err := ParseObisPattern(test.pattern)
+		if err != nil {
+			t.Fatalf("ParseObisPattern(%q) failed: %s", test.pattern, err.Error())
+		}
+
+		if p.Match(NewObis(test.obis)) != test.match {
+			t.Errorf("%q.Match(%q) should be %v", test.pattern, test.obis, test.match)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"1",
+		"1-0:[1.8.0",
+		"1-0:1.8.[4-1]",
+		"1-0:1.8.[a]",
+		"1-0:1.8.[]",
+		"1-0:1.8.0*",
+		"1-0:1..0",
+		"1.8.]0[",
+		"]1[.8.0",
+		"1-0:1.8.[[1]]",
+		"1-0:1.8.0*]1[",
+	}
+
+	for _, raw := range invalid {
+		_, err := ParseObisPattern(raw)
+		if _, ok := err.(*ObisError); !ok {
+			t.Errorf("ParseObisPattern(%q) returned %v, expected *ObisError", raw, err)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	c := ValueCollection{
+		NewObis("1-0:1.8.0"):  kamstrup.Value{Value: 1},
+		NewObis("1-0:1.8.1"):  kamstrup.Value{Value: 2},
+		NewObis("1-0:2.8.0"):  kamstrup.Value{Value: 3},
+		NewObis("1-0:32.7.0"): kamstrup.Value{Value: 4},
+		NewObis("1-0:52.7.0"): kamstrup.Value{Value: 5},
+	}
+
+	if l := len(c.Select(MustObisPattern("1-0:1.8.*"))); l != 2 {
+		t.Errorf("Select(1-0:1.8.*) returned %d values, expected 2", l)
+	}
+
+	if l := len(c.Select(MustObisPattern("1-0:[32,52,72].7.0"), MustObisPattern("1-0:2.8.0"))); l != 3 {
+		t.Errorf("Select() returned %d values, expected 3", l)
+	}
+}
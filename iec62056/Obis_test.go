@@ -1,8 +1,11 @@
 package iec62056
 
 import (
+	"bytes"
 	"fmt"
 	"testing"
+
+	"github.com/abrander/gometer/kamstrup"
 )
 
 func TestParse(t *testing.T) {
@@ -29,9 +32,223 @@ func TestParse(t *testing.T) {
 
 	for raw, expected := range testSet {
 		o := NewObis(raw)
-		if *o != expected {
-			fmt.Printf("Got: %+v, expected: %+v\n", *o, expected)
+		if o != expected {
+			fmt.Printf("Got: %+v, expected: %+v\n", o, expected)
 			t.Fail()
 		}
 	}
 }
+
+func TestParseObis(t *testing.T) {
+	testSet := map[string]Obis{
+		"1.8.0":           Obis{"", "", "1", "8", "0", ""},
+		"0.9.1":           Obis{"", "", "0", "9", "1", ""},
+		"C.1.0":           Obis{"", "", "96", "1", "0", ""},
+		"F.F":             Obis{"", "", "97", "97", "", ""},
+		"1:1.8.1":         Obis{"1", "", "1", "8", "1", ""},
+		"1-0:1.8.0":       Obis{"1", "0", "1", "8", "0", ""},
+		"1-0:1.8.0*01":    Obis{"1", "0", "1", "8", "0", "1"},
+		"1-0:1.8.0&12":    Obis{"1", "0", "1", "8", "0", "12"},
+		"1-0:1.8.0.255":   Obis{"1", "0", "1", "8", "0", ""},
+		"01-00:001.8.0":   Obis{"1", "0", "1", "8", "0", ""},
+		"0-1:24.2.1":      Obis{"0", "1", "24", "2", "1", ""},
+		" 1-0:32.7.0 ":    Obis{"1", "0", "32", "7", "0", ""},
+		"1-0:1.8*255":     Obis{"1", "0", "1", "8", "", ""},
+		"1-0:99.97.0*101": Obis{"1", "0", "99", "97", "0", "101"},
+	}
+
+	for raw, expected := range testSet {
+		o, err := ParseObis(raw)
+		if err != nil {
+			t.Errorf("ParseObis(%q) failed: %s", raw, err.Error())
+		}
+
+		if o != expected {
+			t.Errorf("ParseObis(%q): Got: %+v, expected: %+v", raw, o, expected)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"1",
+		"1.",
+		".8.0",
+		"1..0",
+		"1.8.0.0.0",
+		"1.8.0.255*1",
+		"1.8.256",
+		"1.8.-1",
+		"1.8.x",
+		"L.1.C",
+		"1-:1.8.0",
+		":1.8.0",
+		"-0:1.8.0",
+		"1-0.1.8.0",
+		"1-0:1.8.0*",
+	}
+
+	for _, raw := range invalid {
+		_, err := ParseObis(raw)
+		if err == nil {
+			t.Errorf("ParseObis(%q) did not fail", raw)
+		}
+	}
+}
+
+func TestObisBytes(t *testing.T) {
+	testSet := map[string][]byte{
+		"1-0:1.8.0":     []byte{1, 0, 1, 8, 0, 255},
+		"1-0:1.8.0.255": []byte{1, 0, 1, 8, 0, 255},
+		"0-0:C.1.0*1":   []byte{0, 0, 96, 1, 0, 1},
+		"1.8.1":         []byte{255, 255, 1, 8, 1, 255},
+		"F.F":           []byte{255, 255, 97, 97, 255, 255},
+	}
+
+	for raw, expected := range testSet {
+		o, err := ParseObis(raw)
+		if err != nil {
+			t.Fatalf("ParseObis(%q) failed: %s", raw, err.Error())
+		}
+
+		b, err := o.Bytes()
+		if err != nil {
+			t.Fatalf("%q.Bytes() failed: %s", raw, err.Error())
+		}
+
+		if !bytes.Equal(b, expected) {
+			t.Errorf("%q.Bytes(): Got: % x, expected: % x", raw, b, expected)
+		}
+	}
+
+	o, err := ObisFromBytes([]byte{1, 0, 32, 7, 0, 255})
+	if err != nil {
+		t.Fatalf("ObisFromBytes() failed: %s", err.Error())
+	}
+
+	if o.String() != "1-0:32.7.0" {
+		t.Errorf("ObisFromBytes(): Got: %s, expected 1-0:32.7.0", o.String())
+	}
+
+	if v, ok := o.Group(GroupC); !ok || v != 32 {
+		t.Errorf("Group(C): Got: %d, %v, expected 32, true", v, ok)
+	}
+
+	if _, ok := o.Group(GroupF); ok {
+		t.Errorf("Group(F) should not be present")
+	}
+
+	if _, err := ObisFromBytes([]byte{1, 0, 1}); err == nil {
+		t.Errorf("ObisFromBytes() accepted short input")
+	}
+}
+
+func TestObisCanonical(t *testing.T) {
+	testSet := []string{
+		"1.8.0",
+		"C.1.0",
+		"F.F",
+		"0-0:C.1.0*01",
+		"1-0:1.8.0*255",
+		"1-0:1.8.0.255",
+		"01-00:001.008.000&012",
+		"0-1:24.2.1",
+	}
+
+	for _, raw := range testSet {
+		o, err := ParseObis(raw)
+		if err != nil {
+			t.Fatalf("ParseObis(%q) failed: %s", raw, err.Error())
+		}
+
+		b, err := o.Bytes()
+		if err != nil {
+			t.Fatalf("%q.Bytes() failed: %s", raw, err.Error())
+		}
+
+		fromBytes, err := ObisFromBytes(b)
+		if err != nil {
+			t.Fatalf("ObisFromBytes(% x) failed: %s", b, err.Error())
+		}
+
+		if o != fromBytes {
+			t.Errorf("ParseObis(%q) is %+v, ObisFromBytes(% x) is %+v", raw, o, b, fromBytes)
+		}
+
+		if NewObis(raw).Canonical() != o {
+			t.Errorf("NewObis(%q).Canonical() is %+v, ParseObis() is %+v", raw, NewObis(raw).Canonical(), o)
+		}
+	}
+
+	// NewObis keeps the code as written.
+	legacy := map[string]string{
+		"C.1.0":         "C.1.0",
+		"F.F":           "F.F",
+		"1-0:1.8.0*255": "1-0:1.8.0&255",
+	}
+
+	for raw, expected := range legacy {
+		if o := NewObis(raw); o.String() != expected {
+			t.Errorf("NewObis(%q) is %s, expected %s", raw, o, expected)
+		}
+
+		var o Obis
+		o.Parse(raw)
+		if o != NewObis(raw) {
+			t.Errorf("Parse(%q) is %+v, NewObis() is %+v", raw, o, NewObis(raw))
+		}
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	testSet := map[string]ObisDescription{
+		"1.8.0":         {"", "", "Active power +", "Σ Li", "Import", "Time integral 1", "Total", ""},
+		"1-0:2.8.1":     {"Electricity", "", "Active power -", "Σ Li", "Export", "Time integral 1", "Tariff 1", ""},
+		"1-0:52.7.0":    {"Electricity", "", "Voltage", "L2", "", "Instantaneous value", "", ""},
+		"1-0:1.8.0*101": {"Electricity", "", "Active power +", "Σ Li", "Import", "Time integral 1", "Total", "Last billing period"},
+		"0-1:24.2.1":    {"Abstract objects", "Channel 1", "M-Bus", "", "", "", "", ""},
+		"0-0:96.14.0":   {"Abstract objects", "", "Service entries", "", "", "Currently active tariff", "", ""},
+		"6-0:1.0.0":     {"Heat", "", "Energy", "", "", "Current value", "Total", ""},
+		"0.9.1":         {"", "", "General purpose objects", "", "", "Local time", "", ""},
+	}
+
+	for raw, expected := range testSet {
+		d := NewObis(raw).Describe()
+		if d != expected {
+			t.Errorf("%q.Describe(): Got: %+v, expected: %+v", raw, d, expected)
+		}
+	}
+
+	if d := NewObis("1-0:32.7.0").Description(); d != "Electricity L1 Voltage Instantaneous value" {
+		t.Errorf("Description(): Got %q", d)
+	}
+}
+
+func TestSemantic(t *testing.T) {
+	testSet := map[string]Semantic{
+		"1.8.0":         {QuantityActiveEnergyImport, 0, 0, 0, kamstrup.UnitKWh},
+		"1-0:1.8.2":     {QuantityActiveEnergyImport, 0, 2, 0, kamstrup.UnitKWh},
+		"1-0:52.7.0":    {QuantityVoltage, 2, 0, 0, kamstrup.UnitV},
+		"71.7.0":        {QuantityCurrent, 3, 0, 0, kamstrup.UnitA},
+		"C.1.0":         {QuantityMeterID, 0, 0, 0, kamstrup.UnitNone},
+		"0.9.1":         {QuantityLocalTime, 0, 0, 0, kamstrup.UnitClock},
+		"0-2:24.2.1":    {QuantityGasVolume, 0, 0, 2, kamstrup.UnitCubicMetre},
+		"1-0:1.7.0.255": {QuantityActivePowerImport, 0, 0, 0, kamstrup.UnitKW},
+	}
+
+	for raw, expected := range testSet {
+		s, found := NewObis(raw).Semantic()
+		if !found {
+			t.Errorf("%q.Semantic() not found", raw)
+		}
+
+		if s != expected {
+			t.Errorf("%q.Semantic(): Got: %+v, expected: %+v", raw, s, expected)
+		}
+	}
+
+	for _, raw := range []string{"1-0:1.8.0*101", "1-2:1.8.0", "1-0:99.99.99"} {
+		if _, found := NewObis(raw).Semantic(); found {
+			t.Errorf("%q.Semantic() should not be found", raw)
+		}
+	}
+}
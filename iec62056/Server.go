@@ -0,0 +1,185 @@
+package iec62056
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/tarm/serial"
+)
+
+type (
+	// ReadoutFunc will return the values of a data readout.
+	ReadoutFunc func() (ValueCollection, error)
+
+	// Server answers sign-ons with a data readout, acting as a meter. The
+	// baud rate is never changed.
+	Server struct {
+		// Identification is sent after "/", like "KAM5MT174". The fourth
+		// character is the baud rate character.
+		Identification string
+
+		// Address, if not empty, is the device address of the server. Sign-ons
+		// with another address are ignored, sign-ons without an address are
+		// always answered.
+		Address string
+
+		// ModeC makes the server wait for the acknowledgement and option
+		// select message before sending the readout. Otherwise the readout
+		// follows the identification right away, as in protocol mode A.
+		ModeC bool
+
+		// Readout is called for every sign-on. Calls are serialized.
+		Readout ReadoutFunc
+
+		// OnError, if set, will be called with readout errors. The sign-on
+		// is not answered in that case.
+		OnError func(error)
+
+		mu sync.Mutex
+	}
+)
+
+// maxLineLength limits the length of a line from the client. Longer lines are
+// dropped, keeping only the bytes after the limit.
+const maxLineLength = 128
+
+// Acknowledge starts the option select message of protocol mode C.
+const Acknowledge = byte(0x06)
+
+// Serve will answer sign-ons on port until reading fails. The error from
+// the port is returned, io.EOF included.
+func (s *Server) Serve(port io.ReadWriter) error {
+	r := bufio.NewReader(port)
+
+	for {
+		line, err := readLine(r)
+		if err != nil {
+			return err
+		}
+
+		address, ok := parseSignOn(line)
+		if !ok || (address != "" && address != s.Address) {
+			continue
+		}
+
+		_, err = port.Write([]byte("/" + s.Identification + "\r\n"))
+		if err != nil {
+			return err
+		}
+
+		if s.ModeC {
+			line, err = readLine(r)
+			if err != nil {
+				return err
+			}
+
+			if !strings.HasPrefix(line, string(Acknowledge)) {
+				continue
+			}
+		}
+
+		values, err := s.readout()
+		if err != nil {
+			if s.OnError != nil {
+				s.OnError(err)
+			}
+
+			continue
+		}
+
+		_, err = port.Write(values.DataBlock())
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// ListenAndServe will accept TCP connections on address and serve them.
+func (s *Server) ListenAndServe(address string) error {
+	l, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+
+	return s.ServeListener(l)
+}
+
+// ServeListener will accept connections on l and serve them until l is
+// closed.
+func (s *Server) ServeListener(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+
+		go func() {
+			defer conn.Close()
+
+			s.Serve(conn)
+		}()
+	}
+}
+
+// ServeSerial will serve a serial device, or the slave side of a pty, at
+// 300 baud, 7E1.
+func (s *Server) ServeSerial(device string) error {
+	port, err := serial.OpenPort(&serial.Config{
+		Name:   device,
+		Baud:   300,
+		Size:   7,
+		Parity: serial.ParityEven,
+	})
+	if err != nil {
+		return err
+	}
+	defer port.Close()
+
+	return s.Serve(port)
+}
+
+func (s *Server) readout() (ValueCollection, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.Readout()
+}
+
+// readLine will read a line ending with LF and return it without CR LF. If
+// the line grows longer than maxLineLength, the bytes read so far are dropped,
+// so line noise or a chatty client cannot stop the server.
+func readLine(r *bufio.Reader) (string, error) {
+	var line []byte
+
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return "", err
+		}
+
+		if b == LineFeed {
+			return strings.TrimRight(string(line), "\r"), nil
+		}
+
+		if len(line) >= maxLineLength {
+			line = line[:0]
+		}
+
+		line = append(line, b)
+	}
+}
+
+// parseSignOn will parse a sign-on message like "/?12345678!" and return
+// the device address.
+func parseSignOn(line string) (string, bool) {
+	// Anything before the sign-on, like a wake-up sequence, is ignored.
+	start := strings.Index(line, "/?")
+	if start < 0 || !strings.HasSuffix(line, "!") {
+		return "", false
+	}
+
+	return line[start+2 : len(line)-1], true
+}
@@ -0,0 +1,99 @@
+package iec62056
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/abrander/gometer/kamstrup"
+)
+
+func testCollection() ValueCollection {
+	return ValueCollection{
+		NewObis("1-0:1.8.0"):  {Value: 12345.6, Unit: kamstrup.UnitKWh},
+		NewObis("1-0:32.7.0"): {Value: 230, Unit: kamstrup.UnitV},
+		NewObis("8-0:1.0.0"):  {Value: 10.5, Unit: kamstrup.UnitCubicMetre},
+	}
+}
+
+func TestDataBlock(t *testing.T) {
+	block := testCollection().DataBlock()
+
+	expected := "\x021-0:1.8.0(12345.6*kWh)\r\n1-0:32.7.0(230*V)\r\n8-0:1.0.0(10.5*m3)\r\n!\r\n\x03"
+	if string(block[:len(block)-1]) != expected {
+		t.Errorf("DataBlock() returned %q", block)
+	}
+
+	// The BCC covers everything after STX, so all of it XORs to zero.
+	var check byte
+	for _, b := range block[1:] {
+		check ^= b
+	}
+
+	if check != 0 {
+		t.Errorf("DataBlock() has invalid BCC %02x", block[len(block)-1])
+	}
+
+	// Read back, value group A and B are dropped from the keys.
+	c, err := NewValueCollection(block[:len(block)-1])
+	if err != nil || len(c) != 3 || c[NewObis("1.0.0")] != testCollection()[NewObis("8-0:1.0.0")] {
+		t.Errorf("NewValueCollection() of data block returned %v, %v", c, err)
+	}
+}
+
+func TestServer(t *testing.T) {
+	readouts := 0
+
+	s := &Server{
+		Identification: "GMT5BRIDGE",
+		Address:        "12345678",
+		Readout: func() (ValueCollection, error) {
+			readouts++
+			return testCollection(), nil
+		},
+	}
+
+	server, client := net.Pipe()
+	defer client.Close()
+
+	go func() {
+		defer server.Close()
+		s.Serve(server)
+	}()
+
+	// Another address is ignored.
+	client.Write([]byte("/?87654321!\r\n"))
+
+	i := NewIec62056(client)
+
+	identification, c, err := i.Signin("12345678")
+	if err != nil {
+		t.Fatalf("Signin() returned %s", err)
+	}
+
+	if !bytes.Equal(identification, []byte("/GMT5BRIDGE\r\n")) {
+		t.Errorf("Signin() returned identification %q", identification)
+	}
+
+	if len(c) != 3 || c[NewObis("1.8.0")].Value != 12345.6 {
+		t.Errorf("Signin() returned %v", c)
+	}
+
+	_, c, err = i.Signin("")
+	if err != nil || len(c) != 3 {
+		t.Errorf("Signin() without address returned %v, %v", c, err)
+	}
+
+	// Line noise longer than a line is dropped, and does not stop the
+	// server.
+	client.Write(bytes.Repeat([]byte{0x55}, 3*maxLineLength))
+
+	_, c, err = i.Signin("")
+	if err != nil || len(c) != 3 {
+		t.Errorf("Signin() after line noise returned %v, %v", c, err)
+	}
+
+	if readouts != 3 {
+		t.Errorf("Readout called %d times, expected 3", readouts)
+	}
+}
@@ -3,7 +3,7 @@ package iec62056
 import (
 	"bytes"
 	"errors"
-	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -21,7 +21,70 @@ func NewValueCollection(payload []byte) (ValueCollection, error) {
 	return c, c.ParsePayload(payload)
 }
 
-func parseValue(in string) (kamstrup.Value, error) {
+var (
+	// ErrMalformedLine will be returned if a data line cannot be parsed.
+	ErrMalformedLine = errors.New("malformed data line")
+
+	// Units as written in readouts, which differ from the Kamstrup notation.
+	unitAliases = map[string]kamstrup.Unit{
+		"m3":    kamstrup.UnitCubicMetre,
+		"m3/h":  kamstrup.UnitCubicMetrePerHour,
+		"GJ":    kamstrup.UnitGJ,
+		"MJ":    kamstrup.UnitMJ,
+		"kJ":    kamstrup.UnitKJ,
+		"kVArh": kamstrup.UnitKVarh,
+		"kVAr":  kamstrup.UnitKVar,
+		"kW":    kamstrup.UnitKW,
+		"kvar":  kamstrup.UnitKVar,
+		"°C":    kamstrup.UnitCelsius,
+		"min":   kamstrup.UnitMinute,
+	}
+
+	// Units written differently in readouts, as readouts are ASCII only.
+	readoutUnits = map[kamstrup.Unit]string{
+		kamstrup.UnitCubicMetre:        "m3",
+		kamstrup.UnitCubicMetrePerHour: "m3/h",
+		kamstrup.UnitGJ:                "GJ",
+		kamstrup.UnitMJ:                "MJ",
+		kamstrup.UnitKJ:                "kJ",
+		kamstrup.UnitMinute:            "min",
+	}
+)
+
+// ParseDataLine will split a data line like "1-0:1.8.1(001581.123*kWh)" or
+// "0-1:24.2.1(101209112500W)(12785.123*m3)" into an OBIS code and the raw
+// contents of each set of brackets.
+func ParseDataLine(line string) (Obis, []string, error) {
+	line = strings.TrimSpace(line)
+
+	start := strings.IndexByte(line, '(')
+	if start < 1 || !strings.HasSuffix(line, ")") {
+		return Obis{}, nil, ErrMalformedLine
+	}
+
+	obis := NewObis(line[:start])
+
+	var values []string
+	rest := line[start:]
+	for len(rest) > 0 {
+		if rest[0] != '(' {
+			return Obis{}, nil, ErrMalformedLine
+		}
+
+		end := strings.IndexByte(rest, ')')
+		if end < 0 {
+			return Obis{}, nil, ErrMalformedLine
+		}
+
+		values = append(values, rest[1:end])
+		rest = rest[end+1:]
+	}
+
+	return obis, values, nil
+}
+
+// ParseValue will parse a value as found in a data line, like "001581.123*kWh".
+func ParseValue(in string) (kamstrup.Value, error) {
 	var value kamstrup.Value
 	parts := strings.Split(in, "*")
 	if len(parts) < 1 {
@@ -29,7 +92,7 @@ func parseValue(in string) (kamstrup.Value, error) {
 	}
 
 	if len(parts) > 1 {
-		value.Unit = kamstrup.UnitFromString(parts[1])
+		value.Unit = parseUnit(parts[1])
 	}
 
 	var err error
@@ -42,18 +105,75 @@ func parseValue(in string) (kamstrup.Value, error) {
 	return value, nil
 }
 
-func parseLine(line string) (Obis, kamstrup.Value, error) {
-	expr := regexp.MustCompile(`(?P<obis>[\d.]+)\((?P<value>.+)\)`)
-	match := expr.FindStringSubmatch(line)
+// FormatValue will format a value as found in a data line, like
+// "1581.123*kWh". Values without a unit have no "*".
+func FormatValue(v kamstrup.Value) string {
+	s := strconv.FormatFloat(v.Value, 'f', -1, 64)
+
+	if unit := formatUnit(v.Unit); unit != "" {
+		s += "*" + unit
+	}
+
+	return s
+}
 
-	result := make(map[string]string)
-	for i, name := range expr.SubexpNames() {
-		result[name] = match[i]
+// FormatDataLine will format a data line like "1-0:1.8.0(1581.123*kWh)".
+func FormatDataLine(obis Obis, v kamstrup.Value) string {
+	return obis.String() + "(" + FormatValue(v) + ")"
+}
+
+// DataBlock will format the collection as the data block of a readout,
+// from STX to ETX followed by the BCC. Lines are sorted by OBIS code.
+func (c ValueCollection) DataBlock() []byte {
+	codes := make([]Obis, 0, len(c))
+	for obis := range c {
+		codes = append(codes, obis)
 	}
 
-	obis := NewObis(result["obis"])
+	sort.Slice(codes, func(i, j int) bool { return codes[i].String() < codes[j].String() })
 
-	value, err := parseValue(result["value"])
+	block := []byte{FrameStart}
+	for _, obis := range codes {
+		block = append(block, FormatDataLine(obis, c[obis])...)
+		block = append(block, Completion...)
+	}
+
+	block = append(block, End)
+	block = append(block, Completion...)
+	block = append(block, FrameEnd)
+
+	return append(block, BCC(block))
+}
+
+func formatUnit(u kamstrup.Unit) string {
+	if unit, found := readoutUnits[u]; found {
+		return unit
+	}
+
+	if u == kamstrup.UnitNone || strings.HasPrefix(u.String(), "[") {
+		return ""
+	}
+
+	return u.String()
+}
+
+func parseUnit(in string) kamstrup.Unit {
+	if unit, found := unitAliases[in]; found {
+		return unit
+	}
+
+	return kamstrup.UnitFromString(in)
+}
+
+// parseLine will parse a data line. If the line holds more than one value,
+// the last one is used, as is the case for M-Bus values with a capture time.
+func parseLine(line string) (Obis, kamstrup.Value, error) {
+	obis, values, err := ParseDataLine(line)
+	if err != nil {
+		return obis, kamstrup.Value{}, err
+	}
+
+	value, err := ParseValue(values[len(values)-1])
 	if err != nil {
 		return obis, kamstrup.Value{}, err
 	}
@@ -61,6 +181,11 @@ func parseLine(line string) (Obis, kamstrup.Value, error) {
 	return obis, value, nil
 }
 
+// ParsePayload will parse the data lines of a readout into the collection.
+// Lines that cannot be parsed are skipped.
+//
+// Values are keyed by the OBIS code without value group A and B, so both
+// "1.8.0(...)" and "1-0:1.8.0(...)" give the key "1.8.0".
 func (c ValueCollection) ParsePayload(payload []byte) error {
 	// An empty payload is valid.
 	if payload == nil {
@@ -87,9 +212,27 @@ func (c ValueCollection) ParsePayload(payload []byte) error {
 
 		obis, value, err := parseLine(str)
 		if err == nil {
+			obis.A, obis.B = "", ""
 			c[obis] = value
 		}
 	}
 
 	return nil
 }
+
+// Select will return the values with an OBIS code matching at least one of
+// the patterns.
+func (c ValueCollection) Select(patterns ...ObisPattern) ValueCollection {
+	selected := make(ValueCollection)
+
+	for obis, value := range c {
+		for _, pattern := range patterns {
+			if pattern.Match(obis) {
+				selected[obis] = value
+				break
+			}
+		}
+	}
+
+	return selected
+}
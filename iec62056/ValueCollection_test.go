@@ -0,0 +1,45 @@
+package iec62056
+
+import (
+	"testing"
+)
+
+func TestParsePayloadKeys(t *testing.T) {
+	payload := []byte("\x02" +
+		"1.8.0(001234.5*kWh)\r\n" +
+		"1-0:2.8.0(000012.3*kWh)\r\n" +
+		"0-0:C.1.0(12345678)\r\n" +
+		"1-0:1.8.1*01(000100.0*kWh)\r\n" +
+		"!\r\n\x03")
+
+	c, err := NewValueCollection(payload)
+	if err != nil {
+		t.Fatalf("NewValueCollection() failed: %s", err.Error())
+	}
+
+	// Value group A and B are dropped from the keys.
+	expected := map[Obis]float64{
+		{C: "1", D: "8", E: "0"}:          1234.5,
+		{C: "2", D: "8", E: "0"}:          12.3,
+		{C: "C", D: "1", E: "0"}:          12345678,
+		{C: "1", D: "8", E: "1", F: "01"}: 100,
+	}
+
+	if len(c) != len(expected) {
+		t.Errorf("Got %d values, expected %d: %v", len(c), len(expected), c)
+	}
+
+	for obis, value := range expected {
+		if v, found := c[obis]; !found || v.Value != value {
+			t.Errorf("Value of %s is %v (found: %v), expected %v", obis, v, found, value)
+		}
+	}
+
+	if _, found := c[NewObis("1-0:2.8.0")]; found {
+		t.Errorf("2.8.0 found as 1-0:2.8.0")
+	}
+
+	if v := c[NewObis("2.8.0")]; v.Value != 12.3 {
+		t.Errorf("1-0:2.8.0 not found as 2.8.0")
+	}
+}
@@ -0,0 +1,285 @@
+// Package flagid reads manufacturer lists such as the FLAG ID list published
+// by the DLMS User Association. It is shared by the iec62056 package and the
+// generator of its built-in list, which must not import iec62056 itself.
+package flagid
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+	"unicode"
+)
+
+type (
+	// Entry is a single manufacturer.
+	Entry struct {
+		Code    string
+		Name    string
+		Address string
+		Country string
+	}
+)
+
+var (
+	// ErrNoEntries will be returned if a list contains no usable entries.
+	ErrNoEntries = errors.New("no manufacturers found in list")
+
+	// Header names recognized by Read.
+	columnNames = map[string]string{
+		"flag id":           "code",
+		"flag":              "code",
+		"id":                "code",
+		"code":              "code",
+		"manufacturer":      "name",
+		"manufacturer name": "name",
+		"company":           "name",
+		"name":              "name",
+		"address":           "address",
+		"country":           "country",
+	}
+
+	// Legal forms kept as part of the name when following it after a
+	// comma, like "Co., Ltd." or "management company, JSC". They are
+	// compared in lower case without trailing dots.
+	legalForms = map[string]bool{
+		"ab":      true,
+		"ag":      true,
+		"a/s":     true,
+		"b.v":     true,
+		"bv":      true,
+		"co":      true,
+		"co.ltd":  true,
+		"corp":    true,
+		"gmbh":    true,
+		"inc":     true,
+		"jsc":     true,
+		"kg":      true,
+		"limited": true,
+		"llc":     true,
+		"ltd":     true,
+		"ltda":    true,
+		"n.v":     true,
+		"nv":      true,
+		"oy":      true,
+		"plc":     true,
+		"s.a":     true,
+		"s.l":     true,
+		"s.p.a":   true,
+		"s.r.l":   true,
+		"s.r.o":   true,
+		"sa":      true,
+		"srl":     true,
+	}
+
+	// Legal forms also recognized at the start of a part, as they are
+	// unlikely to start an address.
+	prefixForms = map[string]bool{
+		"inc":     true,
+		"limited": true,
+		"llc":     true,
+		"ltd":     true,
+	}
+)
+
+// Read will read a manufacturer list saved as comma, semicolon or tab
+// separated text. If the first line is a header, columns are identified by
+// name ("FLAG ID", "Manufacturer", "Address", "Country"). Otherwise the
+// columns are expected in that order. If no address or country column is
+// present, the manufacturer column is split using Split. Codes are returned
+// in upper case, and rows without a valid code are skipped.
+func Read(reader io.Reader) ([]Entry, error) {
+	raw, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	text := strings.TrimPrefix(string(raw), "\ufeff")
+
+	firstLine := text
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		firstLine = text[:i]
+	}
+
+	r := csv.NewReader(strings.NewReader(text))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	switch {
+	case strings.Contains(firstLine, "\t"):
+		r.Comma = '\t'
+	case strings.Contains(firstLine, ";"):
+		r.Comma = ';'
+	}
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, ErrNoEntries
+	}
+
+	columns := map[string]int{"code": 0, "name": 1, "address": -1, "country": -1}
+	if len(records[0]) > 2 {
+		columns["address"] = 2
+	}
+	if len(records[0]) > 3 {
+		columns["country"] = 3
+	}
+
+	// Use the header if present.
+	header := make(map[string]int)
+	for i, field := range records[0] {
+		if column, found := columnNames[strings.ToLower(strings.TrimSpace(field))]; found {
+			if _, seen := header[column]; !seen {
+				header[column] = i
+			}
+		}
+	}
+	if _, found := header["code"]; found {
+		columns = map[string]int{"code": -1, "name": -1, "address": -1, "country": -1}
+		for column, i := range header {
+			columns[column] = i
+		}
+		records = records[1:]
+	}
+
+	field := func(record []string, column string) string {
+		i := columns[column]
+		if i < 0 || i >= len(record) {
+			return ""
+		}
+
+		return strings.TrimSpace(record[i])
+	}
+
+	var entries []Entry
+	for _, record := range records {
+		code := NormalizeCode(field(record, "code"))
+		if !ValidCode(code) {
+			continue
+		}
+
+		e := Entry{
+			Code:    code,
+			Name:    field(record, "name"),
+			Address: field(record, "address"),
+			Country: field(record, "country"),
+		}
+
+		if columns["address"] < 0 && columns["country"] < 0 {
+			e.Name, e.Address, e.Country = Split(e.Name)
+		}
+
+		entries = append(entries, e)
+	}
+
+	if len(entries) == 0 {
+		return nil, ErrNoEntries
+	}
+
+	return entries, nil
+}
+
+// NormalizeCode will return the code in upper case. IEC 62056-21 uses a lower
+// case third letter to signal support for a 20 ms reaction time.
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// ValidCode will return true if code is three upper case letters.
+func ValidCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+
+	for _, c := range []byte(code) {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Split will split a free text manufacturer description into name, address
+// and country. The DLMS list is not consistent, so this is a best effort:
+//
+//   - The name is everything before the first comma, including legal forms
+//     following it, like "Co., Ltd.", even if not followed by a comma.
+//   - The country is the last part if it contains no digits. A remark after
+//     the country, like "France. (Gas)", is added to the name.
+//   - The address is everything in between.
+//
+// Descriptions without commas are returned as the name.
+func Split(text string) (string, string, string) {
+	// "Co,. Ltd" is a common typo of "Co., Ltd".
+	text = strings.ReplaceAll(text, ",.", ".,")
+
+	// Commas between digits, like "km 0,600", do not separate parts.
+	var parts []string
+	start := 0
+	for i := 0; i <= len(text); i++ {
+		if i < len(text) && (text[i] != ',' || (i > 0 && i+1 < len(text) && isDigit(text[i-1]) && isDigit(text[i+1]))) {
+			continue
+		}
+
+		if part := strings.TrimSpace(text[start:i]); part != "" {
+			parts = append(parts, part)
+		}
+
+		start = i + 1
+	}
+
+	if len(parts) == 0 {
+		return "", "", ""
+	}
+
+	n := 1
+	for n < len(parts) && legalForms[strings.TrimRight(strings.ToLower(parts[n]), ".")] {
+		n++
+	}
+
+	name := strings.Join(parts[:n], ", ")
+	rest := parts[n:]
+
+	// A missing comma after "Ltd" is common, like "Co., Ltd Some Road 1".
+	if len(rest) > 0 {
+		word := rest[0]
+		if i := strings.IndexFunc(word, func(r rune) bool { return !unicode.IsLetter(r) }); i >= 0 {
+			word = word[:i]
+		}
+
+		if prefixForms[strings.ToLower(word)] && len(word) < len(rest[0]) {
+			if rest[0][len(word)] == '.' {
+				word += "."
+			}
+
+			name += ", " + word
+			rest[0] = strings.TrimLeft(rest[0][len(word):], " (")
+		}
+	}
+	if len(rest) == 0 {
+		return name, "", ""
+	}
+
+	last := rest[len(rest)-1]
+	if i := strings.Index(last, ". ("); i >= 0 && strings.HasSuffix(last, ")") {
+		name += " " + last[i+2:]
+		last = last[:i]
+		rest[len(rest)-1] = last
+	}
+
+	country := ""
+	if !strings.ContainsAny(last, "0123456789") {
+		country = strings.TrimRight(last, ". ")
+		rest = rest[:len(rest)-1]
+	}
+
+	return name, strings.Join(rest, ", "), country
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
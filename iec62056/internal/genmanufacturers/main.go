@@ -0,0 +1,74 @@
+// genmanufacturers will generate the built-in manufacturer list from the FLAG
+// ID list published by the DLMS User Association, kept in flagids.csv. To
+// update it, save the new list as comma, semicolon or tab separated text in
+// flagids.csv and run "go generate" in the iec62056 directory. flagids.csv
+// keeps the name, address and country in separate columns. A list with only
+// a free text manufacturer column is split using flagid.Split, which is a
+// best effort, so check the result before committing it. The generator does
+// not import iec62056, so a broken ManufacturerList.go can always be
+// regenerated.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/abrander/gometer/iec62056/internal/flagid"
+)
+
+func main() {
+	output := flag.String("o", "ManufacturerList.go", "Output file")
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		fmt.Fprintf(os.Stderr, "Usage: %s [-o output] list\n", os.Args[0])
+		os.Exit(2)
+	}
+
+	f, err := os.Open(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	entries, err := flagid.Read(f)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", flag.Arg(0), err.Error())
+		os.Exit(1)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Code < entries[j].Code
+	})
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by genmanufacturers; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package iec62056\n\n")
+	fmt.Fprintf(&buf, "var (\n")
+	fmt.Fprintf(&buf, "\t// Known manufacturers as listed by the DLMS User Association:\n")
+	fmt.Fprintf(&buf, "\t// http://dlms.com/organization/flagmanufacturesids/\n")
+	fmt.Fprintf(&buf, "\tmanufacturerList = []ManufacturerEntry{\n")
+	for _, e := range entries {
+		fmt.Fprintf(&buf, "\t\t{%q, %q, %q, %q},\n", e.Code, e.Name, e.Address, e.Country)
+	}
+	fmt.Fprintf(&buf, "\t}\n")
+	fmt.Fprintf(&buf, ")\n")
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+		os.Exit(1)
+	}
+
+	err = ioutil.WriteFile(*output, source, 0644)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+		os.Exit(1)
+	}
+}
@@ -99,7 +99,7 @@ func (f *Frame) Decode(raw []byte) error {
 	f.CommandID = unescaped[2]
 	f.Data = unescaped[3 : frameLength-3]
 
-	checksum := f.checksum()
+	checksum := f.Checksum()
 
 	// Check if we got a stop byte.
 	if unescaped[frameLength-1] != Stop {
@@ -127,7 +127,7 @@ func (f Frame) Encode() []byte {
 	payload = append(payload, 0x0)
 
 	// Make checksum of everything but direction byte.
-	checksum := f.checksum()
+	checksum := f.Checksum()
 
 	// Replace the two "blank" checksums bytes with the real checksum.
 	payload[len(payload)-2] = byte(checksum >> 8)
@@ -150,7 +150,9 @@ func (f Frame) Encode() []byte {
 	return raw
 }
 
-func (f Frame) checksum() int {
+// Checksum will calculate the checksum of the frame from the address to the
+// end of the data.
+func (f Frame) Checksum() uint16 {
 	var msg []byte
 
 	msg = append(msg, f.Address)
@@ -184,5 +186,5 @@ func (f Frame) checksum() int {
 		}
 	}
 
-	return reg
+	return uint16(reg)
 }
@@ -2,24 +2,45 @@ package kamstrup
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"time"
 
 	"github.com/tarm/serial"
+
+	"github.com/abrander/gometer/trace"
 )
 
 type (
 	// Kamstrup represents a Kamstrup meter.
 	Kamstrup struct {
-		port *serial.Port
+		port   io.ReadWriteCloser
+		tracer trace.Tracer
+
+		// Address is the address of the meter used in all frames. It
+		// defaults to DefaultAddress.
+		Address byte
+	}
+
+	// LogRecord is a record from a log readout.
+	LogRecord struct {
+		Time   time.Time
+		Values map[uint16]Value
 	}
 )
 
+// DefaultAddress is the address answered by all meters on the optical eye.
+const DefaultAddress = byte(0x3f)
+
 var (
 	// ErrWrongNumberOfRegisters will be returned if the meter by any chance
 	// returns a wrong number of registers when replying to a GetRegister
 	// command.
 	ErrWrongNumberOfRegisters = errors.New("Wrong number of registers in reply")
+
+	// ErrUnexpectedReply will be returned if the meter answers a command
+	// with a reply to another command.
+	ErrUnexpectedReply = errors.New("unexpected reply")
 )
 
 // NewKamstrup will initilize a new Kamstrup. device should point to a serial
@@ -36,11 +57,17 @@ func NewKamstrup(device string) (*Kamstrup, error) {
 		return nil, err
 	}
 
-	k := &Kamstrup{
-		port: port,
-	}
+	return NewKamstrupPort(port), nil
+}
 
-	return k, nil
+// NewKamstrupPort will initialize a new Kamstrup with a user provided
+// io.ReadWriteCloser. A read returning io.EOF is taken as the end of a reply,
+// like a serial port with a read timeout.
+func NewKamstrupPort(port io.ReadWriteCloser) *Kamstrup {
+	return &Kamstrup{
+		port:    port,
+		Address: DefaultAddress,
+	}
 }
 
 // Close will close the connection to the meter.
@@ -48,6 +75,13 @@ func (k *Kamstrup) Close() error {
 	return k.port.Close()
 }
 
+// SetTracer will trace all traffic to t, and every exchange annotated with
+// the frame decoded.
+func (k *Kamstrup) SetTracer(t trace.Tracer) {
+	k.port = trace.NewPort(k.port, t)
+	k.tracer = t
+}
+
 func (k *Kamstrup) readReply() ([]byte, error) {
 	var out []byte
 
@@ -85,7 +119,7 @@ readloop:
 func (k *Kamstrup) GetRegisters(registers ...uint16) (map[uint16]Value, error) {
 	f := Frame{
 		Type:      ToMeter,
-		Address:   0x3f,
+		Address:   k.Address,
 		CommandID: GetRegister,
 	}
 
@@ -102,29 +136,35 @@ func (k *Kamstrup) GetRegisters(registers ...uint16) (map[uint16]Value, error) {
 		return nil, err
 	}
 
+	values, _ := decodeRegisters(reply.Data, len(registers))
+
+	return values, nil
+}
+
+// decodeRegisters will decode up to count registers, each a register number
+// followed by a value, and return the values and the number of bytes read.
+func decodeRegisters(data []byte, count int) (map[uint16]Value, int) {
 	values := make(map[uint16]Value)
 	pos := 0
-	for r := 0; r < len(registers); r++ {
-		if pos >= len(reply.Data) {
+	for r := 0; r < count; r++ {
+		if pos+2 >= len(data) {
 			break
 		}
 
-		reg := uint16(reply.Data[pos])<<8 + uint16(reply.Data[pos+1])
+		reg := uint16(data[pos])<<8 + uint16(data[pos+1])
 		pos += 2
 
-		if pos >= len(reply.Data) {
+		read, value, err := NewValue(data[pos:])
+		if err != nil {
+			pos = len(data)
 			break
 		}
 
-		read, value, err := NewValue(reply.Data[pos:])
-		if err == nil {
-			values[reg] = value
-		}
-
+		values[reg] = value
 		pos += read
 	}
 
-	return values, nil
+	return values, pos
 }
 
 // GetRegister will read one value from the supplied register.
@@ -142,16 +182,38 @@ func (k *Kamstrup) GetRegister(register uint16) (Value, error) {
 }
 
 // SendAndReceive will send a frame and try to receive and decode a reply.
-func (k *Kamstrup) SendAndReceive(frame Frame) (Frame, error) {
-	var reply Frame
+func (k *Kamstrup) SendAndReceive(frame Frame) (reply Frame, err error) {
+	var raw []byte
 
 	payload := frame.Encode()
-	_, err := k.port.Write(payload)
+
+	if k.tracer != nil {
+		start := time.Now()
+
+		defer func() {
+			e := trace.Exchange{
+				Protocol: "kmp",
+				Start:    start,
+				End:      time.Now(),
+				Request:  payload,
+				Reply:    raw,
+				Err:      err,
+			}
+
+			if err == nil {
+				e.Result = fmt.Sprintf("address 0x%02x, command 0x%02x, data % x", reply.Address, reply.CommandID, reply.Data)
+			}
+
+			k.tracer.Exchange(e)
+		}()
+	}
+
+	_, err = k.port.Write(payload)
 	if err != nil {
 		return reply, err
 	}
 
-	raw, err := k.readReply()
+	raw, err = k.readReply()
 	if err != nil {
 		return reply, err
 	}
@@ -168,7 +230,7 @@ func (k *Kamstrup) SendAndReceive(frame Frame) (Frame, error) {
 func (k *Kamstrup) GetSerialNo() (int, error) {
 	f := Frame{
 		Type:      ToMeter,
-		Address:   0x3f,
+		Address:   k.Address,
 		CommandID: GetSerialNo,
 	}
 
@@ -195,7 +257,7 @@ func (k *Kamstrup) GetSerialNo() (int, error) {
 func (k *Kamstrup) GetType() ([]byte, error) {
 	f := Frame{
 		Type:      ToMeter,
-		Address:   0x3f,
+		Address:   k.Address,
 		CommandID: GetType,
 	}
 
@@ -211,7 +273,7 @@ func (k *Kamstrup) GetType() ([]byte, error) {
 func (k *Kamstrup) GetEventStatus() (byte, byte, byte, byte, error) {
 	f := Frame{
 		Type:      ToMeter,
-		Address:   0x3f,
+		Address:   k.Address,
 		CommandID: GetType,
 	}
 
@@ -226,3 +288,152 @@ func (k *Kamstrup) GetEventStatus() (byte, byte, byte, byte, error) {
 
 	return reply.Data[0], reply.Data[1], reply.Data[2], reply.Data[3], nil
 }
+
+// PutRegister will write v to register. Most meters only allow writing
+// registers in their setup mode. Values in a unit not used by KMP, like Hz,
+// are rejected with ErrUnitNotKMP.
+func (k *Kamstrup) PutRegister(register uint16, v Value) error {
+	if !v.KMP().Unit.KMP() {
+		return ErrUnitNotKMP
+	}
+
+	f := Frame{
+		Type:      ToMeter,
+		Address:   k.Address,
+		CommandID: PutRegister,
+		Data:      append([]byte{byte(register >> 8), byte(register & 0xff)}, v.Encode()...),
+	}
+
+	return k.command(f)
+}
+
+// SetClock will set the meter clock to t in the time zone of t. The date and
+// time are sent as two values, YYMMDD and hhmmss.
+func (k *Kamstrup) SetClock(t time.Time) error {
+	date := Value{
+		Value: float64((t.Year()%100)*10000 + int(t.Month())*100 + t.Day()),
+		Unit:  UnitDate,
+	}
+
+	clock := Value{
+		Value: float64(t.Hour()*10000 + t.Minute()*100 + t.Second()),
+		Unit:  UnitClock,
+	}
+
+	f := Frame{
+		Type:      ToMeter,
+		Address:   k.Address,
+		CommandID: SetClock,
+		Data:      append(date.Encode(), clock.Encode()...),
+	}
+
+	return k.command(f)
+}
+
+// GetLog will read the log records from the time from towards now. The
+// request holds from as the bytes YY MM DD hh mm ss followed by the registers
+// like a GetRegister command. The reply holds the number of records, and each
+// record holds its time like from followed by the registers like a
+// GetRegister reply. Records are returned in the order sent by the meter,
+// which may not be all records logged if they do not fit a single reply.
+func (k *Kamstrup) GetLog(from time.Time, registers ...uint16) ([]LogRecord, error) {
+	f := Frame{
+		Type:      ToMeter,
+		Address:   k.Address,
+		CommandID: GetLogTimePresent,
+		Data:      append(encodeLogTime(from), byte(len(registers))),
+	}
+
+	for _, register := range registers {
+		f.Data = append(f.Data, byte(register>>8), byte(register&0xff))
+	}
+
+	reply, err := k.SendAndReceive(f)
+	if err != nil {
+		return nil, err
+	}
+
+	if reply.CommandID != GetLogTimePresent {
+		return nil, ErrUnexpectedReply
+	}
+
+	if len(reply.Data) < 1 {
+		return nil, ErrFrameTooShort
+	}
+
+	count := int(reply.Data[0])
+	data := reply.Data[1:]
+
+	records := make([]LogRecord, 0, count)
+	for r := 0; r < count; r++ {
+		if len(data) < 6 {
+			return records, ErrFrameTooShort
+		}
+
+		values, read := decodeRegisters(data[6:], len(registers))
+
+		records = append(records, LogRecord{
+			Time:   decodeLogTime(data[:6], from.Location()),
+			Values: values,
+		})
+
+		data = data[6+read:]
+	}
+
+	return records, nil
+}
+
+// Scan will send GetSerialNo to the addresses from first to last and return
+// the addresses answering. Garbled replies are taken as more than one meter
+// answering, and the address is included.
+func (k *Kamstrup) Scan(first, last byte) ([]byte, error) {
+	defer func(address byte) { k.Address = address }(k.Address)
+
+	var found []byte
+
+	for address := int(first); address <= int(last); address++ {
+		k.Address = byte(address)
+
+		_, err := k.GetSerialNo()
+		switch err {
+		case nil, ErrInvalidChecksum, ErrInvalidFrame, ErrFrameTooShort:
+			found = append(found, byte(address))
+		case ErrFrameEmpty:
+			// No reply before the timeout.
+		default:
+			return found, err
+		}
+	}
+
+	return found, nil
+}
+
+// command will send a frame and check that the meter acknowledged it, or
+// replied to the same command.
+func (k *Kamstrup) command(frame Frame) error {
+	reply, err := k.SendAndReceive(frame)
+	if err != nil {
+		return err
+	}
+
+	if reply.Type != MeterAck && reply.CommandID != frame.CommandID {
+		return ErrUnexpectedReply
+	}
+
+	return nil
+}
+
+func encodeLogTime(t time.Time) []byte {
+	return []byte{
+		byte(t.Year() % 100),
+		byte(t.Month()),
+		byte(t.Day()),
+		byte(t.Hour()),
+		byte(t.Minute()),
+		byte(t.Second()),
+	}
+}
+
+func decodeLogTime(raw []byte, loc *time.Location) time.Time {
+	return time.Date(2000+int(raw[0]), time.Month(raw[1]), int(raw[2]), int(raw[3]), int(raw[4]), int(raw[5]), 0, loc)
+}
@@ -9,7 +9,86 @@ type (
 	Unit byte
 )
 
+// Known units.
+const (
+	UnitNone              = Unit(0x00)
+	UnitWh                = Unit(0x01)
+	UnitKWh               = Unit(0x02)
+	UnitMWh               = Unit(0x03)
+	UnitGWh               = Unit(0x04)
+	UnitJ                 = Unit(0x05)
+	UnitKJ                = Unit(0x06)
+	UnitMJ                = Unit(0x07)
+	UnitGJ                = Unit(0x08)
+	UnitCal               = Unit(0x09)
+	UnitKCal              = Unit(0x0a)
+	UnitMCal              = Unit(0x0b)
+	UnitGCal              = Unit(0x0c)
+	UnitVarh              = Unit(0x0d)
+	UnitKVarh             = Unit(0x0e)
+	UnitMVarh             = Unit(0x0f)
+	UnitGVarh             = Unit(0x10)
+	UnitVAh               = Unit(0x11)
+	UnitKVAh              = Unit(0x12)
+	UnitMVAh              = Unit(0x13)
+	UnitGVAh              = Unit(0x14)
+	UnitKWAlt             = Unit(0x15) // KMP has two codes for kW.
+	UnitKW                = Unit(0x16)
+	UnitMW                = Unit(0x17)
+	UnitGW                = Unit(0x18)
+	UnitKVarAlt           = Unit(0x19) // KMP has two codes for kvar.
+	UnitKVar              = Unit(0x1a)
+	UnitMVar              = Unit(0x1b)
+	UnitGVar              = Unit(0x1c)
+	UnitVA                = Unit(0x1d)
+	UnitKVA               = Unit(0x1e)
+	UnitMVA               = Unit(0x1f)
+	UnitGVA               = Unit(0x20)
+	UnitV                 = Unit(0x21)
+	UnitA                 = Unit(0x22)
+	UnitKV                = Unit(0x23)
+	UnitKA                = Unit(0x24)
+	UnitCelsius           = Unit(0x25)
+	UnitKelvin            = Unit(0x26)
+	UnitLitre             = Unit(0x27)
+	UnitCubicMetre        = Unit(0x28)
+	UnitLitrePerHour      = Unit(0x29)
+	UnitCubicMetrePerHour = Unit(0x2a)
+	UnitCubicMetreCelsius = Unit(0x2b)
+	UnitTon               = Unit(0x2c)
+	UnitTonPerHour        = Unit(0x2d)
+	UnitHour              = Unit(0x2e)
+	UnitClock             = Unit(0x2f) // hh:mm:ss
+	UnitDate              = Unit(0x30) // yy:mm:dd
+	UnitLongDate          = Unit(0x31) // yyyy:mm:dd
+	UnitMonthDay          = Unit(0x32) // mm:dd
+	UnitSpace             = Unit(0x33)
+	UnitBar               = Unit(0x34)
+	UnitRTC               = Unit(0x35)
+	UnitASCII             = Unit(0x36)
+	UnitCubicMetre10      = Unit(0x37)
+	UnitTon10             = Unit(0x38)
+	UnitGJ10              = Unit(0x39)
+	UnitMinute            = Unit(0x3a)
+	UnitBitfield          = Unit(0x3b)
+	UnitSecond            = Unit(0x3c)
+	UnitMillisecond       = Unit(0x3d)
+	UnitDay               = Unit(0x3e)
+	UnitRTCQ              = Unit(0x3f)
+	UnitDatetime          = Unit(0x40)
+
+	// Units not used by KMP. They are used for values read using other
+	// protocols, and are never sent to or received from Kamstrup meters.
+	UnitHz  = Unit(0x80)
+	UnitW   = Unit(0x81)
+	UnitVar = Unit(0x82)
+
+	lastUnit = Unit(0x83)
+)
+
 var (
+	// unitString holds the display string of every unit. KMP uses both 0x15
+	// and 0x16 for kW, and both 0x19 and 0x1a for kvar.
 	unitString = map[Unit]string{
 		0x00: "",
 		0x01: "Wh",
@@ -76,6 +155,9 @@ var (
 		0x3e: "days",
 		0x3f: "RTC-Q",
 		0x40: "Datetime",
+		0x80: "Hz",
+		0x81: "W",
+		0x82: "var",
 	}
 )
 
@@ -91,13 +173,20 @@ func (u Unit) String() string {
 }
 
 // UnitFromString will try to find a "Kamstrup-unit" based on a string
-// representation. If the unit is not found, the empty unit will be returned.
+// representation. If more than one unit has the string, the lowest code is
+// returned. If the unit is not found, the empty unit will be returned.
 func UnitFromString(in string) Unit {
-	for unit, unitStr := range unitString {
-		if unitStr == in {
+	for unit := UnitNone; unit < lastUnit; unit++ {
+		if str, found := unitString[unit]; found && str == in {
 			return unit
 		}
 	}
 
 	return Unit(0)
 }
+
+// KMP will return true if the unit is used by KMP, and can be sent to
+// Kamstrup meters.
+func (u Unit) KMP() bool {
+	return u <= UnitDatetime
+}
@@ -0,0 +1,61 @@
+package kamstrup
+
+import (
+	"testing"
+)
+
+func TestUnitString(t *testing.T) {
+	testSet := map[Unit]string{
+		UnitWh:      "Wh",
+		UnitKWAlt:   "kW",
+		UnitKW:      "kW",
+		UnitKVarAlt: "kvar",
+		UnitKVar:    "kvar",
+		UnitV:       "V",
+		UnitHz:      "Hz",
+		UnitW:       "W",
+		UnitVar:     "var",
+	}
+
+	for unit, expected := range testSet {
+		if unit.String() != expected {
+			t.Errorf("Unit 0x%02x is %q, expected %q", byte(unit), unit.String(), expected)
+		}
+	}
+}
+
+func TestUnitFromString(t *testing.T) {
+	for unit, str := range unitString {
+		found := UnitFromString(str)
+		if unitString[found] != str || found > unit {
+			t.Errorf("UnitFromString(%q) returned 0x%02x, expected 0x%02x or lower", str, byte(found), byte(unit))
+		}
+	}
+
+	if UnitFromString("kW") != UnitKWAlt || UnitFromString("kvar") != UnitKVarAlt {
+		t.Errorf("UnitFromString() did not return the lowest code")
+	}
+
+	if UnitFromString("furlong") != UnitNone {
+		t.Errorf("UnitFromString() of unknown unit did not return UnitNone")
+	}
+}
+
+func TestUnitKMP(t *testing.T) {
+	testSet := map[Value][]byte{
+		{Value: 1500, Unit: UnitW}:    {byte(UnitKW), 4, 0x41, 0, 0, 0, 15},
+		{Value: 250, Unit: UnitVar}:   {byte(UnitKVar), 4, 0x42, 0, 0, 0, 25},
+		{Value: 50, Unit: UnitHz}:     {byte(UnitNone), 4, 0, 0, 0, 0, 50},
+		{Value: 1.5, Unit: UnitKWAlt}: {byte(UnitKWAlt), 4, 0x41, 0, 0, 0, 15},
+	}
+
+	for v, expected := range testSet {
+		if encoded := v.Encode(); string(encoded) != string(expected) {
+			t.Errorf("Encode(%s) returned %x, expected %x", v, encoded, expected)
+		}
+	}
+
+	if UnitHz.KMP() || UnitW.KMP() || !UnitDatetime.KMP() {
+		t.Errorf("KMP() returned wrong result")
+	}
+}
@@ -18,6 +18,58 @@ var (
 	// ErrCouldNotDecodeValue will be returned from NewValue if the value
 	// cannot be decoded.
 	ErrCouldNotDecodeValue = errors.New("could not decode value")
+
+	// ErrUnitNotKMP will be returned if a value with a unit not used by KMP,
+	// like Hz, is sent to a meter.
+	ErrUnitNotKMP = errors.New("unit not used by KMP")
+
+	// kmpUnits maps units not used by KMP to the KMP unit they are sent as.
+	kmpUnits = map[Unit]Unit{
+		UnitW:   UnitKW,
+		UnitVar: UnitKVar,
+	}
+
+	// prefixes maps units to their unprefixed unit and the decimal exponent
+	// of the prefix.
+	prefixes = map[Unit]struct {
+		base     Unit
+		exponent int
+	}{
+		UnitWh:      {UnitWh, 0},
+		UnitKWh:     {UnitWh, 3},
+		UnitMWh:     {UnitWh, 6},
+		UnitGWh:     {UnitWh, 9},
+		UnitJ:       {UnitJ, 0},
+		UnitKJ:      {UnitJ, 3},
+		UnitMJ:      {UnitJ, 6},
+		UnitGJ:      {UnitJ, 9},
+		UnitVarh:    {UnitVarh, 0},
+		UnitKVarh:   {UnitVarh, 3},
+		UnitMVarh:   {UnitVarh, 6},
+		UnitGVarh:   {UnitVarh, 9},
+		UnitVAh:     {UnitVAh, 0},
+		UnitKVAh:    {UnitVAh, 3},
+		UnitMVAh:    {UnitVAh, 6},
+		UnitGVAh:    {UnitVAh, 9},
+		UnitW:       {UnitW, 0},
+		UnitKW:      {UnitW, 3},
+		UnitKWAlt:   {UnitW, 3},
+		UnitMW:      {UnitW, 6},
+		UnitGW:      {UnitW, 9},
+		UnitVar:     {UnitVar, 0},
+		UnitKVar:    {UnitVar, 3},
+		UnitKVarAlt: {UnitVar, 3},
+		UnitMVar:    {UnitVar, 6},
+		UnitGVar:    {UnitVar, 9},
+		UnitVA:      {UnitVA, 0},
+		UnitKVA:     {UnitVA, 3},
+		UnitMVA:     {UnitVA, 6},
+		UnitGVA:     {UnitVA, 9},
+		UnitV:       {UnitV, 0},
+		UnitKV:      {UnitV, 3},
+		UnitA:       {UnitA, 0},
+		UnitKA:      {UnitA, 3},
+	}
 )
 
 // NewValue will initialize a new value based on raw bytes.
@@ -61,3 +113,85 @@ func NewValue(raw []byte) (int, Value, error) {
 func (v Value) String() string {
 	return fmt.Sprintf("%.3f %s", v.Value, v.Unit.String())
 }
+
+// Convert will convert the value to a unit differing only by prefix, like
+// kWh to Wh. It will return false if the units are not compatible.
+func (v Value) Convert(to Unit) (Value, bool) {
+	if v.Unit == to {
+		return v, true
+	}
+
+	from, found1 := prefixes[v.Unit]
+	target, found2 := prefixes[to]
+	if !found1 || !found2 || from.base != target.base {
+		return v, false
+	}
+
+	return Value{
+		Value: v.Value * math.Pow10(from.exponent-target.exponent),
+		Unit:  to,
+	}, true
+}
+
+// Encode will encode the value for the wire as unit, mantissa length, SI
+// exponent and mantissa, the inverse of NewValue. The mantissa is always four
+// bytes, and the exponent is the smallest keeping up to nine decimals. Values
+// in W and var are sent as kW and kvar. Other units not used by KMP, like Hz,
+// are sent without a unit.
+func (v Value) Encode() []byte {
+	v = v.KMP()
+
+	abs := math.Abs(v.Value)
+
+	exponent := 0
+	scaled := abs
+
+	for exponent > -9 && math.Abs(scaled-math.Round(scaled)) > 1e-9 && scaled*10 <= math.MaxUint32 {
+		exponent--
+		scaled = abs * math.Pow10(-exponent)
+	}
+
+	for math.Round(scaled) > math.MaxUint32 && exponent < 0x3f {
+		exponent++
+		scaled = abs * math.Pow10(-exponent)
+	}
+
+	var siEx byte
+	if v.Value < 0 {
+		siEx |= 0x80
+	}
+
+	if exponent < 0 {
+		siEx |= 0x40
+		exponent = -exponent
+	}
+
+	siEx |= byte(exponent)
+
+	mantissa := uint32(math.Round(scaled))
+
+	unit := v.Unit
+	if !unit.KMP() {
+		unit = UnitNone
+	}
+
+	return []byte{
+		byte(unit),
+		4,
+		siEx,
+		byte(mantissa >> 24),
+		byte(mantissa >> 16),
+		byte(mantissa >> 8),
+		byte(mantissa),
+	}
+}
+
+// KMP will return the value in a unit used by KMP if possible. Values in W
+// and var are converted to kW and kvar, other values are returned as is.
+func (v Value) KMP() Value {
+	if to, found := kmpUnits[v.Unit]; found {
+		v, _ = v.Convert(to)
+	}
+
+	return v
+}
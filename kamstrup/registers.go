@@ -46,3 +46,55 @@ const (
 	TapWaterEnergy   = Energy6
 	HeatEnergyY      = Energy7
 )
+
+// Known instantaneous registers for Multical 601.
+const (
+	Volume1 = uint16(0x0044) // Volume register V1
+	Flow1   = uint16(0x004a) // Actual flow V1
+	Power1  = uint16(0x0050) // Actual power
+	T1      = uint16(0x0056) // Flow temperature
+	T2      = uint16(0x0057) // Return flow temperature
+	T1T2    = uint16(0x0059) // Temperature difference
+)
+
+// RegisterNames maps the names of the registers above to their address, for
+// selecting registers by name.
+var RegisterNames = map[string]uint16{
+	"EnergyIn":            EnergyIn,
+	"EnergyOut":           EnergyOut,
+	"EnergyInHiRes":       EnergyInHiRes,
+	"EnergyOutHiRes":      EnergyOutHiRes,
+	"VoltageP1":           VoltageP1,
+	"VoltageP2":           VoltageP2,
+	"VoltageP3":           VoltageP3,
+	"CurrentP1":           CurrentP1,
+	"CurrentP2":           CurrentP2,
+	"CurrentP3":           CurrentP3,
+	"InternalTemperature": InternalTemperature,
+	"PowerP1":             PowerP1,
+	"PowerP2":             PowerP2,
+	"PowerP3":             PowerP3,
+	"Date":                Date,
+	"Energy1":             Energy1,
+	"Energy2":             Energy2,
+	"Energy3":             Energy3,
+	"Energy4":             Energy4,
+	"Energy5":             Energy5,
+	"Energy6":             Energy6,
+	"Energy7":             Energy7,
+	"Energy8":             Energy8,
+	"Energy9":             Energy9,
+	"HeatEnergy":          HeatEnergy,
+	"ControlEnergy":       ControlEnergy,
+	"CoolingEnergy":       CoolingEnergy,
+	"FlowEnergy":          FlowEnergy,
+	"ReturnFlowEnergy":    ReturnFlowEnergy,
+	"TapWaterEnergy":      TapWaterEnergy,
+	"HeatEnergyY":         HeatEnergyY,
+	"Volume1":             Volume1,
+	"Flow1":               Flow1,
+	"Power1":              Power1,
+	"T1":                  T1,
+	"T2":                  T2,
+	"T1T2":                T1T2,
+}
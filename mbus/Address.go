@@ -0,0 +1,187 @@
+package mbus
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+type (
+	// SecondaryAddress identifies a slave independent of its primary address.
+	// Wildcards are 0xf nibbles in ID and all bits set in the other fields.
+	SecondaryAddress struct {
+		ID           uint32 // 8 BCD digits, 0x12345678 is "12345678"
+		Manufacturer uint16
+		Version      byte
+		Medium       Medium
+	}
+
+	// Medium is the device type of a slave.
+	Medium byte
+)
+
+// Some common media.
+const (
+	MediumOther             = Medium(0x00)
+	MediumOil               = Medium(0x01)
+	MediumElectricity       = Medium(0x02)
+	MediumGas               = Medium(0x03)
+	MediumHeatOutlet        = Medium(0x04)
+	MediumSteam             = Medium(0x05)
+	MediumWarmWater         = Medium(0x06)
+	MediumWater             = Medium(0x07)
+	MediumHeatCostAllocator = Medium(0x08)
+	MediumCoolingOutlet     = Medium(0x0a)
+	MediumCoolingInlet      = Medium(0x0b)
+	MediumHeatInlet         = Medium(0x0c)
+	MediumHeatCooling       = Medium(0x0d)
+	MediumHotWater          = Medium(0x15)
+	MediumColdWater         = Medium(0x16)
+	MediumWildcard          = Medium(0xff)
+)
+
+// ManufacturerWildcard matches any manufacturer in a search.
+const ManufacturerWildcard = uint16(0xffff)
+
+var (
+	// Wildcard matches every slave.
+	Wildcard = SecondaryAddress{
+		ID:           0xffffffff,
+		Manufacturer: ManufacturerWildcard,
+		Version:      0xff,
+		Medium:       MediumWildcard,
+	}
+
+	// ErrInvalidSecondaryAddress will be returned if a secondary address
+	// cannot be parsed.
+	ErrInvalidSecondaryAddress = errors.New("invalid secondary address")
+
+	media = map[Medium]string{
+		MediumOther:             "other",
+		MediumOil:               "oil",
+		MediumElectricity:       "electricity",
+		MediumGas:               "gas",
+		MediumHeatOutlet:        "heat (outlet)",
+		MediumSteam:             "steam",
+		MediumWarmWater:         "warm water",
+		MediumWater:             "water",
+		MediumHeatCostAllocator: "heat cost allocator",
+		MediumCoolingOutlet:     "cooling (outlet)",
+		MediumCoolingInlet:      "cooling (inlet)",
+		MediumHeatInlet:         "heat (inlet)",
+		MediumHeatCooling:       "heat/cooling",
+		MediumHotWater:          "hot water",
+		MediumColdWater:         "cold water",
+	}
+)
+
+// ParseSecondaryAddress will parse a secondary address of 8 digits, or 16
+// hex digits with manufacturer code, version and medium appended. Any digit
+// can be 'f' as a wildcard. If only 8 digits are given, the rest is wildcards.
+func ParseSecondaryAddress(s string) (SecondaryAddress, error) {
+	switch len(s) {
+	case 8:
+		s += "ffffffff"
+	case 16:
+	default:
+		return SecondaryAddress{}, ErrInvalidSecondaryAddress
+	}
+
+	id, err := strconv.ParseUint(s[0:8], 16, 32)
+	if err != nil {
+		return SecondaryAddress{}, ErrInvalidSecondaryAddress
+	}
+
+	rest, err := strconv.ParseUint(s[8:16], 16, 32)
+	if err != nil {
+		return SecondaryAddress{}, ErrInvalidSecondaryAddress
+	}
+
+	a := SecondaryAddress{
+		ID:           uint32(id),
+		Manufacturer: uint16(rest >> 16),
+		Version:      byte(rest >> 8),
+		Medium:       Medium(rest),
+	}
+
+	// The ID must be BCD digits or wildcards.
+	for i := 0; i < 8; i++ {
+		if d := a.digit(i); d > 9 && d != 0xf {
+			return SecondaryAddress{}, ErrInvalidSecondaryAddress
+		}
+	}
+
+	return a, nil
+}
+
+// DecodeSecondaryAddress will decode the eight bytes used in selection and
+// in the long header of responses.
+func DecodeSecondaryAddress(raw []byte) (SecondaryAddress, error) {
+	if len(raw) < 8 {
+		return SecondaryAddress{}, ErrInvalidSecondaryAddress
+	}
+
+	return SecondaryAddress{
+		ID:           binary.LittleEndian.Uint32(raw),
+		Manufacturer: binary.LittleEndian.Uint16(raw[4:]),
+		Version:      raw[6],
+		Medium:       Medium(raw[7]),
+	}, nil
+}
+
+// Encode will encode the address as used in selection, least significant
+// byte first.
+func (a SecondaryAddress) Encode() []byte {
+	raw := make([]byte, 8)
+
+	binary.LittleEndian.PutUint32(raw, a.ID)
+	binary.LittleEndian.PutUint16(raw[4:], a.Manufacturer)
+	raw[6] = a.Version
+	raw[7] = byte(a.Medium)
+
+	return raw
+}
+
+// Matches will return true if a matches the pattern b with wildcards.
+func (a SecondaryAddress) Matches(b SecondaryAddress) bool {
+	for i := 0; i < 8; i++ {
+		if b.digit(i) != 0xf && a.digit(i) != b.digit(i) {
+			return false
+		}
+	}
+
+	return (b.Manufacturer == ManufacturerWildcard || a.Manufacturer == b.Manufacturer) &&
+		(b.Version == 0xff || a.Version == b.Version) &&
+		(b.Medium == MediumWildcard || a.Medium == b.Medium)
+}
+
+// String will return the address as 16 hex digits, as accepted by
+// ParseSecondaryAddress.
+func (a SecondaryAddress) String() string {
+	return fmt.Sprintf("%08x%04x%02x%02x", a.ID, a.Manufacturer, a.Version, byte(a.Medium))
+}
+
+// digit will return digit i of the ID, counting from the most significant.
+func (a SecondaryAddress) digit(i int) byte {
+	return byte(a.ID>>uint(28-4*i)) & 0xf
+}
+
+// withDigit will return a copy of a with digit i of the ID replaced.
+func (a SecondaryAddress) withDigit(i int, d byte) SecondaryAddress {
+	shift := uint(28 - 4*i)
+
+	a.ID = a.ID&^(0xf<<shift) | uint32(d)<<shift
+
+	return a
+}
+
+// String will return a description of the medium.
+func (m Medium) String() string {
+	s, found := media[m]
+	if !found {
+		return fmt.Sprintf("medium 0x%02x", byte(m))
+	}
+
+	return s
+}
@@ -0,0 +1,341 @@
+package mbus
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+type (
+	// FrameType is the format of a M-Bus frame.
+	FrameType byte
+
+	// Frame is a M-Bus frame as defined by EN 13757-2. Control frames are
+	// long frames without data.
+	Frame struct {
+		Type    FrameType
+		Control byte
+		Address byte
+		CI      byte
+		Data    []byte
+	}
+
+	// Header is the fixed part of a variable data response. Address is only
+	// set with the long header.
+	Header struct {
+		Address      SecondaryAddress
+		AccessNumber byte
+		Status       byte
+		Signature    uint16
+	}
+
+	// Reader reads M-Bus frames from a stream.
+	Reader struct {
+		r *bufio.Reader
+	}
+)
+
+// Frame types.
+const (
+	FrameAck   = FrameType(0xe5) // Single character
+	FrameShort = FrameType(0x10)
+	FrameLong  = FrameType(0x68)
+)
+
+// Stop ends short and long frames.
+const Stop = byte(0x16)
+
+// Control field values from master to slave, without the frame count bit.
+const (
+	SndNke = byte(0x40) // SND_NKE, link reset
+	SndUd  = byte(0x53) // SND_UD, send user data
+	ReqUd2 = byte(0x5b) // REQ_UD2, request class 2 data
+	ReqUd1 = byte(0x5a) // REQ_UD1, request class 1 data
+)
+
+// Control field values from slave to master.
+const (
+	RspUd = byte(0x08) // RSP_UD, respond user data
+)
+
+const (
+	// fcb is the frame count bit in control fields from the master.
+	fcb = byte(0x20)
+
+	// acdDFC masks the access demand and data flow control bits in control
+	// fields from the slave.
+	acdDFC = byte(0x30)
+)
+
+// Special addresses.
+const (
+	AddressUnconfigured = byte(0)
+	AddressNetwork      = byte(253) // The slave selected by secondary address
+	AddressBroadcast    = byte(254) // All slaves reply
+	AddressBroadcastNR  = byte(255) // No slave replies
+)
+
+// Control information field values.
+const (
+	CIApplicationReset = byte(0x50)
+	CIDataSend         = byte(0x51)
+	CISelectSlave      = byte(0x52)
+	CIResponseLong     = byte(0x72) // Variable data response with long header
+	CIResponseNone     = byte(0x78) // Variable data response without header
+	CIResponseShort    = byte(0x7a) // Variable data response with short header
+)
+
+// maxData is the largest data field of a long frame.
+const maxData = 252
+
+var (
+	// ErrInvalidFrame will be returned if a frame cannot be recognized.
+	ErrInvalidFrame = errors.New("invalid M-Bus frame")
+
+	// ErrInvalidChecksum will be returned if the checksum did not validate.
+	ErrInvalidChecksum = errors.New("checksum did not validate")
+
+	// ErrFrameTooLong will be returned if the data does not fit in a long
+	// frame.
+	ErrFrameTooLong = errors.New("frame too long")
+
+	// ErrUnknownCI will be returned if a frame is not a variable data
+	// response.
+	ErrUnknownCI = errors.New("unknown control information field")
+)
+
+// Encode will encode the frame for the wire.
+func (f *Frame) Encode() ([]byte, error) {
+	switch f.Type {
+	case FrameAck:
+		return []byte{byte(FrameAck)}, nil
+
+	case FrameShort:
+		return []byte{byte(FrameShort), f.Control, f.Address, f.Control + f.Address, Stop}, nil
+
+	case FrameLong:
+		if len(f.Data) > maxData {
+			return nil, ErrFrameTooLong
+		}
+
+		l := byte(3 + len(f.Data))
+
+		raw := []byte{byte(FrameLong), l, l, byte(FrameLong), f.Control, f.Address, f.CI}
+		raw = append(raw, f.Data...)
+
+		return append(raw, f.checksum(), Stop), nil
+	}
+
+	return nil, ErrInvalidFrame
+}
+
+// DecodeFrame will decode a single frame.
+func DecodeFrame(raw []byte) (*Frame, error) {
+	if len(raw) == 0 {
+		return nil, ErrInvalidFrame
+	}
+
+	switch FrameType(raw[0]) {
+	case FrameAck:
+		if len(raw) != 1 {
+			return nil, ErrInvalidFrame
+		}
+
+		return &Frame{Type: FrameAck}, nil
+
+	case FrameShort:
+		if len(raw) != 5 || raw[4] != Stop {
+			return nil, ErrInvalidFrame
+		}
+
+		f := &Frame{Type: FrameShort, Control: raw[1], Address: raw[2]}
+		if f.checksum() != raw[3] {
+			return nil, ErrInvalidChecksum
+		}
+
+		return f, nil
+
+	case FrameLong:
+		if len(raw) < 9 || raw[1] != raw[2] || raw[3] != byte(FrameLong) || raw[1] < 3 {
+			return nil, ErrInvalidFrame
+		}
+
+		l := int(raw[1])
+		if len(raw) != l+6 || raw[len(raw)-1] != Stop {
+			return nil, ErrInvalidFrame
+		}
+
+		f := &Frame{
+			Type:    FrameLong,
+			Control: raw[4],
+			Address: raw[5],
+			CI:      raw[6],
+			Data:    append([]byte{}, raw[7:4+l]...),
+		}
+
+		if f.checksum() != raw[4+l] {
+			return nil, ErrInvalidChecksum
+		}
+
+		return f, nil
+	}
+
+	return nil, ErrInvalidFrame
+}
+
+// Function will return the control field without the frame count bit from
+// the master, or the access demand and data flow control bits from a slave.
+func (f *Frame) Function() byte {
+	if f.Control&0x40 != 0 {
+		return f.Control &^ fcb
+	}
+
+	return f.Control &^ acdDFC
+}
+
+// Header will decode the header of a variable data response and return it
+// with the data records following it.
+func (f *Frame) Header() (Header, []byte, error) {
+	var h Header
+
+	switch f.CI {
+	case CIResponseLong:
+		if len(f.Data) < 12 {
+			return h, nil, ErrInvalidFrame
+		}
+
+		h.Address, _ = DecodeSecondaryAddress(f.Data)
+
+		return h.decodeShort(f.Data[8:]), f.Data[12:], nil
+
+	case CIResponseShort:
+		if len(f.Data) < 4 {
+			return h, nil, ErrInvalidFrame
+		}
+
+		return h.decodeShort(f.Data), f.Data[4:], nil
+
+	case CIResponseNone:
+		return h, f.Data, nil
+	}
+
+	return h, nil, ErrUnknownCI
+}
+
+// decodeShort will decode access number, status and signature.
+func (h Header) decodeShort(raw []byte) Header {
+	h.AccessNumber = raw[0]
+	h.Status = raw[1]
+	h.Signature = binary.LittleEndian.Uint16(raw[2:])
+
+	return h
+}
+
+// String implements fmt.Stringer.
+func (f *Frame) String() string {
+	switch f.Type {
+	case FrameAck:
+		return "ACK"
+	case FrameShort:
+		return fmt.Sprintf("short C=%02x A=%d", f.Control, f.Address)
+	}
+
+	return fmt.Sprintf("long C=%02x A=%d CI=%02x %x", f.Control, f.Address, f.CI, f.Data)
+}
+
+// checksum is the arithmetic sum of all bytes from the control field to the
+// end of the data.
+func (f *Frame) checksum() byte {
+	sum := f.Control + f.Address
+	if f.Type == FrameLong {
+		sum += f.CI
+		for _, b := range f.Data {
+			sum += b
+		}
+	}
+
+	return sum
+}
+
+// NewReader will initialize a new frame reader.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{
+		r: bufio.NewReader(r),
+	}
+}
+
+// ReadRaw will read the bytes of the next frame without validating the
+// checksum. If the stream ends inside a frame, io.ErrUnexpectedEOF is
+// returned.
+func (r *Reader) ReadRaw() ([]byte, error) {
+	start, err := r.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	var remaining int
+
+	switch FrameType(start) {
+	case FrameAck:
+		return []byte{start}, nil
+	case FrameShort:
+		remaining = 4
+	case FrameLong:
+		header := make([]byte, 3)
+		_, err = io.ReadFull(r.r, header)
+		if err != nil {
+			return nil, unexpected(err)
+		}
+
+		if header[0] != header[1] || header[2] != byte(FrameLong) {
+			return nil, ErrInvalidFrame
+		}
+
+		raw := append([]byte{start}, header...)
+		rest := make([]byte, int(header[0])+2)
+		_, err = io.ReadFull(r.r, rest)
+		if err != nil {
+			return nil, unexpected(err)
+		}
+
+		return append(raw, rest...), nil
+	default:
+		return nil, ErrInvalidFrame
+	}
+
+	raw := make([]byte, 1+remaining)
+	raw[0] = start
+	_, err = io.ReadFull(r.r, raw[1:])
+	if err != nil {
+		return nil, unexpected(err)
+	}
+
+	return raw, nil
+}
+
+// ReadFrame will read and decode the next frame.
+func (r *Reader) ReadFrame() (*Frame, error) {
+	raw, err := r.ReadRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	return DecodeFrame(raw)
+}
+
+// Reset will discard any buffered input and continue reading from rd.
+func (r *Reader) Reset(rd io.Reader) {
+	r.r.Reset(rd)
+}
+
+// unexpected will turn io.EOF in the middle of a frame into
+// io.ErrUnexpectedEOF.
+func unexpected(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
@@ -0,0 +1,313 @@
+package mbus
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/tarm/serial"
+)
+
+type (
+	// Master is a M-Bus master talking to slaves on a wired bus through a
+	// level converter.
+	Master struct {
+		port io.ReadWriteCloser
+		r    *Reader
+
+		// fcb is the frame count bit to use for the next request to every
+		// primary address.
+		fcb map[byte]bool
+	}
+
+	// probeResult is the outcome of selecting a secondary address pattern.
+	probeResult int
+)
+
+const (
+	probeNone probeResult = iota
+	probeFound
+	probeCollision
+)
+
+// maxTelegrams limits a multi-telegram readout in case a slave never stops.
+const maxTelegrams = 64
+
+var (
+	// ErrNoReply will be returned if a slave did not answer in time.
+	ErrNoReply = errors.New("no reply from slave")
+
+	// ErrUnexpectedReply will be returned if a slave answers with a frame
+	// not valid for the request.
+	ErrUnexpectedReply = errors.New("unexpected reply from slave")
+
+	// ErrTooManyTelegrams will be returned if a multi-telegram readout does
+	// not end.
+	ErrTooManyTelegrams = errors.New("too many telegrams")
+)
+
+// NewMaster will initialize a new master with a user provided
+// io.ReadWriteCloser. A read returning io.EOF is taken as a timeout, like a
+// serial port with a read timeout.
+func NewMaster(port io.ReadWriteCloser) *Master {
+	return &Master{
+		port: port,
+		r:    NewReader(port),
+		fcb:  make(map[byte]bool),
+	}
+}
+
+// NewMasterSerial will initialize a new master on a serial device. Most
+// slaves use 2400 baud, 8E1, some older ones 300 baud.
+func NewMasterSerial(device string, baud int) (*Master, error) {
+	conf := &serial.Config{
+		Name:        device,
+		Baud:        baud,
+		Size:        8,
+		Parity:      serial.ParityEven,
+		ReadTimeout: time.Millisecond * 500,
+	}
+
+	port, err := serial.OpenPort(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMaster(port), nil
+}
+
+// Close will close the underlying port.
+func (m *Master) Close() error {
+	return m.port.Close()
+}
+
+// Reset will send SND_NKE to a slave and wait for the acknowledge. Sent to
+// AddressNetwork it will deselect the selected slave.
+func (m *Master) Reset(address byte) error {
+	reply, err := m.transact(&Frame{Type: FrameShort, Control: SndNke, Address: address})
+	if err != nil {
+		return err
+	}
+
+	if reply.Type != FrameAck {
+		return ErrUnexpectedReply
+	}
+
+	m.fcb[address] = true
+
+	return nil
+}
+
+// Select will select a slave by secondary address. The selected slave will
+// answer requests to AddressNetwork. If the address contains wildcards,
+// more than one slave can be selected.
+func (m *Master) Select(a SecondaryAddress) error {
+	reply, err := m.transact(&Frame{
+		Type:    FrameLong,
+		Control: SndUd,
+		Address: AddressNetwork,
+		CI:      CISelectSlave,
+		Data:    a.Encode(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if reply.Type != FrameAck {
+		return ErrUnexpectedReply
+	}
+
+	m.fcb[AddressNetwork] = true
+
+	return nil
+}
+
+// RequestData will send REQ_UD2 and return the response. The frame count
+// bit is toggled for every successful request, which makes slaves with more
+// than one telegram send the next one.
+func (m *Master) RequestData(address byte) (*Frame, error) {
+	control := ReqUd2
+	if m.fcb[address] {
+		control |= fcb
+	}
+
+	reply, err := m.transact(&Frame{Type: FrameShort, Control: control, Address: address})
+	if err != nil {
+		return nil, err
+	}
+
+	if reply.Type != FrameLong || reply.Function() != RspUd {
+		return nil, ErrUnexpectedReply
+	}
+
+	if address != AddressNetwork && address != AddressBroadcast && reply.Address != address {
+		return nil, ErrUnexpectedReply
+	}
+
+	m.fcb[address] = !m.fcb[address]
+
+	return reply, nil
+}
+
+// ReadAll will request data from a slave until the last telegram. Slaves
+// signal more telegrams with a 0x1f DIF as the last record.
+func (m *Master) ReadAll(address byte) ([]*Frame, error) {
+	var telegrams []*Frame
+
+	for len(telegrams) < maxTelegrams {
+		f, err := m.RequestData(address)
+		if err != nil {
+			return telegrams, err
+		}
+
+		telegrams = append(telegrams, f)
+
+		_, ud, err := f.Decode()
+		if err != nil || !ud.MoreRecords {
+			return telegrams, nil
+		}
+	}
+
+	return telegrams, ErrTooManyTelegrams
+}
+
+// ReadSecondary will select a slave by secondary address and read all its
+// telegrams.
+func (m *Master) ReadSecondary(a SecondaryAddress) ([]*Frame, error) {
+	err := m.Select(a)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.ReadAll(AddressNetwork)
+}
+
+// ScanPrimary will send SND_NKE to the primary addresses from first to last
+// and return the addresses answering.
+func (m *Master) ScanPrimary(first, last byte) ([]byte, error) {
+	var found []byte
+
+	for address := int(first); address <= int(last); address++ {
+		err := m.Reset(byte(address))
+		switch {
+		case err == nil:
+			found = append(found, byte(address))
+		case isFrameError(err):
+			// More than one slave with this address.
+			found = append(found, byte(address))
+		case err != ErrNoReply:
+			return found, err
+		}
+	}
+
+	return found, nil
+}
+
+// Search will find the secondary addresses of all slaves matching pattern.
+// The wildcard digits of the ID are replaced one by one, and only digits
+// where more than one slave answer are narrowed further. Use Wildcard to
+// find all slaves.
+func (m *Master) Search(pattern SecondaryAddress) ([]SecondaryAddress, error) {
+	var found []SecondaryAddress
+
+	err := m.search(pattern, 0, &found)
+
+	return found, err
+}
+
+func (m *Master) search(pattern SecondaryAddress, pos int, found *[]SecondaryAddress) error {
+	for pos < 8 && pattern.digit(pos) != 0xf {
+		pos++
+	}
+
+	if pos == 8 {
+		a, result, err := m.probe(pattern)
+		if result == probeFound {
+			*found = append(*found, a)
+		}
+
+		return err
+	}
+
+	for d := byte(0); d <= 9; d++ {
+		p := pattern.withDigit(pos, d)
+
+		a, result, err := m.probe(p)
+		if err != nil {
+			return err
+		}
+
+		switch result {
+		case probeFound:
+			*found = append(*found, a)
+		case probeCollision:
+			err = m.search(p, pos+1, found)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// probe will select pattern and request data from the selected slave, and
+// return the secondary address from the response.
+func (m *Master) probe(pattern SecondaryAddress) (SecondaryAddress, probeResult, error) {
+	err := m.Select(pattern)
+	switch {
+	case err == ErrNoReply:
+		return SecondaryAddress{}, probeNone, nil
+	case isFrameError(err) || err == ErrUnexpectedReply:
+		return SecondaryAddress{}, probeCollision, nil
+	case err != nil:
+		return SecondaryAddress{}, probeNone, err
+	}
+
+	f, err := m.RequestData(AddressNetwork)
+	switch {
+	case err == ErrNoReply:
+		return SecondaryAddress{}, probeNone, nil
+	case isFrameError(err) || err == ErrUnexpectedReply:
+		return SecondaryAddress{}, probeCollision, nil
+	case err != nil:
+		return SecondaryAddress{}, probeNone, err
+	}
+
+	h, _, err := f.Header()
+	if err != nil || f.CI != CIResponseLong {
+		// A slave answered, but without its address.
+		return SecondaryAddress{}, probeCollision, nil
+	}
+
+	return h.Address, probeFound, nil
+}
+
+// transact will send a frame and read the reply. Input left from earlier
+// replies is discarded first.
+func (m *Master) transact(f *Frame) (*Frame, error) {
+	raw, err := f.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	m.r.Reset(m.port)
+
+	_, err = m.port.Write(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := m.r.ReadFrame()
+	if err == io.EOF {
+		return nil, ErrNoReply
+	}
+
+	return reply, err
+}
+
+// isFrameError will return true if err was caused by a garbled reply, for
+// example from more than one slave answering.
+func isFrameError(err error) bool {
+	return err == ErrInvalidFrame || err == ErrInvalidChecksum || err == io.ErrUnexpectedEOF
+}
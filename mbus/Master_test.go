@@ -0,0 +1,332 @@
+package mbus
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// testSlave is an in-memory stand-in for a slave on the bus.
+type testSlave struct {
+	primary   byte
+	address   SecondaryAddress
+	telegrams []string // Hex encoded data records
+
+	selected bool
+	sent     bool
+	lastFCB  bool
+	index    int
+}
+
+// testBus connects a master to slaves. Replies sent at the same time are
+// combined like on the wire, where a space from any slave wins.
+type testBus struct {
+	slaves []*testSlave
+	out    bytes.Buffer
+}
+
+func (s *testSlave) addressed(address byte) bool {
+	return address == s.primary || (address == AddressNetwork && s.selected)
+}
+
+func (s *testSlave) handle(t *testing.T, f *Frame) []byte {
+	var reply *Frame
+
+	switch {
+	case f.Type == FrameShort && f.Function() == SndNke && s.addressed(f.Address):
+		if f.Address == AddressNetwork {
+			s.selected = false
+		}
+
+		s.sent = false
+		reply = &Frame{Type: FrameAck}
+
+	case f.Type == FrameLong && f.CI == CISelectSlave && f.Address == AddressNetwork:
+		pattern, _ := DecodeSecondaryAddress(f.Data)
+		s.selected = s.address.Matches(pattern)
+		if !s.selected {
+			return nil
+		}
+
+		s.sent = false
+		reply = &Frame{Type: FrameAck}
+
+	case f.Type == FrameShort && f.Function() == ReqUd2 && s.addressed(f.Address):
+		bit := f.Control&fcb != 0
+		if s.sent && bit != s.lastFCB {
+			s.index = (s.index + 1) % len(s.telegrams)
+		}
+		s.sent = true
+		s.lastFCB = bit
+
+		records, _ := hex.DecodeString(s.telegrams[s.index])
+
+		data := append(s.address.Encode(), byte(s.index), 0x00, 0x00, 0x00)
+		reply = &Frame{
+			Type:    FrameLong,
+			Control: RspUd,
+			Address: s.primary,
+			CI:      CIResponseLong,
+			Data:    append(data, records...),
+		}
+
+	default:
+		return nil
+	}
+
+	raw, err := reply.Encode()
+	if err != nil {
+		t.Fatalf("Encode() returned %s", err)
+	}
+
+	return raw
+}
+
+func (b *testBus) master(t *testing.T) *Master {
+	return NewMaster(&testPort{t: t, bus: b})
+}
+
+type testPort struct {
+	t   *testing.T
+	bus *testBus
+}
+
+func (p *testPort) Write(raw []byte) (int, error) {
+	f, err := DecodeFrame(raw)
+	if err != nil {
+		p.t.Fatalf("Master sent invalid frame %x: %s", raw, err)
+	}
+
+	var combined []byte
+	for _, s := range p.bus.slaves {
+		reply := s.handle(p.t, f)
+
+		for i, b := range reply {
+			if i < len(combined) {
+				combined[i] &= b
+			} else {
+				combined = append(combined, b)
+			}
+		}
+	}
+
+	p.bus.out.Write(combined)
+
+	return len(raw), nil
+}
+
+func (p *testPort) Read(buf []byte) (int, error) {
+	return p.bus.out.Read(buf)
+}
+
+func (p *testPort) Close() error {
+	return nil
+}
+
+func address(t *testing.T, s string) SecondaryAddress {
+	a, err := ParseSecondaryAddress(s)
+	if err != nil {
+		t.Fatalf("ParseSecondaryAddress(%s) returned %s", s, err)
+	}
+
+	return a
+}
+
+func TestFrame(t *testing.T) {
+	cases := []struct {
+		frame *Frame
+		raw   string
+	}{
+		{&Frame{Type: FrameAck}, "e5"},
+		{&Frame{Type: FrameShort, Control: SndNke, Address: 5}, "1040054516"},
+		{&Frame{Type: FrameShort, Control: ReqUd2 | fcb, Address: 1}, "107b017c16"},
+		{&Frame{Type: FrameLong, Control: SndUd, Address: AddressNetwork, CI: CISelectSlave, Data: []byte{0x78, 0x56, 0x34, 0x12, 0xff, 0xff, 0xff, 0xff}},
+			"680b0b6853fd5278563412ffffffffb216"},
+	}
+
+	for _, c := range cases {
+		raw, err := c.frame.Encode()
+		if err != nil {
+			t.Fatalf("Encode() returned %s", err)
+		}
+
+		if hex.EncodeToString(raw) != c.raw {
+			t.Errorf("Encode() returned %x, expected %s", raw, c.raw)
+		}
+
+		f, err := DecodeFrame(raw)
+		if err != nil {
+			t.Fatalf("DecodeFrame(%x) returned %s", raw, err)
+		}
+
+		if !reflect.DeepEqual(f, c.frame) && !(len(f.Data) == 0 && len(c.frame.Data) == 0) {
+			t.Errorf("DecodeFrame(%x) returned %s", raw, f)
+		}
+	}
+
+	_, err := DecodeFrame([]byte{0x10, 0x40, 0x05, 0x46, 0x16})
+	if err != ErrInvalidChecksum {
+		t.Errorf("DecodeFrame() with wrong checksum returned %v", err)
+	}
+
+	_, err = (&Frame{Type: FrameLong, Data: make([]byte, 253)}).Encode()
+	if err != ErrFrameTooLong {
+		t.Errorf("Encode() of too much data returned %v", err)
+	}
+
+	stream, _ := hex.DecodeString("e51040054516680b0b6853fd5278563412ffffffffb21610")
+	r := NewReader(bytes.NewReader(stream))
+
+	for _, expected := range []FrameType{FrameAck, FrameShort, FrameLong} {
+		f, err := r.ReadFrame()
+		if err != nil || f.Type != expected {
+			t.Fatalf("ReadFrame() returned %v, %v", f, err)
+		}
+	}
+
+	_, err = r.ReadFrame()
+	if err != io.ErrUnexpectedEOF {
+		t.Errorf("ReadFrame() of truncated frame returned %v", err)
+	}
+}
+
+func TestSecondaryAddress(t *testing.T) {
+	a := address(t, "123456782c2d1b04")
+	if a.ID != 0x12345678 || a.Manufacturer != 0x2c2d || a.Version != 0x1b || a.Medium != MediumHeatOutlet {
+		t.Errorf("ParseSecondaryAddress() returned %+v", a)
+	}
+
+	if a.String() != "123456782c2d1b04" {
+		t.Errorf("String() returned %s", a)
+	}
+
+	decoded, err := DecodeSecondaryAddress(a.Encode())
+	if err != nil || decoded != a {
+		t.Errorf("DecodeSecondaryAddress() returned %s, %v", decoded, err)
+	}
+
+	if !a.Matches(address(t, "1234ffff")) || !a.Matches(Wildcard) || a.Matches(address(t, "1235ffff")) {
+		t.Errorf("Matches() failed for %s", a)
+	}
+
+	if a.Matches(address(t, "12345678ffffff07")) {
+		t.Errorf("Matches() ignored the medium")
+	}
+
+	_, err = ParseSecondaryAddress("1234567a")
+	if err != ErrInvalidSecondaryAddress {
+		t.Errorf("ParseSecondaryAddress() of non-BCD ID returned %v", err)
+	}
+}
+
+func newTestBus(t *testing.T) *testBus {
+	return &testBus{
+		slaves: []*testSlave{
+			{
+				primary: 1,
+				address: address(t, "123456782c2d1b04"),
+				// Energy, then more records follow; volume, then the end.
+				telegrams: []string{"04061027000001fd17001f", "04132c0100000f"},
+			},
+			{
+				primary:   5,
+				address:   address(t, "123499992c2d1b07"),
+				telegrams: []string{"0413e8030000"},
+			},
+			{
+				primary:   7,
+				address:   address(t, "876543212c2d1b04"),
+				telegrams: []string{"04060100000002fd1700002f2f"},
+			},
+		},
+	}
+}
+
+func TestMasterPrimary(t *testing.T) {
+	m := newTestBus(t).master(t)
+
+	err := m.Reset(1)
+	if err != nil {
+		t.Fatalf("Reset() returned %s", err)
+	}
+
+	err = m.Reset(2)
+	if err != ErrNoReply {
+		t.Errorf("Reset() of missing slave returned %v", err)
+	}
+
+	telegrams, err := m.ReadAll(1)
+	if err != nil {
+		t.Fatalf("ReadAll() returned %s", err)
+	}
+
+	if len(telegrams) != 2 {
+		t.Fatalf("Got %d telegrams, expected 2", len(telegrams))
+	}
+
+	for i, f := range telegrams {
+		h, records, err := f.Header()
+		if err != nil {
+			t.Fatalf("Header() returned %s", err)
+		}
+
+		if h.AccessNumber != byte(i) || h.Address.String() != "123456782c2d1b04" {
+			t.Errorf("Telegram %d has header %+v", i, h)
+		}
+
+		if i == 1 && hex.EncodeToString(records) != "04132c0100000f" {
+			t.Errorf("Telegram %d has records %x", i, records)
+		}
+	}
+
+	// The slave without more records.
+	telegrams, err = m.ReadAll(7)
+	if err != nil || len(telegrams) != 1 {
+		t.Errorf("ReadAll() returned %d telegrams, %v", len(telegrams), err)
+	}
+
+	found, err := m.ScanPrimary(0, 10)
+	if err != nil {
+		t.Fatalf("ScanPrimary() returned %s", err)
+	}
+
+	if !bytes.Equal(found, []byte{1, 5, 7}) {
+		t.Errorf("ScanPrimary() returned %v", found)
+	}
+}
+
+func TestMasterSecondary(t *testing.T) {
+	m := newTestBus(t).master(t)
+
+	telegrams, err := m.ReadSecondary(address(t, "87654321"))
+	if err != nil || len(telegrams) != 1 || telegrams[0].Address != 7 {
+		t.Fatalf("ReadSecondary() returned %v, %v", telegrams, err)
+	}
+
+	err = m.Select(address(t, "11111111"))
+	if err != ErrNoReply {
+		t.Errorf("Select() of missing slave returned %v", err)
+	}
+
+	found, err := m.Search(Wildcard)
+	if err != nil {
+		t.Fatalf("Search() returned %s", err)
+	}
+
+	var addresses []string
+	for _, a := range found {
+		addresses = append(addresses, a.String())
+	}
+
+	expected := []string{"123456782c2d1b04", "123499992c2d1b07", "876543212c2d1b04"}
+	if !reflect.DeepEqual(addresses, expected) {
+		t.Errorf("Search() found %v, expected %v", addresses, expected)
+	}
+
+	found, err = m.Search(address(t, "ffffffffffffff07"))
+	if err != nil || len(found) != 1 || found[0].ID != 0x12349999 {
+		t.Errorf("Search() for water meters returned %v, %v", found, err)
+	}
+}
@@ -0,0 +1,729 @@
+package mbus
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/abrander/gometer/iec62056"
+	"github.com/abrander/gometer/kamstrup"
+)
+
+type (
+	// Function is the function field of a DIF.
+	Function byte
+
+	// Record is a decoded data record.
+	Record struct {
+		Function      Function
+		StorageNumber uint64
+		Tariff        uint32
+		Subunit       uint16
+
+		// VIF and VIFE are as found in the record, extension bits included.
+		VIF  byte
+		VIFE []byte
+
+		// Data is the raw data field.
+		Data []byte
+
+		Description string
+		Unit        kamstrup.Unit
+
+		// Value is set for numeric records, scaled to Unit.
+		Value float64
+
+		// Time is set for date and date-time records.
+		Time time.Time
+
+		// Text is set for variable length ASCII records.
+		Text string
+	}
+
+	// UserData is the data records of a variable data response.
+	UserData struct {
+		Records []Record
+
+		// ManufacturerData is the data following a 0x0f or 0x1f DIF.
+		ManufacturerData []byte
+
+		// MoreRecords is set if the slave has more records in the next
+		// telegram.
+		MoreRecords bool
+	}
+
+	// quantity describes a value information field.
+	quantity struct {
+		description string
+		unit        kamstrup.Unit
+		scale       float64
+		kind        kind
+	}
+
+	// kind is the interpretation of the data field.
+	kind int
+)
+
+// Functions.
+const (
+	FunctionInstantaneous = Function(0)
+	FunctionMaximum       = Function(1)
+	FunctionMinimum       = Function(2)
+	FunctionError         = Function(3)
+)
+
+const (
+	kindNumeric kind = iota
+	kindDate
+	kindDateTime
+)
+
+// Special DIF values.
+const (
+	difManufacturer  = byte(0x0f)
+	difMoreRecords   = byte(0x1f)
+	difIdle          = byte(0x2f)
+	difGlobalReadout = byte(0x7f)
+)
+
+// VIF values with special meaning, without the extension bit.
+const (
+	vifExtensionFB   = byte(0x7b)
+	vifPlainText     = byte(0x7c)
+	vifExtensionFD   = byte(0x7d)
+	vifAny           = byte(0x7e)
+	vifManufacturer  = byte(0x7f)
+	vifeManufacturer = byte(0x7f)
+)
+
+// maxDIFE is the maximum number of DIFE bytes allowed by EN 13757-3.
+const maxDIFE = 10
+
+// Location is the time zone of dates and times in records. M-Bus dates
+// carry no zone.
+var Location = time.Local
+
+var (
+	// ErrMalformedRecord will be returned if a data record is truncated or
+	// uses reserved codes.
+	ErrMalformedRecord = errors.New("malformed data record")
+
+	// dataLengths is the length of the data field for every data field
+	// code of the DIF. Variable length and special functions are -1.
+	dataLengths = [16]int{0, 1, 2, 3, 4, 4, 6, 8, 0, 1, 2, 3, 4, -1, 6, -1}
+
+	kamstrupManufacturer, _ = iec62056.Manufacturer("KAM").ID()
+
+	// kamstrupVIFs are the manufacturer specific VIFE following 0xff used by
+	// Kamstrup.
+	kamstrupVIFs = map[byte]quantity{
+		0x07: {"forward energy (m³·T1)", kamstrup.UnitCubicMetreCelsius, 1, kindNumeric},
+		0x08: {"return energy (m³·T2)", kamstrup.UnitCubicMetreCelsius, 1, kindNumeric},
+		0x20: {"info codes", kamstrup.UnitBitfield, 1, kindNumeric},
+		0x22: {"info codes", kamstrup.UnitBitfield, 1, kindNumeric},
+	}
+
+	// vifeDescriptions are appended to the description of combinable VIFE.
+	vifeDescriptions = map[byte]string{
+		0x20: "per second",
+		0x21: "per minute",
+		0x22: "per hour",
+		0x23: "per day",
+		0x24: "per week",
+		0x25: "per month",
+		0x26: "per year",
+		0x3b: "forward flow",
+		0x3c: "backward flow",
+	}
+
+	energyWh = [4]kamstrup.Unit{kamstrup.UnitWh, kamstrup.UnitKWh, kamstrup.UnitMWh, kamstrup.UnitGWh}
+	energyJ  = [4]kamstrup.Unit{kamstrup.UnitJ, kamstrup.UnitKJ, kamstrup.UnitMJ, kamstrup.UnitGJ}
+	powerW   = [4]kamstrup.Unit{kamstrup.UnitW, kamstrup.UnitKW, kamstrup.UnitMW, kamstrup.UnitGW}
+
+	durations = [4]kamstrup.Unit{kamstrup.UnitSecond, kamstrup.UnitMinute, kamstrup.UnitHour, kamstrup.UnitDay}
+)
+
+// Decode will decode the header and the data records of a variable data
+// response.
+func (f *Frame) Decode() (Header, *UserData, error) {
+	h, records, err := f.Header()
+	if err != nil {
+		return h, nil, err
+	}
+
+	ud, err := DecodeUserData(records, h.Address.Manufacturer)
+
+	return h, ud, err
+}
+
+// DecodeUserData will decode data records. manufacturer is used to decode
+// manufacturer specific VIFs, and can be zero if unknown.
+func DecodeUserData(raw []byte, manufacturer uint16) (*UserData, error) {
+	ud := &UserData{}
+
+	pos := 0
+	for pos < len(raw) {
+		dif := raw[pos]
+
+		switch dif {
+		case difIdle:
+			pos++
+			continue
+		case difManufacturer, difMoreRecords:
+			ud.ManufacturerData = raw[pos+1:]
+			ud.MoreRecords = dif == difMoreRecords
+			return ud, nil
+		case difGlobalReadout:
+			return ud, ErrMalformedRecord
+		}
+
+		r, n, err := decodeRecord(raw[pos:], manufacturer)
+		if err != nil {
+			return ud, err
+		}
+
+		ud.Records = append(ud.Records, r)
+		pos += n
+	}
+
+	return ud, nil
+}
+
+// decodeRecord will decode a single record and return the number of bytes
+// used.
+func decodeRecord(raw []byte, manufacturer uint16) (Record, int, error) {
+	var r Record
+
+	dif := raw[0]
+	pos := 1
+
+	r.Function = Function(dif >> 4 & 0x03)
+	r.StorageNumber = uint64(dif >> 6 & 0x01)
+
+	ext := dif&0x80 != 0
+	for i := 0; ext; i++ {
+		if i >= maxDIFE || pos >= len(raw) {
+			return r, 0, ErrMalformedRecord
+		}
+
+		dife := raw[pos]
+		pos++
+
+		r.StorageNumber |= uint64(dife&0x0f) << uint(1+4*i)
+		r.Tariff |= uint32(dife>>4&0x03) << uint(2*i)
+		r.Subunit |= uint16(dife>>6&0x01) << uint(i)
+
+		ext = dife&0x80 != 0
+	}
+
+	if pos >= len(raw) {
+		return r, 0, ErrMalformedRecord
+	}
+
+	r.VIF = raw[pos]
+	pos++
+
+	ext = r.VIF&0x80 != 0
+	for ext {
+		if pos >= len(raw) {
+			return r, 0, ErrMalformedRecord
+		}
+
+		r.VIFE = append(r.VIFE, raw[pos])
+		ext = raw[pos]&0x80 != 0
+		pos++
+	}
+
+	// The unit of a plain text VIF follows the VIFE, characters reversed.
+	var unitText string
+	if r.VIF&0x7f == vifPlainText {
+		if pos >= len(raw) || pos+1+int(raw[pos]) > len(raw) {
+			return r, 0, ErrMalformedRecord
+		}
+
+		unitText = reverse(raw[pos+1 : pos+1+int(raw[pos])])
+		pos += 1 + int(raw[pos])
+	}
+
+	var lvar byte
+	length := dataLengths[dif&0x0f]
+	if dif&0x0f == 0x0d {
+		if pos >= len(raw) {
+			return r, 0, ErrMalformedRecord
+		}
+
+		lvar = raw[pos]
+		length = lvarLength(lvar)
+		pos++
+	}
+
+	if length < 0 || pos+length > len(raw) {
+		return r, 0, ErrMalformedRecord
+	}
+
+	r.Data = raw[pos : pos+length]
+	pos += length
+
+	q := describe(r.VIF, r.VIFE, manufacturer)
+	if unitText != "" {
+		q.description = unitText
+	}
+
+	r.Description = q.description
+	r.Unit = q.unit
+
+	err := r.decodeData(dif, lvar, q)
+
+	return r, pos, err
+}
+
+// decodeData will interpret the data field. lvar is only used with variable
+// length data.
+func (r *Record) decodeData(dif byte, lvar byte, q quantity) error {
+	code := dif & 0x0f
+
+	switch {
+	case q.kind == kindDate || q.kind == kindDateTime:
+		t, ok := decodeTime(r.Data)
+		if !ok {
+			return nil
+		}
+
+		r.Time = t
+		return nil
+
+	case code == 0x0d && lvar < 0xc0:
+		r.Text = reverse(r.Data)
+		return nil
+
+	case code == 0x0d && lvar < 0xe0:
+		v, ok := decodeBCD(r.Data)
+		if !ok {
+			return ErrMalformedRecord
+		}
+
+		if lvar >= 0xd0 {
+			v = -v
+		}
+
+		r.Value = v * q.scale
+		return nil
+
+	case code == 0x0d:
+		// Binary data is left raw.
+		return nil
+
+	case code == 0x05:
+		r.Value = float64(math.Float32frombits(binary.LittleEndian.Uint32(r.Data))) * q.scale
+		return nil
+
+	case code >= 0x09 && code != 0x0f:
+		v, ok := decodeBCD(r.Data)
+		if !ok {
+			return ErrMalformedRecord
+		}
+
+		r.Value = v * q.scale
+		return nil
+
+	case code >= 0x01 && code <= 0x07:
+		r.Value = float64(decodeInt(r.Data)) * q.scale
+	}
+
+	return nil
+}
+
+// describe will find description, unit and scale of a VIF and its VIFE.
+func describe(vif byte, vife []byte, manufacturer uint16) quantity {
+	var q quantity
+
+	switch vif & 0x7f {
+	case vifExtensionFB:
+		if len(vife) == 0 {
+			return quantity{description: "unknown", scale: 1}
+		}
+
+		q = describeFB(vife[0])
+		vife = vife[1:]
+
+	case vifExtensionFD:
+		if len(vife) == 0 {
+			return quantity{description: "unknown", scale: 1}
+		}
+
+		q = describeFD(vife[0])
+		vife = vife[1:]
+
+	case vifManufacturer:
+		q = quantity{description: "manufacturer specific", scale: 1}
+
+		if manufacturer == kamstrupManufacturer && len(vife) > 0 {
+			if k, found := kamstrupVIFs[vife[0]&0x7f]; found {
+				q = k
+			}
+		}
+
+		// The rest is manufacturer specific as well.
+		return q
+
+	case vifPlainText:
+		q = quantity{scale: 1}
+
+	case vifAny:
+		q = quantity{description: "any", scale: 1}
+
+	default:
+		q = describePrimary(vif & 0x7f)
+	}
+
+	q.applyVIFE(vife)
+
+	return q
+}
+
+// applyVIFE will apply the combinable extensions.
+func (q *quantity) applyVIFE(vife []byte) {
+	for _, e := range vife {
+		e &= 0x7f
+
+		switch {
+		case e == vifeManufacturer:
+			return
+		case e >= 0x70 && e <= 0x77:
+			// Multiplicative correction factor 10^(nnn-6).
+			q.scale *= math.Pow10(int(e&0x07) - 6)
+		case e == 0x7d:
+			q.scale *= 1000
+		default:
+			if s, found := vifeDescriptions[e]; found {
+				q.description += ", " + s
+			}
+		}
+	}
+}
+
+// describePrimary will describe VIFs from the primary table.
+func describePrimary(vif byte) quantity {
+	n := int(vif & 0x07)
+	nn := int(vif & 0x03)
+
+	switch {
+	case vif <= 0x07:
+		return prefixed("energy", energyWh, n-3)
+	case vif <= 0x0f:
+		return prefixed("energy", energyJ, n)
+	case vif <= 0x17:
+		return quantity{"volume", kamstrup.UnitCubicMetre, math.Pow10(n - 6), kindNumeric}
+	case vif <= 0x1f:
+		return quantity{"mass", kamstrup.UnitTon, math.Pow10(n - 6), kindNumeric}
+	case vif <= 0x23:
+		return quantity{"on time", durations[nn], 1, kindNumeric}
+	case vif <= 0x27:
+		return quantity{"operating time", durations[nn], 1, kindNumeric}
+	case vif <= 0x2f:
+		return prefixed("power", powerW, n-3)
+	case vif <= 0x37:
+		return quantity{"power", kamstrup.UnitW, math.Pow10(n) / 3600, kindNumeric}
+	case vif <= 0x3f:
+		return quantity{"volume flow", kamstrup.UnitCubicMetrePerHour, math.Pow10(n - 6), kindNumeric}
+	case vif <= 0x47:
+		return quantity{"volume flow", kamstrup.UnitCubicMetrePerHour, math.Pow10(n-7) * 60, kindNumeric}
+	case vif <= 0x4f:
+		return quantity{"volume flow", kamstrup.UnitCubicMetrePerHour, math.Pow10(n-9) * 3600, kindNumeric}
+	case vif <= 0x57:
+		return quantity{"mass flow", kamstrup.UnitTonPerHour, math.Pow10(n - 6), kindNumeric}
+	case vif <= 0x5b:
+		return quantity{"flow temperature", kamstrup.UnitCelsius, math.Pow10(nn - 3), kindNumeric}
+	case vif <= 0x5f:
+		return quantity{"return temperature", kamstrup.UnitCelsius, math.Pow10(nn - 3), kindNumeric}
+	case vif <= 0x63:
+		return quantity{"temperature difference", kamstrup.UnitKelvin, math.Pow10(nn - 3), kindNumeric}
+	case vif <= 0x67:
+		return quantity{"external temperature", kamstrup.UnitCelsius, math.Pow10(nn - 3), kindNumeric}
+	case vif <= 0x6b:
+		return quantity{"pressure", kamstrup.UnitBar, math.Pow10(nn - 3), kindNumeric}
+	case vif == 0x6c:
+		return quantity{"date", kamstrup.UnitLongDate, 1, kindDate}
+	case vif == 0x6d:
+		return quantity{"time point", kamstrup.UnitDatetime, 1, kindDateTime}
+	case vif == 0x6e:
+		return quantity{"units for H.C.A.", kamstrup.UnitNone, 1, kindNumeric}
+	case vif >= 0x70 && vif <= 0x73:
+		return quantity{"averaging duration", durations[nn], 1, kindNumeric}
+	case vif >= 0x74 && vif <= 0x77:
+		return quantity{"actuality duration", durations[nn], 1, kindNumeric}
+	case vif == 0x78:
+		return quantity{"fabrication number", kamstrup.UnitNone, 1, kindNumeric}
+	case vif == 0x79:
+		return quantity{"identification", kamstrup.UnitNone, 1, kindNumeric}
+	case vif == 0x7a:
+		return quantity{"bus address", kamstrup.UnitNone, 1, kindNumeric}
+	}
+
+	return quantity{fmt.Sprintf("VIF 0x%02x", vif), kamstrup.UnitNone, 1, kindNumeric}
+}
+
+// describeFB will describe VIFs from the first extension table.
+func describeFB(vife byte) quantity {
+	e := vife & 0x7f
+	n := int(e & 0x01)
+
+	switch {
+	case e <= 0x01:
+		return prefixed("energy", energyWh, n+5)
+	case e >= 0x08 && e <= 0x09:
+		return prefixed("energy", energyJ, n+8)
+	case e >= 0x10 && e <= 0x11:
+		return quantity{"volume", kamstrup.UnitCubicMetre, math.Pow10(n + 2), kindNumeric}
+	case e >= 0x18 && e <= 0x19:
+		return quantity{"mass", kamstrup.UnitTon, math.Pow10(n + 2), kindNumeric}
+	case e >= 0x28 && e <= 0x29:
+		return prefixed("power", powerW, n+5)
+	case e >= 0x30 && e <= 0x31:
+		return quantity{"power", kamstrup.UnitW, math.Pow10(n+8) / 3600, kindNumeric}
+	}
+
+	return quantity{fmt.Sprintf("VIF 0xfb 0x%02x", e), kamstrup.UnitNone, 1, kindNumeric}
+}
+
+// describeFD will describe VIFs from the second extension table.
+func describeFD(vife byte) quantity {
+	e := vife & 0x7f
+
+	switch {
+	case e == 0x08:
+		return quantity{"access number", kamstrup.UnitNone, 1, kindNumeric}
+	case e == 0x09:
+		return quantity{"medium", kamstrup.UnitNone, 1, kindNumeric}
+	case e == 0x0a:
+		return quantity{"manufacturer", kamstrup.UnitNone, 1, kindNumeric}
+	case e == 0x0b:
+		return quantity{"parameter set identification", kamstrup.UnitNone, 1, kindNumeric}
+	case e == 0x0c:
+		return quantity{"model/version", kamstrup.UnitNone, 1, kindNumeric}
+	case e == 0x0d:
+		return quantity{"hardware version", kamstrup.UnitNone, 1, kindNumeric}
+	case e == 0x0e:
+		return quantity{"firmware version", kamstrup.UnitNone, 1, kindNumeric}
+	case e == 0x0f:
+		return quantity{"software version", kamstrup.UnitNone, 1, kindNumeric}
+	case e == 0x10:
+		return quantity{"customer location", kamstrup.UnitNone, 1, kindNumeric}
+	case e == 0x11:
+		return quantity{"customer", kamstrup.UnitNone, 1, kindNumeric}
+	case e == 0x16:
+		return quantity{"password", kamstrup.UnitNone, 1, kindNumeric}
+	case e == 0x17:
+		return quantity{"error flags", kamstrup.UnitBitfield, 1, kindNumeric}
+	case e == 0x1a:
+		return quantity{"digital output", kamstrup.UnitBitfield, 1, kindNumeric}
+	case e == 0x1b:
+		return quantity{"digital input", kamstrup.UnitBitfield, 1, kindNumeric}
+	case e == 0x1c:
+		return quantity{"baud rate", kamstrup.UnitNone, 1, kindNumeric}
+	case e == 0x3a:
+		return quantity{"dimensionless", kamstrup.UnitNone, 1, kindNumeric}
+	case e >= 0x40 && e <= 0x4f:
+		return quantity{"voltage", kamstrup.UnitV, math.Pow10(int(e&0x0f) - 9), kindNumeric}
+	case e >= 0x50 && e <= 0x5f:
+		return quantity{"current", kamstrup.UnitA, math.Pow10(int(e&0x0f) - 12), kindNumeric}
+	case e == 0x60:
+		return quantity{"reset counter", kamstrup.UnitNone, 1, kindNumeric}
+	case e == 0x61:
+		return quantity{"cumulation counter", kamstrup.UnitNone, 1, kindNumeric}
+	case e == 0x74:
+		return quantity{"remaining battery lifetime", kamstrup.UnitDay, 1, kindNumeric}
+	}
+
+	return quantity{fmt.Sprintf("VIF 0xfd 0x%02x", e), kamstrup.UnitNone, 1, kindNumeric}
+}
+
+// prefixed will return a quantity in the largest unit of k, M or G not
+// larger than 10^exponent, to match what Kamstrup meters report over KMP.
+func prefixed(description string, units [4]kamstrup.Unit, exponent int) quantity {
+	p := 0
+	for p < 3 && exponent >= 3*(p+1) {
+		p++
+	}
+
+	return quantity{description, units[p], math.Pow10(exponent - 3*p), kindNumeric}
+}
+
+// Reading will return the value and unit of a numeric record.
+func (r Record) Reading() kamstrup.Value {
+	return kamstrup.Value{Value: r.Value, Unit: r.Unit}
+}
+
+// String implements fmt.Stringer.
+func (r Record) String() string {
+	var value string
+
+	switch {
+	case !r.Time.IsZero():
+		value = r.Time.Format("2006-01-02 15:04:05")
+	case r.Text != "":
+		value = r.Text
+	default:
+		value = r.Reading().String()
+	}
+
+	var qualifiers []string
+	if r.Function != FunctionInstantaneous {
+		qualifiers = append(qualifiers, r.Function.String())
+	}
+
+	if r.StorageNumber > 0 {
+		qualifiers = append(qualifiers, fmt.Sprintf("storage %d", r.StorageNumber))
+	}
+
+	if r.Tariff > 0 {
+		qualifiers = append(qualifiers, fmt.Sprintf("tariff %d", r.Tariff))
+	}
+
+	if r.Subunit > 0 {
+		qualifiers = append(qualifiers, fmt.Sprintf("subunit %d", r.Subunit))
+	}
+
+	if len(qualifiers) > 0 {
+		return fmt.Sprintf("%s (%s): %s", r.Description, strings.Join(qualifiers, ", "), value)
+	}
+
+	return fmt.Sprintf("%s: %s", r.Description, value)
+}
+
+// String will return the name of the function.
+func (f Function) String() string {
+	switch f {
+	case FunctionInstantaneous:
+		return "instantaneous"
+	case FunctionMaximum:
+		return "maximum"
+	case FunctionMinimum:
+		return "minimum"
+	}
+
+	return "value during error state"
+}
+
+// decodeInt will decode a little endian signed integer.
+func decodeInt(raw []byte) int64 {
+	var v int64
+	for i := len(raw) - 1; i >= 0; i-- {
+		v = v<<8 | int64(raw[i])
+	}
+
+	// Sign extend.
+	shift := uint(64 - 8*len(raw))
+
+	return v << shift >> shift
+}
+
+// decodeBCD will decode little endian BCD. A high nibble of 0xf in the most
+// significant byte means negative.
+func decodeBCD(raw []byte) (float64, bool) {
+	var v float64
+	negative := false
+
+	for i := len(raw) - 1; i >= 0; i-- {
+		hi, lo := raw[i]>>4, raw[i]&0x0f
+
+		if i == len(raw)-1 && hi == 0x0f {
+			negative = true
+			hi = 0
+		}
+
+		if hi > 9 || lo > 9 {
+			return 0, false
+		}
+
+		v = v*100 + float64(hi)*10 + float64(lo)
+	}
+
+	if negative {
+		v = -v
+	}
+
+	return v, true
+}
+
+// decodeTime will decode date (type G), date-time (type F) and date-time
+// with seconds (type I).
+func decodeTime(raw []byte) (time.Time, bool) {
+	var sec, min, hour int
+	var date []byte
+
+	switch len(raw) {
+	case 2:
+		date = raw
+	case 4:
+		if raw[0]&0x80 != 0 {
+			// Invalid.
+			return time.Time{}, false
+		}
+
+		min = int(raw[0] & 0x3f)
+		hour = int(raw[1] & 0x1f)
+		date = raw[2:]
+	case 6:
+		sec = int(raw[0] & 0x3f)
+		min = int(raw[1] & 0x3f)
+		hour = int(raw[2] & 0x1f)
+		date = raw[3:5]
+	default:
+		return time.Time{}, false
+	}
+
+	day := int(date[0] & 0x1f)
+	month := int(date[1] & 0x0f)
+	year := int(date[0]>>5) | int(date[1]>>4)<<3
+
+	if day == 0 || month == 0 || month > 12 {
+		return time.Time{}, false
+	}
+
+	if year < 81 {
+		year += 2000
+	} else {
+		year += 1900
+	}
+
+	return time.Date(year, time.Month(month), day, hour, min, sec, 0, Location), true
+}
+
+// lvarLength will return the number of bytes of variable length data.
+func lvarLength(lvar byte) int {
+	switch {
+	case lvar < 0xc0:
+		// Characters.
+		return int(lvar)
+	case lvar < 0xe0:
+		// Positive or negative BCD.
+		return int(lvar & 0x0f)
+	case lvar < 0xf0:
+		// Binary.
+		return int(lvar - 0xe0)
+	case lvar <= 0xf4:
+		// Binary, in multiples of four bytes.
+		return 4 * int(lvar-0xec)
+	case lvar == 0xf5:
+		return 48
+	case lvar == 0xf6:
+		return 64
+	}
+
+	return -1
+}
+
+// reverse will return the characters of raw in reverse order. M-Bus sends
+// strings least significant character first.
+func reverse(raw []byte) string {
+	s := make([]byte, len(raw))
+	for i, b := range raw {
+		s[len(raw)-1-i] = b
+	}
+
+	return string(s)
+}
@@ -0,0 +1,121 @@
+package mbus
+
+import (
+	"encoding/hex"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abrander/gometer/kamstrup"
+)
+
+func TestDecodeUserData(t *testing.T) {
+	Location = time.UTC
+
+	records := strings.Join([]string{
+		"04061c330000",     // Energy, 13084 kWh
+		"0414e8030000",     // Volume, 10.00 m³
+		"022d0a00",         // Power, 1000 W
+		"025a2c01",         // Flow temperature, 30.0 °C
+		"025ec800",         // Return temperature, 20.0 °C
+		"02626400",         // Temperature difference, 10.0 K
+		"046d1e0bb121",     // 2021-01-17 11:30
+		"440610270000",     // Storage 1, energy 10000 kWh
+		"841006e8030000",   // Tariff 1, energy 1000 kWh
+		"04ff2201000000",   // Kamstrup info codes
+		"0dfd110434333231", // Customer "1234"
+		"0c7878563412",     // Fabrication number
+		"0b3b9909f0",       // Volume flow -0.999 m³/h, BCD
+		"2f2f",             // Idle filler
+		"0f0102",           // Manufacturer data
+	}, "")
+
+	raw, _ := hex.DecodeString(records)
+
+	ud, err := DecodeUserData(raw, kamstrupManufacturer)
+	if err != nil {
+		t.Fatalf("DecodeUserData() returned %s", err)
+	}
+
+	if len(ud.Records) != 13 {
+		t.Fatalf("Got %d records, expected 13", len(ud.Records))
+	}
+
+	if ud.MoreRecords || hex.EncodeToString(ud.ManufacturerData) != "0102" {
+		t.Errorf("Manufacturer data is %x, more records %v", ud.ManufacturerData, ud.MoreRecords)
+	}
+
+	numeric := []struct {
+		index       int
+		description string
+		value       float64
+		unit        kamstrup.Unit
+	}{
+		{0, "energy", 13084, kamstrup.UnitKWh},
+		{1, "volume", 10, kamstrup.UnitCubicMetre},
+		{2, "power", 1000, kamstrup.UnitW},
+		{3, "flow temperature", 30, kamstrup.UnitCelsius},
+		{4, "return temperature", 20, kamstrup.UnitCelsius},
+		{5, "temperature difference", 10, kamstrup.UnitKelvin},
+		{7, "energy", 10000, kamstrup.UnitKWh},
+		{8, "energy", 1000, kamstrup.UnitKWh},
+		{9, "info codes", 1, kamstrup.UnitBitfield},
+		{11, "fabrication number", 12345678, kamstrup.UnitNone},
+		{12, "volume flow", -0.999, kamstrup.UnitCubicMetrePerHour},
+	}
+
+	for _, c := range numeric {
+		r := ud.Records[c.index]
+
+		if r.Description != c.description || math.Abs(r.Value-c.value) > 1e-9 || r.Unit != c.unit {
+			t.Errorf("Record %d is %s, expected %s: %.3f %s", c.index, r, c.description, c.value, c.unit)
+		}
+	}
+
+	expected := time.Date(2021, 1, 17, 11, 30, 0, 0, time.UTC)
+	if !ud.Records[6].Time.Equal(expected) {
+		t.Errorf("Time point is %s, expected %s", ud.Records[6].Time, expected)
+	}
+
+	if ud.Records[7].StorageNumber != 1 || ud.Records[8].Tariff != 1 || ud.Records[8].StorageNumber != 0 {
+		t.Errorf("Wrong storage or tariff: %s, %s", ud.Records[7], ud.Records[8])
+	}
+
+	if ud.Records[10].Text != "1234" || ud.Records[10].Description != "customer" {
+		t.Errorf("Text record is %s", ud.Records[10])
+	}
+
+	// Without knowing the manufacturer.
+	ud, err = DecodeUserData(raw, 0)
+	if err != nil || ud.Records[9].Description != "manufacturer specific" {
+		t.Errorf("Manufacturer specific record is %s, %v", ud.Records[9], err)
+	}
+
+	_, err = DecodeUserData(raw[:3], 0)
+	if err != ErrMalformedRecord {
+		t.Errorf("DecodeUserData() of truncated record returned %v", err)
+	}
+}
+
+func TestPrefixed(t *testing.T) {
+	cases := []struct {
+		vif   byte
+		unit  kamstrup.Unit
+		scale float64
+	}{
+		{0x03, kamstrup.UnitWh, 1},
+		{0x05, kamstrup.UnitWh, 100},
+		{0x07, kamstrup.UnitKWh, 10},
+		{0x0e, kamstrup.UnitMJ, 1},
+		{0x0f, kamstrup.UnitMJ, 10},
+		{0x2e, kamstrup.UnitKW, 1},
+	}
+
+	for _, c := range cases {
+		q := describePrimary(c.vif)
+		if q.unit != c.unit || math.Abs(q.scale-c.scale) > 1e-9 {
+			t.Errorf("VIF 0x%02x is %g %s, expected %g %s", c.vif, q.scale, q.unit, c.scale, c.unit)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package meter
+
+import (
+	"github.com/abrander/gometer/iec62056"
+)
+
+// NewIEC62056Bridge will expose m as an IEC 62056-21 meter. Every sign-on is
+// answered with a data readout of the quantities matching patterns, read
+// from m at that time. Serve the returned server on a serial device, a pty
+// or TCP.
+func NewIEC62056Bridge(m Meter, identification string, patterns ...iec62056.ObisPattern) *iec62056.Server {
+	return &iec62056.Server{
+		Identification: identification,
+		Readout: func() (iec62056.ValueCollection, error) {
+			r, err := m.Read(patterns...)
+			if err != nil {
+				return nil, err
+			}
+
+			return r.Values, nil
+		},
+	}
+}
@@ -0,0 +1,172 @@
+package meter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abrander/gometer/iec62056"
+	"github.com/abrander/gometer/kamstrup"
+	"github.com/abrander/gometer/modbus"
+)
+
+type (
+	// ModbusGateway publishes values read from a meter as Modbus registers.
+	// The registers are laid out by a register map, where the OBIS code of
+	// every register selects the value published. A value read without
+	// value groups A and B, like "1.8.0" from an IEC 62056-21 readout, is
+	// published for a register with any A and B, like "1-0:1.8.0".
+	//
+	// Registers without a current value are cleared, so a client reading
+	// them gets ExceptionIllegalDataAddress instead of a stale value.
+	ModbusGateway struct {
+		Meter    Meter
+		Map      modbus.RegisterMap
+		Server   *modbus.Server
+		Interval time.Duration
+
+		// MaxMissed is the number of polls in a row a register can go
+		// without a value, because the poll failed or the value was missing,
+		// before it is cleared. With zero, registers are cleared at the first
+		// missed value.
+		MaxMissed int
+
+		// OnError, if set, will be called for failed polls and for values
+		// that cannot be published.
+		OnError func(error)
+
+		patterns []iec62056.ObisPattern
+		missed   []int
+	}
+)
+
+// NewModbusGateway will instantiate a gateway publishing the values of m as
+// laid out by registers. The meter is polled every interval by Run. Serve
+// the server with Server.ListenAndServe. The interval must be positive.
+func NewModbusGateway(m Meter, registers modbus.RegisterMap, interval time.Duration) (*ModbusGateway, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid interval %s", interval)
+	}
+
+	g := &ModbusGateway{
+		Meter:    m,
+		Map:      registers,
+		Server:   modbus.NewServer(),
+		Interval: interval,
+	}
+
+	for _, r := range registers {
+		p, err := registerPattern(r.Obis)
+		if err != nil {
+			return nil, err
+		}
+
+		g.patterns = append(g.patterns, p)
+	}
+
+	g.missed = make([]int, len(registers))
+
+	return g, nil
+}
+
+// registerPattern will return a pattern matching the OBIS code of a register
+// with or without value groups A and B, as IEC 62056-21 readouts often leave
+// them out, like "1.8.0" for "1-0:1.8.0".
+func registerPattern(o iec62056.Obis) (iec62056.ObisPattern, error) {
+	code := o.String()
+
+	a, hasA := o.Group(iec62056.GroupA)
+	b, hasB := o.Group(iec62056.GroupB)
+	if hasA && hasB {
+		code = fmt.Sprintf("[%d,%d]-[%d,%d]:%s", a, iec62056.NotUsed, b, iec62056.NotUsed, code[strings.IndexByte(code, ':')+1:])
+	}
+
+	return iec62056.ParseObisPattern(code)
+}
+
+// Poll will read the meter once and update the registers. Registers the
+// meter did not return a value for are cleared when they have been missed
+// more than MaxMissed times in a row. If reading the meter fails, every
+// register is counted as missed.
+func (g *ModbusGateway) Poll() error {
+	reading, err := g.Meter.Read(g.patterns...)
+	if err != nil {
+		for i := range g.Map {
+			g.miss(i)
+		}
+
+		return err
+	}
+
+	for i, r := range g.Map {
+		v, found := g.lookup(reading.Values, i)
+		if !found {
+			g.miss(i)
+			g.reportError(fmt.Errorf("no value for %s", r.Obis))
+			continue
+		}
+
+		err = g.Server.SetValue(r, v)
+		if err != nil {
+			g.miss(i)
+			g.reportError(fmt.Errorf("%s: %w", r.Obis, err))
+			continue
+		}
+
+		g.missed[i] = 0
+	}
+
+	return nil
+}
+
+// miss will count a missed value for register i, and clear it if it has been
+// missed too many times.
+func (g *ModbusGateway) miss(i int) {
+	g.missed[i]++
+
+	if g.missed[i] > g.MaxMissed {
+		g.Server.ClearValue(g.Map[i])
+	}
+}
+
+// Run will poll the meter every Interval until stop is closed. Failed polls
+// are passed to OnError.
+func (g *ModbusGateway) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(g.Interval)
+	defer ticker.Stop()
+
+	for {
+		err := g.Poll()
+		if err != nil {
+			g.reportError(err)
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// lookup will find the value of register i. A value with the exact OBIS
+// code of the register is preferred to one only matching its pattern.
+func (g *ModbusGateway) lookup(values iec62056.ValueCollection, i int) (kamstrup.Value, bool) {
+	if v, found := values[g.Map[i].Obis]; found {
+		return v, true
+	}
+
+	for obis, v := range values {
+		if g.patterns[i].Match(obis) {
+			return v, true
+		}
+	}
+
+	return kamstrup.Value{}, false
+}
+
+func (g *ModbusGateway) reportError(err error) {
+	if g.OnError != nil {
+		g.OnError(err)
+	}
+}
@@ -0,0 +1,146 @@
+package meter
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tarm/serial"
+
+	"github.com/abrander/gometer/iec62056"
+)
+
+type (
+	// IEC62056 is a meter read using an IEC 62056-21 data readout. Every
+	// call to Identify or Read will sign in and read everything.
+	IEC62056 struct {
+		*iec62056.Iec62056
+
+		// Address is the device address sent in the sign-on. It can be empty
+		// if only one meter is connected.
+		Address string
+	}
+)
+
+var (
+	// Codes carrying the serial number in data readouts, in order of
+	// preference. Only value group C, D and E in canonical form are
+	// compared, so "C.1.0" is the same as "0-0:96.1.0".
+	serialCodes = []iec62056.Obis{
+		{C: "0", D: "0", E: "0"},
+		{C: "96", D: "1", E: "0"},
+	}
+)
+
+func init() {
+	Register("iec62056", openIEC62056)
+}
+
+// NewIEC62056 will wrap i as a Meter.
+func NewIEC62056(i *iec62056.Iec62056, address string) *IEC62056 {
+	return &IEC62056{
+		Iec62056: i,
+		Address:  address,
+	}
+}
+
+// openIEC62056 will open a meter from an URL like
+// "iec62056:///dev/ttyUSB0?addr=12345678" or "iec62056://host:port".
+func openIEC62056(u *url.URL) (Meter, error) {
+	port, err := openPort(u, serial.Config{
+		Baud:        300,
+		Size:        7,
+		Parity:      serial.ParityEven,
+		ReadTimeout: time.Millisecond * 2000,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewIEC62056(iec62056.NewIec62056(port), u.Query().Get("addr")), nil
+}
+
+// Identify implements Meter. The manufacturer and model are taken from the
+// identification line, the serial number from the readout. The serial number
+// is kept as written by the meter, as it can have leading zeros or letters.
+func (i *IEC62056) Identify() (*Identity, error) {
+	identification, payload, err := i.Readout(i.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	id := parseIdentification(string(identification))
+	id.Serial = readoutSerial(payload)
+
+	return id, nil
+}
+
+// Read implements Meter.
+func (i *IEC62056) Read(patterns ...iec62056.ObisPattern) (*Reading, error) {
+	_, values, err := i.Signin(i.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(patterns) > 0 {
+		values = values.Select(patterns...)
+	}
+
+	return &Reading{
+		Time:   time.Now(),
+		Values: values,
+	}, nil
+}
+
+// readoutSerial will return the raw contents of the first serial number code
+// found in a data readout, or an empty string if none is found.
+func readoutSerial(payload []byte) string {
+	serials := make(map[iec62056.Obis]string)
+	for _, line := range strings.Split(string(payload), "\n") {
+		obis, values, err := iec62056.ParseDataLine(strings.Trim(line, "\x02\x03!\r"))
+		if err != nil || len(values) == 0 {
+			continue
+		}
+
+		obis = obis.Canonical()
+		code := iec62056.Obis{C: obis.C, D: obis.D, E: obis.E}
+		if _, found := serials[code]; !found {
+			serials[code] = values[0]
+		}
+	}
+
+	for _, code := range serialCodes {
+		if serial, found := serials[code]; found {
+			return serial
+		}
+	}
+
+	return ""
+}
+
+// parseIdentification will parse an identification line like
+// "/KAM5\2MT174". The first three letters are the manufacturer, followed by
+// the baud rate character and the model. Enhanced identification ("\2") is
+// removed.
+func parseIdentification(line string) *Identity {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "/")
+
+	id := &Identity{Protocol: "iec62056"}
+
+	if len(line) < 4 {
+		id.Model = line
+		return id
+	}
+
+	id.Manufacturer = iec62056.Manufacturer(line[:3])
+
+	model := line[4:]
+	if len(model) >= 2 && model[0] == '\\' {
+		model = model[2:]
+	}
+
+	id.Model = strings.TrimSpace(model)
+
+	return id
+}
@@ -0,0 +1,162 @@
+package meter
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/tarm/serial"
+
+	"github.com/abrander/gometer/iec62056"
+	"github.com/abrander/gometer/kamstrup"
+)
+
+type (
+	// Kamstrup is a Kamstrup meter read using KMP.
+	Kamstrup struct {
+		*kamstrup.Kamstrup
+
+		// Registers maps the registers read by Read to OBIS codes.
+		Registers iec62056.KamstrupMap
+	}
+)
+
+// maxRegistersPerRequest is the number of registers a meter will return in a
+// single GetRegister reply.
+const maxRegistersPerRequest = 8
+
+func init() {
+	Register("kmp", openKamstrup)
+}
+
+// NewKamstrup will wrap k as a Meter reading the registers of m.
+func NewKamstrup(k *kamstrup.Kamstrup, m iec62056.KamstrupMap) *Kamstrup {
+	return &Kamstrup{
+		Kamstrup:  k,
+		Registers: m,
+	}
+}
+
+// openKamstrup will open a meter from an URL like
+// "kmp:///dev/ttyUSB0?addr=0x3f&model=382". The model selects the register
+// map from iec62056.KamstrupModels, and defaults to 382.
+func openKamstrup(u *url.URL) (Meter, error) {
+	model := u.Query().Get("model")
+	if model == "" {
+		model = "382"
+	}
+
+	m, found := iec62056.KamstrupModels[model]
+	if !found {
+		return nil, fmt.Errorf("unknown Kamstrup model %q", model)
+	}
+
+	address, err := queryUint(u, "addr", 8, uint64(kamstrup.DefaultAddress))
+	if err != nil {
+		return nil, err
+	}
+
+	port, err := openPort(u, serial.Config{
+		Baud:        9600,
+		ReadTimeout: time.Millisecond * 200,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	k := kamstrup.NewKamstrupPort(port)
+	k.Address = byte(address)
+
+	return NewKamstrup(k, m), nil
+}
+
+// Identify implements Meter.
+func (k *Kamstrup) Identify() (*Identity, error) {
+	serialNo, err := k.GetSerialNo()
+	if err != nil {
+		return nil, err
+	}
+
+	typ, err := k.GetType()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Identity{
+		Protocol:     "kmp",
+		Manufacturer: "KAM",
+		Model:        fmt.Sprintf("%x", typ),
+		Serial:       fmt.Sprintf("%d", serialNo),
+	}, nil
+}
+
+// Read implements Meter. Only registers in Registers can be read.
+func (k *Kamstrup) Read(patterns ...iec62056.ObisPattern) (*Reading, error) {
+	registers := k.Registers.Registers(patterns...)
+
+	r := &Reading{
+		Values: make(iec62056.ValueCollection),
+	}
+
+	for start := 0; start < len(registers); start += maxRegistersPerRequest {
+		end := start + maxRegistersPerRequest
+		if end > len(registers) {
+			end = len(registers)
+		}
+
+		values, err := k.GetRegisters(registers[start:end]...)
+		if err != nil {
+			return nil, err
+		}
+
+		for obis, value := range k.Registers.Values(values) {
+			r.Values[obis] = value
+		}
+	}
+
+	r.Time = time.Now()
+
+	return r, nil
+}
+
+// WriteRegister implements RegisterWriter. Only registers in Registers can be
+// written.
+func (k *Kamstrup) WriteRegister(o iec62056.Obis, v kamstrup.Value) error {
+	register, found := k.Registers.Register(o)
+	if !found {
+		return fmt.Errorf("no register for %s", o)
+	}
+
+	return k.PutRegister(register, v)
+}
+
+// ReadLog implements LogReader. Only registers in Registers can be read.
+func (k *Kamstrup) ReadLog(from time.Time, patterns ...iec62056.ObisPattern) ([]*Reading, error) {
+	records, err := k.GetLog(from, k.Registers.Registers(patterns...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	readings := make([]*Reading, len(records))
+	for i, record := range records {
+		readings[i] = &Reading{
+			Time:   record.Time,
+			Values: k.Registers.Values(record.Values),
+		}
+	}
+
+	return readings, nil
+}
+
+// Scan implements Scanner. All addresses but the broadcast address are
+// tried, taking close to a minute at the default serial timeout.
+func (k *Kamstrup) Scan() ([]string, error) {
+	addresses, err := k.Kamstrup.Scan(0x01, 0xfe)
+
+	found := make([]string, len(addresses))
+	for i, address := range addresses {
+		found[i] = fmt.Sprintf("0x%02x", address)
+	}
+
+	return found, err
+}
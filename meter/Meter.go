@@ -0,0 +1,94 @@
+package meter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abrander/gometer/iec62056"
+	"github.com/abrander/gometer/kamstrup"
+	"github.com/abrander/gometer/trace"
+)
+
+type (
+	// Meter is a meter read through any of the supported protocols. Values
+	// are normalised to OBIS codes, whatever the protocol uses on the wire.
+	Meter interface {
+		// Identify will return the identity of the meter.
+		Identify() (*Identity, error)
+
+		// Read will read the quantities matching at least one of patterns.
+		// Without patterns all quantities known for the meter are read.
+		Read(patterns ...iec62056.ObisPattern) (*Reading, error)
+
+		// Close will close the connection to the meter.
+		Close() error
+	}
+
+	// ClockSetter is implemented by meters whose clock can be set.
+	ClockSetter interface {
+		SetClock(t time.Time) error
+	}
+
+	// RegisterWriter is implemented by meters with writable registers.
+	RegisterWriter interface {
+		// WriteRegister will write v to the register holding the quantity
+		// of o.
+		WriteRegister(o iec62056.Obis, v kamstrup.Value) error
+	}
+
+	// LogReader is implemented by meters keeping a log of readings.
+	LogReader interface {
+		// ReadLog will read the logged quantities matching at least one of
+		// patterns, from the time from until now.
+		ReadLog(from time.Time, patterns ...iec62056.ObisPattern) ([]*Reading, error)
+	}
+
+	// Scanner is implemented by meters on a bus shared by more than one
+	// meter.
+	Scanner interface {
+		// Scan will return the addresses of the meters answering on the bus,
+		// formatted as for the "addr" parameter of the URL.
+		Scan() ([]string, error)
+	}
+
+	// Traceable is implemented by meters able to trace their traffic.
+	Traceable interface {
+		SetTracer(t trace.Tracer)
+	}
+
+	// Identity identifies a meter. Fields not reported by the meter are
+	// left empty.
+	Identity struct {
+		Protocol     string
+		Manufacturer iec62056.Manufacturer
+		Model        string
+		Serial       string
+	}
+
+	// Reading is a set of values read from a meter at the same time.
+	Reading struct {
+		Time   time.Time
+		Values iec62056.ValueCollection
+	}
+)
+
+// String will return the identity as "KAM 382 (serial 12345678, kmp)".
+func (i *Identity) String() string {
+	var parts []string
+
+	if i.Serial != "" {
+		parts = append(parts, "serial "+i.Serial)
+	}
+
+	if i.Protocol != "" {
+		parts = append(parts, i.Protocol)
+	}
+
+	s := strings.TrimSpace(fmt.Sprintf("%s %s", i.Manufacturer, i.Model))
+	if len(parts) > 0 {
+		s += " (" + strings.Join(parts, ", ") + ")"
+	}
+
+	return s
+}
@@ -0,0 +1,654 @@
+package meter
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/abrander/gometer/iec62056"
+	"github.com/abrander/gometer/kamstrup"
+	"github.com/abrander/gometer/modbus"
+	"github.com/abrander/gometer/trace"
+)
+
+// kmpMeter will answer KMP requests to address like a Kamstrup 382.
+func kmpMeter(address byte, request []byte) []byte {
+	var f kamstrup.Frame
+	if f.Decode(request) != nil || f.Address != address {
+		return nil
+	}
+
+	reply := kamstrup.Frame{
+		Type:      kamstrup.FromMeter,
+		Address:   address,
+		CommandID: f.CommandID,
+	}
+
+	switch f.CommandID {
+	case kamstrup.GetSerialNo:
+		reply.Data = binary.BigEndian.AppendUint32(nil, 12345678)
+
+	case kamstrup.GetType:
+		reply.Data = []byte{0x00, 0x2f, 0x01, 0x02}
+
+	case kamstrup.GetRegister:
+		reply.Data = kmpRegisters(f.Data)
+
+	case kamstrup.PutRegister:
+		// Only 100 kWh can be written to EnergyIn.
+		_, v, err := kamstrup.NewValue(f.Data[2:])
+		if binary.BigEndian.Uint16(f.Data) != kamstrup.EnergyIn || err != nil || v.Value != 100 || v.Unit != kamstrup.UnitKWh {
+			return nil
+		}
+
+		reply.Data = f.Data[:2]
+
+	case kamstrup.SetClock:
+		// Only 2026-10-19 12:34:56 is accepted.
+		n, date, _ := kamstrup.NewValue(f.Data)
+		_, clock, _ := kamstrup.NewValue(f.Data[n:])
+		if date.Value != 261019 || date.Unit != kamstrup.UnitDate || clock.Value != 123456 || clock.Unit != kamstrup.UnitClock {
+			return nil
+		}
+
+	case kamstrup.GetLogTimePresent:
+		// Two hourly records following the requested time.
+		reply.Data = []byte{2}
+		for hour := byte(1); hour <= 2; hour++ {
+			reply.Data = append(reply.Data, f.Data[0], f.Data[1], f.Data[2], f.Data[3]+hour, 0, 0)
+			reply.Data = append(reply.Data, kmpRegisters(f.Data[6:])...)
+		}
+	}
+
+	return reply.Encode()
+}
+
+// kmpRegisters will answer a GetRegister request for the registers listed in
+// request.
+func kmpRegisters(request []byte) []byte {
+	reply := []byte{}
+
+	for i := 0; i < int(request[0]); i++ {
+		register := request[1+2*i : 3+2*i]
+		reply = append(reply, register...)
+
+		switch binary.BigEndian.Uint16(register) {
+		case kamstrup.EnergyIn:
+			// 12345.6 kWh
+			reply = append(reply, byte(kamstrup.UnitKWh), 4, 0x41, 0x00, 0x01, 0xe2, 0x40)
+		case kamstrup.VoltageP1:
+			reply = append(reply, byte(kamstrup.UnitV), 2, 0x00, 0x00, 230)
+		default:
+			reply = append(reply, byte(kamstrup.UnitNone), 1, 0x00, 0x00)
+		}
+	}
+
+	return reply
+}
+
+// iecMeter will answer a sign-on with a data readout.
+func iecMeter(request string) []byte {
+	if request != "/?!\r\n" {
+		return nil
+	}
+
+	payload := "\x020.0.0(87654321)\r\n1.8.0(012345.6*kWh)\r\n32.7.0(231*V)\r\n!\r\n\x03"
+
+	return append([]byte("/KAM5\\2MT174\r\n"+payload), 0x00)
+}
+
+// serve will answer requests ending with delimiter on l.
+func serve(t *testing.T, delimiter byte, respond func([]byte) []byte) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() returned %s", err)
+	}
+
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+
+				r := bufio.NewReader(conn)
+				for {
+					request, err := r.ReadBytes(delimiter)
+					if err != nil {
+						return
+					}
+
+					conn.Write(respond(request))
+				}
+			}()
+		}
+	}()
+
+	return l.Addr().String()
+}
+
+func TestRegistry(t *testing.T) {
+	schemes := Schemes()
+	if len(schemes) < 2 || schemes[0] != "iec62056" || schemes[1] != "kmp" {
+		t.Errorf("Schemes() returned %v", schemes)
+	}
+
+	var opened *url.URL
+	Register("test", func(u *url.URL) (Meter, error) {
+		opened = u
+		return nil, nil
+	})
+
+	_, err := Open("test://host:1234/path?addr=1")
+	if err != nil || opened == nil || opened.Host != "host:1234" || opened.Query().Get("addr") != "1" {
+		t.Errorf("Open() returned %v and opened %v", err, opened)
+	}
+
+	_, err = Open("unknown:///dev/null")
+	if !errors.Is(err, ErrUnknownScheme) {
+		t.Errorf("Open() of unknown scheme returned %v", err)
+	}
+
+	_, err = Open("kmp:///dev/null?addr=0x1ff")
+	if err == nil {
+		t.Errorf("Open() of invalid address returned no error")
+	}
+}
+
+func TestKamstrup(t *testing.T) {
+	address := serve(t, kamstrup.Stop, func(request []byte) []byte {
+		return kmpMeter(0x10, request)
+	})
+
+	m, err := Open("kmp://" + address + "?addr=0x10&timeout=200ms")
+	if err != nil {
+		t.Fatalf("Open() returned %s", err)
+	}
+	defer m.Close()
+
+	id, err := m.Identify()
+	if err != nil {
+		t.Fatalf("Identify() returned %s", err)
+	}
+
+	if id.Serial != "12345678" || id.Manufacturer != "KAM" || id.Protocol != "kmp" {
+		t.Errorf("Identify() returned %s", id)
+	}
+
+	reading, err := m.Read()
+	if err != nil {
+		t.Fatalf("Read() returned %s", err)
+	}
+
+	if len(reading.Values) != len(iec62056.Kamstrup382) || reading.Time.IsZero() {
+		t.Errorf("Read() returned %d values, expected %d", len(reading.Values), len(iec62056.Kamstrup382))
+	}
+
+	energy := reading.Values[iec62056.NewObis("1-0:1.8.0")]
+	if energy.Value != 12345.6 || energy.Unit != kamstrup.UnitKWh {
+		t.Errorf("Energy is %s", energy)
+	}
+
+	reading, err = m.Read(iec62056.MustObisPattern("1-0:[32,52,72].7.0"))
+	if err != nil {
+		t.Fatalf("Read() returned %s", err)
+	}
+
+	voltage := reading.Values[iec62056.NewObis("1-0:32.7.0")]
+	if len(reading.Values) != 3 || voltage.Value != 230 || voltage.Unit != kamstrup.UnitV {
+		t.Errorf("Read() of voltages returned %v", reading.Values)
+	}
+}
+
+func TestKamstrupCommands(t *testing.T) {
+	address := serve(t, kamstrup.Stop, func(request []byte) []byte {
+		return kmpMeter(0x10, request)
+	})
+
+	m, err := Open("kmp://" + address + "?addr=0x10&timeout=5ms")
+	if err != nil {
+		t.Fatalf("Open() returned %s", err)
+	}
+	defer m.Close()
+
+	err = m.(ClockSetter).SetClock(time.Date(2026, 10, 19, 12, 34, 56, 0, time.UTC))
+	if err != nil {
+		t.Errorf("SetClock() returned %s", err)
+	}
+
+	writer := m.(RegisterWriter)
+
+	err = writer.WriteRegister(iec62056.ActiveEnergyImport, kamstrup.Value{Value: 100, Unit: kamstrup.UnitKWh})
+	if err != nil {
+		t.Errorf("WriteRegister() returned %s", err)
+	}
+
+	err = writer.WriteRegister(iec62056.Frequency, kamstrup.Value{Value: 50})
+	if err == nil {
+		t.Errorf("WriteRegister() of unmapped code returned no error")
+	}
+
+	from := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	readings, err := m.(LogReader).ReadLog(from, iec62056.MustObisPattern("1-0:1.8.0"))
+	if err != nil {
+		t.Fatalf("ReadLog() returned %s", err)
+	}
+
+	if len(readings) != 2 || !readings[1].Time.Equal(from.Add(2*time.Hour)) {
+		t.Fatalf("ReadLog() returned %v", readings)
+	}
+
+	energy := readings[0].Values[iec62056.ActiveEnergyImport]
+	if len(readings[0].Values) != 1 || energy.Value != 12345.6 {
+		t.Errorf("ReadLog() returned %v", readings[0].Values)
+	}
+
+	addresses, err := m.(Scanner).Scan()
+	if err != nil || len(addresses) != 1 || addresses[0] != "0x10" {
+		t.Errorf("Scan() returned %v, %v", addresses, err)
+	}
+}
+
+func TestIEC62056(t *testing.T) {
+	address := serve(t, '\n', func(request []byte) []byte {
+		return iecMeter(string(request))
+	})
+
+	m, err := Open("iec62056://" + address)
+	if err != nil {
+		t.Fatalf("Open() returned %s", err)
+	}
+	defer m.Close()
+
+	id, err := m.Identify()
+	if err != nil {
+		t.Fatalf("Identify() returned %s", err)
+	}
+
+	if id.Manufacturer != "KAM" || id.Model != "MT174" || id.Serial != "87654321" {
+		t.Errorf("Identify() returned %s", id)
+	}
+
+	reading, err := m.Read(iec62056.MustObisPattern("1.8.0"))
+	if err != nil {
+		t.Fatalf("Read() returned %s", err)
+	}
+
+	energy := reading.Values[iec62056.NewObis("1.8.0")]
+	if len(reading.Values) != 1 || energy.Value != 12345.6 || energy.Unit != kamstrup.UnitKWh {
+		t.Errorf("Read() returned %v", reading.Values)
+	}
+}
+
+func TestReadoutSerial(t *testing.T) {
+	testSet := map[string]string{
+		"\x020.0.0(00012345)\r\n1.8.0(012345.6*kWh)\r\n!\r\n\x03": "00012345",
+		"\x0296.1.0(12345678901234567890)\r\n!\r\n\x03":           "12345678901234567890",
+		"\x02C.1.0(LGZ1030123456)\r\n0.0.0(ABC-123)\r\n!\r\n\x03": "ABC-123",
+		"\x021.8.0(012345.6*kWh)\r\n!\r\n\x03":                    "",
+		"\x020-0:96.1.0(12345678)\r\n!\r\n\x03":                   "12345678",
+		"\x021-0:0.0.0(12345678)\r\n!\r\n\x03":                    "12345678",
+		"\x020.0.0*255(12345678)\r\n!\r\n\x03":                    "12345678",
+		"\x020-0:C.1.0*01(12345678)\r\n!\r\n\x03":                 "12345678",
+	}
+
+	for payload, expected := range testSet {
+		if serial := readoutSerial([]byte(payload)); serial != expected {
+			t.Errorf("readoutSerial(%q) returned %q, expected %q", payload, serial, expected)
+		}
+	}
+}
+
+func TestIEC62056Bridge(t *testing.T) {
+	address := serve(t, kamstrup.Stop, func(request []byte) []byte {
+		return kmpMeter(kamstrup.DefaultAddress, request)
+	})
+
+	m, err := Open("kmp://" + address + "?timeout=200ms")
+	if err != nil {
+		t.Fatalf("Open() returned %s", err)
+	}
+	defer m.Close()
+
+	bridge := NewIEC62056Bridge(m, "KAM5KMPBRIDGE", iec62056.MustObisPattern("1-0:1.8.0"), iec62056.MustObisPattern("1-0:32.7.0"))
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() returned %s", err)
+	}
+	defer l.Close()
+
+	go bridge.ServeListener(l)
+
+	client, err := Open("iec62056://" + l.Addr().String())
+	if err != nil {
+		t.Fatalf("Open() returned %s", err)
+	}
+	defer client.Close()
+
+	reading, err := client.Read()
+	if err != nil {
+		t.Fatalf("Read() returned %s", err)
+	}
+
+	energy := reading.Values[iec62056.NewObis("1.8.0")]
+	if len(reading.Values) != 2 || energy.Value != 12345.6 || energy.Unit != kamstrup.UnitKWh {
+		t.Errorf("Read() through bridge returned %v", reading.Values)
+	}
+}
+
+func TestModbusGateway(t *testing.T) {
+	address := serve(t, kamstrup.Stop, func(request []byte) []byte {
+		return kmpMeter(kamstrup.DefaultAddress, request)
+	})
+
+	m, err := Open("kmp://" + address + "?timeout=200ms")
+	if err != nil {
+		t.Fatalf("Open() returned %s", err)
+	}
+	defer m.Close()
+
+	registers := modbus.RegisterMap{
+		{Obis: iec62056.NewObis("1-0:1.8.0"), Table: modbus.TableInput, Address: 0x0000, Type: modbus.TypeUint32, Unit: kamstrup.UnitWh},
+		{Obis: iec62056.NewObis("1-0:32.7.0"), Table: modbus.TableInput, Address: 0x0002, Type: modbus.TypeFloat32, Unit: kamstrup.UnitV},
+		{Obis: iec62056.NewObis("1-0:99.99.0"), Table: modbus.TableHolding, Address: 0x0000, Type: modbus.TypeUint16},
+	}
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if _, err := NewModbusGateway(m, registers, interval); err == nil {
+			t.Errorf("NewModbusGateway() accepted interval %s", interval)
+		}
+	}
+
+	g, err := NewModbusGateway(m, registers, time.Hour)
+	if err != nil {
+		t.Fatalf("NewModbusGateway() returned %s", err)
+	}
+
+	var errs []error
+	g.OnError = func(err error) {
+		errs = append(errs, err)
+	}
+
+	err = g.Poll()
+	if err != nil {
+		t.Fatalf("Poll() returned %s", err)
+	}
+
+	if len(errs) != 1 {
+		t.Errorf("Poll() reported %v, expected one missing value", errs)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() returned %s", err)
+	}
+	defer l.Close()
+
+	go g.Server.ServeListener(l)
+
+	c, err := modbus.DialTCP(l.Addr().String())
+	if err != nil {
+		t.Fatalf("DialTCP() returned %s", err)
+	}
+	defer c.Close()
+
+	values, err := c.ReadMap(1, registers[:2])
+	if err != nil {
+		t.Fatalf("ReadMap() returned %s", err)
+	}
+
+	energy := values[iec62056.NewObis("1-0:1.8.0")]
+	voltage := values[iec62056.NewObis("1-0:32.7.0")]
+	if energy.Value != 12345600 || voltage.Value != 230 {
+		t.Errorf("ReadMap() through gateway returned %v", values)
+	}
+}
+
+// stubMeter returns the values of readings in turn. A nil collection fails.
+type stubMeter struct {
+	readings []iec62056.ValueCollection
+}
+
+func (s *stubMeter) Identify() (*Identity, error) {
+	return &Identity{}, nil
+}
+
+func (s *stubMeter) Read(patterns ...iec62056.ObisPattern) (*Reading, error) {
+	values := s.readings[0]
+	s.readings = s.readings[1:]
+
+	if values == nil {
+		return nil, errors.New("no reply")
+	}
+
+	return &Reading{Time: time.Now(), Values: values}, nil
+}
+
+func (s *stubMeter) Close() error {
+	return nil
+}
+
+func TestModbusGatewayStale(t *testing.T) {
+	energy := iec62056.NewObis("1-0:1.8.0")
+	voltage := iec62056.NewObis("1-0:32.7.0")
+
+	m := &stubMeter{readings: []iec62056.ValueCollection{
+		{energy: {Value: 1, Unit: kamstrup.UnitKWh}, voltage: {Value: 230, Unit: kamstrup.UnitV}},
+		{energy: {Value: 2, Unit: kamstrup.UnitKWh}},
+		nil,
+		nil,
+	}}
+
+	registers := modbus.RegisterMap{
+		{Obis: energy, Table: modbus.TableInput, Address: 0x0000, Type: modbus.TypeUint32, Unit: kamstrup.UnitWh},
+		{Obis: voltage, Table: modbus.TableInput, Address: 0x0002, Type: modbus.TypeUint16, Unit: kamstrup.UnitV},
+	}
+
+	g, err := NewModbusGateway(m, registers, time.Hour)
+	if err != nil {
+		t.Fatalf("NewModbusGateway() returned %s", err)
+	}
+
+	g.MaxMissed = 1
+
+	read := func(address uint16, count uint16) []byte {
+		return g.Server.Handle(1, []byte{modbus.FuncReadInputRegisters, 0, byte(address), 0, byte(count)})
+	}
+
+	cleared := func(response []byte) bool {
+		return len(response) == 2 && response[1] == byte(modbus.ExceptionIllegalDataAddress)
+	}
+
+	expected := []struct {
+		err             bool
+		energy, voltage bool
+	}{
+		{false, true, true},
+		{false, true, true}, // Voltage missed once.
+		{true, true, false}, // Voltage missed twice, energy once.
+		{true, false, false},
+	}
+
+	for i, e := range expected {
+		err = g.Poll()
+		if (err != nil) != e.err {
+			t.Errorf("Poll() %d returned %v", i, err)
+		}
+
+		if cleared(read(0, 2)) == e.energy || cleared(read(2, 1)) == e.voltage {
+			t.Errorf("Poll() %d left energy %x and voltage %x", i, read(0, 2), read(2, 1))
+		}
+	}
+}
+
+func TestModbusGatewayIEC62056(t *testing.T) {
+	address := serve(t, '\n', func(request []byte) []byte {
+		return iecMeter(string(request))
+	})
+
+	m, err := Open("iec62056://" + address)
+	if err != nil {
+		t.Fatalf("Open() returned %s", err)
+	}
+	defer m.Close()
+
+	// The readout leaves out value groups A and B.
+	registers := modbus.RegisterMap{
+		{Obis: iec62056.NewObis("1-0:1.8.0"), Table: modbus.TableInput, Address: 0x0000, Type: modbus.TypeUint32, Unit: kamstrup.UnitWh},
+		{Obis: iec62056.NewObis("1-0:32.7.0"), Table: modbus.TableInput, Address: 0x0002, Type: modbus.TypeFloat32, Unit: kamstrup.UnitV},
+		{Obis: iec62056.NewObis("1-0:2.8.0"), Table: modbus.TableInput, Address: 0x0004, Type: modbus.TypeUint32, Unit: kamstrup.UnitWh},
+	}
+
+	g, err := NewModbusGateway(m, registers, time.Hour)
+	if err != nil {
+		t.Fatalf("NewModbusGateway() returned %s", err)
+	}
+
+	var errs []error
+	g.OnError = func(err error) {
+		errs = append(errs, err)
+	}
+
+	err = g.Poll()
+	if err != nil {
+		t.Fatalf("Poll() returned %s", err)
+	}
+
+	if len(errs) != 1 {
+		t.Errorf("Poll() reported %v, expected one missing value", errs)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() returned %s", err)
+	}
+	defer l.Close()
+
+	go g.Server.ServeListener(l)
+
+	c, err := modbus.DialTCP(l.Addr().String())
+	if err != nil {
+		t.Fatalf("DialTCP() returned %s", err)
+	}
+	defer c.Close()
+
+	values, err := c.ReadMap(1, registers[:2])
+	if err != nil {
+		t.Fatalf("ReadMap() returned %s", err)
+	}
+
+	energy := values[iec62056.NewObis("1-0:1.8.0")]
+	voltage := values[iec62056.NewObis("1-0:32.7.0")]
+	if energy.Value != 12345600 || voltage.Value != 231 {
+		t.Errorf("ReadMap() through gateway returned %v", values)
+	}
+}
+
+func TestTraceReplay(t *testing.T) {
+	address := serve(t, kamstrup.Stop, func(request []byte) []byte {
+		return kmpMeter(0x10, request)
+	})
+
+	m, err := Open("kmp://" + address + "?addr=0x10&timeout=200ms")
+	if err != nil {
+		t.Fatalf("Open() returned %s", err)
+	}
+
+	recorder := &trace.Recorder{}
+	m.(Traceable).SetTracer(recorder)
+
+	id, err := m.Identify()
+	if err != nil {
+		t.Fatalf("Identify() returned %s", err)
+	}
+	m.Close()
+
+	exchanges := recorder.Exchanges()
+	if len(exchanges) == 0 || exchanges[0].Protocol != "kmp" || exchanges[0].Err != nil || exchanges[0].Result == "" {
+		t.Fatalf("Traced exchanges %v", exchanges)
+	}
+
+	path := filepath.Join(t.TempDir(), "session.trace")
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Create() returned %s", err)
+	}
+
+	w := trace.NewWriter(f)
+	for _, c := range recorder.Chunks() {
+		w.Chunk(c)
+	}
+
+	for _, e := range exchanges {
+		w.Exchange(e)
+	}
+	f.Close()
+
+	if w.Err() != nil {
+		t.Fatalf("Writer returned %s", w.Err())
+	}
+
+	replay, err := Open("kmp:?addr=0x10&replay=" + url.QueryEscape(path))
+	if err != nil {
+		t.Fatalf("Open() of replay returned %s", err)
+	}
+	defer replay.Close()
+
+	replayed, err := replay.Identify()
+	if err != nil {
+		t.Fatalf("Identify() of replay returned %s", err)
+	}
+
+	if replayed.String() != id.String() {
+		t.Errorf("Identify() of replay returned %s, expected %s", replayed, id)
+	}
+
+	// The replay ends with the recorded session.
+	_, err = replay.Read()
+	if err == nil {
+		t.Errorf("Read() beyond the recorded session returned no error")
+	}
+}
+
+func TestTraceIEC62056(t *testing.T) {
+	address := serve(t, '\n', func(request []byte) []byte {
+		return iecMeter(string(request))
+	})
+
+	m, err := Open("iec62056://" + address)
+	if err != nil {
+		t.Fatalf("Open() returned %s", err)
+	}
+	defer m.Close()
+
+	recorder := &trace.Recorder{}
+	m.(Traceable).SetTracer(recorder)
+
+	_, err = m.Identify()
+	if err != nil {
+		t.Fatalf("Identify() returned %s", err)
+	}
+
+	exchanges := recorder.Exchanges()
+	if len(exchanges) != 1 || string(exchanges[0].Request) != "/?!\r\n" || len(exchanges[0].Reply) == 0 {
+		t.Fatalf("Traced exchanges %v", exchanges)
+	}
+
+	chunks := recorder.Chunks()
+	if len(chunks) < 2 || chunks[0].Direction != trace.TX || chunks[1].Direction != trace.RX {
+		t.Errorf("Traced chunks %v", chunks)
+	}
+}
@@ -0,0 +1,179 @@
+package meter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/tarm/serial"
+
+	"github.com/abrander/gometer/trace"
+)
+
+type (
+	// Driver will open a meter from a parsed URL. The scheme selects the
+	// driver, the rest of the URL is up to the driver.
+	Driver func(u *url.URL) (Meter, error)
+
+	// timeoutConn turns a network connection into something behaving like a
+	// serial port with a read timeout: a read timing out returns io.EOF.
+	timeoutConn struct {
+		net.Conn
+		timeout time.Duration
+	}
+)
+
+// DefaultTimeout is the read timeout used for network connections unless
+// set with the "timeout" parameter.
+const DefaultTimeout = 2 * time.Second
+
+var (
+	// ErrUnknownScheme will be returned by Open if no driver is registered
+	// for the scheme of the URL.
+	ErrUnknownScheme = errors.New("unknown meter URL scheme")
+
+	driversMu sync.RWMutex
+	drivers   = make(map[string]Driver)
+)
+
+// Register will make a driver available by scheme. It will panic if the
+// scheme is already registered or driver is nil.
+func Register(scheme string, driver Driver) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+
+	if driver == nil {
+		panic("meter: Register driver is nil")
+	}
+
+	if _, found := drivers[scheme]; found {
+		panic("meter: Register called twice for " + scheme)
+	}
+
+	drivers[scheme] = driver
+}
+
+// Schemes will return the registered schemes, sorted.
+func Schemes() []string {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+
+	schemes := make([]string, 0, len(drivers))
+	for scheme := range drivers {
+		schemes = append(schemes, scheme)
+	}
+
+	sort.Strings(schemes)
+
+	return schemes
+}
+
+// Open will open a meter from a URL like "kmp:///dev/ttyUSB0?addr=0x3f" or
+// "iec62056://host:port". A URL with a host connects using TCP, otherwise
+// the path names a serial device. A URL like "kmp:?replay=session.trace"
+// replays a session recorded in a trace file instead.
+func Open(rawURL string) (Meter, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	driversMu.RLock()
+	driver, found := drivers[u.Scheme]
+	driversMu.RUnlock()
+
+	if !found {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownScheme, u.Scheme)
+	}
+
+	return driver(u)
+}
+
+// openPort will open the serial device or TCP connection named by u. conf is
+// the serial configuration of the protocol. It can be changed by the "baud"
+// parameter, and the network timeout by the "timeout" parameter. The
+// "replay" parameter names a trace file to replay.
+func openPort(u *url.URL, conf serial.Config) (io.ReadWriteCloser, error) {
+	query := u.Query()
+
+	if path := query.Get("replay"); path != "" {
+		chunks, err := trace.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		return trace.NewReplay(chunks), nil
+	}
+
+	if u.Host != "" {
+		timeout := DefaultTimeout
+
+		if v := query.Get("timeout"); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timeout %q: %w", v, err)
+			}
+
+			timeout = d
+		}
+
+		conn, err := net.DialTimeout("tcp", u.Host, timeout)
+		if err != nil {
+			return nil, err
+		}
+
+		return &timeoutConn{Conn: conn, timeout: timeout}, nil
+	}
+
+	if u.Path == "" {
+		return nil, fmt.Errorf("no device or host in %q", u.String())
+	}
+
+	conf.Name = u.Path
+
+	if v := query.Get("baud"); v != "" {
+		baud, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid baud %q: %w", v, err)
+		}
+
+		conf.Baud = baud
+	}
+
+	return serial.OpenPort(&conf)
+}
+
+// queryUint will parse an unsigned integer parameter. Prefixes like "0x"
+// are accepted.
+func queryUint(u *url.URL, name string, bitSize int, def uint64) (uint64, error) {
+	v := u.Query().Get(name)
+	if v == "" {
+		return def, nil
+	}
+
+	n, err := strconv.ParseUint(v, 0, bitSize)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", name, v, err)
+	}
+
+	return n, nil
+}
+
+// Read implements io.Reader.
+func (c *timeoutConn) Read(p []byte) (int, error) {
+	c.Conn.SetReadDeadline(time.Now().Add(c.timeout))
+
+	n, err := c.Conn.Read(p)
+	if os.IsTimeout(err) {
+		return n, io.EOF
+	}
+
+	return n, err
+}
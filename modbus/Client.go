@@ -0,0 +1,180 @@
+package modbus
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+type (
+	// Transport carries PDUs to and from a server.
+	Transport interface {
+		Transact(unit byte, request []byte) ([]byte, error)
+		Close() error
+	}
+
+	// Client is a Modbus client.
+	Client struct {
+		t Transport
+	}
+
+	// ExceptionError is an exception code returned by a server.
+	ExceptionError byte
+)
+
+// Function codes.
+const (
+	FuncReadHoldingRegisters   = byte(0x03)
+	FuncReadInputRegisters     = byte(0x04)
+	FuncWriteMultipleRegisters = byte(0x10)
+
+	// exceptionFlag is set in the function code of exception responses.
+	exceptionFlag = byte(0x80)
+)
+
+// Exception codes.
+const (
+	ExceptionIllegalFunction         = ExceptionError(0x01)
+	ExceptionIllegalDataAddress      = ExceptionError(0x02)
+	ExceptionIllegalDataValue        = ExceptionError(0x03)
+	ExceptionServerDeviceFailure     = ExceptionError(0x04)
+	ExceptionAcknowledge             = ExceptionError(0x05)
+	ExceptionServerDeviceBusy        = ExceptionError(0x06)
+	ExceptionGatewayPathUnavailable  = ExceptionError(0x0a)
+	ExceptionGatewayTargetNoResponse = ExceptionError(0x0b)
+)
+
+// Limits of the number of registers per request.
+const (
+	MaxReadRegisters  = 125
+	MaxWriteRegisters = 123
+)
+
+var (
+	// ErrTimeout will be returned if a server did not answer in time.
+	ErrTimeout = errors.New("no response from server")
+
+	// ErrUnexpectedResponse will be returned if the response does not match
+	// the request.
+	ErrUnexpectedResponse = errors.New("unexpected response")
+
+	// ErrInvalidCount will be returned if the number of registers is zero
+	// or above the limit of a single request.
+	ErrInvalidCount = errors.New("invalid register count")
+
+	exceptionErrors = map[ExceptionError]string{
+		ExceptionIllegalFunction:         "illegal function",
+		ExceptionIllegalDataAddress:      "illegal data address",
+		ExceptionIllegalDataValue:        "illegal data value",
+		ExceptionServerDeviceFailure:     "server device failure",
+		ExceptionAcknowledge:             "acknowledge",
+		ExceptionServerDeviceBusy:        "server device busy",
+		ExceptionGatewayPathUnavailable:  "gateway path unavailable",
+		ExceptionGatewayTargetNoResponse: "gateway target device failed to respond",
+	}
+)
+
+// Error implements error.
+func (e ExceptionError) Error() string {
+	s, found := exceptionErrors[e]
+	if !found {
+		return fmt.Sprintf("exception %d", byte(e))
+	}
+
+	return s
+}
+
+// NewClient will instantiate a new client on a user provided transport.
+func NewClient(t Transport) *Client {
+	return &Client{t: t}
+}
+
+// Close will close the transport.
+func (c *Client) Close() error {
+	return c.t.Close()
+}
+
+// ReadHoldingRegisters will read count holding registers starting at
+// address.
+func (c *Client) ReadHoldingRegisters(unit byte, address uint16, count uint16) ([]uint16, error) {
+	return c.readRegisters(unit, FuncReadHoldingRegisters, address, count)
+}
+
+// ReadInputRegisters will read count input registers starting at address.
+func (c *Client) ReadInputRegisters(unit byte, address uint16, count uint16) ([]uint16, error) {
+	return c.readRegisters(unit, FuncReadInputRegisters, address, count)
+}
+
+// WriteMultipleRegisters will write values to the holding registers starting
+// at address.
+func (c *Client) WriteMultipleRegisters(unit byte, address uint16, values []uint16) error {
+	if len(values) == 0 || len(values) > MaxWriteRegisters {
+		return ErrInvalidCount
+	}
+
+	request := []byte{FuncWriteMultipleRegisters}
+	request = binary.BigEndian.AppendUint16(request, address)
+	request = binary.BigEndian.AppendUint16(request, uint16(len(values)))
+	request = append(request, byte(2*len(values)))
+	for _, v := range values {
+		request = binary.BigEndian.AppendUint16(request, v)
+	}
+
+	response, err := c.transact(unit, request)
+	if err != nil {
+		return err
+	}
+
+	if len(response) != 5 ||
+		binary.BigEndian.Uint16(response[1:]) != address ||
+		binary.BigEndian.Uint16(response[3:]) != uint16(len(values)) {
+		return ErrUnexpectedResponse
+	}
+
+	return nil
+}
+
+func (c *Client) readRegisters(unit byte, function byte, address uint16, count uint16) ([]uint16, error) {
+	if count == 0 || count > MaxReadRegisters {
+		return nil, ErrInvalidCount
+	}
+
+	request := []byte{function}
+	request = binary.BigEndian.AppendUint16(request, address)
+	request = binary.BigEndian.AppendUint16(request, count)
+
+	response, err := c.transact(unit, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(response) < 2 || int(response[1]) != 2*int(count) || len(response) != 2+2*int(count) {
+		return nil, ErrUnexpectedResponse
+	}
+
+	registers := make([]uint16, count)
+	for i := range registers {
+		registers[i] = binary.BigEndian.Uint16(response[2+2*i:])
+	}
+
+	return registers, nil
+}
+
+// transact will send a request and check the function code of the response.
+// Exception responses are returned as ExceptionError.
+func (c *Client) transact(unit byte, request []byte) ([]byte, error) {
+	response, err := c.t.Transact(unit, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(response) == 2 && response[0] == request[0]|exceptionFlag {
+		return nil, ExceptionError(response[1])
+	}
+
+	if len(response) == 0 || response[0] != request[0] {
+		return nil, ErrUnexpectedResponse
+	}
+
+	return response, nil
+}
@@ -0,0 +1,308 @@
+package modbus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"net"
+	"testing"
+
+	"github.com/abrander/gometer/iec62056"
+	"github.com/abrander/gometer/kamstrup"
+)
+
+// testServer is a stand-in for a meter with holding and input registers.
+type testServer struct {
+	unit      byte
+	registers map[Table]map[uint16]uint16
+	requests  int
+}
+
+func newTestServer(unit byte) *testServer {
+	return &testServer{
+		unit: unit,
+		registers: map[Table]map[uint16]uint16{
+			TableHolding: {},
+			TableInput:   {},
+		},
+	}
+}
+
+func (s *testServer) setFloat(table Table, address uint16, v float32) {
+	bits := math.Float32bits(v)
+	s.registers[table][address] = uint16(bits >> 16)
+	s.registers[table][address+1] = uint16(bits)
+}
+
+// handle will answer a request PDU.
+func (s *testServer) handle(pdu []byte) []byte {
+	s.requests++
+
+	exception := func(e ExceptionError) []byte {
+		return []byte{pdu[0] | exceptionFlag, byte(e)}
+	}
+
+	if len(pdu) < 5 {
+		return exception(ExceptionIllegalDataValue)
+	}
+
+	address := binary.BigEndian.Uint16(pdu[1:])
+	count := binary.BigEndian.Uint16(pdu[3:])
+
+	switch pdu[0] {
+	case FuncReadHoldingRegisters, FuncReadInputRegisters:
+		table := s.registers[Table(pdu[0])]
+		response := []byte{pdu[0], byte(2 * count)}
+
+		for i := uint16(0); i < count; i++ {
+			v, found := table[address+i]
+			if !found {
+				return exception(ExceptionIllegalDataAddress)
+			}
+
+			response = binary.BigEndian.AppendUint16(response, v)
+		}
+
+		return response
+
+	case FuncWriteMultipleRegisters:
+		for i := uint16(0); i < count; i++ {
+			s.registers[TableHolding][address+i] = binary.BigEndian.Uint16(pdu[6+2*i:])
+		}
+
+		return pdu[:5]
+	}
+
+	return exception(ExceptionIllegalFunction)
+}
+
+// serve will answer Modbus TCP requests on l.
+func (s *testServer) serve(l net.Listener) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			defer conn.Close()
+
+			for {
+				id, unit, pdu, err := ReadMBAP(conn)
+				if err != nil {
+					return
+				}
+
+				if unit != s.unit {
+					// Like a gateway with nothing at this address.
+					conn.Write(EncodeMBAP(id, unit, []byte{pdu[0] | exceptionFlag, byte(ExceptionGatewayTargetNoResponse)}))
+					continue
+				}
+
+				conn.Write(EncodeMBAP(id, unit, s.handle(pdu)))
+			}
+		}()
+	}
+}
+
+// testPort is a RS-485 line with a single RTU server.
+type testPort struct {
+	server *testServer
+	out    bytes.Buffer
+}
+
+func (p *testPort) Read(b []byte) (int, error) {
+	if p.out.Len() == 0 {
+		return 0, io.EOF
+	}
+
+	return p.out.Read(b)
+}
+
+func (p *testPort) Write(b []byte) (int, error) {
+	n := len(b) - 2
+	if b[0] != p.server.unit || crc16(b[:n]) != binary.LittleEndian.Uint16(b[n:]) {
+		return len(b), nil
+	}
+
+	response := append([]byte{b[0]}, p.server.handle(b[1:n])...)
+	response = binary.LittleEndian.AppendUint16(response, crc16(response))
+	p.out.Write(response)
+
+	return len(b), nil
+}
+
+func (p *testPort) Close() error {
+	return nil
+}
+
+func sdm630() *testServer {
+	s := newTestServer(1)
+
+	for _, r := range EastronSDM630 {
+		s.setFloat(TableInput, r.Address, 0)
+	}
+
+	s.setFloat(TableInput, 0x0000, 230.5)
+	s.setFloat(TableInput, 0x0034, -1500)
+	s.setFloat(TableInput, 0x0048, 12345.25)
+
+	return s
+}
+
+func checkSDM630(t *testing.T, values iec62056.ValueCollection) {
+	if len(values) != len(EastronSDM630) {
+		t.Errorf("ReadMap() returned %d values, expected %d", len(values), len(EastronSDM630))
+	}
+
+	cases := []struct {
+		obis  string
+		value float64
+		unit  kamstrup.Unit
+	}{
+		{"1-0:32.7.0", 230.5, kamstrup.UnitV},
+		{"1-0:16.7.0", -1500, kamstrup.UnitW},
+		{"1-0:1.8.0", 12345.25, kamstrup.UnitKWh},
+	}
+
+	for _, c := range cases {
+		v := values[iec62056.NewObis(c.obis)]
+		if v.Value != c.value || v.Unit != c.unit {
+			t.Errorf("%s is %s, expected %v %s", c.obis, v, c.value, c.unit)
+		}
+	}
+}
+
+func TestCRC(t *testing.T) {
+	if v := crc16([]byte("123456789")); v != 0x4b37 {
+		t.Errorf("crc16() returned %04x, expected 4b37", v)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	cases := []struct {
+		t      DataType
+		scaler int8
+		words  []uint16
+		value  float64
+	}{
+		{TypeUint16, 0, []uint16{0xffff}, 65535},
+		{TypeInt16, -1, []uint16{0xfffe}, -0.2},
+		{TypeUint32, 0, []uint16{0x0001, 0x0002}, 65538},
+		{TypeInt32, 0, []uint16{0xffff, 0xfffe}, -2},
+		{TypeUint32Swapped, 0, []uint16{0x0002, 0x0001}, 65538},
+		{TypeInt32Swapped, -1, []uint16{0xfff6, 0xffff}, -1},
+		{TypeUint64, -2, []uint16{0, 0, 0x0001, 0x0000}, 655.36},
+		{TypeFloat32, 0, []uint16{0x4366, 0x8000}, 230.5},
+		{TypeFloat32Swapped, 0, []uint16{0x8000, 0x4366}, 230.5},
+	}
+
+	for _, c := range cases {
+		v, err := Register{Type: c.t, Scaler: c.scaler}.Decode(c.words)
+		if err != nil {
+			t.Fatalf("Decode() returned %s", err)
+		}
+
+		if math.Abs(v.Value-c.value) > 1e-9 {
+			t.Errorf("Decode() of type %d returned %v, expected %v", c.t, v.Value, c.value)
+		}
+	}
+}
+
+func TestSpans(t *testing.T) {
+	spans := EastronSDM630.spans()
+
+	// 0x0000-0x0011, 0x0034, 0x0046-0x004b, 0x0156
+	if len(spans) != 4 {
+		t.Fatalf("spans() returned %d spans, expected 4", len(spans))
+	}
+
+	if spans[0].address != 0 || spans[0].count != 18 || len(spans[0].registers) != 9 {
+		t.Errorf("First span is %+v", spans[0])
+	}
+
+	if spans[2].address != 0x46 || spans[2].count != 6 {
+		t.Errorf("Third span is %+v", spans[2])
+	}
+}
+
+func TestClientTCP(t *testing.T) {
+	s := sdm630()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() returned %s", err)
+	}
+	defer l.Close()
+
+	go s.serve(l)
+
+	c, err := DialTCP(l.Addr().String())
+	if err != nil {
+		t.Fatalf("DialTCP() returned %s", err)
+	}
+	defer c.Close()
+
+	values, err := c.ReadMap(1, EastronSDM630)
+	if err != nil {
+		t.Fatalf("ReadMap() returned %s", err)
+	}
+
+	checkSDM630(t, values)
+
+	if s.requests != 4 {
+		t.Errorf("ReadMap() used %d requests, expected 4", s.requests)
+	}
+
+	err = c.WriteMultipleRegisters(1, 0x0010, []uint16{0x1234, 0x5678})
+	if err != nil {
+		t.Fatalf("WriteMultipleRegisters() returned %s", err)
+	}
+
+	words, err := c.ReadHoldingRegisters(1, 0x0010, 2)
+	if err != nil || len(words) != 2 || words[0] != 0x1234 || words[1] != 0x5678 {
+		t.Errorf("ReadHoldingRegisters() returned %v, %v", words, err)
+	}
+
+	_, err = c.ReadInputRegisters(1, 0x1000, 1)
+	if err != ExceptionIllegalDataAddress {
+		t.Errorf("ReadInputRegisters() of unmapped register returned %v", err)
+	}
+
+	_, err = c.ReadInputRegisters(2, 0x0000, 1)
+	if err != ExceptionGatewayTargetNoResponse {
+		t.Errorf("ReadInputRegisters() of missing unit returned %v", err)
+	}
+
+	_, err = c.ReadInputRegisters(1, 0x0000, MaxReadRegisters+1)
+	if err != ErrInvalidCount {
+		t.Errorf("ReadInputRegisters() of too many registers returned %v", err)
+	}
+}
+
+func TestClientRTU(t *testing.T) {
+	c := NewClientRTU(&testPort{server: sdm630()})
+
+	values, err := c.ReadMap(1, EastronSDM630)
+	if err != nil {
+		t.Fatalf("ReadMap() returned %s", err)
+	}
+
+	checkSDM630(t, values)
+
+	err = c.WriteMultipleRegisters(1, 0x0010, []uint16{1})
+	if err != nil {
+		t.Fatalf("WriteMultipleRegisters() returned %s", err)
+	}
+
+	_, err = c.ReadHoldingRegisters(1, 0x1000, 1)
+	if err != ExceptionIllegalDataAddress {
+		t.Errorf("ReadHoldingRegisters() of unmapped register returned %v", err)
+	}
+
+	_, err = c.ReadHoldingRegisters(7, 0x0010, 1)
+	if err != ErrTimeout {
+		t.Errorf("ReadHoldingRegisters() of missing unit returned %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package modbus
+
+import (
+	"github.com/abrander/gometer/iec62056"
+	"github.com/abrander/gometer/kamstrup"
+)
+
+// Register maps of common meters.
+var (
+	// EastronSDM120 is the map of the single phase Eastron SDM120.
+	EastronSDM120 = RegisterMap{
+		input("1-0:12.7.0", 0x0000, TypeFloat32, 0, kamstrup.UnitV),
+		input("1-0:11.7.0", 0x0006, TypeFloat32, 0, kamstrup.UnitA),
+		input("1-0:16.7.0", 0x000c, TypeFloat32, 0, kamstrup.UnitW),
+		input("1-0:13.7.0", 0x001e, TypeFloat32, 0, kamstrup.UnitNone),
+		input("1-0:14.7.0", 0x0046, TypeFloat32, 0, kamstrup.UnitHz),
+		input("1-0:1.8.0", 0x0048, TypeFloat32, 0, kamstrup.UnitKWh),
+		input("1-0:2.8.0", 0x004a, TypeFloat32, 0, kamstrup.UnitKWh),
+		input("1-0:15.8.0", 0x0156, TypeFloat32, 0, kamstrup.UnitKWh),
+	}
+
+	// EastronSDM630 is the map of the three phase Eastron SDM630 and
+	// SDM72.
+	EastronSDM630 = RegisterMap{
+		input("1-0:32.7.0", 0x0000, TypeFloat32, 0, kamstrup.UnitV),
+		input("1-0:52.7.0", 0x0002, TypeFloat32, 0, kamstrup.UnitV),
+		input("1-0:72.7.0", 0x0004, TypeFloat32, 0, kamstrup.UnitV),
+		input("1-0:31.7.0", 0x0006, TypeFloat32, 0, kamstrup.UnitA),
+		input("1-0:51.7.0", 0x0008, TypeFloat32, 0, kamstrup.UnitA),
+		input("1-0:71.7.0", 0x000a, TypeFloat32, 0, kamstrup.UnitA),
+		input("1-0:21.7.0", 0x000c, TypeFloat32, 0, kamstrup.UnitW),
+		input("1-0:41.7.0", 0x000e, TypeFloat32, 0, kamstrup.UnitW),
+		input("1-0:61.7.0", 0x0010, TypeFloat32, 0, kamstrup.UnitW),
+		input("1-0:16.7.0", 0x0034, TypeFloat32, 0, kamstrup.UnitW),
+		input("1-0:14.7.0", 0x0046, TypeFloat32, 0, kamstrup.UnitHz),
+		input("1-0:1.8.0", 0x0048, TypeFloat32, 0, kamstrup.UnitKWh),
+		input("1-0:2.8.0", 0x004a, TypeFloat32, 0, kamstrup.UnitKWh),
+		input("1-0:15.8.0", 0x0156, TypeFloat32, 0, kamstrup.UnitKWh),
+	}
+
+	// ABBB23 is the map of the ABB B21, B23 and B24 meters.
+	ABBB23 = RegisterMap{
+		holding("1-0:1.8.0", 0x5000, TypeUint64, -2, kamstrup.UnitKWh),
+		holding("1-0:2.8.0", 0x5004, TypeUint64, -2, kamstrup.UnitKWh),
+		holding("1-0:32.7.0", 0x5b00, TypeUint32, -1, kamstrup.UnitV),
+		holding("1-0:52.7.0", 0x5b02, TypeUint32, -1, kamstrup.UnitV),
+		holding("1-0:72.7.0", 0x5b04, TypeUint32, -1, kamstrup.UnitV),
+		holding("1-0:31.7.0", 0x5b0c, TypeUint32, -2, kamstrup.UnitA),
+		holding("1-0:51.7.0", 0x5b0e, TypeUint32, -2, kamstrup.UnitA),
+		holding("1-0:71.7.0", 0x5b10, TypeUint32, -2, kamstrup.UnitA),
+		holding("1-0:16.7.0", 0x5b14, TypeInt32, -2, kamstrup.UnitW),
+		holding("1-0:21.7.0", 0x5b16, TypeInt32, -2, kamstrup.UnitW),
+		holding("1-0:41.7.0", 0x5b18, TypeInt32, -2, kamstrup.UnitW),
+		holding("1-0:61.7.0", 0x5b1a, TypeInt32, -2, kamstrup.UnitW),
+		holding("1-0:14.7.0", 0x5b2c, TypeUint16, -2, kamstrup.UnitHz),
+	}
+
+	// CarloGavazziEM24 is the map of the Carlo Gavazzi EM24 and EM340.
+	CarloGavazziEM24 = RegisterMap{
+		holding("1-0:32.7.0", 0x0000, TypeInt32Swapped, -1, kamstrup.UnitV),
+		holding("1-0:52.7.0", 0x0002, TypeInt32Swapped, -1, kamstrup.UnitV),
+		holding("1-0:72.7.0", 0x0004, TypeInt32Swapped, -1, kamstrup.UnitV),
+		holding("1-0:31.7.0", 0x000c, TypeInt32Swapped, -3, kamstrup.UnitA),
+		holding("1-0:51.7.0", 0x000e, TypeInt32Swapped, -3, kamstrup.UnitA),
+		holding("1-0:71.7.0", 0x0010, TypeInt32Swapped, -3, kamstrup.UnitA),
+		holding("1-0:21.7.0", 0x0012, TypeInt32Swapped, -1, kamstrup.UnitW),
+		holding("1-0:41.7.0", 0x0014, TypeInt32Swapped, -1, kamstrup.UnitW),
+		holding("1-0:61.7.0", 0x0016, TypeInt32Swapped, -1, kamstrup.UnitW),
+		holding("1-0:16.7.0", 0x0028, TypeInt32Swapped, -1, kamstrup.UnitW),
+		holding("1-0:14.7.0", 0x0033, TypeInt16, -1, kamstrup.UnitHz),
+		holding("1-0:1.8.0", 0x0034, TypeInt32Swapped, -1, kamstrup.UnitKWh),
+		holding("1-0:2.8.0", 0x004e, TypeInt32Swapped, -1, kamstrup.UnitKWh),
+	}
+)
+
+func input(obis string, address uint16, t DataType, scaler int8, unit kamstrup.Unit) Register {
+	return Register{
+		Obis:    iec62056.NewObis(obis),
+		Table:   TableInput,
+		Address: address,
+		Type:    t,
+		Scaler:  scaler,
+		Unit:    unit,
+	}
+}
+
+func holding(obis string, address uint16, t DataType, scaler int8, unit kamstrup.Unit) Register {
+	r := input(obis, address, t, scaler, unit)
+	r.Table = TableHolding
+
+	return r
+}
@@ -0,0 +1,155 @@
+package modbus
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/tarm/serial"
+)
+
+type (
+	// rtuTransport frames PDUs with unit address and CRC for serial lines.
+	rtuTransport struct {
+		port io.ReadWriteCloser
+		r    *bufio.Reader
+	}
+)
+
+var (
+	// ErrInvalidCRC will be returned if the CRC of a RTU frame does not
+	// match.
+	ErrInvalidCRC = errors.New("invalid CRC")
+)
+
+// NewClientRTU will instantiate a new Modbus RTU client with a user provided
+// io.ReadWriteCloser. A read returning io.EOF is taken as a timeout, like a
+// serial port with a read timeout.
+func NewClientRTU(port io.ReadWriteCloser) *Client {
+	return NewClient(&rtuTransport{
+		port: port,
+		r:    bufio.NewReader(port),
+	})
+}
+
+// NewClientRTUSerial will instantiate a new Modbus RTU client on a serial
+// device with a RS-485 adapter. Eastron and most other meters default to
+// 9600 baud, 8N1.
+func NewClientRTUSerial(device string, baud int) (*Client, error) {
+	return NewClientRTUSerialConfig(&serial.Config{
+		Name:        device,
+		Baud:        baud,
+		Size:        8,
+		ReadTimeout: time.Millisecond * 500,
+	})
+}
+
+// NewClientRTUSerialConfig will instantiate a new Modbus RTU client on a
+// serial device with a custom configuration.
+func NewClientRTUSerialConfig(conf *serial.Config) (*Client, error) {
+	port, err := serial.OpenPort(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClientRTU(port), nil
+}
+
+// Transact implements Transport.
+func (t *rtuTransport) Transact(unit byte, request []byte) ([]byte, error) {
+	frame := append([]byte{unit}, request...)
+	frame = binary.LittleEndian.AppendUint16(frame, crc16(frame))
+
+	// Discard anything left from earlier responses.
+	t.r.Reset(t.port)
+
+	_, err := t.port.Write(frame)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]byte, 2, 256)
+	err = t.read(response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response[0] != unit {
+		return nil, ErrUnexpectedResponse
+	}
+
+	// The length of the remaining frame depends on the function.
+	var remaining int
+	switch function := response[1]; {
+	case function&exceptionFlag != 0:
+		remaining = 1
+	case function == FuncReadHoldingRegisters || function == FuncReadInputRegisters:
+		count := make([]byte, 1)
+		err = t.read(count)
+		if err != nil {
+			return nil, err
+		}
+
+		response = append(response, count[0])
+		remaining = int(count[0])
+	case function == FuncWriteMultipleRegisters:
+		remaining = 4
+	default:
+		return nil, ErrUnexpectedResponse
+	}
+
+	rest := make([]byte, remaining+2)
+	err = t.read(rest)
+	if err != nil {
+		return nil, err
+	}
+
+	response = append(response, rest...)
+
+	n := len(response) - 2
+	if crc16(response[:n]) != binary.LittleEndian.Uint16(response[n:]) {
+		return nil, ErrInvalidCRC
+	}
+
+	return response[1:n], nil
+}
+
+// Close implements Transport.
+func (t *rtuTransport) Close() error {
+	return t.port.Close()
+}
+
+// read will fill p. A timeout is returned as ErrTimeout, a partial read as
+// ErrUnexpectedResponse.
+func (t *rtuTransport) read(p []byte) error {
+	_, err := io.ReadFull(t.r, p)
+	switch err {
+	case io.EOF:
+		return ErrTimeout
+	case io.ErrUnexpectedEOF:
+		return ErrUnexpectedResponse
+	}
+
+	return err
+}
+
+// crc16 will calculate the CRC of Modbus RTU, CRC-16/MODBUS.
+func crc16(data []byte) uint16 {
+	reg := uint16(0xffff)
+
+	for _, b := range data {
+		reg ^= uint16(b)
+
+		for i := 0; i < 8; i++ {
+			if reg&0x0001 > 0 {
+				reg = (reg >> 1) ^ 0xa001
+			} else {
+				reg >>= 1
+			}
+		}
+	}
+
+	return reg
+}
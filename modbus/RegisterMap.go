@@ -0,0 +1,280 @@
+package modbus
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/abrander/gometer/iec62056"
+	"github.com/abrander/gometer/kamstrup"
+)
+
+type (
+	// Table is a register table of a server.
+	Table byte
+
+	// DataType is the encoding of a value in one or more registers.
+	DataType byte
+
+	// Register describes a value in a register map.
+	Register struct {
+		Obis    iec62056.Obis
+		Table   Table
+		Address uint16
+		Type    DataType
+
+		// The value is scaled by 10^Scaler.
+		Scaler int8
+		Unit   kamstrup.Unit
+	}
+
+	// RegisterMap is the layout of the values of a meter.
+	RegisterMap []Register
+
+	// span is a range of registers read in one request.
+	span struct {
+		table     Table
+		address   uint16
+		count     uint16
+		registers []Register
+	}
+)
+
+// Register tables.
+const (
+	TableHolding = Table(FuncReadHoldingRegisters)
+	TableInput   = Table(FuncReadInputRegisters)
+)
+
+// Data types. Values of more than one register are sent with the most
+// significant register first, unless Swapped, where the order of the
+// registers is reversed. Bytes within a register are always big endian.
+const (
+	TypeUint16 DataType = iota
+	TypeInt16
+	TypeUint32
+	TypeInt32
+	TypeUint64
+	TypeFloat32
+	TypeUint32Swapped
+	TypeInt32Swapped
+	TypeFloat32Swapped
+)
+
+var (
+	// ErrOutOfRange will be returned if a value does not fit the data type
+	// of a register.
+	ErrOutOfRange = errors.New("value out of range for register")
+)
+
+// String will return the name of the table.
+func (t Table) String() string {
+	switch t {
+	case TableHolding:
+		return "holding"
+	case TableInput:
+		return "input"
+	}
+
+	return fmt.Sprintf("table-%d", byte(t))
+}
+
+// Size will return the number of registers used by the type.
+func (t DataType) Size() uint16 {
+	switch t {
+	case TypeUint16, TypeInt16:
+		return 1
+	case TypeUint64:
+		return 4
+	}
+
+	return 2
+}
+
+// Decode will decode the value of a register from the words read at its
+// address.
+func (r Register) Decode(words []uint16) (kamstrup.Value, error) {
+	if len(words) < int(r.Type.Size()) {
+		return kamstrup.Value{}, ErrInvalidCount
+	}
+
+	var v float64
+
+	switch r.Type {
+	case TypeUint16:
+		v = float64(words[0])
+	case TypeInt16:
+		v = float64(int16(words[0]))
+	case TypeUint32:
+		v = float64(join32(words[0], words[1]))
+	case TypeInt32:
+		v = float64(int32(join32(words[0], words[1])))
+	case TypeUint64:
+		v = float64(uint64(join32(words[0], words[1]))<<32 | uint64(join32(words[2], words[3])))
+	case TypeFloat32:
+		v = float64(math.Float32frombits(join32(words[0], words[1])))
+	case TypeUint32Swapped:
+		v = float64(join32(words[1], words[0]))
+	case TypeInt32Swapped:
+		v = float64(int32(join32(words[1], words[0])))
+	case TypeFloat32Swapped:
+		v = float64(math.Float32frombits(join32(words[1], words[0])))
+	default:
+		return kamstrup.Value{}, fmt.Errorf("unknown data type %d", r.Type)
+	}
+
+	if r.Scaler != 0 {
+		v *= math.Pow10(int(r.Scaler))
+	}
+
+	return kamstrup.Value{Value: v, Unit: r.Unit}, nil
+}
+
+// Encode will encode v as the words of the register. The value is converted
+// to the unit of the register if the units differ only by prefix. A value
+// without a unit is taken as being in the unit of the register.
+func (r Register) Encode(v kamstrup.Value) ([]uint16, error) {
+	if r.Unit != kamstrup.UnitNone && v.Unit != kamstrup.UnitNone {
+		converted, ok := v.Convert(r.Unit)
+		if !ok {
+			return nil, fmt.Errorf("cannot convert %s to %s", v.Unit, r.Unit)
+		}
+
+		v = converted
+	}
+
+	raw := v.Value
+	if r.Scaler != 0 {
+		raw /= math.Pow10(int(r.Scaler))
+	}
+
+	// Integer types are rounded and must fit.
+	var lowest, highest float64
+
+	switch r.Type {
+	case TypeUint16:
+		lowest, highest = 0, math.MaxUint16
+	case TypeInt16:
+		lowest, highest = math.MinInt16, math.MaxInt16
+	case TypeUint32, TypeUint32Swapped:
+		lowest, highest = 0, math.MaxUint32
+	case TypeInt32, TypeInt32Swapped:
+		lowest, highest = math.MinInt32, math.MaxInt32
+	case TypeUint64:
+		// math.MaxUint64 rounds to 2^64 as a float64, which does not fit.
+		lowest, highest = 0, math.Nextafter(1<<64, 0)
+	}
+
+	if r.Type != TypeFloat32 && r.Type != TypeFloat32Swapped {
+		raw = math.Round(raw)
+
+		if raw < lowest || raw > highest || math.IsNaN(raw) {
+			return nil, ErrOutOfRange
+		}
+	}
+
+	switch r.Type {
+	case TypeUint16:
+		return []uint16{uint16(raw)}, nil
+	case TypeInt16:
+		return []uint16{uint16(int16(raw))}, nil
+	case TypeUint32:
+		return split32(uint32(raw)), nil
+	case TypeInt32:
+		return split32(uint32(int32(raw))), nil
+	case TypeUint64:
+		u := uint64(raw)
+		return append(split32(uint32(u>>32)), split32(uint32(u))...), nil
+	case TypeFloat32:
+		return split32(math.Float32bits(float32(raw))), nil
+	case TypeUint32Swapped:
+		return swap(split32(uint32(raw))), nil
+	case TypeInt32Swapped:
+		return swap(split32(uint32(int32(raw)))), nil
+	case TypeFloat32Swapped:
+		return swap(split32(math.Float32bits(float32(raw)))), nil
+	}
+
+	return nil, fmt.Errorf("unknown data type %d", r.Type)
+}
+
+func split32(v uint32) []uint16 {
+	return []uint16{uint16(v >> 16), uint16(v)}
+}
+
+func swap(words []uint16) []uint16 {
+	return []uint16{words[1], words[0]}
+}
+
+func join32(high uint16, low uint16) uint32 {
+	return uint32(high)<<16 | uint32(low)
+}
+
+// spans will group the registers of the map in as few requests as possible.
+// Only adjacent or overlapping registers are joined, as servers answer
+// reads of unmapped addresses with an exception.
+func (m RegisterMap) spans() []span {
+	sorted := append(RegisterMap{}, m...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Table != sorted[j].Table {
+			return sorted[i].Table < sorted[j].Table
+		}
+
+		return sorted[i].Address < sorted[j].Address
+	})
+
+	var spans []span
+
+	for _, r := range sorted {
+		end := uint32(r.Address) + uint32(r.Type.Size())
+
+		if len(spans) > 0 {
+			s := &spans[len(spans)-1]
+			start := uint32(s.address)
+
+			if s.table == r.Table && uint32(r.Address) <= start+uint32(s.count) && end-start <= MaxReadRegisters {
+				if end-start > uint32(s.count) {
+					s.count = uint16(end - start)
+				}
+
+				s.registers = append(s.registers, r)
+
+				continue
+			}
+		}
+
+		spans = append(spans, span{
+			table:     r.Table,
+			address:   r.Address,
+			count:     r.Type.Size(),
+			registers: []Register{r},
+		})
+	}
+
+	return spans
+}
+
+// ReadMap will read all registers of m from a server and return the values
+// keyed by OBIS code.
+func (c *Client) ReadMap(unit byte, m RegisterMap) (iec62056.ValueCollection, error) {
+	values := make(iec62056.ValueCollection)
+
+	for _, s := range m.spans() {
+		words, err := c.readRegisters(unit, byte(s.table), s.address, s.count)
+		if err != nil {
+			return values, err
+		}
+
+		for _, r := range s.registers {
+			v, err := r.Decode(words[r.Address-s.address:])
+			if err != nil {
+				return values, err
+			}
+
+			values[r.Obis] = v
+		}
+	}
+
+	return values, nil
+}
@@ -0,0 +1,174 @@
+package modbus
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/abrander/gometer/kamstrup"
+)
+
+type (
+	// Server is a Modbus TCP server publishing holding and input
+	// registers. Registers can be read, but not written by clients.
+	Server struct {
+		// Unit, if not zero, is the only unit identifier answered. Requests
+		// to other units are answered with a gateway exception.
+		Unit byte
+
+		mu        sync.RWMutex
+		registers map[Table]map[uint16]uint16
+	}
+)
+
+// NewServer will instantiate a new server without registers.
+func NewServer() *Server {
+	return &Server{
+		registers: map[Table]map[uint16]uint16{
+			TableHolding: make(map[uint16]uint16),
+			TableInput:   make(map[uint16]uint16),
+		},
+	}
+}
+
+// Set will set the registers starting at address.
+func (s *Server) Set(table Table, address uint16, words ...uint16) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	registers, found := s.registers[table]
+	if !found {
+		registers = make(map[uint16]uint16)
+		s.registers[table] = registers
+	}
+
+	for i, w := range words {
+		registers[address+uint16(i)] = w
+	}
+}
+
+// SetValue will encode v and set the registers of r.
+func (s *Server) SetValue(r Register, v kamstrup.Value) error {
+	words, err := r.Encode(v)
+	if err != nil {
+		return err
+	}
+
+	s.Set(r.Table, r.Address, words...)
+
+	return nil
+}
+
+// Clear will remove count registers starting at address. Reads including
+// them are answered with ExceptionIllegalDataAddress until they are set
+// again.
+func (s *Server) Clear(table Table, address uint16, count uint16) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := uint16(0); i < count; i++ {
+		delete(s.registers[table], address+i)
+	}
+}
+
+// ClearValue will remove the registers of r.
+func (s *Server) ClearValue(r Register) {
+	s.Clear(r.Table, r.Address, r.Type.Size())
+}
+
+// Handle will answer a request PDU. Reads including addresses never set are
+// answered with ExceptionIllegalDataAddress.
+func (s *Server) Handle(unit byte, request []byte) []byte {
+	if len(request) == 0 {
+		return nil
+	}
+
+	exception := func(e ExceptionError) []byte {
+		return []byte{request[0] | exceptionFlag, byte(e)}
+	}
+
+	if s.Unit != 0 && unit != s.Unit {
+		return exception(ExceptionGatewayTargetNoResponse)
+	}
+
+	function := request[0]
+	if function != FuncReadHoldingRegisters && function != FuncReadInputRegisters {
+		return exception(ExceptionIllegalFunction)
+	}
+
+	if len(request) != 5 {
+		return exception(ExceptionIllegalDataValue)
+	}
+
+	address := binary.BigEndian.Uint16(request[1:])
+	count := binary.BigEndian.Uint16(request[3:])
+	if count == 0 || count > MaxReadRegisters {
+		return exception(ExceptionIllegalDataValue)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	registers := s.registers[Table(function)]
+	response := []byte{function, byte(2 * count)}
+
+	for i := uint16(0); i < count; i++ {
+		w, found := registers[address+i]
+		if !found {
+			return exception(ExceptionIllegalDataAddress)
+		}
+
+		response = binary.BigEndian.AppendUint16(response, w)
+	}
+
+	return response
+}
+
+// Serve will answer requests on a connection until reading fails. The error
+// from the connection is returned, io.EOF included.
+func (s *Server) Serve(conn io.ReadWriter) error {
+	for {
+		id, unit, request, err := ReadMBAP(conn)
+		if err != nil {
+			return err
+		}
+
+		_, err = conn.Write(EncodeMBAP(id, unit, s.Handle(unit, request)))
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// ListenAndServe will accept TCP connections on address and serve them. If
+// address has no port, DefaultTCPPort is used.
+func (s *Server) ListenAndServe(address string) error {
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, DefaultTCPPort)
+	}
+
+	l, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+
+	return s.ServeListener(l)
+}
+
+// ServeListener will accept connections on l and serve them until l is
+// closed.
+func (s *Server) ServeListener(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+
+		go func() {
+			defer conn.Close()
+
+			s.Serve(conn)
+		}()
+	}
+}
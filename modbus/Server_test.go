@@ -0,0 +1,117 @@
+package modbus
+
+import (
+	"math"
+	"net"
+	"testing"
+
+	"github.com/abrander/gometer/iec62056"
+	"github.com/abrander/gometer/kamstrup"
+)
+
+func TestEncode(t *testing.T) {
+	cases := []struct {
+		register Register
+		value    kamstrup.Value
+		words    []uint16
+	}{
+		{Register{Type: TypeUint16, Unit: kamstrup.UnitV}, kamstrup.Value{Value: 230.4, Unit: kamstrup.UnitV}, []uint16{230}},
+		{Register{Type: TypeInt16, Scaler: -1}, kamstrup.Value{Value: -1.5}, []uint16{0xfff1}},
+		{Register{Type: TypeUint32, Unit: kamstrup.UnitWh}, kamstrup.Value{Value: 12345.6, Unit: kamstrup.UnitKWh}, []uint16{0x00bc, 0x6100}},
+		{Register{Type: TypeUint32Swapped, Scaler: 1}, kamstrup.Value{Value: 1000}, []uint16{100, 0}},
+		{Register{Type: TypeFloat32}, kamstrup.Value{Value: 230.5}, []uint16{0x4366, 0x8000}},
+		{Register{Type: TypeFloat32Swapped}, kamstrup.Value{Value: 230.5}, []uint16{0x8000, 0x4366}},
+		{Register{Type: TypeUint64}, kamstrup.Value{Value: 1 << 32}, []uint16{0, 1, 0, 0}},
+		{Register{Type: TypeUint16, Scaler: -2, Unit: kamstrup.UnitHz}, kamstrup.Value{Value: 50.01, Unit: kamstrup.UnitHz}, []uint16{5001}},
+		{Register{Type: TypeUint16, Scaler: -2, Unit: kamstrup.UnitHz}, kamstrup.Value{Value: 49.99}, []uint16{4999}},
+	}
+
+	for _, c := range cases {
+		words, err := c.register.Encode(c.value)
+		if err != nil {
+			t.Fatalf("Encode(%s) returned %s", c.value, err)
+		}
+
+		if len(words) != len(c.words) {
+			t.Fatalf("Encode(%s) returned %04x, expected %04x", c.value, words, c.words)
+		}
+
+		for i := range words {
+			if words[i] != c.words[i] {
+				t.Errorf("Encode(%s) returned %04x, expected %04x", c.value, words, c.words)
+				break
+			}
+		}
+	}
+
+	_, err := Register{Type: TypeUint16}.Encode(kamstrup.Value{Value: -1})
+	if err != ErrOutOfRange {
+		t.Errorf("Encode() of negative value returned %v", err)
+	}
+
+	_, err = Register{Type: TypeUint64}.Encode(kamstrup.Value{Value: 1 << 64})
+	if err != ErrOutOfRange {
+		t.Errorf("Encode() of 2^64 returned %v", err)
+	}
+
+	words, err := Register{Type: TypeUint64}.Encode(kamstrup.Value{Value: math.Nextafter(1<<64, 0)})
+	if err != nil || words[0] != 0xffff || words[3] != 0xf800 {
+		t.Errorf("Encode() of largest uint64 returned %04x, %v", words, err)
+	}
+
+	_, err = Register{Type: TypeFloat32, Unit: kamstrup.UnitWh}.Encode(kamstrup.Value{Value: 1, Unit: kamstrup.UnitV})
+	if err == nil {
+		t.Errorf("Encode() of wrong unit did not fail")
+	}
+}
+
+func TestServer(t *testing.T) {
+	s := NewServer()
+	s.Unit = 1
+
+	for _, r := range EastronSDM120 {
+		err := s.SetValue(r, kamstrup.Value{Value: 42, Unit: r.Unit})
+		if err != nil {
+			t.Fatalf("SetValue(%s) returned %s", r.Obis, err)
+		}
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() returned %s", err)
+	}
+	defer l.Close()
+
+	go s.ServeListener(l)
+
+	c, err := DialTCP(l.Addr().String())
+	if err != nil {
+		t.Fatalf("DialTCP() returned %s", err)
+	}
+	defer c.Close()
+
+	values, err := c.ReadMap(1, EastronSDM120)
+	if err != nil {
+		t.Fatalf("ReadMap() returned %s", err)
+	}
+
+	voltage := values[iec62056.NewObis("1-0:12.7.0")]
+	if len(values) != len(EastronSDM120) || voltage.Value != 42 || voltage.Unit != kamstrup.UnitV {
+		t.Errorf("ReadMap() returned %v", values)
+	}
+
+	_, err = c.ReadInputRegisters(1, 0x1000, 1)
+	if err != ExceptionIllegalDataAddress {
+		t.Errorf("ReadInputRegisters() of unset register returned %v", err)
+	}
+
+	_, err = c.ReadInputRegisters(2, 0x0000, 2)
+	if err != ExceptionGatewayTargetNoResponse {
+		t.Errorf("ReadInputRegisters() of other unit returned %v", err)
+	}
+
+	err = c.WriteMultipleRegisters(1, 0x0000, []uint16{1})
+	if err != ExceptionIllegalFunction {
+		t.Errorf("WriteMultipleRegisters() returned %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package modbus
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"time"
+)
+
+type (
+	// tcpTransport frames PDUs with the MBAP header for Modbus TCP.
+	tcpTransport struct {
+		conn    net.Conn
+		timeout time.Duration
+
+		transactionID uint16
+	}
+)
+
+// DefaultTCPPort is the registered port of Modbus TCP.
+const DefaultTCPPort = "502"
+
+// mbapSize is the size of the MBAP header including the unit identifier.
+const mbapSize = 7
+
+// maxPDUSize is the largest PDU allowed by the specification.
+const maxPDUSize = 253
+
+// NewClientTCP will instantiate a new Modbus TCP client on a connection.
+// Every request will time out after timeout.
+func NewClientTCP(conn net.Conn, timeout time.Duration) *Client {
+	return NewClient(&tcpTransport{
+		conn:    conn,
+		timeout: timeout,
+	})
+}
+
+// DialTCP will connect to a Modbus TCP server or gateway. If address has no
+// port, DefaultTCPPort is used.
+func DialTCP(address string) (*Client, error) {
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, DefaultTCPPort)
+	}
+
+	conn, err := net.DialTimeout("tcp", address, 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClientTCP(conn, 2*time.Second), nil
+}
+
+// EncodeMBAP will prefix pdu with the MBAP header.
+func EncodeMBAP(transactionID uint16, unit byte, pdu []byte) []byte {
+	raw := binary.BigEndian.AppendUint16(nil, transactionID)
+	raw = binary.BigEndian.AppendUint16(raw, 0) // Protocol identifier
+	raw = binary.BigEndian.AppendUint16(raw, uint16(len(pdu)+1))
+	raw = append(raw, unit)
+
+	return append(raw, pdu...)
+}
+
+// ReadMBAP will read a single MBAP framed PDU.
+func ReadMBAP(r io.Reader) (transactionID uint16, unit byte, pdu []byte, err error) {
+	header := make([]byte, mbapSize)
+
+	_, err = io.ReadFull(r, header)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	length := int(binary.BigEndian.Uint16(header[4:]))
+	if binary.BigEndian.Uint16(header[2:]) != 0 || length < 2 || length > maxPDUSize+1 {
+		return 0, 0, nil, ErrUnexpectedResponse
+	}
+
+	pdu = make([]byte, length-1)
+
+	_, err = io.ReadFull(r, pdu)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+
+	return binary.BigEndian.Uint16(header), header[6], pdu, err
+}
+
+// Transact implements Transport.
+func (t *tcpTransport) Transact(unit byte, request []byte) ([]byte, error) {
+	t.transactionID++
+
+	if t.timeout > 0 {
+		t.conn.SetDeadline(time.Now().Add(t.timeout))
+	}
+
+	_, err := t.conn.Write(EncodeMBAP(t.transactionID, unit, request))
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		id, _, response, err := ReadMBAP(t.conn)
+		if os.IsTimeout(err) {
+			return nil, ErrTimeout
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		// Late responses to earlier requests are skipped.
+		if id == t.transactionID {
+			return response, nil
+		}
+	}
+}
+
+// Close implements Transport.
+func (t *tcpTransport) Close() error {
+	return t.conn.Close()
+}
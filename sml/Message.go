@@ -0,0 +1,468 @@
+package sml
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/bits"
+	"time"
+
+	"github.com/abrander/gometer/dlms"
+	"github.com/abrander/gometer/iec62056"
+	"github.com/abrander/gometer/kamstrup"
+)
+
+type (
+	// MessageTag identifies the body of a message.
+	MessageTag uint32
+
+	// File is a decoded SML file, the payload between the escape sequences.
+	File struct {
+		Messages []Message
+	}
+
+	// Message is a single message of a file.
+	Message struct {
+		TransactionID []byte
+		GroupNo       byte
+		AbortOnError  byte
+		Tag           MessageTag
+
+		// Body is the undecoded message body.
+		Body Node
+	}
+
+	// Time is a time as sent by the meter. Meters without a real time clock
+	// send a seconds index counting from an unspecified point instead.
+	Time struct {
+		SecIndex  uint32
+		Timestamp time.Time
+	}
+
+	// OpenResponse is the body of SML_PublicOpen.Res.
+	OpenResponse struct {
+		Codepage  []byte
+		ClientID  []byte
+		ReqFileID []byte
+		ServerID  []byte
+		RefTime   Time
+		Version   uint64
+	}
+
+	// GetListResponse is the body of SML_GetList.Res, the list pushed by
+	// most meters.
+	GetListResponse struct {
+		ClientID    []byte
+		ServerID    []byte
+		ListName    []byte
+		SensorTime  Time
+		Entries     []ListEntry
+		GatewayTime Time
+	}
+
+	// ListEntry is a single value of a list.
+	ListEntry struct {
+		Name   iec62056.Obis
+		Status uint64
+		Time   Time
+		Unit   dlms.Unit
+		Scaler int8
+		Value  Node
+	}
+)
+
+// Message tags as defined by SML 1.04.
+const (
+	TagOpenRequest              = MessageTag(0x0100)
+	TagOpenResponse             = MessageTag(0x0101)
+	TagCloseRequest             = MessageTag(0x0200)
+	TagCloseResponse            = MessageTag(0x0201)
+	TagGetProfilePackRequest    = MessageTag(0x0300)
+	TagGetProfilePackResponse   = MessageTag(0x0301)
+	TagGetProfileListRequest    = MessageTag(0x0400)
+	TagGetProfileListResponse   = MessageTag(0x0401)
+	TagGetProcParameterRequest  = MessageTag(0x0500)
+	TagGetProcParameterResponse = MessageTag(0x0501)
+	TagSetProcParameterRequest  = MessageTag(0x0600)
+	TagGetListRequest           = MessageTag(0x0700)
+	TagGetListResponse          = MessageTag(0x0701)
+	TagAttentionResponse        = MessageTag(0xff01)
+)
+
+// Choices of SML_Time.
+const (
+	timeSecIndex       = 1
+	timeTimestamp      = 2
+	timeLocalTimestamp = 3
+)
+
+var (
+	// ErrInvalidCRC will be returned if the CRC of a file or a message does
+	// not match.
+	ErrInvalidCRC = errors.New("invalid SML CRC")
+
+	// ErrUnexpectedTag will be returned when decoding a message body of
+	// another type.
+	ErrUnexpectedTag = errors.New("unexpected SML message tag")
+)
+
+// String will return the name of the message.
+func (t MessageTag) String() string {
+	switch t {
+	case TagOpenRequest:
+		return "OpenRequest"
+	case TagOpenResponse:
+		return "OpenResponse"
+	case TagCloseRequest:
+		return "CloseRequest"
+	case TagCloseResponse:
+		return "CloseResponse"
+	case TagGetProfilePackRequest:
+		return "GetProfilePackRequest"
+	case TagGetProfilePackResponse:
+		return "GetProfilePackResponse"
+	case TagGetProfileListRequest:
+		return "GetProfileListRequest"
+	case TagGetProfileListResponse:
+		return "GetProfileListResponse"
+	case TagGetProcParameterRequest:
+		return "GetProcParameterRequest"
+	case TagGetProcParameterResponse:
+		return "GetProcParameterResponse"
+	case TagSetProcParameterRequest:
+		return "SetProcParameterRequest"
+	case TagGetListRequest:
+		return "GetListRequest"
+	case TagGetListResponse:
+		return "GetListResponse"
+	case TagAttentionResponse:
+		return "AttentionResponse"
+	}
+
+	return fmt.Sprintf("tag-%04x", uint32(t))
+}
+
+// DecodeFile will decode the messages of a file. The padding following the
+// last message is ignored.
+func DecodeFile(raw []byte) (*File, error) {
+	f := &File{}
+
+	for pos := 0; pos < len(raw); {
+		if raw[pos] == 0x00 {
+			pos++
+			continue
+		}
+
+		m, used, err := DecodeMessage(raw[pos:])
+		if err != nil {
+			return nil, err
+		}
+
+		f.Messages = append(f.Messages, *m)
+		pos += used
+	}
+
+	return f, nil
+}
+
+// DecodeMessage will decode a single message and verify its CRC. The number
+// of bytes used is returned.
+func DecodeMessage(raw []byte) (*Message, int, error) {
+	if len(raw) == 0 || raw[0] != 0x76 {
+		return nil, 0, ErrUnexpectedNode
+	}
+
+	// The elements are decoded one by one to know where the CRC starts.
+	var elements [6]Node
+	var crc uint16
+
+	pos := 1
+	for i := range elements {
+		if i == 4 {
+			crc = checksum(raw[:pos])
+		}
+
+		n, used, err := DecodeNode(raw[pos:])
+		if err != nil {
+			return nil, 0, err
+		}
+
+		elements[i] = n
+		pos += used
+	}
+
+	if elements[5].Type != TypeEndOfMessage {
+		return nil, 0, ErrUnexpectedNode
+	}
+
+	// The CRC is sent with the bytes swapped.
+	v, ok := elements[4].Uint()
+	if !ok || v != uint64(bits.ReverseBytes16(crc)) {
+		return nil, 0, ErrInvalidCRC
+	}
+
+	m := &Message{}
+
+	m.TransactionID, _ = elements[0].Bytes()
+	group, _ := elements[1].Uint()
+	abort, _ := elements[2].Uint()
+	m.GroupNo = byte(group)
+	m.AbortOnError = byte(abort)
+
+	body := elements[3].Elements()
+	if elements[3].Type != TypeList || len(body) != 2 {
+		return nil, 0, ErrUnexpectedNode
+	}
+
+	tag, ok := body[0].Uint()
+	if !ok {
+		return nil, 0, ErrUnexpectedNode
+	}
+
+	m.Tag = MessageTag(tag)
+	m.Body = body[1]
+
+	return m, pos, nil
+}
+
+// OpenResponse will decode the body of an OpenResponse message.
+func (m *Message) OpenResponse() (*OpenResponse, error) {
+	if m.Tag != TagOpenResponse {
+		return nil, ErrUnexpectedTag
+	}
+
+	elements := m.Body.Elements()
+	if len(elements) != 6 {
+		return nil, ErrUnexpectedNode
+	}
+
+	r := &OpenResponse{}
+	r.Codepage, _ = elements[0].Bytes()
+	r.ClientID, _ = elements[1].Bytes()
+	r.ReqFileID, _ = elements[2].Bytes()
+	r.ServerID, _ = elements[3].Bytes()
+	r.Version, _ = elements[5].Uint()
+
+	var err error
+	r.RefTime, err = decodeTime(elements[4])
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// GetListResponse will decode the body of a GetListResponse message.
+func (m *Message) GetListResponse() (*GetListResponse, error) {
+	if m.Tag != TagGetListResponse {
+		return nil, ErrUnexpectedTag
+	}
+
+	elements := m.Body.Elements()
+	if len(elements) != 7 || elements[4].Type != TypeList {
+		return nil, ErrUnexpectedNode
+	}
+
+	r := &GetListResponse{}
+	r.ClientID, _ = elements[0].Bytes()
+	r.ServerID, _ = elements[1].Bytes()
+	r.ListName, _ = elements[2].Bytes()
+
+	var err error
+	r.SensorTime, err = decodeTime(elements[3])
+	if err != nil {
+		return nil, err
+	}
+
+	r.GatewayTime, err = decodeTime(elements[6])
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range elements[4].Elements() {
+		entry, err := decodeListEntry(e)
+		if err != nil {
+			return nil, err
+		}
+
+		r.Entries = append(r.Entries, entry)
+	}
+
+	return r, nil
+}
+
+// decodeListEntry will decode an SML_ListEntry.
+func decodeListEntry(n Node) (ListEntry, error) {
+	elements := n.Elements()
+	if len(elements) != 7 {
+		return ListEntry{}, ErrUnexpectedNode
+	}
+
+	name, ok := elements[0].Bytes()
+	if !ok {
+		return ListEntry{}, ErrUnexpectedNode
+	}
+
+	obis, err := iec62056.ObisFromBytes(name)
+	if err != nil {
+		return ListEntry{}, err
+	}
+
+	e := ListEntry{
+		Name:  obis,
+		Value: elements[5],
+	}
+
+	e.Status, _ = elements[1].Uint()
+
+	e.Time, err = decodeTime(elements[2])
+	if err != nil {
+		return ListEntry{}, err
+	}
+
+	unit, _ := elements[3].Uint()
+	e.Unit = dlms.Unit(unit)
+
+	scaler, _ := elements[4].Int()
+	e.Scaler = int8(scaler)
+
+	return e, nil
+}
+
+// decodeTime will decode an optional SML_Time. Some meters send a bare
+// seconds index instead of the list.
+func decodeTime(n Node) (Time, error) {
+	switch n.Type {
+	case TypeOptional:
+		return Time{}, nil
+
+	case TypeUnsigned:
+		v, _ := n.Uint()
+
+		return Time{SecIndex: uint32(v)}, nil
+
+	case TypeList:
+	default:
+		return Time{}, ErrUnexpectedNode
+	}
+
+	elements := n.Elements()
+	if len(elements) != 2 {
+		return Time{}, ErrUnexpectedNode
+	}
+
+	choice, _ := elements[0].Uint()
+
+	switch choice {
+	case timeSecIndex:
+		v, ok := elements[1].Uint()
+		if !ok {
+			return Time{}, ErrUnexpectedNode
+		}
+
+		return Time{SecIndex: uint32(v)}, nil
+
+	case timeTimestamp:
+		v, ok := elements[1].Uint()
+		if !ok {
+			return Time{}, ErrUnexpectedNode
+		}
+
+		return Time{Timestamp: time.Unix(int64(v), 0)}, nil
+
+	case timeLocalTimestamp:
+		local := elements[1].Elements()
+		if len(local) != 3 {
+			return Time{}, ErrUnexpectedNode
+		}
+
+		v, ok1 := local[0].Uint()
+		offset, ok2 := local[1].Int()
+		season, ok3 := local[2].Int()
+		if !ok1 || !ok2 || !ok3 {
+			return Time{}, ErrUnexpectedNode
+		}
+
+		zone := time.FixedZone("", int(offset+season)*60)
+
+		return Time{Timestamp: time.Unix(int64(v), 0).In(zone)}, nil
+	}
+
+	return Time{}, ErrUnexpectedNode
+}
+
+// IsZero will return true if the time was not set.
+func (t Time) IsZero() bool {
+	return t.SecIndex == 0 && t.Timestamp.IsZero()
+}
+
+// String will return the timestamp or the seconds index.
+func (t Time) String() string {
+	if !t.Timestamp.IsZero() {
+		return t.Timestamp.String()
+	}
+
+	return fmt.Sprintf("%ds", t.SecIndex)
+}
+
+// Reading will return the value scaled to the unit of the meter. It will
+// return false if the value is not a number.
+func (e *ListEntry) Reading() (kamstrup.Value, bool) {
+	var v float64
+
+	switch raw := e.Value.Value.(type) {
+	case int64:
+		v = float64(raw)
+	case uint64:
+		v = float64(raw)
+	default:
+		return kamstrup.Value{}, false
+	}
+
+	s := dlms.ScalerUnit{Scaler: e.Scaler, Unit: e.Unit}
+
+	return kamstrup.Value{Value: s.Apply(v), Unit: e.Unit.Kamstrup()}, true
+}
+
+// String will return the entry as "1-0:1.8.0*255 = 1234.5 Wh".
+func (e *ListEntry) String() string {
+	if r, ok := e.Reading(); ok {
+		return fmt.Sprintf("%s = %s", e.Name, r)
+	}
+
+	if v, ok := e.Value.Bytes(); ok {
+		return fmt.Sprintf("%s = %s", e.Name, hex.EncodeToString(v))
+	}
+
+	return fmt.Sprintf("%s = %s", e.Name, e.Value)
+}
+
+// Lists will return all lists of the file. Messages that cannot be decoded
+// are skipped.
+func (f *File) Lists() []*GetListResponse {
+	var lists []*GetListResponse
+
+	for i := range f.Messages {
+		l, err := f.Messages[i].GetListResponse()
+		if err == nil {
+			lists = append(lists, l)
+		}
+	}
+
+	return lists
+}
+
+// Values will return all numeric values of all lists of the file.
+func (f *File) Values() iec62056.ValueCollection {
+	c := make(iec62056.ValueCollection)
+
+	for _, l := range f.Lists() {
+		for i := range l.Entries {
+			if r, ok := l.Entries[i].Reading(); ok {
+				c[l.Entries[i].Name] = r
+			}
+		}
+	}
+
+	return c
+}
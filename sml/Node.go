@@ -0,0 +1,342 @@
+package sml
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type (
+	// NodeType is the type of an element of the SML tree.
+	NodeType byte
+
+	// Node is a decoded element of the SML tree. Value holds one of the
+	// following depending on Type:
+	//
+	//   nil       optional element not set, end of message
+	//   []byte    octet string
+	//   bool      boolean
+	//   int64     integer
+	//   uint64    unsigned
+	//   []Node    list
+	Node struct {
+		Type  NodeType
+		Value interface{}
+	}
+)
+
+// Node types as encoded in the type-length field.
+const (
+	TypeOctetString  = NodeType(0x00)
+	TypeBoolean      = NodeType(0x40)
+	TypeInteger      = NodeType(0x50)
+	TypeUnsigned     = NodeType(0x60)
+	TypeList         = NodeType(0x70)
+	TypeOptional     = NodeType(0xfe)
+	TypeEndOfMessage = NodeType(0xff)
+)
+
+var (
+	// ErrMalformed will be returned if the SML tree is truncated or has
+	// invalid lengths.
+	ErrMalformed = errors.New("malformed SML data")
+
+	// ErrUnexpectedNode will be returned when the SML tree does not have the
+	// expected structure.
+	ErrUnexpectedNode = errors.New("unexpected SML node")
+)
+
+// maxDepth limits nesting of lists in malformed input.
+const maxDepth = 16
+
+// maxTypeLength is the maximum number of type-length bytes. Four bytes hold
+// a 16 bit length, more than any real node.
+const maxTypeLength = 4
+
+// String will return a name for the type.
+func (t NodeType) String() string {
+	switch t {
+	case TypeOctetString:
+		return "octet-string"
+	case TypeBoolean:
+		return "boolean"
+	case TypeInteger:
+		return "integer"
+	case TypeUnsigned:
+		return "unsigned"
+	case TypeList:
+		return "list"
+	case TypeOptional:
+		return "optional"
+	case TypeEndOfMessage:
+		return "end-of-message"
+	}
+
+	return fmt.Sprintf("type-%02x", byte(t))
+}
+
+// DecodeNode will decode a single node and return the number of bytes used.
+func DecodeNode(raw []byte) (Node, int, error) {
+	return decodeNode(raw, 0)
+}
+
+func decodeNode(raw []byte, depth int) (Node, int, error) {
+	if depth > maxDepth {
+		return Node{}, 0, ErrMalformed
+	}
+
+	if len(raw) == 0 {
+		return Node{}, 0, ErrMalformed
+	}
+
+	switch raw[0] {
+	case 0x00:
+		return Node{Type: TypeEndOfMessage}, 1, nil
+	case 0x01:
+		return Node{Type: TypeOptional}, 1, nil
+	}
+
+	typ := NodeType(raw[0] & 0x70)
+	length := int(raw[0] & 0x0f)
+
+	// The length continues in following bytes while the top bit is set.
+	pos := 1
+	for more := raw[0]&0x80 != 0; more; pos++ {
+		if pos >= maxTypeLength || pos >= len(raw) || raw[pos]&0x70 != 0 {
+			return Node{}, 0, ErrMalformed
+		}
+
+		more = raw[pos]&0x80 != 0
+		length = length<<4 | int(raw[pos]&0x0f)
+	}
+
+	if typ == TypeList {
+		// Every element is at least one byte.
+		if length > len(raw)-pos {
+			return Node{}, 0, ErrMalformed
+		}
+
+		elements := make([]Node, 0, length)
+
+		for i := 0; i < length; i++ {
+			n, used, err := decodeNode(raw[pos:], depth+1)
+			if err != nil {
+				return Node{}, 0, err
+			}
+
+			elements = append(elements, n)
+			pos += used
+		}
+
+		return Node{Type: TypeList, Value: elements}, pos, nil
+	}
+
+	// For all other types the length includes the type-length field.
+	if length < pos || length > len(raw) {
+		return Node{}, 0, ErrMalformed
+	}
+
+	value := raw[pos:length]
+
+	switch typ {
+	case TypeOctetString:
+		return Node{Type: typ, Value: append([]byte{}, value...)}, length, nil
+
+	case TypeBoolean:
+		if len(value) != 1 {
+			return Node{}, 0, ErrMalformed
+		}
+
+		return Node{Type: typ, Value: value[0] != 0}, length, nil
+
+	case TypeInteger:
+		if len(value) == 0 || len(value) > 8 {
+			return Node{}, 0, ErrMalformed
+		}
+
+		v := int64(int8(value[0]))
+		for _, b := range value[1:] {
+			v = v<<8 | int64(b)
+		}
+
+		return Node{Type: typ, Value: v}, length, nil
+
+	case TypeUnsigned:
+		if len(value) == 0 || len(value) > 8 {
+			return Node{}, 0, ErrMalformed
+		}
+
+		var v uint64
+		for _, b := range value {
+			v = v<<8 | uint64(b)
+		}
+
+		return Node{Type: typ, Value: v}, length, nil
+	}
+
+	return Node{}, 0, ErrMalformed
+}
+
+// Encode will encode the node. Integers are encoded using the smallest
+// possible size.
+func (n Node) Encode() []byte {
+	switch n.Type {
+	case TypeEndOfMessage:
+		return []byte{0x00}
+
+	case TypeOptional:
+		return []byte{0x01}
+
+	case TypeList:
+		elements := n.Elements()
+		raw := typeLength(TypeList, len(elements), false)
+		for _, e := range elements {
+			raw = append(raw, e.Encode()...)
+		}
+
+		return raw
+
+	case TypeBoolean:
+		v, _ := n.Value.(bool)
+		if v {
+			return []byte{0x42, 0x01}
+		}
+
+		return []byte{0x42, 0x00}
+
+	case TypeInteger:
+		v, _ := n.Value.(int64)
+
+		size := 1
+		for size < 8 && (v < -1<<(8*size-1) || v >= 1<<(8*size-1)) {
+			size++
+		}
+
+		return appendBigEndian(typeLength(TypeInteger, size, true), uint64(v), size)
+
+	case TypeUnsigned:
+		v, _ := n.Value.(uint64)
+
+		size := 1
+		for size < 8 && v >= 1<<(8*size) {
+			size++
+		}
+
+		return appendBigEndian(typeLength(TypeUnsigned, size, true), v, size)
+	}
+
+	v, _ := n.Value.([]byte)
+
+	return append(typeLength(TypeOctetString, len(v), true), v...)
+}
+
+// typeLength will encode a type-length field. If inclusive is true the
+// length will include the type-length field itself.
+func typeLength(t NodeType, length int, inclusive bool) []byte {
+	size := 1
+	for {
+		total := length
+		if inclusive {
+			total += size
+		}
+
+		if total < 1<<(4*size) {
+			length = total
+			break
+		}
+
+		size++
+	}
+
+	raw := make([]byte, size)
+	for i := size - 1; i >= 0; i-- {
+		raw[i] = byte(length & 0x0f)
+		if i < size-1 {
+			raw[i] |= 0x80
+		}
+		length >>= 4
+	}
+
+	raw[0] |= byte(t)
+
+	return raw
+}
+
+func appendBigEndian(raw []byte, v uint64, size int) []byte {
+	for i := size - 1; i >= 0; i-- {
+		raw = append(raw, byte(v>>(8*i)))
+	}
+
+	return raw
+}
+
+// Elements will return the elements of a list, or nil for other types.
+func (n Node) Elements() []Node {
+	elements, _ := n.Value.([]Node)
+
+	return elements
+}
+
+// Bytes will return the value of an octet string.
+func (n Node) Bytes() ([]byte, bool) {
+	v, ok := n.Value.([]byte)
+
+	return v, ok
+}
+
+// Int will return the value of an integer or unsigned node.
+func (n Node) Int() (int64, bool) {
+	switch v := n.Value.(type) {
+	case int64:
+		return v, true
+	case uint64:
+		return int64(v), true
+	}
+
+	return 0, false
+}
+
+// Uint will return the value of an unsigned or integer node.
+func (n Node) Uint() (uint64, bool) {
+	switch v := n.Value.(type) {
+	case uint64:
+		return v, true
+	case int64:
+		return uint64(v), true
+	}
+
+	return 0, false
+}
+
+// IsSet will return false for optional elements not set.
+func (n Node) IsSet() bool {
+	return n.Type != TypeOptional
+}
+
+// String will return a readable representation of the node.
+func (n Node) String() string {
+	switch n.Type {
+	case TypeOptional:
+		return "-"
+
+	case TypeEndOfMessage:
+		return "end"
+
+	case TypeList:
+		elements := n.Elements()
+		s := make([]string, len(elements))
+		for i, e := range elements {
+			s[i] = e.String()
+		}
+
+		return "[" + strings.Join(s, ", ") + "]"
+
+	case TypeOctetString:
+		v, _ := n.Bytes()
+
+		return hex.EncodeToString(v)
+	}
+
+	return fmt.Sprintf("%v", n.Value)
+}
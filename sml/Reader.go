@@ -0,0 +1,236 @@
+package sml
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/tarm/serial"
+)
+
+type (
+	// Reader reads SML files from a meter using SML transport version 1.
+	Reader struct {
+		r      *bufio.Reader
+		closer io.Closer
+	}
+)
+
+// maxFileSize limits the size of a file in case the end is never found.
+const maxFileSize = 64 * 1024
+
+var (
+	escapeSequence = []byte{0x1b, 0x1b, 0x1b, 0x1b}
+	startSequence  = []byte{0x01, 0x01, 0x01, 0x01}
+
+	// endMarker is the first byte following the escape sequence at the end
+	// of a file. It is followed by the number of padding bytes and the CRC.
+	endMarker = byte(0x1a)
+)
+
+var (
+	// ErrInvalidEscape will be returned if an unknown escape sequence is
+	// read.
+	ErrInvalidEscape = errors.New("invalid SML escape sequence")
+
+	// ErrFileTooLong will be returned if no end sequence is found.
+	ErrFileTooLong = errors.New("SML file too long")
+)
+
+// NewReader will initialize a new reader with a user provided io.Reader. If r
+// is an io.Closer, Close will close it.
+func NewReader(r io.Reader) *Reader {
+	reader := &Reader{
+		r: bufio.NewReader(r),
+	}
+
+	if closer, ok := r.(io.Closer); ok {
+		reader.closer = closer
+	}
+
+	return reader
+}
+
+// NewReaderSerial will initialize a new reader on a serial device with an
+// optical read head. Most meters push at 9600 baud, 8N1.
+func NewReaderSerial(device string) (*Reader, error) {
+	conf := &serial.Config{
+		Name: device,
+		Baud: 9600,
+		Size: 8,
+	}
+
+	port, err := serial.OpenPort(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewReader(port), nil
+}
+
+// Close will close the underlying reader if possible.
+func (r *Reader) Close() error {
+	if r.closer == nil {
+		return nil
+	}
+
+	return r.closer.Close()
+}
+
+// ReadRaw will read the next file, verify the CRC and return the payload
+// without escape sequences and padding. Data before the start sequence is
+// skipped.
+func (r *Reader) ReadRaw() ([]byte, error) {
+	err := r.seekStart()
+	if err != nil {
+		return nil, err
+	}
+
+	// The CRC covers everything from the start sequence except itself.
+	crcInput := append(append([]byte{}, escapeSequence...), startSequence...)
+
+	var payload []byte
+	block := make([]byte, 4)
+
+	for len(payload) < maxFileSize {
+		err = r.readBlock(block)
+		if err != nil {
+			return nil, err
+		}
+
+		if !bytes.Equal(block, escapeSequence) {
+			payload = append(payload, block...)
+			crcInput = append(crcInput, block...)
+
+			continue
+		}
+
+		err = r.readBlock(block)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case bytes.Equal(block, escapeSequence):
+			payload = append(payload, escapeSequence...)
+			crcInput = append(crcInput, escapeSequence...)
+			crcInput = append(crcInput, escapeSequence...)
+
+		case bytes.Equal(block, startSequence):
+			// A new file started before the end of the previous one.
+			payload = nil
+			crcInput = crcInput[:8]
+
+		case block[0] == endMarker:
+			crcInput = append(crcInput, escapeSequence...)
+			crcInput = append(crcInput, block[:2]...)
+
+			crc := checksum(crcInput)
+			if block[2] != byte(crc) || block[3] != byte(crc>>8) {
+				return nil, ErrInvalidCRC
+			}
+
+			padding := int(block[1])
+			if padding > len(payload) {
+				return nil, ErrMalformed
+			}
+
+			return payload[:len(payload)-padding], nil
+
+		default:
+			return nil, ErrInvalidEscape
+		}
+	}
+
+	return nil, ErrFileTooLong
+}
+
+// ReadFile will read and decode the next file.
+func (r *Reader) ReadFile() (*File, error) {
+	raw, err := r.ReadRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	return DecodeFile(raw)
+}
+
+// seekStart will read until the escape and start sequences.
+func (r *Reader) seekStart() error {
+	start := append(append([]byte{}, escapeSequence...), startSequence...)
+	window := make([]byte, 0, len(start))
+
+	for {
+		b, err := r.r.ReadByte()
+		if err != nil {
+			return err
+		}
+
+		if len(window) == len(start) {
+			copy(window, window[1:])
+			window = window[:len(start)-1]
+		}
+
+		window = append(window, b)
+
+		if bytes.Equal(window, start) {
+			return nil
+		}
+	}
+}
+
+// readBlock will read the next four bytes. Files are always padded to a
+// multiple of four bytes, so escape sequences are aligned to blocks.
+func (r *Reader) readBlock(block []byte) error {
+	_, err := io.ReadFull(r.r, block)
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
+
+// EncodeTransport will wrap payload in the escape sequences of SML transport
+// version 1, adding padding and CRC.
+func EncodeTransport(payload []byte) []byte {
+	raw := append(append([]byte{}, escapeSequence...), startSequence...)
+
+	for i := 0; i < len(payload); i += 4 {
+		block := make([]byte, 4)
+		copy(block, payload[i:])
+
+		raw = append(raw, block...)
+		if bytes.Equal(block, escapeSequence) {
+			raw = append(raw, escapeSequence...)
+		}
+	}
+
+	padding := (4 - len(payload)%4) % 4
+
+	raw = append(raw, escapeSequence...)
+	raw = append(raw, endMarker, byte(padding))
+
+	crc := checksum(raw)
+
+	return append(raw, byte(crc), byte(crc>>8))
+}
+
+// checksum will calculate CRC-16/X-25 as used for files and messages.
+func checksum(data []byte) uint16 {
+	reg := uint16(0xffff)
+
+	for _, b := range data {
+		reg ^= uint16(b)
+
+		for i := 0; i < 8; i++ {
+			if reg&0x0001 > 0 {
+				reg = (reg >> 1) ^ 0x8408
+			} else {
+				reg >>= 1
+			}
+		}
+	}
+
+	return reg ^ 0xffff
+}
@@ -0,0 +1,251 @@
+package sml
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"math/bits"
+	"testing"
+
+	"github.com/abrander/gometer/dlms"
+	"github.com/abrander/gometer/iec62056"
+	"github.com/abrander/gometer/kamstrup"
+)
+
+func list(elements ...Node) Node {
+	return Node{Type: TypeList, Value: elements}
+}
+
+func octets(s string) Node {
+	v, _ := hex.DecodeString(s)
+
+	return Node{Type: TypeOctetString, Value: v}
+}
+
+func unsigned(v uint64) Node {
+	return Node{Type: TypeUnsigned, Value: v}
+}
+
+func integer(v int64) Node {
+	return Node{Type: TypeInteger, Value: v}
+}
+
+var optional = Node{Type: TypeOptional}
+
+// message will encode a message with a valid CRC.
+func message(id string, tag MessageTag, body Node) []byte {
+	raw := []byte{0x76}
+	raw = append(raw, octets(id).Encode()...)
+	raw = append(raw, unsigned(0).Encode()...)
+	raw = append(raw, unsigned(0).Encode()...)
+	raw = append(raw, list(unsigned(uint64(tag)), body).Encode()...)
+
+	crc := bits.ReverseBytes16(checksum(raw))
+
+	raw = append(raw, 0x63, byte(crc>>8), byte(crc))
+
+	return append(raw, 0x00)
+}
+
+func entry(obis string, unit dlms.Unit, scaler int64, value Node) Node {
+	name, _ := iec62056.NewObis(obis).Bytes()
+
+	return list(
+		Node{Type: TypeOctetString, Value: name},
+		optional,
+		optional,
+		unsigned(uint64(unit)),
+		integer(scaler),
+		value,
+		optional,
+	)
+}
+
+// testFile is a file as pushed by an eHZ: open, list and close.
+func testFile() []byte {
+	var raw []byte
+
+	raw = append(raw, message("01", TagOpenResponse, list(
+		optional,
+		optional,
+		octets("0a0b"),
+		octets("0a01454d480000123456"),
+		optional,
+		optional,
+	))...)
+
+	raw = append(raw, message("02", TagGetListResponse, list(
+		optional,
+		octets("0a01454d480000123456"),
+		octets("0100620affff"),
+		list(unsigned(timeSecIndex), unsigned(123456)),
+		list(
+			entry("1-0:96.50.1*1", 0, 0, octets("454d48")),
+			entry("1-0:1.8.0*255", dlms.UnitWattHour, -1, unsigned(123456789)),
+			entry("1-0:16.7.0*255", dlms.UnitWatt, 0, integer(-250)),
+		),
+		optional,
+		optional,
+	))...)
+
+	raw = append(raw, message("03", TagCloseResponse, list(optional))...)
+
+	return raw
+}
+
+func TestNode(t *testing.T) {
+	cases := []struct {
+		node Node
+		raw  string
+	}{
+		{optional, "01"},
+		{unsigned(0x12), "6212"},
+		{unsigned(0x1234), "631234"},
+		{integer(-1), "52ff"},
+		{integer(-250), "53ff06"},
+		{Node{Type: TypeBoolean, Value: true}, "4201"},
+		{octets("454d48"), "04454d48"},
+		{octets(hex.EncodeToString(make([]byte, 15))), "8101" + hex.EncodeToString(make([]byte, 15))},
+		{list(unsigned(1), optional), "726201" + "01"},
+	}
+
+	for _, c := range cases {
+		raw := hex.EncodeToString(c.node.Encode())
+		if raw != c.raw {
+			t.Errorf("Encode() of %s returned %s, expected %s", c.node, raw, c.raw)
+		}
+
+		in, _ := hex.DecodeString(c.raw)
+
+		n, used, err := DecodeNode(in)
+		if err != nil {
+			t.Fatalf("DecodeNode(%s) returned %s", c.raw, err)
+		}
+
+		if used != len(in) || n.String() != c.node.String() {
+			t.Errorf("DecodeNode(%s) returned %s using %d bytes", c.raw, n, used)
+		}
+	}
+
+	malformed := []string{
+		"0501",                               // Truncated string
+		"8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f8f0f", // Type-length overflowing
+		"8f8f8f8f0f",                         // Five type-length bytes
+		"f18f8f0f01",                         // List longer than the data
+		"7301",                               // List longer than the data
+	}
+
+	for _, m := range malformed {
+		in, _ := hex.DecodeString(m)
+
+		_, _, err := DecodeNode(in)
+		if err != ErrMalformed {
+			t.Errorf("DecodeNode(%s) returned %v, expected %s", m, err, ErrMalformed)
+		}
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	// The check value of CRC-16/X-25.
+	if v := checksum([]byte("123456789")); v != 0x906e {
+		t.Errorf("checksum() returned %04x, expected 906e", v)
+	}
+}
+
+func TestDecodeFile(t *testing.T) {
+	f, err := DecodeFile(append(testFile(), 0x00, 0x00))
+	if err != nil {
+		t.Fatalf("DecodeFile() returned %s", err)
+	}
+
+	if len(f.Messages) != 3 || f.Messages[2].Tag != TagCloseResponse {
+		t.Fatalf("DecodeFile() returned %+v", f)
+	}
+
+	open, err := f.Messages[0].OpenResponse()
+	if err != nil {
+		t.Fatalf("OpenResponse() returned %s", err)
+	}
+
+	if hex.EncodeToString(open.ServerID) != "0a01454d480000123456" {
+		t.Errorf("Server ID is %x", open.ServerID)
+	}
+
+	_, err = f.Messages[0].GetListResponse()
+	if err != ErrUnexpectedTag {
+		t.Errorf("GetListResponse() of OpenResponse returned %v", err)
+	}
+
+	lists := f.Lists()
+	if len(lists) != 1 || len(lists[0].Entries) != 3 || lists[0].SensorTime.SecIndex != 123456 {
+		t.Fatalf("Lists() returned %+v", lists)
+	}
+
+	values := f.Values()
+	if len(values) != 2 {
+		t.Errorf("Values() returned %v", values)
+	}
+
+	energy := values[iec62056.NewObis("1-0:1.8.0")]
+	if energy.Value != 12345678.9 || energy.Unit != kamstrup.UnitWh {
+		t.Errorf("Energy is %s", energy)
+	}
+
+	power := values[iec62056.NewObis("1-0:16.7.0")]
+	if power.Value != -250 || power.Unit != kamstrup.UnitW {
+		t.Errorf("Power is %s", power)
+	}
+
+	corrupt := testFile()
+	corrupt[4] ^= 0x01
+	_, err = DecodeFile(corrupt)
+	if err != ErrInvalidCRC {
+		t.Errorf("DecodeFile() of corrupt message returned %v", err)
+	}
+}
+
+func TestReader(t *testing.T) {
+	payload := testFile()
+
+	// An escape sequence in the payload must survive the transport.
+	escaped := append(append([]byte{}, payload...), make([]byte, (4-len(payload)%4)%4)...)
+	escaped = append(escaped, 0x1b, 0x1b, 0x1b, 0x1b, 0x01)
+
+	var input []byte
+	input = append(input, 0x55, 0x1b, 0x1b) // Noise before the start
+	input = append(input, EncodeTransport(payload)...)
+	input = append(input, EncodeTransport(escaped)...)
+
+	r := NewReader(bytes.NewReader(input))
+
+	f, err := r.ReadFile()
+	if err != nil {
+		t.Fatalf("ReadFile() returned %s", err)
+	}
+
+	if len(f.Values()) != 2 {
+		t.Errorf("ReadFile() returned %+v", f)
+	}
+
+	raw, err := r.ReadRaw()
+	if err != nil {
+		t.Fatalf("ReadRaw() returned %s", err)
+	}
+
+	if !bytes.Equal(raw, escaped) {
+		t.Errorf("ReadRaw() returned %x, expected %x", raw, escaped)
+	}
+
+	_, err = r.ReadRaw()
+	if err != io.EOF {
+		t.Errorf("ReadRaw() at end returned %v", err)
+	}
+
+	corrupt := EncodeTransport(payload)
+	corrupt[len(corrupt)-1] ^= 0x01
+
+	_, err = NewReader(bytes.NewReader(corrupt)).ReadRaw()
+	if err != ErrInvalidCRC {
+		t.Errorf("ReadRaw() of corrupt file returned %v", err)
+	}
+}
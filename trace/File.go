@@ -0,0 +1,137 @@
+package trace
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// Writer is a tracer writing a trace file. Every chunk is a line with
+	// the time, the direction and the data in hex:
+	//
+	//	2026-10-19T12:34:56.123456789Z TX 803f100100015c140d
+	//
+	// Exchanges are written as comment lines starting with "#", ignored
+	// when reading the file.
+	Writer struct {
+		mu  sync.Mutex
+		w   io.Writer
+		err error
+	}
+)
+
+var (
+	// ErrMalformedLine will be returned when reading a trace file with a
+	// line not being a comment or a chunk.
+	ErrMalformedLine = errors.New("malformed trace line")
+)
+
+// NewWriter will instantiate a new tracer writing to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Chunk implements Tracer.
+func (w *Writer) Chunk(c Chunk) {
+	w.write("%s %s %x\n", c.Time.UTC().Format(time.RFC3339Nano), c.Direction, c.Data)
+}
+
+// Exchange implements Tracer.
+func (w *Writer) Exchange(e Exchange) {
+	lines := strings.Split(strings.TrimSuffix(e.String(), "\n"), "\n")
+
+	w.write("# %s\n", strings.Join(lines, "\n# "))
+}
+
+// Err will return the first error writing, if any. Tracing continues
+// after errors, but nothing more is written.
+func (w *Writer) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.err
+}
+
+func (w *Writer) write(format string, args ...interface{}) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.err != nil {
+		return
+	}
+
+	_, w.err = fmt.Fprintf(w.w, format, args...)
+}
+
+// Read will read the chunks of a trace file.
+func Read(r io.Reader) ([]Chunk, error) {
+	var chunks []Chunk
+
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<20)
+
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		c, err := parseChunk(text)
+		if err != nil {
+			return chunks, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		chunks = append(chunks, c)
+	}
+
+	return chunks, s.Err()
+}
+
+// ReadFile will read the chunks of the trace file at path.
+func ReadFile(path string) ([]Chunk, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Read(f)
+}
+
+func parseChunk(text string) (Chunk, error) {
+	var c Chunk
+
+	fields := strings.Fields(text)
+	if len(fields) != 3 {
+		return c, ErrMalformedLine
+	}
+
+	var err error
+	c.Time, err = time.Parse(time.RFC3339Nano, fields[0])
+	if err != nil {
+		return c, ErrMalformedLine
+	}
+
+	switch fields[1] {
+	case "TX":
+		c.Direction = TX
+	case "RX":
+		c.Direction = RX
+	default:
+		return c, ErrMalformedLine
+	}
+
+	c.Data, err = hex.DecodeString(fields[2])
+	if err != nil {
+		return c, ErrMalformedLine
+	}
+
+	return c, nil
+}
@@ -0,0 +1,113 @@
+package trace
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"time"
+)
+
+type (
+	// Port wraps a connection and passes every chunk read or written to a
+	// tracer.
+	Port struct {
+		io.ReadWriteCloser
+		Tracer Tracer
+	}
+
+	// Replay is a connection answering with the chunks received in a
+	// recorded session. A read with no chunk received pending returns
+	// io.EOF, like a serial port timing out.
+	Replay struct {
+		chunks []Chunk
+
+		// pending is the rest of a chunk received, not read yet.
+		pending []byte
+	}
+)
+
+var (
+	// ErrUnexpectedWrite will be returned by Replay if a write differs from
+	// the chunk sent in the recorded session.
+	ErrUnexpectedWrite = errors.New("write differs from recorded session")
+)
+
+// NewPort will wrap port, tracing to t.
+func NewPort(port io.ReadWriteCloser, t Tracer) *Port {
+	return &Port{
+		ReadWriteCloser: port,
+		Tracer:          t,
+	}
+}
+
+// Read implements io.Reader.
+func (p *Port) Read(b []byte) (int, error) {
+	n, err := p.ReadWriteCloser.Read(b)
+	if n > 0 {
+		p.Tracer.Chunk(Chunk{
+			Time:      time.Now(),
+			Direction: RX,
+			Data:      append([]byte{}, b[:n]...),
+		})
+	}
+
+	return n, err
+}
+
+// Write implements io.Writer.
+func (p *Port) Write(b []byte) (int, error) {
+	p.Tracer.Chunk(Chunk{
+		Time:      time.Now(),
+		Direction: TX,
+		Data:      append([]byte{}, b...),
+	})
+
+	return p.ReadWriteCloser.Write(b)
+}
+
+// NewReplay will replay chunks. Writes must match the chunks sent, in order.
+func NewReplay(chunks []Chunk) *Replay {
+	return &Replay{
+		chunks: chunks,
+	}
+}
+
+// Read implements io.Reader.
+func (r *Replay) Read(b []byte) (int, error) {
+	if len(r.pending) == 0 {
+		if len(r.chunks) == 0 || r.chunks[0].Direction != RX {
+			return 0, io.EOF
+		}
+
+		r.pending = r.chunks[0].Data
+		r.chunks = r.chunks[1:]
+	}
+
+	n := copy(b, r.pending)
+	r.pending = r.pending[n:]
+
+	return n, nil
+}
+
+// Write implements io.Writer. Chunks received, but not read when writing,
+// are dropped, as a client would miss them too.
+func (r *Replay) Write(b []byte) (int, error) {
+	r.pending = nil
+
+	for len(r.chunks) > 0 && r.chunks[0].Direction == RX {
+		r.chunks = r.chunks[1:]
+	}
+
+	if len(r.chunks) == 0 || !bytes.Equal(r.chunks[0].Data, b) {
+		return 0, ErrUnexpectedWrite
+	}
+
+	r.chunks = r.chunks[1:]
+
+	return len(b), nil
+}
+
+// Close implements io.Closer.
+func (r *Replay) Close() error {
+	return nil
+}
@@ -0,0 +1,158 @@
+package trace
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// loopback answers every write with reply.
+type loopback struct {
+	reply   []byte
+	pending []byte
+}
+
+func (l *loopback) Read(b []byte) (int, error) {
+	if len(l.pending) == 0 {
+		return 0, io.EOF
+	}
+
+	n := copy(b, l.pending)
+	l.pending = l.pending[n:]
+
+	return n, nil
+}
+
+func (l *loopback) Write(b []byte) (int, error) {
+	l.pending = append(l.pending, l.reply...)
+
+	return len(b), nil
+}
+
+func (l *loopback) Close() error {
+	return nil
+}
+
+func TestPort(t *testing.T) {
+	recorder := &Recorder{}
+	port := NewPort(&loopback{reply: []byte{0x40, 0x3f, 0x0d}}, recorder)
+
+	port.Write([]byte{0x80, 0x3f, 0x0d})
+
+	buf := make([]byte, 2)
+	port.Read(buf)
+	port.Read(buf)
+
+	_, err := port.Read(buf)
+	if err != io.EOF {
+		t.Errorf("Read() returned %v, expected io.EOF", err)
+	}
+
+	chunks := recorder.Chunks()
+	if len(chunks) != 3 {
+		t.Fatalf("Recorded %d chunks, expected 3", len(chunks))
+	}
+
+	expected := []struct {
+		direction Direction
+		data      []byte
+	}{
+		{TX, []byte{0x80, 0x3f, 0x0d}},
+		{RX, []byte{0x40, 0x3f}},
+		{RX, []byte{0x0d}},
+	}
+
+	for i, e := range expected {
+		if chunks[i].Direction != e.direction || !bytes.Equal(chunks[i].Data, e.data) || chunks[i].Time.IsZero() {
+			t.Errorf("Chunk %d is %v, expected %s % x", i, chunks[i], e.direction, e.data)
+		}
+	}
+}
+
+func TestFile(t *testing.T) {
+	start := time.Date(2026, 10, 19, 12, 34, 56, 123456789, time.UTC)
+	chunks := []Chunk{
+		{start, TX, []byte{0x80, 0x3f, 0x0d}},
+		{start.Add(50 * time.Millisecond), RX, []byte{0x40, 0x3f, 0x0d}},
+	}
+
+	var b bytes.Buffer
+	w := NewWriter(&b)
+
+	for _, c := range chunks {
+		w.Chunk(c)
+	}
+
+	w.Exchange(Exchange{
+		Protocol: "kmp",
+		Start:    start,
+		End:      start.Add(50 * time.Millisecond),
+		Request:  chunks[0].Data,
+		Reply:    chunks[1].Data,
+		Err:      errors.New("frame too short"),
+	})
+
+	expected := "2026-10-19T12:34:56.123456789Z TX 803f0d\n" +
+		"2026-10-19T12:34:56.173456789Z RX 403f0d\n" +
+		"# 12:34:56.123 kmp exchange, 50ms\n" +
+		"#   request: 80 3f 0d\n" +
+		"#   reply:   40 3f 0d\n" +
+		"#   error:   frame too short\n"
+
+	if b.String() != expected {
+		t.Errorf("Writer wrote\n%s\nexpected\n%s", b.String(), expected)
+	}
+
+	read, err := Read(&b)
+	if err != nil {
+		t.Fatalf("Read() returned %s", err)
+	}
+
+	if len(read) != len(chunks) {
+		t.Fatalf("Read() returned %d chunks, expected %d", len(read), len(chunks))
+	}
+
+	for i := range chunks {
+		if !read[i].Time.Equal(chunks[i].Time) || read[i].Direction != chunks[i].Direction || !bytes.Equal(read[i].Data, chunks[i].Data) {
+			t.Errorf("Read() chunk %d is %v, expected %v", i, read[i], chunks[i])
+		}
+	}
+
+	_, err = Read(strings.NewReader("2026-10-19T12:34:56Z XX 00\n"))
+	if !errors.Is(err, ErrMalformedLine) {
+		t.Errorf("Read() of malformed line returned %v", err)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	r := NewReplay([]Chunk{
+		{Direction: TX, Data: []byte("/?!\r\n")},
+		{Direction: RX, Data: []byte("/KAM5")},
+		{Direction: RX, Data: []byte("MT174\r\n")},
+		{Direction: TX, Data: []byte{0x06}},
+		{Direction: RX, Data: []byte{0x02}},
+	})
+
+	_, err := r.Read(make([]byte, 10))
+	if err != io.EOF {
+		t.Errorf("Read() before writing returned %v, expected io.EOF", err)
+	}
+
+	_, err = r.Write([]byte("/?!\r\n"))
+	if err != nil {
+		t.Fatalf("Write() returned %s", err)
+	}
+
+	reply, err := io.ReadAll(r)
+	if err != nil || string(reply) != "/KAM5MT174\r\n" {
+		t.Errorf("ReadAll() returned %q, %v", reply, err)
+	}
+
+	_, err = r.Write([]byte{0x15})
+	if err != ErrUnexpectedWrite {
+		t.Errorf("Write() of unexpected data returned %v", err)
+	}
+}
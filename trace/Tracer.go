@@ -0,0 +1,124 @@
+// Package trace records the traffic between a client and a meter, for
+// debugging and for replaying a session later.
+package trace
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// Direction is the direction of a chunk of data.
+	Direction byte
+
+	// Chunk is the data of a single read or write.
+	Chunk struct {
+		Time      time.Time
+		Direction Direction
+		Data      []byte
+	}
+
+	// Exchange is a request and its reply as seen by a protocol.
+	Exchange struct {
+		Protocol string
+		Start    time.Time
+		End      time.Time
+
+		// Request is the request as encoded for the wire.
+		Request []byte
+
+		// Reply is the raw reply, as read before decoding.
+		Reply []byte
+
+		// Result is a description of the decoded reply, if decoded.
+		Result string
+
+		// Err is the error of the exchange, if any.
+		Err error
+	}
+
+	// Tracer receives the traffic of a connection. Chunk is called for
+	// every read and write, Exchange by protocols after every request.
+	Tracer interface {
+		Chunk(c Chunk)
+		Exchange(e Exchange)
+	}
+
+	// Recorder is a tracer keeping everything traced in memory.
+	Recorder struct {
+		mu        sync.Mutex
+		chunks    []Chunk
+		exchanges []Exchange
+	}
+)
+
+// Directions.
+const (
+	TX Direction = iota // Sent to the meter.
+	RX                  // Received from the meter.
+)
+
+// String will return "TX" or "RX".
+func (d Direction) String() string {
+	switch d {
+	case TX:
+		return "TX"
+	case RX:
+		return "RX"
+	}
+
+	return fmt.Sprintf("direction-%d", byte(d))
+}
+
+// String will return the exchange as annotated text, one line per part.
+func (e Exchange) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s %s exchange, %s\n", e.Start.Format("15:04:05.000"), e.Protocol, e.End.Sub(e.Start).Round(time.Millisecond))
+	fmt.Fprintf(&b, "  request: % x\n", e.Request)
+	fmt.Fprintf(&b, "  reply:   % x\n", e.Reply)
+
+	if e.Result != "" {
+		fmt.Fprintf(&b, "  decoded: %s\n", e.Result)
+	}
+
+	if e.Err != nil {
+		fmt.Fprintf(&b, "  error:   %s\n", e.Err.Error())
+	}
+
+	return b.String()
+}
+
+// Chunk implements Tracer.
+func (r *Recorder) Chunk(c Chunk) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.chunks = append(r.chunks, c)
+}
+
+// Exchange implements Tracer.
+func (r *Recorder) Exchange(e Exchange) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.exchanges = append(r.exchanges, e)
+}
+
+// Chunks will return the chunks recorded.
+func (r *Recorder) Chunks() []Chunk {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Chunk{}, r.chunks...)
+}
+
+// Exchanges will return the exchanges recorded.
+func (r *Recorder) Exchanges() []Exchange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Exchange{}, r.exchanges...)
+}
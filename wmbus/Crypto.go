@@ -0,0 +1,148 @@
+package wmbus
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+)
+
+// crcPolynomial is the CRC polynomial of EN 13757-4.
+const crcPolynomial = 0x3d65
+
+// crc will compute the CRC of EN 13757-4. The initial value is zero and the
+// result is complemented.
+func crc(data []byte) uint16 {
+	var reg uint16
+
+	for _, b := range data {
+		reg ^= uint16(b) << 8
+
+		for i := 0; i < 8; i++ {
+			if reg&0x8000 != 0 {
+				reg = reg<<1 ^ crcPolynomial
+			} else {
+				reg <<= 1
+			}
+		}
+	}
+
+	return ^reg
+}
+
+// checkCRC will return true if data is followed by its big endian CRC.
+func checkCRC(block []byte) bool {
+	if len(block) < 2 {
+		return false
+	}
+
+	n := len(block) - 2
+
+	return crc(block[:n]) == binary.BigEndian.Uint16(block[n:])
+}
+
+// decryptCBC will decrypt whole blocks of data in place.
+func decryptCBC(key []byte, iv []byte, data []byte) error {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+
+	n := len(data) / aes.BlockSize * aes.BlockSize
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(data[:n], data[:n])
+
+	return nil
+}
+
+// decryptCTR will decrypt data in place.
+func decryptCTR(key []byte, iv []byte, data []byte) error {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+
+	cipher.NewCTR(block, iv).XORKeyStream(data, data)
+
+	return nil
+}
+
+// cmac will compute AES-CMAC as defined by RFC 4493.
+func cmac(key []byte, message []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// Subkeys.
+	k1 := make([]byte, aes.BlockSize)
+	block.Encrypt(k1, k1)
+	k1 = shiftSubkey(k1)
+	k2 := shiftSubkey(k1)
+
+	n := (len(message) + aes.BlockSize - 1) / aes.BlockSize
+	complete := n > 0 && len(message)%aes.BlockSize == 0
+	if n == 0 {
+		n = 1
+	}
+
+	last := make([]byte, aes.BlockSize)
+	copy(last, message[(n-1)*aes.BlockSize:])
+	if complete {
+		xor(last, k1)
+	} else {
+		last[len(message)-(n-1)*aes.BlockSize] = 0x80
+		xor(last, k2)
+	}
+
+	x := make([]byte, aes.BlockSize)
+	for i := 0; i < n-1; i++ {
+		xor(x, message[i*aes.BlockSize:(i+1)*aes.BlockSize])
+		block.Encrypt(x, x)
+	}
+
+	xor(x, last)
+	block.Encrypt(x, x)
+
+	return x, nil
+}
+
+// shiftSubkey will shift k one bit left and apply the constant Rb if the
+// most significant bit was set.
+func shiftSubkey(k []byte) []byte {
+	out := make([]byte, len(k))
+
+	for i := 0; i < len(k); i++ {
+		out[i] = k[i] << 1
+		if i+1 < len(k) {
+			out[i] |= k[i+1] >> 7
+		}
+	}
+
+	if k[0]&0x80 != 0 {
+		out[len(out)-1] ^= 0x87
+	}
+
+	return out
+}
+
+// xor will xor b into a.
+func xor(a []byte, b []byte) {
+	for i := range a {
+		a[i] ^= b[i]
+	}
+}
+
+// deriveKey will derive the ephemeral encryption key of security mode 7 from
+// the message counter and the meter id, as defined by OMS.
+func deriveKey(key []byte, counter uint32, id uint32) ([]byte, error) {
+	input := []byte{
+		0x00, // Encryption key from meter
+		0, 0, 0, 0,
+		0, 0, 0, 0,
+		0x07, 0x07, 0x07, 0x07, 0x07, 0x07, 0x07,
+	}
+
+	binary.LittleEndian.PutUint32(input[1:], counter)
+	binary.LittleEndian.PutUint32(input[5:], id)
+
+	return cmac(key, input)
+}
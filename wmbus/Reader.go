@@ -0,0 +1,72 @@
+package wmbus
+
+import (
+	"bufio"
+	"encoding/hex"
+	"io"
+	"strings"
+)
+
+type (
+	// Reader reads telegrams from hex lines as written by radio dongles and
+	// tools like rtl-wmbus. Each line holds one telegram starting with the
+	// L field. Fields separated by semicolons are skipped up to the last one,
+	// and "0x" prefixes, "|" separators and spaces are ignored. Empty lines
+	// and lines starting with # are skipped.
+	Reader struct {
+		s *bufio.Scanner
+		d *Decoder
+	}
+)
+
+// NewReader will initialize a new reader. d can be nil if no telegrams are
+// encrypted and all have a CRC. Tools like rtl-wmbus write telegrams without
+// CRC, which must be read with WithoutCRC set on d.
+func NewReader(r io.Reader, d *Decoder) *Reader {
+	if d == nil {
+		d = NewDecoder()
+	}
+
+	return &Reader{
+		s: bufio.NewScanner(r),
+		d: d,
+	}
+}
+
+// ReadRaw will return the bytes of the next telegram. io.EOF is returned at
+// the end of input.
+func (r *Reader) ReadRaw() ([]byte, error) {
+	for r.s.Scan() {
+		line := strings.TrimSpace(r.s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if i := strings.LastIndex(line, ";"); i >= 0 {
+			line = line[i+1:]
+		}
+
+		line = strings.TrimPrefix(line, "telegram=")
+		line = strings.TrimPrefix(strings.TrimPrefix(line, "0x"), "0X")
+		line = strings.NewReplacer("|", "", " ", "").Replace(line)
+
+		return hex.DecodeString(line)
+	}
+
+	err := r.s.Err()
+	if err == nil {
+		err = io.EOF
+	}
+
+	return nil, err
+}
+
+// ReadTelegram will read and decode the next telegram.
+func (r *Reader) ReadTelegram() (*Telegram, error) {
+	raw, err := r.ReadRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.d.Decode(raw)
+}
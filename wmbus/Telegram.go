@@ -0,0 +1,475 @@
+package wmbus
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/abrander/gometer/iec62056"
+	"github.com/abrander/gometer/mbus"
+)
+
+type (
+	// Telegram is a decoded wireless M-Bus telegram.
+	Telegram struct {
+		// Control is the C field of the link layer.
+		Control byte
+
+		// Address is the sender from the link layer. The medium is the device
+		// type.
+		Address mbus.SecondaryAddress
+
+		// CI is the control information field of the transport layer, and
+		// Header its access number, status and configuration word (as
+		// Signature).
+		CI     byte
+		Header mbus.Header
+
+		Encryption Encryption
+
+		// Payload is the decrypted application data.
+		Payload  []byte
+		UserData *mbus.UserData
+	}
+
+	// Encryption is the security mode of a telegram.
+	Encryption int
+
+	// Decoder decodes telegrams using per-meter keys.
+	Decoder struct {
+		keys map[uint32][]byte
+
+		// WithoutCRC must be set to accept telegrams without CRC, as
+		// written by receivers removing it, like rtl-wmbus and
+		// wmbusmeters. A telegram of frame format B, with a CRC not
+		// validating, is then taken as a telegram without CRC.
+		WithoutCRC bool
+	}
+)
+
+// Security modes.
+const (
+	EncryptionNone  Encryption = iota
+	EncryptionMode5            // AES-128-CBC with static key
+	EncryptionMode7            // AES-128-CBC with ephemeral key
+	EncryptionELL              // AES-128-CTR in the extended link layer
+)
+
+// Control information values of the layers between link and transport
+// layer.
+const (
+	ciELLShort = byte(0x8c)
+	ciELLLong  = byte(0x8d)
+	ciAFL      = byte(0x90)
+)
+
+// Bits of the AFL fragmentation control field.
+const (
+	aflMessageControl = uint16(0x2000)
+	aflMessageCounter = uint16(0x0800)
+	aflKeyInformation = uint16(0x0200)
+)
+
+// Security mode numbers of the configuration word.
+const (
+	modeNone = 0
+	mode5    = 5
+	mode7    = 7
+)
+
+// verification is the start of decrypted application data.
+var verification = []byte{0x2f, 0x2f}
+
+var (
+	// ErrTelegramTooShort will be returned if a telegram is too short to
+	// hold the headers.
+	ErrTelegramTooShort = errors.New("telegram too short")
+
+	// ErrInvalidLength will be returned if the length of a telegram does not
+	// match the L field with or without CRC.
+	ErrInvalidLength = errors.New("telegram length does not match L field")
+
+	// ErrInvalidCRC will be returned if a CRC did not validate.
+	ErrInvalidCRC = errors.New("CRC did not validate")
+
+	// ErrUnknownCI will be returned for unsupported CI fields.
+	ErrUnknownCI = errors.New("unknown control information field")
+
+	// ErrUnsupportedMode will be returned for unsupported security modes.
+	ErrUnsupportedMode = errors.New("unsupported security mode")
+
+	// ErrNoKey will be returned if a telegram is encrypted and no key is
+	// known for the meter.
+	ErrNoKey = errors.New("no key for meter")
+
+	// ErrInvalidKey will be returned by AddKey if the key is not 16 bytes.
+	ErrInvalidKey = errors.New("key must be 16 bytes")
+
+	// ErrNoCounter will be returned for security mode 7 without a message
+	// counter in the authentication and fragmentation layer.
+	ErrNoCounter = errors.New("no message counter for key derivation")
+
+	// ErrDecryptionFailed will be returned if decrypted data does not start
+	// with 0x2f 0x2f, usually because of a wrong key.
+	ErrDecryptionFailed = errors.New("decryption failed, wrong key?")
+
+	encryptionNames = map[Encryption]string{
+		EncryptionNone:  "none",
+		EncryptionMode5: "mode 5",
+		EncryptionMode7: "mode 7",
+		EncryptionELL:   "ELL",
+	}
+)
+
+// NewDecoder will initialize a new decoder without keys.
+func NewDecoder() *Decoder {
+	return &Decoder{
+		keys: make(map[uint32][]byte),
+	}
+}
+
+// AddKey will add the AES key of a meter. id is the BCD id, 0x12345678 for
+// meter "12345678".
+func (d *Decoder) AddKey(id uint32, key []byte) error {
+	if len(key) != 16 {
+		return ErrInvalidKey
+	}
+
+	d.keys[id] = append([]byte{}, key...)
+
+	return nil
+}
+
+// Decode will decode a telegram starting with the L field. The CRC of
+// frame format A or B is verified and removed.
+func (d *Decoder) Decode(raw []byte) (*Telegram, error) {
+	frame, err := removeCRC(raw, d.WithoutCRC)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Telegram{
+		Control: frame[1],
+		Address: mbus.SecondaryAddress{
+			ID:           binary.LittleEndian.Uint32(frame[4:]),
+			Manufacturer: binary.LittleEndian.Uint16(frame[2:]),
+			Version:      frame[8],
+			Medium:       mbus.Medium(frame[9]),
+		},
+	}
+
+	var counter uint32
+	hasCounter := false
+
+	payload := frame[10:]
+	for {
+		if len(payload) == 0 {
+			return t, ErrTelegramTooShort
+		}
+
+		switch payload[0] {
+		case ciELLShort:
+			// Communication control and access number.
+			if len(payload) < 3 {
+				return t, ErrTelegramTooShort
+			}
+
+			payload = payload[3:]
+
+		case ciELLLong:
+			payload, err = d.decodeELL(t, frame, payload)
+			if err != nil {
+				return t, err
+			}
+
+		case ciAFL:
+			payload, counter, hasCounter, err = decodeAFL(payload)
+			if err != nil {
+				return t, err
+			}
+
+		case mbus.CIResponseShort, mbus.CIResponseLong, mbus.CIResponseNone:
+			return t, d.decodeTransport(t, payload, counter, hasCounter)
+
+		default:
+			return t, ErrUnknownCI
+		}
+	}
+}
+
+// decodeELL will decode the long extended link layer and decrypt the rest
+// of the telegram if encrypted.
+func (d *Decoder) decodeELL(t *Telegram, frame []byte, payload []byte) ([]byte, error) {
+	// CI, communication control, access number, session number and the
+	// payload CRC.
+	if len(payload) < 9 {
+		return nil, ErrTelegramTooShort
+	}
+
+	sn := binary.LittleEndian.Uint32(payload[3:])
+
+	rest := payload[7:]
+	if sn>>29&0x07 == 0 {
+		return rest[2:], nil
+	}
+
+	if sn>>29&0x07 != 1 {
+		return nil, ErrUnsupportedMode
+	}
+
+	key, found := d.keys[t.Address.ID]
+	if !found {
+		return nil, ErrNoKey
+	}
+
+	// Manufacturer and address, communication control, session number,
+	// and zero frame and block counters.
+	iv := make([]byte, 16)
+	copy(iv, frame[2:10])
+	iv[8] = payload[1]
+	copy(iv[9:], payload[3:7])
+
+	err := decryptCTR(key, iv, rest)
+	if err != nil {
+		return nil, err
+	}
+
+	if crc(rest[2:]) != binary.LittleEndian.Uint16(rest) {
+		return nil, ErrDecryptionFailed
+	}
+
+	t.Encryption = EncryptionELL
+
+	return rest[2:], nil
+}
+
+// decodeAFL will skip the authentication and fragmentation layer, and return
+// the message counter if present.
+func decodeAFL(payload []byte) ([]byte, uint32, bool, error) {
+	if len(payload) < 4 || payload[1] < 2 || len(payload) < 2+int(payload[1]) {
+		return nil, 0, false, ErrTelegramTooShort
+	}
+
+	fields := payload[2 : 2+int(payload[1])]
+	rest := payload[2+int(payload[1]):]
+
+	fcl := binary.LittleEndian.Uint16(fields)
+	pos := 2
+
+	if fcl&aflMessageControl != 0 {
+		pos++
+	}
+
+	if fcl&aflKeyInformation != 0 {
+		pos += 2
+	}
+
+	if fcl&aflMessageCounter == 0 {
+		return rest, 0, false, nil
+	}
+
+	if len(fields) < pos+4 {
+		return nil, 0, false, ErrTelegramTooShort
+	}
+
+	return rest, binary.LittleEndian.Uint32(fields[pos:]), true, nil
+}
+
+// decodeTransport will decode the transport layer header, decrypt and
+// decode the data records.
+func (d *Decoder) decodeTransport(t *Telegram, payload []byte, counter uint32, hasCounter bool) error {
+	f := &mbus.Frame{Type: mbus.FrameLong, CI: payload[0], Data: payload[1:]}
+
+	h, data, err := f.Header()
+	if err != nil {
+		return err
+	}
+
+	t.CI = f.CI
+	t.Header = h
+
+	// The long header holds the address of the meter, the link layer the
+	// address of the sender, which can be a repeater.
+	meter := t.Address
+	if f.CI == mbus.CIResponseLong {
+		meter = h.Address
+	}
+
+	mode := modeNone
+	if f.CI != mbus.CIResponseNone {
+		mode = int(h.Signature >> 8 & 0x1f)
+	}
+
+	switch mode {
+	case modeNone:
+	case mode5, mode7:
+		key, found := d.keys[meter.ID]
+		if !found {
+			return ErrNoKey
+		}
+
+		data, err = decrypt(mode, key, meter, h, data, counter, hasCounter)
+		if err != nil {
+			return err
+		}
+
+		t.Encryption = EncryptionMode5
+		if mode == mode7 {
+			t.Encryption = EncryptionMode7
+		}
+	default:
+		return ErrUnsupportedMode
+	}
+
+	t.Payload = data
+	t.UserData, err = mbus.DecodeUserData(data, meter.Manufacturer)
+
+	return err
+}
+
+// decrypt will decrypt data using security mode 5 or 7. The number of
+// encrypted blocks is taken from the configuration word, the rest is left
+// as is.
+func decrypt(mode int, key []byte, meter mbus.SecondaryAddress, h mbus.Header, data []byte, counter uint32, hasCounter bool) ([]byte, error) {
+	iv := make([]byte, 16)
+
+	if mode == mode7 {
+		// The configuration field extension is not encrypted.
+		if len(data) < 1 {
+			return nil, ErrTelegramTooShort
+		}
+		data = data[1:]
+
+		if !hasCounter {
+			return nil, ErrNoCounter
+		}
+
+		var err error
+		key, err = deriveKey(key, counter, meter.ID)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// Manufacturer, address and eight times the access number.
+		binary.LittleEndian.PutUint16(iv, meter.Manufacturer)
+		binary.LittleEndian.PutUint32(iv[2:], meter.ID)
+		iv[6] = meter.Version
+		iv[7] = byte(meter.Medium)
+		for i := 8; i < 16; i++ {
+			iv[i] = h.AccessNumber
+		}
+	}
+
+	n := int(h.Signature>>4&0x0f) * 16
+	if n == 0 || n > len(data) {
+		n = len(data) / 16 * 16
+	}
+
+	plain := append([]byte{}, data...)
+
+	err := decryptCBC(key, iv, plain[:n])
+	if err != nil {
+		return nil, err
+	}
+
+	if n < 2 || plain[0] != verification[0] || plain[1] != verification[1] {
+		return nil, ErrDecryptionFailed
+	}
+
+	return plain, nil
+}
+
+// removeCRC will verify and remove the CRC of frame format A or B. If
+// withoutCRC is true, telegrams matching the L field without CRC, and not
+// validating as format B, are returned as is.
+func removeCRC(raw []byte, withoutCRC bool) ([]byte, error) {
+	if len(raw) < 12 {
+		return nil, ErrTelegramTooShort
+	}
+
+	l := int(raw[0])
+
+	// Format A: a block of 10 bytes followed by blocks of 16 bytes, each
+	// with CRC. L does not count the CRC.
+	blocks := 1 + (l-9+15)/16
+	if len(raw) == l+1+2*blocks {
+		frame := make([]byte, 0, l+1)
+
+		for start := 0; start < len(raw); {
+			size := 16
+			if start == 0 {
+				size = 10
+			}
+
+			if start+size+2 > len(raw) {
+				size = len(raw) - start - 2
+			}
+
+			block := raw[start : start+size+2]
+			if !checkCRC(block) {
+				return nil, ErrInvalidCRC
+			}
+
+			frame = append(frame, block[:size]...)
+			start += size + 2
+		}
+
+		return frame, nil
+	}
+
+	if len(raw) != l+1 {
+		return nil, ErrInvalidLength
+	}
+
+	// Format B: one CRC for the first 126 bytes, and one for the rest. L
+	// counts the CRC.
+	switch {
+	case l+1 <= 128 && checkCRC(raw):
+		frame := append([]byte{}, raw[:l-1]...)
+		frame[0] = byte(len(frame) - 1)
+
+		return frame, nil
+
+	case l+1 > 130 && checkCRC(raw[:128]) && checkCRC(raw[128:]):
+		frame := append([]byte{}, raw[:126]...)
+		frame = append(frame, raw[128:l-1]...)
+		frame[0] = byte(len(frame) - 1)
+
+		return frame, nil
+
+	case withoutCRC:
+		return append([]byte{}, raw...), nil
+
+	case l+1 > 128 && l+1 <= 130:
+		// Too long for one block, too short for a second block with CRC.
+		return nil, ErrInvalidLength
+	}
+
+	return nil, ErrInvalidCRC
+}
+
+// String will return the name of the security mode.
+func (e Encryption) String() string {
+	s, found := encryptionNames[e]
+	if !found {
+		return fmt.Sprintf("mode %d", int(e))
+	}
+
+	return s
+}
+
+// String implements fmt.Stringer.
+func (t *Telegram) String() string {
+	records := 0
+	if t.UserData != nil {
+		records = len(t.UserData.Records)
+	}
+
+	manufacturer := fmt.Sprintf("0x%04x", t.Address.Manufacturer)
+	if m, err := iec62056.ManufacturerFromID(t.Address.Manufacturer); err == nil {
+		manufacturer = string(m)
+	}
+
+	return fmt.Sprintf("%s %s %s, CI=%02x, encryption %s, %d records",
+		manufacturer, t.Address, t.Address.Medium, t.CI, t.Encryption, records)
+}
@@ -0,0 +1,374 @@
+package wmbus
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/abrander/gometer/kamstrup"
+	"github.com/abrander/gometer/mbus"
+)
+
+var (
+	testKey, _ = hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+
+	// Kamstrup, id 12345678, version 0x1b, heat meter.
+	testLink, _ = hex.DecodeString("442d2c785634121b04")
+
+	// Energy 13084 kWh, volume 10.00 m³.
+	testRecords, _ = hex.DecodeString("04061c3300000414e8030000")
+)
+
+// telegram will return a telegram without CRC.
+func telegram(payload []byte) []byte {
+	raw := append([]byte{byte(len(testLink) + len(payload))}, testLink...)
+
+	return append(raw, payload...)
+}
+
+// formatA will add the CRC blocks of frame format A.
+func formatA(frame []byte) []byte {
+	var raw []byte
+
+	for start := 0; start < len(frame); {
+		size := 16
+		if start == 0 {
+			size = 10
+		}
+
+		if start+size > len(frame) {
+			size = len(frame) - start
+		}
+
+		block := frame[start : start+size]
+		raw = append(raw, block...)
+		raw = binary.BigEndian.AppendUint16(raw, crc(block))
+		start += size
+	}
+
+	return raw
+}
+
+// encrypted will pad plain with 0x2f and encrypt it using AES-CBC.
+func encrypted(t *testing.T, key []byte, iv []byte, plain []byte) []byte {
+	data := append([]byte{}, plain...)
+	for len(data)%16 != 0 {
+		data = append(data, 0x2f)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatalf("NewCipher() returned %s", err)
+	}
+
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(data, data)
+
+	return data
+}
+
+func checkRecords(t *testing.T, tg *Telegram) {
+	if tg.UserData == nil || len(tg.UserData.Records) != 2 {
+		t.Fatalf("Telegram %s has no records", tg)
+	}
+
+	energy := tg.UserData.Records[0]
+	if energy.Value != 13084 || energy.Unit != kamstrup.UnitKWh {
+		t.Errorf("Energy is %s", energy)
+	}
+
+	volume := tg.UserData.Records[1]
+	if volume.Value != 10 || volume.Unit != kamstrup.UnitCubicMetre {
+		t.Errorf("Volume is %s", volume)
+	}
+}
+
+func TestCRC(t *testing.T) {
+	if v := crc([]byte("123456789")); v != 0xc2b7 {
+		t.Errorf("crc() returned %04x, expected c2b7", v)
+	}
+}
+
+func TestCMAC(t *testing.T) {
+	key, _ := hex.DecodeString("2b7e151628aed2a6abf7158809cf4f3c")
+	message, _ := hex.DecodeString("6bc1bee22e409f96e93d7e117393172aae2d8a571e03ac9c9eb76fac45af8e5130c81c46a35ce411e5fbc1191a0a52eff69f2445df4f9b17ad2b417be66c3710")
+
+	// From RFC 4493.
+	cases := []struct {
+		length int
+		mac    string
+	}{
+		{0, "bb1d6929e95937287fa37d129b756746"},
+		{16, "070a16b46b4d4144f79bdd9dd04a287c"},
+		{40, "dfa66747de9ae63030ca32611497c827"},
+		{64, "51f0bebf7e3b9d92fc49741779363cfe"},
+	}
+
+	for _, c := range cases {
+		mac, err := cmac(key, message[:c.length])
+		if err != nil {
+			t.Fatalf("cmac() returned %s", err)
+		}
+
+		if hex.EncodeToString(mac) != c.mac {
+			t.Errorf("cmac() of %d bytes returned %x, expected %s", c.length, mac, c.mac)
+		}
+	}
+}
+
+func TestDecodePlain(t *testing.T) {
+	frame := telegram(append([]byte{mbus.CIResponseShort, 0x2a, 0x00, 0x00, 0x00}, testRecords...))
+
+	d := NewDecoder()
+	d.WithoutCRC = true
+
+	for _, raw := range [][]byte{frame, formatA(frame)} {
+		tg, err := d.Decode(raw)
+		if err != nil {
+			t.Fatalf("Decode(%x) returned %s", raw, err)
+		}
+
+		if tg.Address.String() != "123456782c2d1b04" || tg.Header.AccessNumber != 0x2a || tg.Encryption != EncryptionNone {
+			t.Errorf("Decode() returned %s", tg)
+		}
+
+		checkRecords(t, tg)
+	}
+
+	// Format B, L counts the CRC.
+	b := append([]byte{}, frame...)
+	b[0] += 2
+	b = binary.BigEndian.AppendUint16(b, crc(b))
+
+	tg, err := d.Decode(b)
+	if err != nil {
+		t.Fatalf("Decode() of format B returned %s", err)
+	}
+	checkRecords(t, tg)
+
+	// Telegrams without CRC are not accepted by default, and a format B
+	// telegram with a wrong CRC is not taken as one.
+	d.WithoutCRC = false
+	_, err = d.Decode(frame)
+	if err != ErrInvalidCRC {
+		t.Errorf("Decode() of telegram without CRC returned %v", err)
+	}
+
+	b[len(b)-1] ^= 0x01
+	_, err = d.Decode(b)
+	if err != ErrInvalidCRC {
+		t.Errorf("Decode() of corrupt format B telegram returned %v", err)
+	}
+
+	corrupt := formatA(frame)
+	corrupt[15] ^= 0x01
+	_, err = d.Decode(corrupt)
+	if err != ErrInvalidCRC {
+		t.Errorf("Decode() of corrupt telegram returned %v", err)
+	}
+
+	_, err = d.Decode(frame[:len(frame)-3])
+	if err != ErrInvalidLength {
+		t.Errorf("Decode() of truncated telegram returned %v", err)
+	}
+}
+
+func TestDecodeMode5(t *testing.T) {
+	iv := append(append([]byte{}, testLink[1:9]...), 0x2a, 0x2a, 0x2a, 0x2a, 0x2a, 0x2a, 0x2a, 0x2a)
+
+	data := encrypted(t, testKey, iv, append([]byte{0x2f, 0x2f}, testRecords...))
+
+	// Configuration word: mode 5, one encrypted block.
+	frame := telegram(append([]byte{mbus.CIResponseShort, 0x2a, 0x00, 0x10, 0x05}, data...))
+
+	d := NewDecoder()
+
+	_, err := d.Decode(formatA(frame))
+	if err != ErrNoKey {
+		t.Errorf("Decode() without key returned %v", err)
+	}
+
+	err = d.AddKey(0x12345678, testKey[1:])
+	if err != ErrInvalidKey {
+		t.Errorf("AddKey() of short key returned %v", err)
+	}
+
+	wrong := make([]byte, 16)
+	d.AddKey(0x12345678, wrong)
+	_, err = d.Decode(formatA(frame))
+	if err != ErrDecryptionFailed {
+		t.Errorf("Decode() with wrong key returned %v", err)
+	}
+
+	d.AddKey(0x12345678, testKey)
+	tg, err := d.Decode(formatA(frame))
+	if err != nil {
+		t.Fatalf("Decode() returned %s", err)
+	}
+
+	if tg.Encryption != EncryptionMode5 {
+		t.Errorf("Encryption is %s", tg.Encryption)
+	}
+
+	checkRecords(t, tg)
+}
+
+func TestDecodeMode7(t *testing.T) {
+	counter := uint32(0x01020304)
+
+	key, err := deriveKey(testKey, counter, 0x12345678)
+	if err != nil {
+		t.Fatalf("deriveKey() returned %s", err)
+	}
+
+	data := encrypted(t, key, make([]byte, 16), append([]byte{0x2f, 0x2f}, testRecords...))
+
+	// AFL with message counter.
+	payload := []byte{ciAFL, 0x06, 0x00, 0x08, 0x04, 0x03, 0x02, 0x01}
+
+	// Configuration word: mode 7, one encrypted block, and the
+	// configuration field extension.
+	payload = append(payload, mbus.CIResponseShort, 0x2a, 0x00, 0x10, 0x07, 0x10)
+	payload = append(payload, data...)
+
+	d := NewDecoder()
+	d.AddKey(0x12345678, testKey)
+
+	tg, err := d.Decode(formatA(telegram(payload)))
+	if err != nil {
+		t.Fatalf("Decode() returned %s", err)
+	}
+
+	if tg.Encryption != EncryptionMode7 {
+		t.Errorf("Encryption is %s", tg.Encryption)
+	}
+
+	checkRecords(t, tg)
+
+	// Without AFL there is no counter.
+	_, err = d.Decode(formatA(telegram(payload[8:])))
+	if err != ErrNoCounter {
+		t.Errorf("Decode() without counter returned %v", err)
+	}
+}
+
+func TestDecodeELL(t *testing.T) {
+	sn := []byte{0x11, 0x22, 0x33, 0x20} // Encryption mode 1 in the top bits
+
+	plain := append([]byte{mbus.CIResponseNone}, testRecords...)
+	plain = append(binary.LittleEndian.AppendUint16(nil, crc(plain)), plain...)
+
+	iv := make([]byte, 16)
+	copy(iv, testLink[1:9])
+	iv[8] = 0x20
+	copy(iv[9:], sn)
+
+	block, _ := aes.NewCipher(testKey)
+	cipher.NewCTR(block, iv).XORKeyStream(plain, plain)
+
+	payload := append([]byte{ciELLLong, 0x20, 0x2a}, sn...)
+	payload = append(payload, plain...)
+
+	d := NewDecoder()
+	d.AddKey(0x12345678, testKey)
+
+	tg, err := d.Decode(formatA(telegram(payload)))
+	if err != nil {
+		t.Fatalf("Decode() returned %s", err)
+	}
+
+	if tg.Encryption != EncryptionELL || tg.CI != mbus.CIResponseNone {
+		t.Errorf("Decode() returned %s", tg)
+	}
+
+	checkRecords(t, tg)
+}
+
+// TestDecodeKnownAnswer decodes published telegrams, encrypted by others.
+func TestDecodeKnownAnswer(t *testing.T) {
+	cases := []struct {
+		name       string
+		id         uint32
+		key        string
+		telegram   string
+		encryption Encryption
+		record     string
+	}{
+		// Mode 5 example from the OMS specification, volume 2, annex N.
+		{
+			"OMS mode 5", 0x12345678, "0102030405060708090a0b0c0d0e0f11",
+			"2e4493157856341233027a2a0020255923c95aaa26d1b2e7493b013ec4a6f6d3529b520edff0ea6defc99d6d69ebf3",
+			EncryptionMode5, "volume: 38504.270 m³",
+		},
+
+		// Kamstrup Multical 21 using the extended link layer, from the
+		// wmbusmeters test suite.
+		{
+			"Multical 21 ELL", 0x76348799, "28f64a24988064a079aa2c807d6102ae",
+			"2a442d2c998734761b168d2091d37cac21e1d68cdaffcd3dc452bd802913ff7b1706ca9e355d6c2701cc24",
+			EncryptionELL, "volume: 6.408 m³",
+		},
+	}
+
+	for _, c := range cases {
+		key, _ := hex.DecodeString(c.key)
+		raw, _ := hex.DecodeString(c.telegram)
+
+		d := NewDecoder()
+		d.WithoutCRC = true
+		d.AddKey(c.id, key)
+
+		tg, err := d.Decode(raw)
+		if err != nil {
+			t.Errorf("%s: Decode() returned %s", c.name, err)
+			continue
+		}
+
+		if tg.Encryption != c.encryption || tg.UserData == nil {
+			t.Errorf("%s: Decode() returned %s", c.name, tg)
+			continue
+		}
+
+		found := false
+		for _, r := range tg.UserData.Records {
+			found = found || r.String() == c.record
+		}
+
+		if !found {
+			t.Errorf("%s: Got: %v, expected: %s", c.name, tg.UserData.Records, c.record)
+		}
+	}
+}
+
+func TestReader(t *testing.T) {
+	frame := telegram(append([]byte{mbus.CIResponseShort, 0x2a, 0x00, 0x00, 0x00}, testRecords...))
+
+	input := strings.Join([]string{
+		"# Recorded with rtl-wmbus",
+		"T1;1;1;2021-01-12 17:35:59.000;97;148;12345678;0x" + hex.EncodeToString(formatA(frame)),
+		"",
+		"telegram=|" + strings.ToUpper(hex.EncodeToString(frame)) + "|",
+	}, "\n")
+
+	d := NewDecoder()
+	d.WithoutCRC = true
+
+	r := NewReader(strings.NewReader(input), d)
+
+	for i := 0; i < 2; i++ {
+		tg, err := r.ReadTelegram()
+		if err != nil {
+			t.Fatalf("ReadTelegram() returned %s", err)
+		}
+
+		checkRecords(t, tg)
+	}
+
+	_, err := r.ReadTelegram()
+	if err != io.EOF {
+		t.Errorf("ReadTelegram() at end returned %v", err)
+	}
+}